@@ -0,0 +1,333 @@
+// Package archive stores subreddit listings, and optionally their comment
+// trees, in a SQLite database for offline analysis.
+//
+// The schema has four tables: posts, comments, authors, and fetch_runs, one
+// row per archive run. Re-archiving a post or comment updates its score and
+// counts in place. A run that stops early keeps its listing cursor, and the
+// next run of the same listing resumes from it.
+package archive
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"iter"
+	"time"
+
+	_ "modernc.org/sqlite" // Registers the "sqlite" driver
+
+	"github.com/Koshroy/grapeddit/redditclient"
+)
+
+// Run statuses in fetch_runs
+const (
+	StatusRunning     = "running"
+	StatusInterrupted = "interrupted"
+	StatusComplete    = "complete"
+)
+
+// DefaultPageSize is how many posts are requested, and stored, at a time
+const DefaultPageSize = 100
+
+// Source is what Archive reads from. *redditclient.Client implements it.
+type Source interface {
+	SubredditPosts(ctx context.Context, subreddit, sort string, opts *redditclient.ListingOptions) iter.Seq2[redditclient.Post, error]
+	GetAllComments(ctx context.Context, subreddit, postID string, opts *redditclient.CommentOptions) (*redditclient.CommentListing, error)
+}
+
+// Options controls an Archive run
+type Options struct {
+	Sort     string // Listing sort; empty means "new"
+	Pages    int    // Pages to fetch in the run, counting those of a resumed run; zero means no cap
+	PageSize int    // Posts per page; zero means DefaultPageSize
+	Comments bool   // Also store each post's full comment tree
+	Restart  bool   // Start a new run instead of resuming an unfinished one
+}
+
+// Result summarizes an Archive run
+type Result struct {
+	RunID    int64
+	Resumed  bool // The run continued an unfinished one
+	Pages    int  // Pages stored by this call
+	Posts    int  // Posts stored by this call
+	Comments int  // Comments stored by this call
+	Complete bool // The listing was archived to its end or the page cap
+}
+
+// DB is an archive database
+type DB struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// Open opens the SQLite database at path, creating it if needed, and
+// migrates it to the current schema. ":memory:" opens a private in-memory
+// database.
+func Open(ctx context.Context, path string) (*DB, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	// One connection serializes writes, which SQLite does anyway, and keeps
+	// an in-memory database from being a different one per connection
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.ExecContext(ctx, "PRAGMA foreign_keys = ON"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if err := migrate(ctx, db, migrations); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &DB{db: db, now: time.Now}, nil
+}
+
+// Close closes the database
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// SQL returns the underlying database, for queries against the archive
+func (d *DB) SQL() *sql.DB {
+	return d.db
+}
+
+// run is the state of a fetch_runs row
+type run struct {
+	id    int64
+	after string
+	pages int
+}
+
+// Archive walks subreddit's listing page by page, storing each page's posts,
+// and with opts.Comments their comment trees, in one transaction together
+// with the run's cursor. An unfinished run of the same listing is resumed
+// unless opts.Restart is set. If fetching fails or ctx is cancelled, the
+// pages stored so far are kept, the run is marked interrupted, and the error
+// is returned with a Result covering what was stored.
+func (d *DB) Archive(ctx context.Context, src Source, subreddit string, opts Options) (*Result, error) {
+	if opts.Sort == "" {
+		opts.Sort = string(redditclient.SortNew)
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultPageSize
+	}
+
+	r, resumed, err := d.startRun(ctx, subreddit, opts.Sort, opts.Restart)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{RunID: r.id, Resumed: resumed}
+
+	// Storing what was fetched must outlive a cancelled ctx, or an
+	// interrupted run would lose its last page and cursor
+	storeCtx := context.WithoutCancel(ctx)
+
+	remaining := 0
+	if opts.Pages > 0 {
+		remaining = opts.Pages - r.pages
+		if remaining <= 0 {
+			result.Complete = true
+			return result, d.finishRun(storeCtx, r, StatusComplete)
+		}
+	}
+
+	listOpts := &redditclient.ListingOptions{Limit: opts.PageSize, After: r.after, MaxPages: remaining}
+	var page []redditclient.Post
+	flush := func() error {
+		var trees []*redditclient.CommentListing
+		var commentsErr error
+		if opts.Comments {
+			for i := range page {
+				tree, err := src.GetAllComments(ctx, subreddit, page[i].ID, nil)
+				if err != nil {
+					// Store only the posts before this one, so a resumed run
+					// starts with it
+					commentsErr = fmt.Errorf("failed to fetch comments of %s: %w", page[i].Name, err)
+					page = page[:i]
+					break
+				}
+				trees = append(trees, tree)
+			}
+		}
+		if err := d.storePage(storeCtx, r, page, trees); err != nil {
+			return err
+		}
+		if len(page) > 0 {
+			result.Pages++
+			result.Posts += len(page)
+			for _, tree := range trees {
+				result.Comments += redditclient.CountComments(tree)
+			}
+		}
+		page = page[:0]
+		return commentsErr
+	}
+
+	var fetchErr error
+	for post, err := range src.SubredditPosts(ctx, subreddit, opts.Sort, listOpts) {
+		if err != nil {
+			fetchErr = err
+			break
+		}
+		page = append(page, post)
+		if len(page) == opts.PageSize {
+			if fetchErr = flush(); fetchErr != nil {
+				break
+			}
+		}
+	}
+	if fetchErr == nil {
+		fetchErr = flush()
+	} else if len(page) > 0 {
+		// Keep the partial page fetched before the error
+		if err := flush(); err != nil {
+			fetchErr = errors.Join(fetchErr, err)
+		}
+	}
+
+	if fetchErr != nil {
+		if err := d.finishRun(storeCtx, r, StatusInterrupted); err != nil {
+			fetchErr = errors.Join(fetchErr, err)
+		}
+		return result, fetchErr
+	}
+	result.Complete = true
+	return result, d.finishRun(storeCtx, r, StatusComplete)
+}
+
+// startRun resumes the latest run of a listing if it is unfinished, or
+// starts a new one. It reports whether it resumed.
+func (d *DB) startRun(ctx context.Context, subreddit, sort string, restart bool) (*run, bool, error) {
+	if !restart {
+		r := &run{}
+		var status string
+		err := d.db.QueryRowContext(ctx, `SELECT id, status, after_cursor, pages_fetched FROM fetch_runs
+			WHERE subreddit = ? AND sort = ? ORDER BY id DESC LIMIT 1`,
+			subreddit, sort).Scan(&r.id, &status, &r.after, &r.pages)
+		switch {
+		case err == nil && status != StatusComplete:
+			_, err := d.db.ExecContext(ctx, "UPDATE fetch_runs SET status = ? WHERE id = ?", StatusRunning, r.id)
+			return r, true, err
+		case err != nil && !errors.Is(err, sql.ErrNoRows):
+			return nil, false, err
+		}
+	}
+
+	res, err := d.db.ExecContext(ctx, "INSERT INTO fetch_runs (subreddit, sort, status, started_at) VALUES (?, ?, ?, ?)",
+		subreddit, sort, StatusRunning, d.timestamp())
+	if err != nil {
+		return nil, false, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, false, err
+	}
+	return &run{id: id}, false, nil
+}
+
+func (d *DB) finishRun(ctx context.Context, r *run, status string) error {
+	_, err := d.db.ExecContext(ctx, "UPDATE fetch_runs SET status = ?, finished_at = ? WHERE id = ?", status, d.timestamp(), r.id)
+	return err
+}
+
+// storePage upserts a page of posts, the comment trees fetched for them, if
+// any, and their authors, and advances the run's cursor past the page, all
+// in one transaction
+func (d *DB) storePage(ctx context.Context, r *run, posts []redditclient.Post, trees []*redditclient.CommentListing) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := d.timestamp()
+	for i := range posts {
+		p := &posts[i]
+		if err := upsertAuthor(ctx, tx, p.Author, now); err != nil {
+			return err
+		}
+		if err := upsertPost(ctx, tx, r.id, p, now); err != nil {
+			return fmt.Errorf("failed to store post %s: %w", p.Name, err)
+		}
+		if i < len(trees) {
+			for _, c := range redditclient.FlattenComments(trees[i]) {
+				if err := upsertAuthor(ctx, tx, c.Author, now); err != nil {
+					return err
+				}
+				if err := upsertComment(ctx, tx, r.id, p.ID, c, now); err != nil {
+					return fmt.Errorf("failed to store comment %s: %w", c.ID, err)
+				}
+			}
+		}
+	}
+
+	r.after = posts[len(posts)-1].Name
+	r.pages++
+	if _, err := tx.ExecContext(ctx, `UPDATE fetch_runs SET after_cursor = ?, pages_fetched = ?, posts_seen = posts_seen + ?
+		WHERE id = ?`, r.after, r.pages, len(posts), r.id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deletedAuthor is the author of deleted posts and comments, which is not a
+// user worth a row in authors
+const deletedAuthor = "[deleted]"
+
+func upsertAuthor(ctx context.Context, tx *sql.Tx, name, now string) error {
+	if name == "" || name == deletedAuthor {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `INSERT INTO authors (name, first_seen_at, last_seen_at) VALUES (?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET last_seen_at = excluded.last_seen_at`, name, now, now)
+	return err
+}
+
+func upsertPost(ctx context.Context, tx *sql.Tx, runID int64, p *redditclient.Post, now string) error {
+	var edited sql.NullFloat64
+	if p.Edited.Edited {
+		edited = sql.NullFloat64{Float64: float64(p.Edited.At), Valid: true}
+	}
+	_, err := tx.ExecContext(ctx, `INSERT INTO posts (id, subreddit, author, title, selftext, url, permalink, domain, flair,
+			is_self, over_18, score, upvote_ratio, num_comments, created_utc, edited_utc, first_run_id, last_run_id, fetched_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			author = excluded.author,
+			selftext = excluded.selftext,
+			flair = excluded.flair,
+			over_18 = excluded.over_18,
+			score = excluded.score,
+			upvote_ratio = excluded.upvote_ratio,
+			num_comments = excluded.num_comments,
+			edited_utc = excluded.edited_utc,
+			last_run_id = excluded.last_run_id,
+			fetched_at = excluded.fetched_at`,
+		p.ID, p.Subreddit, p.Author, p.Title, p.SelfText, p.URL, p.Permalink, p.Domain, p.LinkFlairText,
+		p.IsSelf, p.Over18, p.Score, p.UpvoteRatio, p.NumComments, float64(p.Created), edited, runID, runID, now)
+	return err
+}
+
+func upsertComment(ctx context.Context, tx *sql.Tx, runID int64, postID string, c *redditclient.Comment, now string) error {
+	_, err := tx.ExecContext(ctx, `INSERT INTO comments (id, post_id, parent_id, author, body, score, depth, created_utc, last_run_id, fetched_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			author = excluded.author,
+			body = excluded.body,
+			score = excluded.score,
+			last_run_id = excluded.last_run_id,
+			fetched_at = excluded.fetched_at`,
+		c.ID, postID, c.ParentID, c.Author, c.Body, c.Score, c.Depth, float64(c.Created), runID, now)
+	return err
+}
+
+// timestamp is the current time as stored in the database
+func (d *DB) timestamp() string {
+	return d.now().UTC().Format(time.RFC3339)
+}
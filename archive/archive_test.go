@@ -0,0 +1,310 @@
+package archive
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+)
+
+// fakeSource pages through a fixed list of posts, following the After
+// cursor, Limit and MaxPages as the client's iterator does
+type fakeSource struct {
+	posts     []redditclient.Post
+	failAfter int // Fail after yielding this many posts in a call; zero never fails
+	calls     []redditclient.ListingOptions
+}
+
+var errFetch = errors.New("connection reset")
+
+func (f *fakeSource) SubredditPosts(ctx context.Context, subreddit, sort string, opts *redditclient.ListingOptions) iter.Seq2[redditclient.Post, error] {
+	f.calls = append(f.calls, *opts)
+	return func(yield func(redditclient.Post, error) bool) {
+		start := 0
+		if opts.After != "" {
+			start = slices.IndexFunc(f.posts, func(p redditclient.Post) bool { return p.Name == opts.After }) + 1
+		}
+		end := len(f.posts)
+		if opts.MaxPages > 0 {
+			end = min(end, start+opts.MaxPages*opts.Limit)
+		}
+		for i, p := range f.posts[start:end] {
+			if f.failAfter > 0 && i == f.failAfter {
+				yield(redditclient.Post{}, errFetch)
+				return
+			}
+			if !yield(p, nil) {
+				return
+			}
+		}
+	}
+}
+
+// GetAllComments returns a top-level comment with one reply
+func (f *fakeSource) GetAllComments(ctx context.Context, subreddit, postID string, opts *redditclient.CommentOptions) (*redditclient.CommentListing, error) {
+	reply := &redditclient.Comment{ID: postID + "c2", ParentID: "t1_" + postID + "c1", Author: "replier", Body: "Agreed", Depth: 1}
+	top := &redditclient.Comment{ID: postID + "c1", ParentID: "t3_" + postID, Author: "commenter", Body: "First", Replies: &redditclient.CommentListing{}}
+	top.Replies.Data.Children = []redditclient.CommentChild{{Kind: "t1", Comment: reply}}
+
+	listing := &redditclient.CommentListing{}
+	listing.Data.Children = []redditclient.CommentChild{{Kind: "t1", Comment: top}}
+	return listing, nil
+}
+
+func makePosts(n int) []redditclient.Post {
+	posts := make([]redditclient.Post, n)
+	for i := range posts {
+		id := fmt.Sprintf("p%03d", i)
+		posts[i] = redditclient.Post{
+			ID:          id,
+			Name:        "t3_" + id,
+			Subreddit:   "golang",
+			Author:      fmt.Sprintf("author%d", i%7),
+			Title:       "Post " + id,
+			Score:       i,
+			NumComments: i % 10,
+			Created:     redditclient.Timestamp(1712966400 - i*60),
+		}
+	}
+	return posts
+}
+
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+
+	db, err := Open(t.Context(), ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	db.now = func() time.Time { return time.Date(2024, 4, 14, 0, 0, 0, 0, time.UTC) }
+	return db
+}
+
+func count(t *testing.T, db *DB, query string, args ...any) int {
+	t.Helper()
+
+	var n int
+	require.NoError(t, db.SQL().QueryRow(query, args...).Scan(&n))
+	return n
+}
+
+func TestOpen_Migrates(t *testing.T) {
+	db := openTestDB(t)
+
+	assert.Equal(t, len(migrations), count(t, db, "PRAGMA user_version"))
+	for _, table := range []string{"posts", "comments", "authors", "fetch_runs"} {
+		assert.Equal(t, 1, count(t, db, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", table), table)
+	}
+}
+
+func TestMigrate_AppliesMissingSteps(t *testing.T) {
+	raw, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer raw.Close()
+	raw.SetMaxOpenConns(1)
+	ctx := t.Context()
+
+	require.NoError(t, migrate(ctx, raw, []string{"CREATE TABLE a (x)"}))
+	// Rerunning is a no-op, and only the new step runs on an upgrade
+	require.NoError(t, migrate(ctx, raw, []string{"CREATE TABLE a (x)"}))
+	require.NoError(t, migrate(ctx, raw, []string{"CREATE TABLE a (x)", "CREATE TABLE b (y)"}))
+
+	var version int
+	require.NoError(t, raw.QueryRow("PRAGMA user_version").Scan(&version))
+	assert.Equal(t, 2, version)
+}
+
+func TestMigrate_FailedStepIsRolledBack(t *testing.T) {
+	raw, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer raw.Close()
+	raw.SetMaxOpenConns(1)
+
+	err = migrate(t.Context(), raw, []string{"CREATE TABLE a (x); CREATE TABLE"})
+	assert.ErrorContains(t, err, "version 1")
+
+	var version, tables int
+	require.NoError(t, raw.QueryRow("PRAGMA user_version").Scan(&version))
+	require.NoError(t, raw.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'a'").Scan(&tables))
+	assert.Equal(t, 0, version)
+	assert.Equal(t, 0, tables)
+}
+
+func TestMigrate_RefusesNewerSchema(t *testing.T) {
+	db := openTestDB(t)
+	_, err := db.SQL().Exec("PRAGMA user_version = 99")
+	require.NoError(t, err)
+
+	err = migrate(t.Context(), db.SQL(), migrations)
+	assert.ErrorContains(t, err, "newer")
+}
+
+func TestArchive_StoresPages(t *testing.T) {
+	db := openTestDB(t)
+	src := &fakeSource{posts: makePosts(250)}
+
+	result, err := db.Archive(t.Context(), src, "golang", Options{})
+	require.NoError(t, err)
+	assert.Equal(t, &Result{RunID: 1, Pages: 3, Posts: 250, Complete: true}, result)
+	assert.Equal(t, []redditclient.ListingOptions{{Limit: DefaultPageSize}}, src.calls)
+
+	assert.Equal(t, 250, count(t, db, "SELECT COUNT(*) FROM posts"))
+	assert.Equal(t, 7, count(t, db, "SELECT COUNT(*) FROM authors"))
+
+	var status, after, sort string
+	var pages, seen int
+	require.NoError(t, db.SQL().QueryRow("SELECT status, after_cursor, sort, pages_fetched, posts_seen FROM fetch_runs WHERE id = 1").
+		Scan(&status, &after, &sort, &pages, &seen))
+	assert.Equal(t, StatusComplete, status)
+	assert.Equal(t, "t3_p249", after)
+	assert.Equal(t, "new", sort)
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, 250, seen)
+}
+
+func TestArchive_RerunUpdatesRows(t *testing.T) {
+	db := openTestDB(t)
+	src := &fakeSource{posts: makePosts(20)}
+
+	_, err := db.Archive(t.Context(), src, "golang", Options{})
+	require.NoError(t, err)
+
+	for i := range src.posts {
+		src.posts[i].Score += 1000
+		src.posts[i].NumComments += 5
+	}
+	result, err := db.Archive(t.Context(), src, "golang", Options{})
+	require.NoError(t, err)
+	assert.False(t, result.Resumed, "a complete run is not resumed")
+	assert.Equal(t, int64(2), result.RunID)
+
+	assert.Equal(t, 20, count(t, db, "SELECT COUNT(*) FROM posts"))
+	var score, comments, firstRun, lastRun int
+	require.NoError(t, db.SQL().QueryRow("SELECT score, num_comments, first_run_id, last_run_id FROM posts WHERE id = 'p003'").
+		Scan(&score, &comments, &firstRun, &lastRun))
+	assert.Equal(t, 1003, score)
+	assert.Equal(t, 8, comments)
+	assert.Equal(t, 1, firstRun)
+	assert.Equal(t, 2, lastRun)
+}
+
+func TestArchive_ResumesInterruptedRun(t *testing.T) {
+	db := openTestDB(t)
+	src := &fakeSource{posts: makePosts(250), failAfter: 150}
+
+	result, err := db.Archive(t.Context(), src, "golang", Options{})
+	require.ErrorIs(t, err, errFetch)
+	assert.Equal(t, &Result{RunID: 1, Pages: 2, Posts: 150}, result, "the partial page before the error is kept")
+
+	var status, after string
+	require.NoError(t, db.SQL().QueryRow("SELECT status, after_cursor FROM fetch_runs WHERE id = 1").Scan(&status, &after))
+	assert.Equal(t, StatusInterrupted, status)
+	assert.Equal(t, "t3_p149", after)
+
+	src.failAfter = 0
+	result, err = db.Archive(t.Context(), src, "golang", Options{})
+	require.NoError(t, err)
+	assert.Equal(t, &Result{RunID: 1, Resumed: true, Pages: 1, Posts: 100, Complete: true}, result)
+	assert.Equal(t, "t3_p149", src.calls[1].After)
+
+	assert.Equal(t, 250, count(t, db, "SELECT COUNT(*) FROM posts"))
+	assert.Equal(t, 1, count(t, db, "SELECT COUNT(*) FROM fetch_runs"))
+	assert.Equal(t, 1, count(t, db, "SELECT COUNT(*) FROM fetch_runs WHERE status = ?", StatusComplete))
+}
+
+func TestArchive_Restart(t *testing.T) {
+	db := openTestDB(t)
+	src := &fakeSource{posts: makePosts(50), failAfter: 10}
+
+	_, err := db.Archive(t.Context(), src, "golang", Options{})
+	require.Error(t, err)
+
+	src.failAfter = 0
+	result, err := db.Archive(t.Context(), src, "golang", Options{Restart: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.RunID)
+	assert.False(t, result.Resumed)
+	assert.Empty(t, src.calls[1].After)
+
+	// The abandoned run is not picked up once a newer run has completed
+	result, err = db.Archive(t.Context(), src, "golang", Options{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.RunID)
+}
+
+func TestArchive_PageCapCountsResumedPages(t *testing.T) {
+	db := openTestDB(t)
+	src := &fakeSource{posts: makePosts(500), failAfter: 100}
+
+	_, err := db.Archive(t.Context(), src, "golang", Options{Pages: 3})
+	require.Error(t, err)
+	assert.Equal(t, 3, src.calls[0].MaxPages)
+
+	src.failAfter = 0
+	result, err := db.Archive(t.Context(), src, "golang", Options{Pages: 3})
+	require.NoError(t, err)
+	assert.Equal(t, 2, src.calls[1].MaxPages)
+	assert.Equal(t, 200, result.Posts)
+	assert.Equal(t, 300, count(t, db, "SELECT COUNT(*) FROM posts"))
+}
+
+func TestArchive_Comments(t *testing.T) {
+	db := openTestDB(t)
+	src := &fakeSource{posts: makePosts(3)}
+
+	result, err := db.Archive(t.Context(), src, "golang", Options{Comments: true})
+	require.NoError(t, err)
+	assert.Equal(t, 6, result.Comments)
+
+	assert.Equal(t, 6, count(t, db, "SELECT COUNT(*) FROM comments"))
+	assert.Equal(t, 2, count(t, db, "SELECT COUNT(*) FROM comments WHERE post_id = 'p001'"))
+	assert.Equal(t, 1, count(t, db, "SELECT COUNT(*) FROM comments WHERE parent_id = 't1_p001c1' AND depth = 1"))
+	assert.Equal(t, 1, count(t, db, "SELECT COUNT(*) FROM authors WHERE name = 'replier'"))
+}
+
+func TestArchive_CancelledRunKeepsProgress(t *testing.T) {
+	db := openTestDB(t)
+	posts := makePosts(250)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	src := &cancellingSource{fakeSource: fakeSource{posts: posts}, cancelAt: 120, cancel: cancel}
+	result, err := db.Archive(ctx, src, "golang", Options{})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 120, result.Posts)
+
+	var status, after string
+	require.NoError(t, db.SQL().QueryRow("SELECT status, after_cursor FROM fetch_runs WHERE id = 1").Scan(&status, &after))
+	assert.Equal(t, StatusInterrupted, status)
+	assert.Equal(t, "t3_p119", after)
+}
+
+// cancellingSource cancels the archive's context after yielding cancelAt
+// posts, then yields the context's error as the client's iterator does
+type cancellingSource struct {
+	fakeSource
+	cancelAt int
+	cancel   context.CancelFunc
+}
+
+func (c *cancellingSource) SubredditPosts(ctx context.Context, subreddit, sort string, opts *redditclient.ListingOptions) iter.Seq2[redditclient.Post, error] {
+	return func(yield func(redditclient.Post, error) bool) {
+		for i, p := range c.posts {
+			if i == c.cancelAt {
+				c.cancel()
+				yield(redditclient.Post{}, ctx.Err())
+				return
+			}
+			if !yield(p, nil) {
+				return
+			}
+		}
+	}
+}
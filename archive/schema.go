@@ -0,0 +1,105 @@
+package archive
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// migrations builds the schema one version at a time. migrations[i] takes a
+// database from version i to i+1, tracked in SQLite's user_version. Released
+// migrations are never edited; schema changes are appended as new ones, so
+// the tables stay stable for the queries people write against them.
+var migrations = []string{
+	// 1: posts, comments, their authors, and the runs that fetched them
+	`CREATE TABLE fetch_runs (
+		id            INTEGER PRIMARY KEY AUTOINCREMENT,
+		subreddit     TEXT NOT NULL,
+		sort          TEXT NOT NULL,
+		status        TEXT NOT NULL, -- running, interrupted or complete
+		after_cursor  TEXT NOT NULL DEFAULT '', -- Fullname to resume the listing after
+		pages_fetched INTEGER NOT NULL DEFAULT 0,
+		posts_seen    INTEGER NOT NULL DEFAULT 0,
+		started_at    TEXT NOT NULL,
+		finished_at   TEXT
+	);
+	CREATE INDEX fetch_runs_listing ON fetch_runs (subreddit, sort, status);
+
+	CREATE TABLE authors (
+		name          TEXT PRIMARY KEY,
+		first_seen_at TEXT NOT NULL,
+		last_seen_at  TEXT NOT NULL
+	);
+
+	CREATE TABLE posts (
+		id            TEXT PRIMARY KEY, -- Base-36 ID without the t3_ prefix
+		subreddit     TEXT NOT NULL,
+		author        TEXT NOT NULL,
+		title         TEXT NOT NULL,
+		selftext      TEXT NOT NULL,
+		url           TEXT NOT NULL,
+		permalink     TEXT NOT NULL,
+		domain        TEXT NOT NULL,
+		flair         TEXT NOT NULL,
+		is_self       INTEGER NOT NULL,
+		over_18       INTEGER NOT NULL,
+		score         INTEGER NOT NULL,
+		upvote_ratio  REAL NOT NULL,
+		num_comments  INTEGER NOT NULL,
+		created_utc   REAL NOT NULL,
+		edited_utc    REAL, -- NULL when never edited
+		first_run_id  INTEGER NOT NULL REFERENCES fetch_runs (id),
+		last_run_id   INTEGER NOT NULL REFERENCES fetch_runs (id),
+		fetched_at    TEXT NOT NULL
+	);
+	CREATE INDEX posts_subreddit_created ON posts (subreddit, created_utc);
+	CREATE INDEX posts_author ON posts (author);
+
+	CREATE TABLE comments (
+		id           TEXT PRIMARY KEY, -- Base-36 ID without the t1_ prefix
+		post_id      TEXT NOT NULL REFERENCES posts (id),
+		parent_id    TEXT NOT NULL, -- Fullname: t3_ for top-level comments, t1_ for replies
+		author       TEXT NOT NULL,
+		body         TEXT NOT NULL,
+		score        INTEGER NOT NULL,
+		depth        INTEGER NOT NULL,
+		created_utc  REAL NOT NULL,
+		last_run_id  INTEGER NOT NULL REFERENCES fetch_runs (id),
+		fetched_at   TEXT NOT NULL
+	);
+	CREATE INDEX comments_post ON comments (post_id);
+	CREATE INDEX comments_author ON comments (author);`,
+}
+
+// migrate brings db up to the last of steps, applying each missing step in
+// its own transaction. A database from a newer version is refused rather
+// than written with an older idea of the schema.
+func migrate(ctx context.Context, db *sql.DB, steps []string) error {
+	var version int
+	if err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version > len(steps) {
+		return fmt.Errorf("database schema version %d is newer than the %d this grapeddit supports", version, len(steps))
+	}
+
+	for ; version < len(steps); version++ {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, steps[version]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to migrate schema to version %d: %w", version+1, err)
+		}
+		// PRAGMA takes no parameters; version is an int
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", version+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record schema version %d: %w", version+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
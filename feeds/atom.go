@@ -0,0 +1,85 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+)
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Author     atomAuthor     `xml:"author"`
+	Links      []atomLink     `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Content    atomContent    `xml:"content"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+func newAtomFeed(posts []*redditclient.Post, opts FeedOptions) *atomFeed {
+	feed := &atomFeed{
+		Title:   opts.Title,
+		ID:      opts.FeedURL,
+		Updated: newest(posts).Format(time.RFC3339),
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: opts.FeedURL},
+			{Rel: "alternate", Type: "text/html", Href: opts.FeedURL},
+		},
+	}
+
+	for _, p := range posts {
+		entry := atomEntry{
+			Title:     p.Title,
+			ID:        permalink(p, opts),
+			Published: p.Created.Time().Format(time.RFC3339),
+			Updated:   updated(p).Format(time.RFC3339),
+			Author:    atomAuthor{Name: "/u/" + p.Author},
+			Links:     []atomLink{{Rel: "alternate", Href: target(p, opts)}},
+			Content:   atomContent{Type: "html", Body: content(p, opts)},
+		}
+		if p.Author != "" && !p.IsDeleted() {
+			entry.Author.URI = opts.BaseURL + "/user/" + p.Author
+		}
+		if !p.IsSelf {
+			entry.Links = append(entry.Links, atomLink{Rel: "replies", Type: "text/html", Href: permalink(p, opts)})
+		}
+		if p.LinkFlairText != "" {
+			entry.Categories = []atomCategory{{Term: p.LinkFlairText}}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return feed
+}
@@ -0,0 +1,180 @@
+// Package feeds turns subreddit listings into RSS 2.0 and Atom 1.0 feeds, so
+// any listing the client can fetch can be followed in a feed reader.
+package feeds
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+)
+
+// Format is a feed format
+type Format string
+
+const (
+	RSS  Format = "rss"  // RSS 2.0
+	Atom Format = "atom" // Atom 1.0, RFC 4287
+)
+
+// LinkTarget is where a link post's entry points
+type LinkTarget int
+
+const (
+	LinkExternal  LinkTarget = iota // The URL the post links to
+	LinkPermalink                   // The post's comments page on Reddit
+)
+
+// DefaultBaseURL is prefixed to permalinks when FeedOptions.BaseURL is empty
+const DefaultBaseURL = "https://www.reddit.com"
+
+// FeedOptions configures FromListing. The zero value makes an RSS feed whose
+// link posts point at the pages they link to.
+type FeedOptions struct {
+	Format Format // Defaults to RSS
+
+	// Title names the feed. It defaults to the subreddit, e.g. "r/golang",
+	// or to "Reddit" when the listing mixes subreddits.
+	Title string
+
+	// LinkPosts says where link posts' entries point. Self posts always
+	// point at their permalink, as that is where their content lives.
+	LinkPosts LinkTarget
+
+	// BaseURL is the site permalinks are resolved against, for feeds read
+	// through a Reddit frontend. Defaults to DefaultBaseURL.
+	BaseURL string
+
+	// FeedURL is the feed's own address, used as its Atom ID and self link.
+	// It defaults to the subreddit's page under BaseURL.
+	FeedURL string
+}
+
+// FromListing renders listing as a feed. Each post becomes an entry with its
+// title, author, permalink, publication time and content: the selftext of
+// self posts, or a link to the page a link post points at.
+func FromListing(listing *redditclient.SubredditListing, opts FeedOptions) ([]byte, error) {
+	if listing == nil {
+		return nil, fmt.Errorf("%w: listing is nil", redditclient.ErrInvalidArgument)
+	}
+	if opts.BaseURL == "" {
+		opts.BaseURL = DefaultBaseURL
+	}
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+
+	posts := make([]*redditclient.Post, len(listing.Data.Children))
+	for i := range listing.Data.Children {
+		posts[i] = &listing.Data.Children[i].Data
+	}
+
+	subreddit := commonSubreddit(posts)
+	if opts.Title == "" {
+		opts.Title = "Reddit"
+		if subreddit != "" {
+			opts.Title = "r/" + subreddit
+		}
+	}
+	if opts.FeedURL == "" {
+		opts.FeedURL = opts.BaseURL + "/"
+		if subreddit != "" {
+			opts.FeedURL = opts.BaseURL + "/r/" + subreddit + "/"
+		}
+	}
+
+	var feed any
+	switch opts.Format {
+	case RSS, "":
+		feed = newRSSFeed(posts, opts)
+	case Atom:
+		feed = newAtomFeed(posts, opts)
+	default:
+		return nil, fmt.Errorf("%w: unknown feed format %q", redditclient.ErrInvalidArgument, opts.Format)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		return nil, fmt.Errorf("failed to encode feed: %w", err)
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// commonSubreddit returns the subreddit every post is in, or "" if they
+// differ or there are none
+func commonSubreddit(posts []*redditclient.Post) string {
+	if len(posts) == 0 {
+		return ""
+	}
+	sub := posts[0].Subreddit
+	for _, p := range posts[1:] {
+		if !strings.EqualFold(p.Subreddit, sub) {
+			return ""
+		}
+	}
+	return sub
+}
+
+// permalink returns p's comments page under opts.BaseURL
+func permalink(p *redditclient.Post, opts FeedOptions) string {
+	return opts.BaseURL + p.Permalink
+}
+
+// target returns the page p's entry links to
+func target(p *redditclient.Post, opts FeedOptions) string {
+	if p.IsSelf || p.URL == "" || opts.LinkPosts == LinkPermalink {
+		return permalink(p, opts)
+	}
+	return p.URL
+}
+
+// content returns p's entry body as HTML
+func content(p *redditclient.Post, opts FeedOptions) string {
+	switch {
+	case p.IsDeleted():
+		return "<p>[deleted]</p>"
+	case p.IsRemoved():
+		return "<p>[removed]</p>"
+	case !p.IsSelf:
+		u := html.EscapeString(p.URL)
+		return fmt.Sprintf(`<p><a href="%s">%s</a></p><p><a href="%s">[comments]</a></p>`,
+			u, u, html.EscapeString(permalink(p, opts)))
+	}
+	if rendered := p.RenderHTML(true); rendered != "" {
+		return rendered
+	}
+
+	var b strings.Builder
+	for _, para := range strings.Split(strings.TrimSpace(p.SelfText), "\n\n") {
+		if para = strings.TrimSpace(para); para != "" {
+			b.WriteString("<p>" + html.EscapeString(para) + "</p>")
+		}
+	}
+	return b.String()
+}
+
+// updated returns when p was last edited, or created if never
+func updated(p *redditclient.Post) time.Time {
+	if p.Edited.At > p.Created {
+		return p.Edited.At.Time()
+	}
+	return p.Created.Time()
+}
+
+// newest returns the latest update time among posts, or the Unix epoch if
+// there are none
+func newest(posts []*redditclient.Post) time.Time {
+	latest := time.Unix(0, 0).UTC()
+	for _, p := range posts {
+		if t := updated(p); t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
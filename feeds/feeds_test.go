@@ -0,0 +1,186 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+	"github.com/Koshroy/grapeddit/redditclient/redditclienttest"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestFromListing_Golden(t *testing.T) {
+	tests := []struct {
+		golden string
+		format Format
+	}{
+		{"golang.rss", RSS},
+		{"golang.atom", Atom},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			got, err := FromListing(redditclienttest.SubredditListing(), FeedOptions{Format: tt.format})
+			require.NoError(t, err)
+
+			path := filepath.Join("testdata", tt.golden)
+			if *update {
+				require.NoError(t, os.WriteFile(path, got, 0o644))
+			}
+			want, err := os.ReadFile(path)
+			require.NoError(t, err, "run go test -update to create the golden file")
+			assert.Equal(t, string(want), string(got))
+		})
+	}
+}
+
+// rssDoc and atomDoc decode feeds independently of the types that encode
+// them, with namespaces spelled out, so a round trip checks the XML itself
+type rssDoc struct {
+	XMLName xml.Name `xml:"rss"`
+	Version string   `xml:"version,attr"`
+	Channel struct {
+		Title string `xml:"title"`
+		Link  string `xml:"link"`
+		Items []struct {
+			Title       string `xml:"title"`
+			Link        string `xml:"link"`
+			GUID        string `xml:"guid"`
+			Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+			PubDate     string `xml:"pubDate"`
+			Description string `xml:"description"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+type atomDoc struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string   `xml:"title"`
+	ID      string   `xml:"id"`
+	Updated string   `xml:"updated"`
+	Entries []struct {
+		Title     string `xml:"title"`
+		ID        string `xml:"id"`
+		Published string `xml:"published"`
+		Author    string `xml:"author>name"`
+		Links     []struct {
+			Rel  string `xml:"rel,attr"`
+			Href string `xml:"href,attr"`
+		} `xml:"link"`
+		Content struct {
+			Type string `xml:"type,attr"`
+			Body string `xml:",chardata"`
+		} `xml:"content"`
+	} `xml:"entry"`
+}
+
+func TestFromListing_RSSRoundTrip(t *testing.T) {
+	listing := redditclienttest.SubredditListing()
+	out, err := FromListing(listing, FeedOptions{})
+	require.NoError(t, err)
+
+	var doc rssDoc
+	require.NoError(t, xml.Unmarshal(out, &doc))
+	assert.Equal(t, "2.0", doc.Version)
+	assert.Equal(t, "r/golang", doc.Channel.Title)
+	assert.Equal(t, "https://www.reddit.com/r/golang/", doc.Channel.Link)
+	require.Len(t, doc.Channel.Items, len(listing.Data.Children))
+
+	for i, item := range doc.Channel.Items {
+		post := listing.Data.Children[i].Data
+		assert.Equal(t, post.Title, item.Title)
+		assert.Equal(t, "https://www.reddit.com"+post.Permalink, item.GUID)
+		assert.Equal(t, "/u/"+post.Author, item.Creator)
+
+		published, err := time.Parse(time.RFC1123Z, item.PubDate)
+		require.NoError(t, err)
+		assert.Equal(t, int64(post.Created), published.Unix())
+	}
+
+	// A self post links its permalink, a link post the page it links to
+	assert.Equal(t, "https://www.reddit.com/r/golang/comments/1c3xk7a/whos_hiring_monthly_thread/", doc.Channel.Items[0].Link)
+	assert.Equal(t, "https://go.dev/blog/range-functions", doc.Channel.Items[1].Link)
+	assert.Equal(t, "<p>Post your openings here, one per top-level comment.</p>", doc.Channel.Items[0].Description)
+}
+
+func TestFromListing_AtomRoundTrip(t *testing.T) {
+	listing := redditclienttest.SubredditListing()
+	out, err := FromListing(listing, FeedOptions{Format: Atom, Title: "Go news", FeedURL: "https://example.com/golang.xml"})
+	require.NoError(t, err)
+
+	var doc atomDoc
+	require.NoError(t, xml.Unmarshal(out, &doc))
+	assert.Equal(t, atomNamespace, doc.XMLName.Space)
+	assert.Equal(t, "Go news", doc.Title)
+	assert.Equal(t, "https://example.com/golang.xml", doc.ID)
+	require.Len(t, doc.Entries, len(listing.Data.Children))
+
+	// The feed was last updated when the link post was edited
+	assert.Equal(t, "2024-04-13T01:00:00Z", doc.Updated)
+
+	for i, entry := range doc.Entries {
+		post := listing.Data.Children[i].Data
+		assert.Equal(t, post.Title, entry.Title)
+		assert.Equal(t, "https://www.reddit.com"+post.Permalink, entry.ID)
+		assert.Equal(t, "/u/"+post.Author, entry.Author)
+		assert.Equal(t, "html", entry.Content.Type)
+
+		published, err := time.Parse(time.RFC3339, entry.Published)
+		require.NoError(t, err)
+		assert.Equal(t, int64(post.Created), published.Unix())
+	}
+
+	linkPost := doc.Entries[1]
+	assert.Equal(t, "https://go.dev/blog/range-functions", linkPost.Links[0].Href)
+	assert.Equal(t, "replies", linkPost.Links[1].Rel)
+	assert.Contains(t, linkPost.Content.Body, `<a href="https://go.dev/blog/range-functions">`)
+}
+
+func TestFromListing_LinkPermalink(t *testing.T) {
+	out, err := FromListing(redditclienttest.SubredditListing(), FeedOptions{
+		LinkPosts: LinkPermalink,
+		BaseURL:   "https://old.reddit.com/",
+	})
+	require.NoError(t, err)
+
+	var doc rssDoc
+	require.NoError(t, xml.Unmarshal(out, &doc))
+	assert.Equal(t, "https://old.reddit.com/r/golang/comments/1c3xk8f/go_122_rangeoverfunc_is_great_for_iterators/", doc.Channel.Items[1].Link)
+}
+
+func TestFromListing_MixedSubreddits(t *testing.T) {
+	listing := redditclienttest.SubredditListing()
+	listing.Data.Children[2].Data.Subreddit = "rust"
+
+	out, err := FromListing(listing, FeedOptions{})
+	require.NoError(t, err)
+
+	var doc rssDoc
+	require.NoError(t, xml.Unmarshal(out, &doc))
+	assert.Equal(t, "Reddit", doc.Channel.Title)
+	assert.Equal(t, "https://www.reddit.com/", doc.Channel.Link)
+}
+
+func TestFromListing_EmptyListing(t *testing.T) {
+	for _, format := range []Format{RSS, Atom} {
+		out, err := FromListing(&redditclient.SubredditListing{}, FeedOptions{Format: format})
+		require.NoError(t, err)
+		assert.NoError(t, xml.Unmarshal(out, new(any)), string(format))
+	}
+}
+
+func TestFromListing_InvalidArguments(t *testing.T) {
+	_, err := FromListing(nil, FeedOptions{})
+	assert.ErrorIs(t, err, redditclient.ErrInvalidArgument)
+
+	_, err = FromListing(redditclienttest.SubredditListing(), FeedOptions{Format: "json"})
+	assert.ErrorIs(t, err, redditclient.ErrInvalidArgument)
+}
@@ -0,0 +1,73 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+)
+
+// dcNamespace is Dublin Core, whose creator element names an author without
+// the email address RSS's own author element requires
+const dcNamespace = "http://purl.org/dc/elements/1.1/"
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	DC      string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	Creator     string   `xml:"dc:creator"`
+	PubDate     string   `xml:"pubDate"`
+	Categories  []string `xml:"category"`
+	Comments    string   `xml:"comments"`
+	Description string   `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func newRSSFeed(posts []*redditclient.Post, opts FeedOptions) *rssFeed {
+	feed := &rssFeed{
+		Version: "2.0",
+		DC:      dcNamespace,
+		Channel: rssChannel{
+			Title:         opts.Title,
+			Link:          opts.FeedURL,
+			Description:   opts.Title,
+			LastBuildDate: newest(posts).Format(time.RFC1123Z),
+		},
+	}
+
+	for _, p := range posts {
+		item := rssItem{
+			Title:       p.Title,
+			Link:        target(p, opts),
+			GUID:        rssGUID{IsPermaLink: true, Value: permalink(p, opts)},
+			Creator:     "/u/" + p.Author,
+			PubDate:     p.Created.Time().Format(time.RFC1123Z),
+			Comments:    permalink(p, opts),
+			Description: content(p, opts),
+		}
+		if p.LinkFlairText != "" {
+			item.Categories = []string{p.LinkFlairText}
+		}
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+	return feed
+}
@@ -0,0 +1,406 @@
+// Package gemini serves a RedditClient as gemtext over the Gemini protocol,
+// for reading Reddit from Gemini clients.
+//
+// Routes:
+//
+//	/                                 index
+//	/r/{subreddit}                    hot listing
+//	/r/{subreddit}/comments/{id}/...  post and comment thread
+//	/r/{subreddit}/more/{id}/{ids}    comments behind a "more" placeholder
+//	/u/{username}                     profile
+//	/search?{query}                   search; asks for input when the query is empty
+//
+// Thread routes accept Reddit permalinks as they are, so the links in
+// listings point at the same paths Reddit uses. Upstream errors are answered
+// with the Gemini status closest to the HTTP status the JSON API would use;
+// see server.StatusCode.
+package gemini
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+	"github.com/Koshroy/grapeddit/render"
+	"github.com/Koshroy/grapeddit/server"
+)
+
+// Gemini status codes used by the server
+const (
+	StatusInput               = 10
+	StatusSuccess             = 20
+	StatusTemporaryFailure    = 40
+	StatusSlowDown            = 44
+	StatusPermanentFailure    = 50
+	StatusNotFound            = 51
+	StatusGone                = 52
+	StatusProxyRequestRefused = 53
+	StatusBadRequest          = 59
+)
+
+const (
+	// maxRequestLength is the longest request URL the spec allows, in bytes
+	maxRequestLength = 1024
+
+	// requestTimeout bounds a whole request, from reading the URL to writing
+	// the last byte of the response
+	requestTimeout = 30 * time.Second
+
+	// maxMoreLinkChildren caps the comment IDs in a "more" link so the URL
+	// stays well under maxRequestLength
+	maxMoreLinkChildren = 50
+)
+
+// validName matches the subreddit, post, comment and user names the server
+// passes to the client. Path segments are decoded, so a "?" or ".." sent
+// escaped would reach Reddit intact without this check.
+var validName = regexp.MustCompile(`^[A-Za-z0-9_+-]+$`)
+
+// Server answers Gemini requests from a RedditClient. It is safe for
+// concurrent use as long as the client is.
+type Server struct {
+	client   redditclient.RedditClient
+	hostname string
+}
+
+// NewServer returns a Server backed by client, which must already be
+// authenticated. When hostname is set, requests for any other host are
+// refused as proxy requests; when empty, any host is accepted.
+func NewServer(client redditclient.RedditClient, hostname string) *Server {
+	return &Server{client: client, hostname: hostname}
+}
+
+// Serve accepts connections on ln until ctx is done, then waits for the
+// requests in flight to finish. ln should be a TLS listener, as Gemini
+// requires TLS; see SelfSignedCertificate. Serve returns nil after a clean
+// shutdown, or the error that stopped it accepting.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	stop := context.AfterFunc(ctx, func() { ln.Close() })
+	defer stop()
+
+	// Requests in flight when ctx ends are allowed to finish
+	reqCtx := context.WithoutCancel(ctx)
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.serveConn(reqCtx, conn)
+		}()
+	}
+}
+
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	conn.SetDeadline(time.Now().Add(requestTimeout))
+
+	w := bufio.NewWriter(conn)
+	defer w.Flush()
+
+	u, status, meta := readRequest(conn)
+	if status != 0 {
+		writeHeader(w, status, meta)
+		return
+	}
+	if u.Scheme != "gemini" || (s.hostname != "" && !strings.EqualFold(u.Hostname(), s.hostname)) {
+		writeHeader(w, StatusProxyRequestRefused, "proxy requests are refused")
+		return
+	}
+	s.serve(ctx, w, u)
+}
+
+// readRequest reads the request line: an absolute URL followed by CRLF. A
+// non-zero status reports a malformed request.
+func readRequest(r io.Reader) (*url.URL, int, string) {
+	// The URL, CR and LF, plus one byte to tell an overlong line apart
+	br := bufio.NewReaderSize(io.LimitReader(r, maxRequestLength+3), maxRequestLength+3)
+	line, err := br.ReadString('\n')
+	if err != nil {
+		if len(line) > maxRequestLength+1 {
+			return nil, StatusBadRequest, "request is longer than 1024 bytes"
+		}
+		return nil, StatusBadRequest, "request must end with CRLF"
+	}
+	line, ok := strings.CutSuffix(line, "\r\n")
+	if !ok {
+		return nil, StatusBadRequest, "request must end with CRLF"
+	}
+	if len(line) > maxRequestLength {
+		return nil, StatusBadRequest, "request is longer than 1024 bytes"
+	}
+
+	u, err := url.Parse(line)
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return nil, StatusBadRequest, "request must be an absolute URL"
+	}
+	if u.User != nil || u.Fragment != "" {
+		return nil, StatusBadRequest, "request URL must not have userinfo or a fragment"
+	}
+	return u, 0, ""
+}
+
+// serve routes a request by its path segments
+func (s *Server) serve(ctx context.Context, w io.Writer, u *url.URL) {
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch {
+	case u.Path == "" || u.Path == "/":
+		s.index(w)
+	case len(segments) == 2 && segments[0] == "r":
+		s.subreddit(ctx, w, segments[1])
+	case len(segments) >= 4 && len(segments) <= 5 && segments[0] == "r" && segments[2] == "comments":
+		s.thread(ctx, w, segments[1], segments[3])
+	case len(segments) == 5 && segments[0] == "r" && segments[2] == "more":
+		s.more(ctx, w, segments[1], segments[3], strings.Split(segments[4], ","))
+	case len(segments) == 2 && (segments[0] == "u" || segments[0] == "user"):
+		s.user(ctx, w, segments[1])
+	case len(segments) == 1 && segments[0] == "search":
+		s.search(ctx, w, u.RawQuery)
+	default:
+		writeHeader(w, StatusNotFound, "no such page")
+	}
+}
+
+func (s *Server) index(w io.Writer) {
+	respond(w, func(w io.Writer) error {
+		_, err := fmt.Fprint(w, "# grapeddit\n\n"+
+			"Reddit, read over Gemini. Open /r/{subreddit} for a subreddit, or any Reddit permalink path for its thread.\n\n"+
+			"=> /search Search Reddit\n")
+		return err
+	})
+}
+
+func (s *Server) subreddit(ctx context.Context, w io.Writer, subreddit string) {
+	if !checkNames(w, subreddit) {
+		return
+	}
+	listing, err := s.client.GetSubredditListing(ctx, subreddit, redditclient.SortHot, nil)
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	posts := make([]redditclient.Post, len(listing.Data.Children))
+	for i, child := range listing.Data.Children {
+		posts[i] = child.Data
+	}
+	respond(w, func(w io.Writer) error {
+		return render.Gemtext{}.Posts(w, "r/"+subreddit, posts)
+	})
+}
+
+func (s *Server) thread(ctx context.Context, w io.Writer, subreddit, id string) {
+	if !checkNames(w, subreddit, id) {
+		return
+	}
+	thread, err := s.client.GetCommentsWithOptions(ctx, subreddit, id, nil)
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	post, err := thread.Post()
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	respond(w, func(w io.Writer) error {
+		return s.gemtext(subreddit, id).Thread(w, post, &thread.CommentListing)
+	})
+}
+
+func (s *Server) more(ctx context.Context, w io.Writer, subreddit, id string, children []string) {
+	if len(children) > maxMoreLinkChildren || slices.Contains(children, "") {
+		writeHeader(w, StatusBadRequest, "malformed comment IDs")
+		return
+	}
+	if !checkNames(w, append([]string{subreddit, id}, children...)...) {
+		return
+	}
+	more, err := s.client.GetMoreComments(ctx, redditclient.KindPost+"_"+id, children)
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	comments := commentTree(more.JSON.Data.Things)
+	respond(w, func(w io.Writer) error {
+		if err := s.gemtext(subreddit, id).Comments(w, "More comments", comments); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(w, "\n=> /r/%s/comments/%s/ Back to the thread\n", subreddit, id)
+		return err
+	})
+}
+
+func (s *Server) user(ctx context.Context, w io.Writer, username string) {
+	if !checkNames(w, username) {
+		return
+	}
+	user, err := s.client.GetUser(ctx, username)
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	respond(w, func(w io.Writer) error {
+		return render.Gemtext{}.User(w, &user.Data)
+	})
+}
+
+func (s *Server) search(ctx context.Context, w io.Writer, rawQuery string) {
+	query, err := url.QueryUnescape(rawQuery)
+	if err != nil {
+		writeHeader(w, StatusBadRequest, "malformed query")
+		return
+	}
+	if strings.TrimSpace(query) == "" {
+		writeHeader(w, StatusInput, "Search Reddit")
+		return
+	}
+
+	results, err := s.client.SearchWithOptions(ctx, query, &redditclient.SearchOptions{})
+	if err != nil {
+		fail(w, err)
+		return
+	}
+	respond(w, func(w io.Writer) error {
+		return render.Gemtext{}.Posts(w, "Search: "+query, results.Posts)
+	})
+}
+
+// checkNames reports whether every name is valid, answering the request with
+// a bad request status if not
+func checkNames(w io.Writer, names ...string) bool {
+	for _, name := range names {
+		if !validName.MatchString(name) {
+			writeHeader(w, StatusBadRequest, fmt.Sprintf("malformed name %q", name))
+			return false
+		}
+	}
+	return true
+}
+
+// gemtext returns a renderer whose "more" links load the comments of the
+// post id in subreddit. Placeholders without children are left unlinked, and
+// larger ones link only their first maxMoreLinkChildren comments.
+func (s *Server) gemtext(subreddit, id string) render.Gemtext {
+	return render.Gemtext{
+		MoreLink: func(more *redditclient.MoreComments) string {
+			if len(more.Children) == 0 {
+				return ""
+			}
+			children := more.Children[:min(len(more.Children), maxMoreLinkChildren)]
+			return fmt.Sprintf("/r/%s/more/%s/%s", subreddit, id, strings.Join(children, ","))
+		},
+	}
+}
+
+// commentTree nests the flat comments returned by GetMoreComments under
+// their parents. Comments whose parent is not among them are the roots.
+func commentTree(things []redditclient.CommentChild) *redditclient.CommentListing {
+	byName := make(map[string]*redditclient.Comment)
+	for _, thing := range things {
+		if thing.Comment != nil {
+			byName[redditclient.KindComment+"_"+thing.Comment.ID] = thing.Comment
+		}
+	}
+
+	roots := &redditclient.CommentListing{Kind: redditclient.KindListing}
+	for _, thing := range things {
+		var parentID string
+		switch {
+		case thing.Comment != nil:
+			parentID = thing.Comment.ParentID
+		case thing.More != nil:
+			parentID = thing.More.ParentID
+		default:
+			continue
+		}
+
+		parent, ok := byName[parentID]
+		if !ok {
+			roots.Data.Children = append(roots.Data.Children, thing)
+			continue
+		}
+		if parent.Replies == nil {
+			parent.Replies = &redditclient.CommentListing{Kind: redditclient.KindListing}
+		}
+		parent.Replies.Data.Children = append(parent.Replies.Data.Children, thing)
+	}
+	return roots
+}
+
+// respond renders a page and writes it with a success header. The page is
+// rendered in full first, so a rendering error can still be reported.
+func respond(w io.Writer, page func(w io.Writer) error) {
+	var body bytes.Buffer
+	if err := page(&body); err != nil {
+		fail(w, err)
+		return
+	}
+	writeHeader(w, StatusSuccess, "text/gemini; charset=utf-8")
+	w.Write(body.Bytes())
+}
+
+// fail writes the failure header err maps to
+func fail(w io.Writer, err error) {
+	status, meta := Status(err)
+	writeHeader(w, status, meta)
+}
+
+// Status maps an error from the client to a Gemini status and meta line
+func Status(err error) (int, string) {
+	meta := err.Error()
+	switch server.StatusCode(err) {
+	case http.StatusBadRequest:
+		return StatusBadRequest, meta
+	case http.StatusNotFound:
+		return StatusNotFound, meta
+	case http.StatusGone:
+		return StatusGone, meta
+	case http.StatusForbidden:
+		return StatusPermanentFailure, meta
+	case http.StatusTooManyRequests:
+		// The meta of a slow down response is the seconds to wait
+		var rateErr *redditclient.RateLimitError
+		seconds := 1
+		if errors.As(err, &rateErr) && rateErr.RetryAfter > 0 {
+			seconds = int(math.Ceil(rateErr.RetryAfter.Seconds()))
+		}
+		return StatusSlowDown, fmt.Sprint(seconds)
+	default:
+		return StatusTemporaryFailure, meta
+	}
+}
+
+// writeHeader writes a response header. meta is flattened to one line and
+// cut to the 1024 bytes the spec allows.
+func writeHeader(w io.Writer, status int, meta string) {
+	meta = strings.Join(strings.Fields(meta), " ")
+	if len(meta) > maxRequestLength {
+		meta = strings.ToValidUTF8(meta[:maxRequestLength], "")
+	}
+	fmt.Fprintf(w, "%d %s\r\n", status, meta)
+}
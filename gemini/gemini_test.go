@@ -0,0 +1,219 @@
+package gemini
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+	"github.com/Koshroy/grapeddit/redditclient/redditclienttest"
+)
+
+// startServer serves fake over TLS on a loopback port until the test ends,
+// and returns the address
+func startServer(t *testing.T, fake *redditclienttest.FakeClient, hostname string) string {
+	t.Helper()
+
+	cert, err := SelfSignedCertificate("127.0.0.1")
+	require.NoError(t, err)
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", TLSConfig(cert))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- NewServer(fake, hostname).Serve(ctx, ln) }()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+	})
+	return ln.Addr().String()
+}
+
+// request sends line, unterminated, over a raw TLS connection and returns the
+// response header line and body
+func request(t *testing.T, addr, line string) (string, string) {
+	t.Helper()
+
+	conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+
+	_, err = io.WriteString(conn, line)
+	require.NoError(t, err)
+	resp, err := io.ReadAll(conn)
+	require.NoError(t, err)
+
+	header, body, ok := strings.Cut(string(resp), "\r\n")
+	require.True(t, ok, "response has no CRLF-terminated header: %q", resp)
+	return header, body
+}
+
+func TestServer_Listing(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+	addr := startServer(t, fake, "")
+
+	header, body := request(t, addr, "gemini://127.0.0.1/r/golang\r\n")
+	assert.Equal(t, "20 text/gemini; charset=utf-8", header)
+	assert.True(t, strings.HasPrefix(body, "# r/golang\n"), body)
+	assert.Contains(t, body, "\n=> /r/golang/comments/1c3xk9p/how_do_you_structure_a_large_go_monorepo/ How do you structure a large Go monorepo?\n```\n")
+
+	calls := fake.CallsTo("GetSubredditListing")
+	require.Len(t, calls, 1)
+	assert.Equal(t, "golang", calls[0].Args[0])
+	assert.Equal(t, redditclient.SortHot, calls[0].Args[1])
+}
+
+func TestServer_Thread(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+	addr := startServer(t, fake, "")
+
+	// A permalink path, as linked from listings
+	header, body := request(t, addr, "gemini://127.0.0.1/r/golang/comments/1c3xk9p/how_do_you_structure_a_large_go_monorepo/\r\n")
+	assert.Equal(t, "20 text/gemini; charset=utf-8", header)
+	assert.True(t, strings.HasPrefix(body, "# How do you structure a large Go monorepo?\n"), body)
+	assert.Contains(t, body, "\n    u/busy_builder · 12 points · ")
+	assert.Contains(t, body, "\n=> /r/golang/more/1c3xk9p/kz1a005,kz1a006     2 more comments\n")
+
+	assert.Equal(t, []any{"golang", "1c3xk9p", (*redditclient.CommentOptions)(nil)}, fake.CallsTo("GetCommentsWithOptions")[0].Args)
+}
+
+func TestServer_MoreComments(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+	fake.MoreCommentsFn = func(ctx context.Context, linkID string, children []string) (*redditclient.MoreChildrenResponse, error) {
+		var resp redditclient.MoreChildrenResponse
+		resp.JSON.Data.Things = []redditclient.CommentChild{
+			{Kind: "t1", Comment: &redditclient.Comment{ID: "kz1a005", ParentID: "t1_kz1a001", Author: "first", Body: "Top of this batch"}},
+			{Kind: "t1", Comment: &redditclient.Comment{ID: "kz1a009", ParentID: "t1_kz1a005", Author: "second", Body: "A reply to it"}},
+			{Kind: "t1", Comment: &redditclient.Comment{ID: "kz1a006", ParentID: "t1_kz1a001", Author: "third", Body: "Another sibling"}},
+		}
+		return &resp, nil
+	}
+	addr := startServer(t, fake, "")
+
+	header, body := request(t, addr, "gemini://127.0.0.1/r/golang/more/1c3xk9p/kz1a005,kz1a006\r\n")
+	assert.Equal(t, "20 text/gemini; charset=utf-8", header)
+	assert.Regexp(t, `(?s)u/first .*\nTop of this batch\n\n    u/second .*\n    A reply to it\n\nu/third .*\nAnother sibling\n`, body)
+	assert.Contains(t, body, "=> /r/golang/comments/1c3xk9p/ Back to the thread\n")
+
+	assert.Equal(t, []any{"t3_1c3xk9p", []string{"kz1a005", "kz1a006"}}, fake.CallsTo("GetMoreComments")[0].Args)
+}
+
+func TestServer_User(t *testing.T) {
+	addr := startServer(t, redditclienttest.NewFakeClient(), "")
+
+	header, body := request(t, addr, "gemini://127.0.0.1/u/gopher_jane\r\n")
+	assert.Equal(t, "20 text/gemini; charset=utf-8", header)
+	assert.True(t, strings.HasPrefix(body, "# u/gopher_jane\n"), body)
+}
+
+func TestServer_Search(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+	addr := startServer(t, fake, "")
+
+	header, body := request(t, addr, "gemini://127.0.0.1/search\r\n")
+	assert.Equal(t, "10 Search Reddit", header)
+	assert.Empty(t, body)
+	assert.Empty(t, fake.Calls())
+
+	header, body = request(t, addr, "gemini://127.0.0.1/search?range%20over%20func\r\n")
+	assert.Equal(t, "20 text/gemini; charset=utf-8", header)
+	assert.True(t, strings.HasPrefix(body, "# Search: range over func\n"), body)
+	assert.Equal(t, "range over func", fake.CallsTo("SearchWithOptions")[0].Args[0])
+}
+
+func TestServer_StatusCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		err    error
+		header string
+	}{
+		{"unknown page", "gemini://127.0.0.1/nope\r\n", nil, "51 no such page"},
+		{"missing subreddit", "gemini://127.0.0.1/r/nosuchsub\r\n", &redditclient.APIError{StatusCode: 404, Endpoint: "/r/nosuchsub"}, "51 "},
+		{"banned", "gemini://127.0.0.1/r/banned\r\n", redditclient.ErrSubredditBanned, "51 "},
+		{"suspended", "gemini://127.0.0.1/u/spammer\r\n", redditclient.ErrUserSuspended, "52 "},
+		{"private", "gemini://127.0.0.1/r/secret\r\n", fmt.Errorf("r/secret: %w", redditclient.ErrSubredditPrivate), "50 r/secret: "},
+		{"rate limited", "gemini://127.0.0.1/r/golang\r\n", &redditclient.RateLimitError{
+			APIError:   &redditclient.APIError{StatusCode: 429, Endpoint: "/r/golang"},
+			RetryAfter: 1500 * time.Millisecond,
+		}, "44 2"},
+		{"upstream failure", "gemini://127.0.0.1/r/golang\r\n", &redditclient.APIError{StatusCode: 503, Endpoint: "/r/golang"}, "40 "},
+		{"no CRLF", "gemini://127.0.0.1/r/golang\n", nil, "59 request must end with CRLF"},
+		{"relative URL", "/r/golang\r\n", nil, "59 request must be an absolute URL"},
+		{"too long", "gemini://127.0.0.1/" + strings.Repeat("a", 1024) + "\r\n", nil, "59 request is longer than 1024 bytes"},
+		{"userinfo", "gemini://me@127.0.0.1/\r\n", nil, "59 request URL must not have userinfo or a fragment"},
+		{"other scheme", "https://127.0.0.1/r/golang\r\n", nil, "53 proxy requests are refused"},
+		{"escaped query in a name", "gemini://127.0.0.1/r/golang%3F/comments/abc\r\n", nil, "59 malformed name"},
+		{"dot segment", "gemini://127.0.0.1/r/golang/comments/..%3F\r\n", nil, "59 malformed name"},
+		{"escaped slash in a user", "gemini://127.0.0.1/u/x%2F..%2F..%2Fapi%2Fv1%2Fme%3F\r\n", nil, "51 no such page"},
+		{"escaped query in a user", "gemini://127.0.0.1/u/me%3Fx\r\n", nil, "59 malformed name"},
+		{"bad comment ID", "gemini://127.0.0.1/r/golang/more/abc/x,y%3F\r\n", nil, "59 malformed name"},
+		{"too many more IDs", "gemini://127.0.0.1/r/golang/more/abc/" + strings.Repeat("x,", 50) + "x\r\n", nil, "59 malformed comment IDs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := redditclienttest.NewFakeClient()
+			fake.Err = tt.err
+			addr := startServer(t, fake, "")
+
+			header, body := request(t, addr, tt.line)
+			assert.True(t, strings.HasPrefix(header, tt.header), "header %q, want prefix %q", header, tt.header)
+			assert.Empty(t, body)
+		})
+	}
+}
+
+func TestServer_Hostname(t *testing.T) {
+	addr := startServer(t, redditclienttest.NewFakeClient(), "gemini.example.org")
+
+	header, _ := request(t, addr, "gemini://gemini.example.org:1965/\r\n")
+	assert.Equal(t, "20 text/gemini; charset=utf-8", header)
+
+	header, _ = request(t, addr, "gemini://elsewhere.example.org/\r\n")
+	assert.Equal(t, "53 proxy requests are refused", header)
+}
+
+func TestServer_ShutdownStopsAccepting(t *testing.T) {
+	cert, err := SelfSignedCertificate()
+	require.NoError(t, err)
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", TLSConfig(cert))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- NewServer(redditclienttest.NewFakeClient(), "").Serve(ctx, ln) }()
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after its context ended")
+	}
+	_, err = net.Dial("tcp", ln.Addr().String())
+	assert.Error(t, err)
+}
+
+func TestSelfSignedCertificate(t *testing.T) {
+	cert, err := SelfSignedCertificate("gemini.example.org", "127.0.0.1")
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, "gemini.example.org", leaf.Subject.CommonName)
+	assert.NoError(t, leaf.VerifyHostname("gemini.example.org"))
+	assert.NoError(t, leaf.VerifyHostname("127.0.0.1"))
+	assert.Error(t, leaf.VerifyHostname("other.example.org"))
+	assert.True(t, leaf.NotAfter.After(time.Now().AddDate(1, 0, 0)))
+}
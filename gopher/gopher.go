@@ -0,0 +1,325 @@
+// Package gopher serves a RedditClient over the Gopher protocol (RFC 1436),
+// for reading Reddit from Gopher clients.
+//
+// Selectors:
+//
+//	(empty) or /        root menu
+//	/r/{subreddit}      menu of the hot listing, one text item per post
+//	/r/{subreddit}/{id} the post and its comment tree as a text file
+//	/search             search; a type 7 item whose query is the search
+//
+// Menus list each post as a text item followed by an info line with its
+// score, author and comment count. Errors are answered with a menu holding a
+// single error item.
+package gopher
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+	"github.com/Koshroy/grapeddit/render"
+)
+
+// Item types used in menus
+const (
+	TypeText   = '0'
+	TypeError  = '3'
+	TypeSearch = '7'
+	TypeInfo   = 'i'
+)
+
+const (
+	// maxRequestLength bounds the request line: a selector of up to 255
+	// bytes, plus a search query
+	maxRequestLength = 1024
+
+	// requestTimeout bounds a whole request, from reading the selector to
+	// writing the last byte of the response
+	requestTimeout = 30 * time.Second
+)
+
+// validName matches the subreddit and post names a selector may carry
+var validName = regexp.MustCompile(`^[A-Za-z0-9_+-]+$`)
+
+// Server answers Gopher requests from a RedditClient. It is safe for
+// concurrent use as long as the client is.
+type Server struct {
+	client redditclient.RedditClient
+	host   string
+	port   int
+}
+
+// NewServer returns a Server backed by client, which must already be
+// authenticated. host and port are where clients reach the server, and are
+// written into every menu item that points back at it.
+func NewServer(client redditclient.RedditClient, host string, port int) *Server {
+	return &Server{client: client, host: host, port: port}
+}
+
+// Serve accepts connections on ln until ctx is done, then waits for the
+// requests in flight to finish. Serve returns nil after a clean shutdown,
+// or the error that stopped it accepting.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	stop := context.AfterFunc(ctx, func() { ln.Close() })
+	defer stop()
+
+	// Requests in flight when ctx ends are allowed to finish
+	reqCtx := context.WithoutCancel(ctx)
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.serveConn(reqCtx, conn)
+		}()
+	}
+}
+
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	conn.SetDeadline(time.Now().Add(requestTimeout))
+
+	w := bufio.NewWriter(conn)
+	defer w.Flush()
+
+	selector, query, err := readRequest(conn)
+	if err != nil {
+		w.Write(s.errorMenu(err.Error()))
+		w.Flush()
+		discardRequest(conn)
+		return
+	}
+	w.Write(s.serve(ctx, selector, query))
+}
+
+// discardRequest reads and drops the rest of a rejected request once the
+// response is sent, so closing the connection does not reset it before the
+// client has read the response
+func discardRequest(conn net.Conn) {
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		tcp.CloseWrite()
+	}
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	io.Copy(io.Discard, io.LimitReader(conn, 64<<10))
+}
+
+// readRequest reads the request line: a selector, optionally followed by a
+// tab and a search query, ended by CRLF. Clients that end it with a bare LF,
+// or that add Gopher+ fields after another tab, are tolerated.
+func readRequest(r io.Reader) (selector, query string, err error) {
+	br := bufio.NewReaderSize(io.LimitReader(r, maxRequestLength+1), maxRequestLength+1)
+	line, err := br.ReadString('\n')
+	if err != nil {
+		if len(line) > maxRequestLength {
+			return "", "", fmt.Errorf("request is longer than %d bytes", maxRequestLength)
+		}
+		return "", "", fmt.Errorf("request must end with CRLF")
+	}
+	line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+
+	fields := strings.Split(line, "\t")
+	selector = fields[0]
+	if len(fields) > 1 {
+		query = fields[1]
+	}
+	return selector, query, nil
+}
+
+// serve routes a request by its selector's path segments and returns the
+// response
+func (s *Server) serve(ctx context.Context, selector, query string) []byte {
+	segments := strings.Split(strings.Trim(selector, "/"), "/")
+	switch {
+	case selector == "" || selector == "/":
+		return s.root()
+	case len(segments) == 2 && segments[0] == "r":
+		return s.subreddit(ctx, segments[1])
+	case len(segments) == 3 && segments[0] == "r":
+		return s.thread(ctx, segments[1], segments[2])
+	case len(segments) == 1 && segments[0] == "search":
+		return s.search(ctx, query)
+	default:
+		return s.errorMenu("no such selector: " + selector)
+	}
+}
+
+func (s *Server) root() []byte {
+	m := s.newMenu()
+	m.info("grapeddit: Reddit over Gopher")
+	m.info("")
+	m.info("Open the selector /r/{subreddit} for a subreddit.")
+	m.item(TypeSearch, "Search Reddit", "/search")
+	return m.bytes()
+}
+
+func (s *Server) subreddit(ctx context.Context, subreddit string) []byte {
+	if err := checkNames(subreddit); err != nil {
+		return s.errorMenu(err.Error())
+	}
+	listing, err := s.client.GetSubredditListing(ctx, subreddit, redditclient.SortHot, nil)
+	if err != nil {
+		return s.errorMenu(err.Error())
+	}
+
+	m := s.newMenu()
+	m.info("r/" + subreddit)
+	for _, child := range listing.Data.Children {
+		m.post(&child.Data)
+	}
+	return m.bytes()
+}
+
+func (s *Server) thread(ctx context.Context, subreddit, id string) []byte {
+	if err := checkNames(subreddit, id); err != nil {
+		return s.errorMenu(err.Error())
+	}
+	thread, err := s.client.GetCommentsWithOptions(ctx, subreddit, id, nil)
+	if err != nil {
+		return s.errorMenu(err.Error())
+	}
+	post, err := thread.Post()
+	if err != nil {
+		return s.errorMenu(err.Error())
+	}
+
+	var text bytes.Buffer
+	if err := (render.Text{}).Thread(&text, post, &thread.CommentListing); err != nil {
+		return s.errorMenu(err.Error())
+	}
+	return textFile(text.Bytes())
+}
+
+// checkNames returns an error for the first name that is not a valid Reddit
+// name. Selectors are passed through raw, so "..", "?" and "#" would
+// otherwise reach the client as part of its endpoint.
+func checkNames(names ...string) error {
+	for _, name := range names {
+		if !validName.MatchString(name) {
+			return fmt.Errorf("malformed name %q", name)
+		}
+	}
+	return nil
+}
+
+func (s *Server) search(ctx context.Context, query string) []byte {
+	if strings.TrimSpace(query) == "" {
+		return s.errorMenu("search needs a query")
+	}
+	results, err := s.client.SearchWithOptions(ctx, query, &redditclient.SearchOptions{})
+	if err != nil {
+		return s.errorMenu(err.Error())
+	}
+
+	m := s.newMenu()
+	m.info("Search: " + query)
+	if len(results.Posts) == 0 {
+		m.info("")
+		m.info("No posts found.")
+	}
+	for i := range results.Posts {
+		m.post(&results.Posts[i])
+	}
+	return m.bytes()
+}
+
+func (s *Server) errorMenu(message string) []byte {
+	m := s.newMenu()
+	m.line(TypeError, message, "", "error.host", 1)
+	return m.bytes()
+}
+
+// menu builds a menu whose items point back at the server
+type menu struct {
+	buf  bytes.Buffer
+	host string
+	port int
+}
+
+func (s *Server) newMenu() *menu {
+	return &menu{host: s.host, port: s.port}
+}
+
+// post adds a text item for a post's thread, and an info line with its
+// metadata
+func (m *menu) post(p *redditclient.Post) {
+	m.info("")
+	m.item(TypeText, p.Title, fmt.Sprintf("/r/%s/%s", p.Subreddit, p.ID))
+	m.info(render.Text{}.Meta(p))
+}
+
+// item adds an item pointing at selector on the server
+func (m *menu) item(itemType byte, display, selector string) {
+	m.line(itemType, display, selector, m.host, m.port)
+}
+
+// info adds an informational line
+func (m *menu) info(text string) {
+	m.line(TypeInfo, text, "", "error.host", 1)
+}
+
+// line adds a menu line. Tabs and line breaks would corrupt the menu, so
+// they are replaced by spaces in the display string and selector.
+func (m *menu) line(itemType byte, display, selector, host string, port int) {
+	m.buf.WriteByte(itemType)
+	m.buf.WriteString(menuField(display))
+	m.buf.WriteByte('\t')
+	m.buf.WriteString(menuField(selector))
+	m.buf.WriteByte('\t')
+	m.buf.WriteString(host)
+	m.buf.WriteByte('\t')
+	m.buf.WriteString(strconv.Itoa(port))
+	m.buf.WriteString("\r\n")
+}
+
+// bytes returns the menu with its terminating line
+func (m *menu) bytes() []byte {
+	m.buf.WriteString(".\r\n")
+	return m.buf.Bytes()
+}
+
+var menuFieldReplacer = strings.NewReplacer("\t", " ", "\r\n", " ", "\r", " ", "\n", " ")
+
+func menuField(s string) string {
+	return menuFieldReplacer.Replace(s)
+}
+
+// textFile encodes text as a Gopher text file: CRLF line endings, lines
+// starting with a period doubled so none reads as the end, and a lone
+// period as the last line
+func textFile(text []byte) []byte {
+	var buf bytes.Buffer
+	lines := strings.Split(strings.TrimSuffix(string(text), "\n"), "\n")
+	for _, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.HasPrefix(line, ".") {
+			buf.WriteByte('.')
+		}
+		buf.WriteString(line)
+		buf.WriteString("\r\n")
+	}
+	buf.WriteString(".\r\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,206 @@
+package gopher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+	"github.com/Koshroy/grapeddit/redditclient/redditclienttest"
+)
+
+// startServer serves fake on a loopback port until the test ends, and
+// returns the address
+func startServer(t *testing.T, fake *redditclienttest.FakeClient) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- NewServer(fake, "gopher.example.org", 7070).Serve(ctx, ln) }()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+	})
+	return ln.Addr().String()
+}
+
+// request sends line over a raw TCP connection and returns the response
+func request(t *testing.T, addr, line string) string {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+
+	_, err = io.WriteString(conn, line)
+	require.NoError(t, err)
+	resp, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	return string(resp)
+}
+
+// menuItem is one decoded menu line
+type menuItem struct {
+	Type     byte
+	Display  string
+	Selector string
+	Host     string
+	Port     string
+}
+
+// parseMenu decodes a menu, checking its framing: CRLF line endings, four
+// tab-separated fields per line, and a lone period as the last line
+func parseMenu(t *testing.T, resp string) []menuItem {
+	t.Helper()
+
+	body, ok := strings.CutSuffix(resp, "\r\n.\r\n")
+	require.True(t, ok, "menu does not end with a lone period: %q", resp)
+
+	var items []menuItem
+	for _, line := range strings.Split(body, "\r\n") {
+		require.NotContains(t, line, "\n", "bare line feed in menu line %q", line)
+		fields := strings.Split(line, "\t")
+		require.Len(t, fields, 4, "menu line %q", line)
+		items = append(items, menuItem{
+			Type:     line[0],
+			Display:  fields[0][1:],
+			Selector: fields[1],
+			Host:     fields[2],
+			Port:     fields[3],
+		})
+	}
+	return items
+}
+
+func TestServer_Root(t *testing.T) {
+	addr := startServer(t, redditclienttest.NewFakeClient())
+
+	items := parseMenu(t, request(t, addr, "\r\n"))
+	search := items[len(items)-1]
+	assert.Equal(t, menuItem{TypeSearch, "Search Reddit", "/search", "gopher.example.org", "7070"}, search)
+	for _, item := range items[:len(items)-1] {
+		assert.Equal(t, byte(TypeInfo), item.Type)
+	}
+}
+
+func TestServer_SubredditMenu(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+	addr := startServer(t, fake)
+
+	items := parseMenu(t, request(t, addr, "/r/golang\r\n"))
+	assert.Equal(t, menuItem{TypeInfo, "r/golang", "", "error.host", "1"}, items[0])
+
+	var posts []menuItem
+	for i, item := range items {
+		if item.Type != TypeText {
+			continue
+		}
+		posts = append(posts, item)
+
+		// Each post is followed by an info line with its metadata
+		require.Less(t, i+1, len(items))
+		meta := items[i+1]
+		assert.Equal(t, byte(TypeInfo), meta.Type)
+		assert.Regexp(t, `^r/golang · u/\w+ · \d+ points · \d+ comments · `, meta.Display)
+	}
+	assert.Equal(t, []menuItem{
+		{TypeText, "Who's hiring? Monthly thread", "/r/golang/1c3xk7a", "gopher.example.org", "7070"},
+		{TypeText, "Go 1.22 range-over-func is great for iterators", "/r/golang/1c3xk8f", "gopher.example.org", "7070"},
+		{TypeText, "How do you structure a large Go monorepo?", "/r/golang/1c3xk9p", "gopher.example.org", "7070"},
+	}, posts)
+
+	calls := fake.CallsTo("GetSubredditListing")
+	require.Len(t, calls, 1)
+	assert.Equal(t, "golang", calls[0].Args[0])
+	assert.Equal(t, redditclient.SortHot, calls[0].Args[1])
+}
+
+func TestServer_ThreadText(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+	addr := startServer(t, fake)
+
+	resp := request(t, addr, "/r/golang/1c3xk9p\r\n")
+	body, ok := strings.CutSuffix(resp, "\r\n.\r\n")
+	require.True(t, ok, "text file does not end with a lone period: %q", resp)
+	assert.True(t, strings.HasPrefix(body, "How do you structure a large Go monorepo?\r\nr/golang · u/busy_builder · "), body)
+	assert.Contains(t, body, "\r\n    u/busy_builder · 12 points · ")
+	assert.NotContains(t, strings.ReplaceAll(body, "\r\n", ""), "\n", "text file has bare line feeds")
+
+	assert.Equal(t, []any{"golang", "1c3xk9p", (*redditclient.CommentOptions)(nil)}, fake.CallsTo("GetCommentsWithOptions")[0].Args)
+}
+
+func TestServer_Search(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+	addr := startServer(t, fake)
+
+	items := parseMenu(t, request(t, addr, "/search\trange over func\r\n"))
+	assert.Equal(t, "Search: range over func", items[0].Display)
+	assert.Equal(t, "/r/golang/1c3xk7a", items[2].Selector)
+	assert.Equal(t, "range over func", fake.CallsTo("SearchWithOptions")[0].Args[0])
+}
+
+func TestServer_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		err     error
+		message string
+	}{
+		{"unknown selector", "/nope\r\n", nil, "no such selector: /nope"},
+		{"empty search", "/search\r\n", nil, "search needs a query"},
+		{"missing subreddit", "/r/nosuchsub\r\n", fmt.Errorf("r/nosuchsub: %w", redditclient.ErrNotFound), "r/nosuchsub: not found"},
+		{"dot segment", "/r/..\r\n", nil, `malformed name ".."`},
+		{"query in a subreddit", "/r/golang?x=1\r\n", nil, `malformed name "golang?x=1"`},
+		{"fragment in a post", "/r/golang/abc#x\r\n", nil, `malformed name "abc#x"`},
+		{"escaped slash", "/r/x%2F..%2F..%2Fapi%2Fv1%2Fme/abc\r\n", nil, `malformed name "x%2F..%2F..%2Fapi%2Fv1%2Fme"`},
+		{"no line end", "/r/golang", nil, "request must end with CRLF"},
+		{"too long", "/" + strings.Repeat("a", 1024) + "\r\n", nil, "request is longer than 1024 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := redditclienttest.NewFakeClient()
+			fake.Err = tt.err
+			addr := startServer(t, fake)
+
+			conn, err := net.Dial("tcp", addr)
+			require.NoError(t, err)
+			defer conn.Close()
+			require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+			_, err = io.WriteString(conn, tt.line)
+			require.NoError(t, err)
+			if !strings.HasSuffix(tt.line, "\n") {
+				require.NoError(t, conn.(*net.TCPConn).CloseWrite())
+			}
+			resp, err := io.ReadAll(conn)
+			require.NoError(t, err)
+
+			items := parseMenu(t, string(resp))
+			require.Len(t, items, 1)
+			assert.Equal(t, byte(TypeError), items[0].Type)
+			assert.Equal(t, tt.message, items[0].Display)
+		})
+	}
+}
+
+func TestMenu_EscapesFields(t *testing.T) {
+	m := (&Server{host: "localhost", port: 70}).newMenu()
+	m.item(TypeText, "a\ttitle\r\nacross lines", "/r/x/1")
+	assert.Equal(t, "0a title across lines\t/r/x/1\tlocalhost\t70\r\n.\r\n", string(m.bytes()))
+}
+
+func TestTextFile(t *testing.T) {
+	got := textFile([]byte("first\n.dotted\n\nlast\n"))
+	assert.Equal(t, "first\r\n..dotted\r\n\r\nlast\r\n.\r\n", string(got))
+}
@@ -1,39 +1,800 @@
+// Command grapeddit reads Reddit from the command line, authenticating as the
+// Android app so no API key is needed.
+//
+// Usage:
+//
+//	grapeddit sub <name> [--sort hot] [--time day] [--limit 25]
+//	grapeddit post <subreddit> <id>
+//	grapeddit comments <url|id> [--sort top] [--limit 0]
+//	grapeddit user <name>
+//	grapeddit search <query> [--sort relevance] [--time all] [--sub name] [--limit 25]
+//	grapeddit serve [--listen :8080] [--cache-ttl 1m]
+//	grapeddit gemini [--cert cert.pem --key key.pem] [--listen :1965] [--hostname host]
+//	grapeddit gopher [--listen :7070] [--hostname localhost] [--port 0]
+//	grapeddit watch <subreddit> [--keyword re] [--min-score 0] [--flair f] [--author a] [--interval 30s] [--webhook url] [--exec cmd] [--state file]
+//	grapeddit archive <subreddit> [--sort new] [--pages 0] [--db grapeddit.db] [--comments] [--restart]
+//	grapeddit feed <subreddit> [--sort hot] [--limit 25] [--format rss|atom] [--links external]
+//
+// Listings print one post per line as tab-separated score, ID, subreddit and
+// title, so the output can be piped into cut, awk or sort. The global
+// --format flag picks another output: "json" prints newline-delimited JSON
+// records and "json-array" a single JSON array, for piping into jq, and
+// "markdown" renders posts and comment threads for glow or notes. --json and
+// --json-array are short for the JSON formats. Global flags may come before
+// or after the command name. The feed command instead takes --format rss or
+// --format atom.
 package main
 
 import (
+	"cmp"
 	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
 
-	"github.com/Koshroy/grapeddit/internal/redditclient"
+	"github.com/Koshroy/grapeddit/archive"
+	"github.com/Koshroy/grapeddit/feeds"
+	"github.com/Koshroy/grapeddit/gemini"
+	"github.com/Koshroy/grapeddit/gopher"
+	"github.com/Koshroy/grapeddit/redditclient"
+	"github.com/Koshroy/grapeddit/render"
+	"github.com/Koshroy/grapeddit/server"
 )
 
 func main() {
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	client, err := redditclient.NewClient(nil)
+	app := &cli{stdout: os.Stdout, stderr: os.Stderr, connect: connect}
+	os.Exit(app.run(ctx, os.Args[1:]))
+}
+
+// connect creates a client with opts and authenticates it anonymously
+func connect(ctx context.Context, opts ...redditclient.Option) (redditclient.RedditClient, error) {
+	client, err := redditclient.NewClient(opts...)
 	if err != nil {
-		log.Fatalf("Failed to create Reddit client: %v", err)
+		return nil, fmt.Errorf("failed to create Reddit client: %w", err)
 	}
-
-	fmt.Println("Authenticating with Reddit...")
 	if err := client.Authenticate(ctx); err != nil {
-		log.Fatalf("Authentication failed: %v", err)
+		return nil, fmt.Errorf("authentication failed: %w", err)
 	}
+	return client, nil
+}
 
-	fmt.Println("Successfully authenticated!")
+// Exit codes
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
 
-	// Example: Get posts from r/golang
-	posts, err := client.GetSubreddit(ctx, "golang", "hot")
-	if err != nil {
-		log.Fatalf("Failed to get subreddit: %v", err)
+// cli runs subcommands against the client returned by connect
+type cli struct {
+	stdout  io.Writer
+	stderr  io.Writer
+	connect func(ctx context.Context, opts ...redditclient.Option) (redditclient.RedditClient, error)
+
+	format string           // Output format, set by the global flags
+	now    func() time.Time // Clock that ages are measured from; nil means time.Now
+}
+
+func (app *cli) clock() time.Time {
+	if app.now == nil {
+		return time.Now()
+	}
+	return app.now()
+}
+
+// Output formats
+const (
+	formatText      = "text"
+	formatMarkdown  = "markdown"
+	formatJSON      = "json"
+	formatJSONArray = "json-array"
+	formatRSS       = "rss"
+	formatAtom      = "atom"
+	formatGemtext   = "gemtext"
+)
+
+// outputFormats are the formats of commands that do not list their own
+var outputFormats = []string{formatText, formatMarkdown, formatJSON, formatJSONArray}
+
+// globalFlags lists the flags every command accepts, and whether each takes
+// a value, so run can move them from before the command name to after it
+var globalFlags = map[string]bool{"format": true, "json": false, "json-array": false}
+
+// bindGlobalFlags registers the global flags on a command's flag set. The
+// first of formats is the default.
+func (app *cli) bindGlobalFlags(fs *flag.FlagSet, formats []string) {
+	app.format = formats[0]
+	setFormat := func(s string) error {
+		if !slices.Contains(formats, s) {
+			return fmt.Errorf("unknown format %q", s)
+		}
+		app.format = s
+		return nil
+	}
+
+	fs.Func("format", "output format: "+strings.Join(formats, ", ")+" (default "+formats[0]+")", setFormat)
+	fs.BoolFunc("json", "short for --format json", func(string) error { return setFormat(formatJSON) })
+	fs.BoolFunc("json-array", "short for --format json-array", func(string) error { return setFormat(formatJSONArray) })
+}
+
+// jsonOutput reports whether one of the JSON formats was asked for
+func (app *cli) jsonOutput() bool {
+	return app.format == formatJSON || app.format == formatJSONArray
+}
+
+// globalFlagArgs returns how many of the leading args are a global flag and
+// its value, or 0 if args does not start with one
+func globalFlagArgs(args []string) int {
+	if len(args) == 0 || !strings.HasPrefix(args[0], "-") {
+		return 0
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(args[0], "-"), "-")
+	name, _, hasValue := strings.Cut(name, "=")
+	takesValue, ok := globalFlags[name]
+	switch {
+	case !ok:
+		return 0
+	case takesValue && !hasValue && len(args) > 1:
+		return 2
+	}
+	return 1
+}
+
+// command is one grapeddit subcommand
+type command struct {
+	name    string
+	args    string // Synopsis of the arguments, for usage messages
+	summary string
+	run     func(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error
+	formats []string // Output formats it supports; nil means outputFormats
+}
+
+var commands = []command{
+	{name: "sub", args: "<name> [--sort hot] [--time day] [--limit 25]", summary: "list a subreddit's posts", run: runSub},
+	{name: "post", args: "<subreddit> <id>", summary: "show a post", run: runPost},
+	{name: "comments", args: "<url|id> [--sort top] [--limit 0]", summary: "show a post's comment tree", run: runComments},
+	{name: "user", args: "<name>", summary: "show a user's profile", run: runUser},
+	{name: "search", args: "<query> [--sort relevance] [--time all] [--sub name] [--limit 25]", summary: "search posts", run: runSearch},
+	{name: "serve", args: "[--listen :8080] [--cache-ttl 1m]", summary: "serve subreddits, threads, users and search as a JSON HTTP API", run: runServe, formats: []string{formatJSON}},
+	{name: "gemini", args: "[--cert cert.pem --key key.pem] [--listen :1965] [--hostname host]", summary: "serve subreddits, threads, users and search as gemtext over Gemini", run: runGemini, formats: []string{formatGemtext}},
+	{name: "gopher", args: "[--listen :7070] [--hostname localhost] [--port 0]", summary: "serve subreddits, threads and search as Gopher menus", run: runGopher, formats: []string{formatText}},
+	{name: "watch", args: "<subreddit> [--keyword re] [--min-score 0] [--flair f] [--author a] [--interval 30s] [--webhook url] [--exec cmd] [--state file]", summary: "report new posts matching filters, on stdout, to a webhook or to a command", run: runWatch, formats: []string{formatText, formatJSON}},
+	{name: "archive", args: "<subreddit> [--sort new] [--pages 0] [--db grapeddit.db] [--comments] [--restart]", summary: "store a subreddit's posts, and optionally comments, in a SQLite database", run: runArchive, formats: []string{formatText}},
+	{name: "feed", args: "<subreddit> [--sort hot] [--limit 25] [--format rss|atom] [--links external]", summary: "print a subreddit as an RSS or Atom feed", run: runFeed, formats: []string{formatRSS, formatAtom}},
+}
+
+// usageError is a mistake in the command line, reported with the usage text
+type usageError string
+
+func (e usageError) Error() string { return string(e) }
+
+// run executes the subcommand named by args[0] and returns the exit code
+func (app *cli) run(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		app.usage(app.stderr)
+		return exitUsage
+	}
+
+	var globals []string
+	for n := globalFlagArgs(args); n > 0; n = globalFlagArgs(args) {
+		globals = append(globals, args[:n]...)
+		args = args[n:]
+	}
+	if len(args) == 0 {
+		app.usage(app.stderr)
+		return exitUsage
+	}
+
+	name := args[0]
+	if name == "-h" || name == "-help" || name == "--help" || name == "help" {
+		app.usage(app.stdout)
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+
+		fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+		fs.SetOutput(app.stderr)
+		formats := cmd.formats
+		if formats == nil {
+			formats = outputFormats
+		}
+		app.bindGlobalFlags(fs, formats)
+		fs.Usage = func() {
+			fmt.Fprintf(fs.Output(), "usage: grapeddit %s %s\n", cmd.name, cmd.args)
+			fs.PrintDefaults()
+		}
+
+		err := cmd.run(ctx, app, fs, append(args[1:], globals...))
+		var usageErr usageError
+		switch {
+		case err == nil:
+			return exitOK
+		case errors.Is(err, flag.ErrHelp):
+			return exitOK
+		case errors.As(err, &usageErr):
+			fmt.Fprintf(app.stderr, "grapeddit %s: %s\n", cmd.name, usageErr)
+			fs.Usage()
+			return exitUsage
+		case isFlagError(err):
+			// The flag package has already printed the error and usage
+			return exitUsage
+		default:
+			fmt.Fprintf(app.stderr, "grapeddit %s: %v\n", cmd.name, err)
+			return exitError
+		}
+	}
+
+	fmt.Fprintf(app.stderr, "grapeddit: unknown command %q\n", name)
+	app.usage(app.stderr)
+	return exitUsage
+}
+
+// usage prints the list of subcommands
+func (app *cli) usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: grapeddit [--format text|markdown|json|json-array] <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-9s %s\n", cmd.name, cmd.summary)
 	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "grapeddit <command> --help" for a command's flags.`)
+}
+
+// flagError marks errors returned by FlagSet.Parse
+type flagError struct{ err error }
+
+func (e flagError) Error() string { return e.err.Error() }
+func (e flagError) Unwrap() error { return e.err }
 
-	fmt.Printf("Found %d posts in r/golang\n", len(posts.Data.Children))
-	for i, post := range posts.Data.Children {
-		if i >= 5 { // Show only first 5 posts
+func isFlagError(err error) bool {
+	var fe flagError
+	return errors.As(err, &fe)
+}
+
+// parseArgs parses fs from args, allowing flags before, between and after the
+// positional arguments, and checks that there are between min and max of
+// them. max < 0 means no upper limit.
+func parseArgs(fs *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, flagError{err}
+		}
+		args = fs.Args()
+		if len(args) == 0 {
 			break
 		}
-		fmt.Printf("- %s (Score: %d)\n", post.Data.Title, post.Data.Score)
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	switch {
+	case len(positional) < min:
+		return nil, usageError("missing arguments")
+	case max >= 0 && len(positional) > max:
+		return nil, usageError("too many arguments")
+	}
+	return positional, nil
+}
+
+func runSub(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	sort := fs.String("sort", "hot", "sort order: hot, new, top, rising, controversial or best")
+	timeframe := fs.String("time", "", "time range for top and controversial sorts: hour, day, week, month, year or all")
+	limit := fs.Int("limit", 25, "number of posts to fetch")
+	pos, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := app.connect(ctx)
+	if err != nil {
+		return err
+	}
+	opts := &redditclient.ListingOptions{Limit: *limit}
+	var listing *redditclient.SubredditListing
+	switch redditclient.Sort(*sort) {
+	case redditclient.SortTop:
+		listing, err = client.GetSubredditTop(ctx, pos[0], redditclient.Timeframe(*timeframe), opts)
+	case redditclient.SortControversial:
+		listing, err = client.GetSubredditControversial(ctx, pos[0], redditclient.Timeframe(*timeframe), opts)
+	default:
+		if *timeframe != "" {
+			return usageError("--time only applies to the top and controversial sorts")
+		}
+		listing, err = client.GetSubredditListing(ctx, pos[0], redditclient.Sort(*sort), opts)
+	}
+	if err != nil {
+		return err
+	}
+
+	if app.jsonOutput() {
+		records := make([]postRecord, len(listing.Data.Children))
+		for i := range listing.Data.Children {
+			records[i] = newPostRecord(&listing.Data.Children[i].Data)
+		}
+		return writeRecords(app.stdout, app.format == formatJSONArray, records)
+	}
+	if app.format == formatMarkdown {
+		posts := make([]redditclient.Post, len(listing.Data.Children))
+		for i, child := range listing.Data.Children {
+			posts[i] = child.Data
+		}
+		return render.Markdown{}.Posts(app.stdout, posts)
+	}
+	for _, child := range listing.Data.Children {
+		printPostLine(app.stdout, &child.Data)
+	}
+	return nil
+}
+
+func runPost(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	pos, err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	client, err := app.connect(ctx)
+	if err != nil {
+		return err
+	}
+	thread, err := client.GetCommentsWithOptions(ctx, pos[0], strings.TrimPrefix(pos[1], redditclient.KindPost+"_"), &redditclient.CommentOptions{Limit: 1})
+	if err != nil {
+		return err
+	}
+	post, err := thread.Post()
+	if err != nil {
+		return err
+	}
+
+	if app.jsonOutput() {
+		return writeRecords(app.stdout, app.format == formatJSONArray, []postRecord{newPostRecord(post)})
+	}
+	if app.format == formatMarkdown {
+		return render.Markdown{}.Post(app.stdout, post)
+	}
+	printPost(app.stdout, post, app.clock())
+	return nil
+}
+
+func runComments(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	sort := fs.String("sort", "", "sort order: confidence, top, new, controversial, old, qa or random")
+	limit := fs.Int("limit", 0, "maximum number of comments; 0 uses Reddit's default")
+	pos, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := app.connect(ctx)
+	if err != nil {
+		return err
+	}
+	subreddit, postID, err := locatePost(ctx, client, pos[0])
+	if err != nil {
+		return err
+	}
+	thread, err := client.GetCommentsWithOptions(ctx, subreddit, postID, &redditclient.CommentOptions{
+		Sort:  redditclient.CommentSort(*sort),
+		Limit: *limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	post, err := thread.Post()
+	if err != nil {
+		return err
+	}
+	if app.jsonOutput() {
+		return writeRecords(app.stdout, app.format == formatJSONArray, []threadRecord{newThreadRecord(post, &thread.CommentListing)})
+	}
+	if app.format == formatMarkdown {
+		return render.Markdown{}.Thread(app.stdout, post, &thread.CommentListing)
+	}
+	printPostLine(app.stdout, post)
+	redditclient.WalkComments(&thread.CommentListing, func(c *redditclient.Comment, depth int) bool {
+		body, _, _ := strings.Cut(c.Body, "\n")
+		fmt.Fprintf(app.stdout, "%s%d\tu/%s\t%s\n", strings.Repeat("  ", depth), c.Score, c.Author, body)
+		return true
+	})
+	return nil
+}
+
+// locatePost returns the subreddit and ID of the post a URL or bare ID refers to
+func locatePost(ctx context.Context, client redditclient.RedditClient, arg string) (subreddit, postID string, err error) {
+	if strings.Contains(arg, "/") {
+		ref, err := client.ResolveURL(ctx, arg)
+		if err != nil {
+			return "", "", err
+		}
+		if ref.Subreddit != "" {
+			return ref.Subreddit, ref.PostID, nil
+		}
+		arg = ref.PostID
+	}
+
+	postID = strings.TrimPrefix(arg, redditclient.KindPost+"_")
+	posts, err := client.GetPostsByID(ctx, []string{redditclient.KindPost + "_" + postID})
+	if err != nil {
+		return "", "", err
+	}
+	if len(posts) == 0 {
+		return "", "", fmt.Errorf("post %s: %w", postID, redditclient.ErrNotFound)
+	}
+	return posts[0].Subreddit, postID, nil
+}
+
+func runUser(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	pos, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	client, err := app.connect(ctx)
+	if err != nil {
+		return err
+	}
+	user, err := client.GetUser(ctx, strings.TrimPrefix(pos[0], "u/"))
+	if err != nil {
+		return err
+	}
+
+	u := user.Data
+	if app.jsonOutput() {
+		return writeRecords(app.stdout, app.format == formatJSONArray, []userRecord{newUserRecord(&u)})
+	}
+	if app.format == formatMarkdown {
+		return render.Markdown{}.User(app.stdout, &u)
+	}
+	fmt.Fprintf(app.stdout, "u/%s\n", u.Name)
+	fmt.Fprintf(app.stdout, "link karma:    %d\n", u.LinkKarma)
+	fmt.Fprintf(app.stdout, "comment karma: %d\n", u.CommentKarma)
+	fmt.Fprintf(app.stdout, "created:       %s\n", formatTime(u.Created, app.clock()))
+	return nil
+}
+
+func runSearch(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	sort := fs.String("sort", "", "sort order: relevance, hot, top, new or comments")
+	timeframe := fs.String("time", "", "time range for top and comments sorts: hour, day, week, month, year or all")
+	subreddit := fs.String("sub", "", "only search this subreddit")
+	limit := fs.Int("limit", 25, "number of results to fetch")
+	pos, err := parseArgs(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+
+	client, err := app.connect(ctx)
+	if err != nil {
+		return err
+	}
+	results, err := client.SearchWithOptions(ctx, strings.Join(pos, " "), &redditclient.SearchOptions{
+		ListingOptions: redditclient.ListingOptions{Limit: *limit},
+		Subreddit:      *subreddit,
+		Sort:           redditclient.SearchSort(*sort),
+		Timeframe:      redditclient.Timeframe(*timeframe),
+	})
+	if err != nil {
+		return err
+	}
+
+	if app.jsonOutput() {
+		records := make([]postRecord, len(results.Posts))
+		for i := range results.Posts {
+			records[i] = newPostRecord(&results.Posts[i])
+		}
+		return writeRecords(app.stdout, app.format == formatJSONArray, records)
+	}
+	if app.format == formatMarkdown {
+		return render.Markdown{}.Posts(app.stdout, results.Posts)
+	}
+	for i := range results.Posts {
+		printPostLine(app.stdout, &results.Posts[i])
+	}
+	return nil
+}
+
+func runFeed(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	sort := fs.String("sort", "hot", "sort order: hot, new, top, rising, controversial or best")
+	limit := fs.Int("limit", 25, "number of posts to include")
+	links := fs.String("links", "external", "where link posts' entries point: external or permalink")
+	pos, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	opts := feeds.FeedOptions{Format: feeds.Format(app.format)}
+	switch *links {
+	case "external":
+		opts.LinkPosts = feeds.LinkExternal
+	case "permalink":
+		opts.LinkPosts = feeds.LinkPermalink
+	default:
+		return usageError(fmt.Sprintf("unknown --links value %q", *links))
+	}
+
+	client, err := app.connect(ctx)
+	if err != nil {
+		return err
+	}
+	subreddit := strings.TrimPrefix(strings.TrimPrefix(pos[0], "/"), "r/")
+	listing, err := client.GetSubredditListing(ctx, subreddit, redditclient.Sort(*sort), &redditclient.ListingOptions{Limit: *limit})
+	if err != nil {
+		return err
+	}
+
+	feed, err := feeds.FromListing(listing, opts)
+	if err != nil {
+		return err
+	}
+	_, err = app.stdout.Write(feed)
+	return err
+}
+
+// shutdownTimeout bounds how long serve waits for requests in flight when
+// interrupted
+const shutdownTimeout = 10 * time.Second
+
+func runServe(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	listen := fs.String("listen", ":8080", "address to listen on")
+	cache := bindCacheFlags(fs)
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if err := cache.validate(); err != nil {
+		return err
+	}
+
+	client, err := cache.connect(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Handler:           server.NewHandler(client, *cache.ttl),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Fprintf(app.stderr, "grapeddit: serving on http://%s\n", ln.Addr())
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	fmt.Fprintln(app.stderr, "grapeddit: shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
+
+func runGemini(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	listen := fs.String("listen", ":1965", "address to listen on")
+	certFile := fs.String("cert", "", "PEM certificate file; a self-signed certificate is generated when unset")
+	keyFile := fs.String("key", "", "PEM private key file for --cert")
+	hostname := fs.String("hostname", "", "host name to answer for; other hosts are refused")
+	cache := bindCacheFlags(fs)
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if err := cache.validate(); err != nil {
+		return err
+	}
+	if (*certFile == "") != (*keyFile == "") {
+		return usageError("--cert and --key must be given together")
+	}
+
+	var cert tls.Certificate
+	var err error
+	if *certFile != "" {
+		cert, err = tls.LoadX509KeyPair(*certFile, *keyFile)
+	} else {
+		host := cmp.Or(*hostname, "localhost")
+		fmt.Fprintf(app.stderr, "grapeddit: no --cert given, using a self-signed certificate for %s that changes on every start\n", host)
+		cert, err = gemini.SelfSignedCertificate(host)
+	}
+	if err != nil {
+		return err
+	}
+
+	client, err := cache.connect(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	ln, err := tls.Listen("tcp", *listen, gemini.TLSConfig(cert))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(app.stderr, "grapeddit: serving on gemini://%s\n", ln.Addr())
+
+	err = gemini.NewServer(client, *hostname).Serve(ctx, ln)
+	if ctx.Err() != nil {
+		fmt.Fprintln(app.stderr, "grapeddit: shut down")
+	}
+	return err
+}
+
+func runGopher(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	listen := fs.String("listen", ":7070", "address to listen on")
+	hostname := fs.String("hostname", "localhost", "host name clients reach the server at, written into menus")
+	port := fs.Int("port", 0, "port clients reach the server at, written into menus; 0 means the listening port")
+	cache := bindCacheFlags(fs)
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if err := cache.validate(); err != nil {
+		return err
+	}
+
+	client, err := cache.connect(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return err
+	}
+	if *port == 0 {
+		*port = ln.Addr().(*net.TCPAddr).Port
+	}
+	fmt.Fprintf(app.stderr, "grapeddit: serving on gopher://%s, menus point at %s:%d\n", ln.Addr(), *hostname, *port)
+
+	err = gopher.NewServer(client, *hostname, *port).Serve(ctx, ln)
+	if ctx.Err() != nil {
+		fmt.Fprintln(app.stderr, "grapeddit: shut down")
+	}
+	return err
+}
+
+func runArchive(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	sort := fs.String("sort", "new", "sort order: hot, new, top, rising, controversial or best")
+	pages := fs.Int("pages", 0, "pages to fetch, counting those of a resumed run; 0 means all")
+	pageSize := fs.Int("limit", archive.DefaultPageSize, "posts per page, at most 100")
+	dbPath := fs.String("db", "grapeddit.db", "SQLite database file, created if missing")
+	comments := fs.Bool("comments", false, "also store each post's full comment tree")
+	restart := fs.Bool("restart", false, "start over instead of resuming an unfinished run")
+	pos, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if *pages < 0 || *pageSize <= 0 || *pageSize > 100 {
+		return usageError("--pages must not be negative and --limit must be between 1 and 100")
+	}
+	subreddit := strings.TrimPrefix(pos[0], "r/")
+
+	client, err := app.connect(ctx, redditclient.WithRateLimitBehavior(redditclient.WaitOnLimit))
+	if err != nil {
+		return err
+	}
+	src, ok := client.(archive.Source)
+	if !ok {
+		return errors.New("client cannot page through listings")
+	}
+
+	db, err := archive.Open(ctx, *dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	result, err := db.Archive(ctx, src, subreddit, archive.Options{
+		Sort:     *sort,
+		Pages:    *pages,
+		PageSize: *pageSize,
+		Comments: *comments,
+		Restart:  *restart,
+	})
+	if result != nil {
+		verb := "archived"
+		if result.Resumed {
+			verb = "resumed run and archived"
+		}
+		fmt.Fprintf(app.stdout, "run %d: %s %d posts and %d comments from r/%s in %d pages\n",
+			result.RunID, verb, result.Posts, result.Comments, subreddit, result.Pages)
+		if err != nil {
+			fmt.Fprintf(app.stderr, "grapeddit: run %d interrupted; run the same command again to resume\n", result.RunID)
+		}
+	}
+	return err
+}
+
+// cacheFlags are the response cache flags of the server commands
+type cacheFlags struct {
+	ttl  *time.Duration
+	size *int
+}
+
+func bindCacheFlags(fs *flag.FlagSet) cacheFlags {
+	return cacheFlags{
+		ttl:  fs.Duration("cache-ttl", time.Minute, "how long Reddit responses are cached and may be cached by consumers"),
+		size: fs.Int("cache-size", 1000, "maximum number of cached responses"),
+	}
+}
+
+func (f cacheFlags) validate() error {
+	if *f.ttl <= 0 || *f.size <= 0 {
+		return usageError("--cache-ttl and --cache-size must be positive")
+	}
+	return nil
+}
+
+// connect connects the client a server command shares between every
+// consumer, so they share its identity, cache and rate limit; waiting out the
+// limit beats failing requests
+func (f cacheFlags) connect(ctx context.Context, app *cli) (redditclient.RedditClient, error) {
+	return app.connect(ctx,
+		redditclient.WithCache(redditclient.NewMemoryCache(*f.size), *f.ttl),
+		redditclient.WithRequestCoalescing(),
+		redditclient.WithRateLimitBehavior(redditclient.WaitOnLimit),
+	)
+}
+
+// printPostLine prints a post as one tab-separated line
+func printPostLine(w io.Writer, p *redditclient.Post) {
+	fmt.Fprintf(w, "%d\t%s\tr/%s\t%s\n", p.Score, p.ID, p.Subreddit, p.Title)
+}
+
+// printPost prints a post's details and text, with its age at now
+func printPost(w io.Writer, p *redditclient.Post, now time.Time) {
+	fmt.Fprintln(w, p.Title)
+	fmt.Fprintf(w, "r/%s · u/%s · %d points · %d comments · %s\n",
+		p.Subreddit, p.Author, p.Score, p.NumComments, formatTime(p.Created, now))
+	fmt.Fprintln(w, p.URL)
+	if p.SelfText != "" {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, p.SelfText)
+	}
+}
+
+// formatTime formats a Reddit timestamp with its age at now, e.g.
+// "2024-04-13 00:00:00 (3h ago)"
+func formatTime(ts redditclient.Timestamp, now time.Time) string {
+	return fmt.Sprintf("%s (%s ago)", ts.Time().Format(time.DateTime), formatAge(ts.Age(now)))
+}
+
+// formatAge formats d in its largest whole unit, e.g. "45s", "3h" or "2d".
+// A negative d, from clock skew, is "0s".
+func formatAge(d time.Duration) string {
+	const day = 24 * time.Hour
+
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", max(d/time.Second, 0))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	case d < day:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d < 365*day:
+		return fmt.Sprintf("%dd", d/day)
+	default:
+		return fmt.Sprintf("%dy", d/(365*day))
 	}
 }
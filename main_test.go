@@ -0,0 +1,547 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"iter"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Koshroy/grapeddit/archive"
+	"github.com/Koshroy/grapeddit/redditclient"
+	"github.com/Koshroy/grapeddit/redditclient/redditclienttest"
+)
+
+// cliNow is the clock runCLI gives the CLI
+var cliNow = time.Date(2024, 4, 13, 3, 0, 0, 0, time.UTC)
+
+// runCLI runs grapeddit with args against fake and returns the exit code and
+// what was written to stdout and stderr
+func runCLI(t *testing.T, fake *redditclienttest.FakeClient, args ...string) (int, string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	app := &cli{
+		stdout: &stdout,
+		stderr: &stderr,
+		connect: func(ctx context.Context, opts ...redditclient.Option) (redditclient.RedditClient, error) {
+			return fake, nil
+		},
+		now: func() time.Time { return cliNow },
+	}
+	code := app.run(t.Context(), args)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestCLI_Sub(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+
+	code, stdout, stderr := runCLI(t, fake, "sub", "golang", "--sort", "new", "--limit", "3")
+	require.Equal(t, exitOK, code, stderr)
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(strings.SplitN(lines[0], "\t", 2)[1], "1c3xk7a\tr/golang\t"), lines[0])
+
+	calls := fake.CallsTo("GetSubredditListing")
+	require.Len(t, calls, 1)
+	assert.Equal(t, []any{"golang", redditclient.SortNew, &redditclient.ListingOptions{Limit: 3}}, calls[0].Args)
+}
+
+func TestCLI_SubTime(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+
+	code, _, stderr := runCLI(t, fake, "sub", "golang", "--sort", "top", "--time", "all")
+	require.Equal(t, exitOK, code, stderr)
+
+	calls := fake.CallsTo("GetSubredditTop")
+	require.Len(t, calls, 1)
+	assert.Equal(t, []any{"golang", redditclient.TimeframeAll, &redditclient.ListingOptions{Limit: 25}}, calls[0].Args)
+
+	code, _, stderr = runCLI(t, fake, "sub", "golang", "--sort", "new", "--time", "all")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "--time")
+}
+
+func TestCLI_Post(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+
+	code, stdout, stderr := runCLI(t, fake, "post", "golang", "t3_abc123")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stdout, "r/golang")
+	assert.Contains(t, stdout, "2024-04-13 00:00:00 (3h ago)")
+
+	calls := fake.CallsTo("GetCommentsWithOptions")
+	require.Len(t, calls, 1)
+	assert.Equal(t, "abc123", calls[0].Args[1])
+}
+
+func TestCLI_Comments(t *testing.T) {
+	t.Run("URL", func(t *testing.T) {
+		fake := redditclienttest.NewFakeClient()
+
+		code, stdout, stderr := runCLI(t, fake, "comments", "--sort", "top", "https://www.reddit.com/r/golang/comments/abc123/title/")
+		require.Equal(t, exitOK, code, stderr)
+		assert.NotEmpty(t, stdout)
+		assert.Empty(t, fake.CallsTo("GetPostsByID"))
+
+		calls := fake.CallsTo("GetCommentsWithOptions")
+		require.Len(t, calls, 1)
+		assert.Equal(t, []any{"golang", "abc123", &redditclient.CommentOptions{Sort: redditclient.CommentSortTop}}, calls[0].Args)
+	})
+
+	t.Run("ID", func(t *testing.T) {
+		fake := redditclienttest.NewFakeClient()
+
+		code, _, stderr := runCLI(t, fake, "comments", "1c3xk8f")
+		require.Equal(t, exitOK, code, stderr)
+
+		calls := fake.CallsTo("GetCommentsWithOptions")
+		require.Len(t, calls, 1)
+		assert.Equal(t, "golang", calls[0].Args[0])
+		assert.Equal(t, "1c3xk8f", calls[0].Args[1])
+	})
+
+	t.Run("unknown ID", func(t *testing.T) {
+		code, _, stderr := runCLI(t, redditclienttest.NewFakeClient(), "comments", "zzzzzz")
+		assert.Equal(t, exitError, code)
+		assert.Contains(t, stderr, "zzzzzz")
+	})
+}
+
+func TestCLI_User(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+
+	code, stdout, stderr := runCLI(t, fake, "user", "u/spez")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stdout, "link karma:")
+	assert.Contains(t, stdout, "created:       2015-01-01 00:00:00 (9y ago)")
+
+	calls := fake.CallsTo("GetUser")
+	require.Len(t, calls, 1)
+	assert.Equal(t, []any{"spez"}, calls[0].Args)
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{-time.Minute, "0s"},
+		{45 * time.Second, "45s"},
+		{90 * time.Second, "1m"},
+		{3*time.Hour + 59*time.Minute, "3h"},
+		{49 * time.Hour, "2d"},
+		{364 * 24 * time.Hour, "364d"},
+		{800 * 24 * time.Hour, "2y"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, formatAge(tt.d), tt.d.String())
+	}
+}
+
+func TestCLI_Search(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+
+	code, stdout, stderr := runCLI(t, fake, "search", "generics", "--sort", "top", "--time", "week", "--sub", "golang", "errors")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Len(t, strings.Split(strings.TrimSpace(stdout), "\n"), 3)
+
+	calls := fake.CallsTo("SearchWithOptions")
+	require.Len(t, calls, 1)
+	assert.Equal(t, []any{"generics errors", &redditclient.SearchOptions{
+		ListingOptions: redditclient.ListingOptions{Limit: 25},
+		Subreddit:      "golang",
+		Sort:           redditclient.SearchSortTop,
+		Timeframe:      redditclient.TimeframeWeek,
+	}}, calls[0].Args)
+}
+
+func TestCLI_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{"no command", nil, exitUsage, "usage: grapeddit [--format text|markdown|json|json-array] <command>"},
+		{"unknown command", []string{"frontpage"}, exitUsage, `unknown command "frontpage"`},
+		{"missing argument", []string{"sub"}, exitUsage, "missing arguments"},
+		{"extra argument", []string{"user", "a", "b"}, exitUsage, "too many arguments"},
+		{"unknown flag", []string{"sub", "golang", "--top"}, exitUsage, "flag provided but not defined"},
+		{"bad flag value", []string{"sub", "golang", "--limit", "ten"}, exitUsage, "invalid value"},
+		{"unknown format", []string{"--format", "yaml", "user", "spez"}, exitUsage, `unknown format "yaml"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := redditclienttest.NewFakeClient()
+			code, stdout, stderr := runCLI(t, fake, tt.args...)
+			assert.Equal(t, tt.code, code)
+			assert.Empty(t, stdout)
+			assert.Contains(t, stderr, tt.stderr)
+			assert.Empty(t, fake.Calls(), "nothing should be fetched after a usage error")
+		})
+	}
+
+	t.Run("client error", func(t *testing.T) {
+		fake := redditclienttest.NewFakeClient()
+		fake.Err = errors.New("boom")
+
+		code, stdout, stderr := runCLI(t, fake, "user", "spez")
+		assert.Equal(t, exitError, code)
+		assert.Empty(t, stdout)
+		assert.Equal(t, "grapeddit user: boom\n", stderr)
+	})
+}
+
+func TestCLI_Help(t *testing.T) {
+	code, stdout, _ := runCLI(t, redditclienttest.NewFakeClient(), "--help")
+	assert.Equal(t, exitOK, code)
+	for _, cmd := range commands {
+		assert.Contains(t, stdout, cmd.name)
+	}
+
+	code, _, stderr := runCLI(t, redditclienttest.NewFakeClient(), "search", "--help")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stderr, "usage: grapeddit search")
+	assert.Contains(t, stderr, "-time")
+}
+
+func TestCLI_Markdown(t *testing.T) {
+	for _, args := range [][]string{
+		{"--format", "markdown", "comments", "1c3xk9p"},
+		{"comments", "1c3xk9p", "--format=markdown"},
+	} {
+		code, stdout, stderr := runCLI(t, redditclienttest.NewFakeClient(), args...)
+		require.Equal(t, exitOK, code, stderr)
+		assert.True(t, strings.HasPrefix(stdout, "# How do you structure a large Go monorepo?\n"), stdout)
+		assert.Contains(t, stdout, "\n> > **u/busy\\_builder** · 12 points · ")
+		assert.Contains(t, stdout, "\n> *27 more comments*\n")
+	}
+
+	code, stdout, stderr := runCLI(t, redditclienttest.NewFakeClient(), "sub", "golang", "--format", "markdown")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Len(t, strings.Split(strings.TrimSpace(stdout), "\n- "), 3)
+}
+
+func TestCLI_Feed(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+
+	code, stdout, stderr := runCLI(t, fake, "feed", "r/golang", "--sort", "new", "--format", "atom")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stdout, `<feed xmlns="http://www.w3.org/2005/Atom">`)
+	assert.Contains(t, stdout, "<title>r/golang</title>")
+
+	calls := fake.CallsTo("GetSubredditListing")
+	require.Len(t, calls, 1)
+	assert.Equal(t, []any{"golang", redditclient.SortNew, &redditclient.ListingOptions{Limit: 25}}, calls[0].Args)
+
+	code, stdout, stderr = runCLI(t, redditclienttest.NewFakeClient(), "feed", "golang", "--links", "permalink")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stdout, `<rss version="2.0"`)
+	assert.NotContains(t, stdout, "<link>https://go.dev/")
+}
+
+func TestCLI_FormatsPerCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"JSON feed", []string{"feed", "golang", "--json"}},
+		{"markdown feed", []string{"--format", "markdown", "feed", "golang"}},
+		{"atom listing", []string{"sub", "golang", "--format", "atom"}},
+		{"unknown links", []string{"feed", "golang", "--links", "both"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := redditclienttest.NewFakeClient()
+			code, stdout, _ := runCLI(t, fake, tt.args...)
+			assert.Equal(t, exitUsage, code)
+			assert.Empty(t, stdout)
+			assert.Empty(t, fake.Calls())
+		})
+	}
+}
+
+func TestCLI_Serve(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+
+	var stdout, stderr bytes.Buffer
+	var clientOpts []redditclient.Option
+	app := &cli{
+		stdout: &stdout,
+		stderr: &stderr,
+		connect: func(ctx context.Context, opts ...redditclient.Option) (redditclient.RedditClient, error) {
+			clientOpts = opts
+			return redditclienttest.NewFakeClient(), nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan int)
+	go func() { done <- app.run(ctx, []string{"serve", "--listen", addr}) }()
+
+	var resp *http.Response
+	require.Eventually(t, func() bool {
+		resp, err = http.Get("http://" + addr + "/r/golang")
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "public, max-age=60", resp.Header.Get("Cache-Control"))
+	assert.Len(t, clientOpts, 3, "serve should ask for a cache, coalescing and rate limit waiting")
+
+	cancel()
+	select {
+	case code := <-done:
+		assert.Equal(t, exitOK, code, stderr.String())
+	case <-time.After(5 * time.Second):
+		t.Fatal("serve did not shut down")
+	}
+	assert.Contains(t, stderr.String(), "shutting down")
+}
+
+func TestCLI_Gemini(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+
+	var stdout, stderr bytes.Buffer
+	app := &cli{
+		stdout: &stdout,
+		stderr: &stderr,
+		connect: func(ctx context.Context, opts ...redditclient.Option) (redditclient.RedditClient, error) {
+			assert.Len(t, opts, 3, "gemini should ask for a cache, coalescing and rate limit waiting")
+			return redditclienttest.NewFakeClient(), nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan int)
+	go func() { done <- app.run(ctx, []string{"gemini", "--listen", addr}) }()
+
+	var conn *tls.Conn
+	require.Eventually(t, func() bool {
+		conn, err = tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	defer conn.Close()
+	_, err = io.WriteString(conn, "gemini://localhost/r/golang\r\n")
+	require.NoError(t, err)
+	resp, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(resp), "20 text/gemini; charset=utf-8\r\n# r/golang\n"), string(resp))
+
+	cancel()
+	select {
+	case code := <-done:
+		assert.Equal(t, exitOK, code, stderr.String())
+	case <-time.After(5 * time.Second):
+		t.Fatal("gemini did not shut down")
+	}
+	assert.Contains(t, stderr.String(), "self-signed certificate for localhost")
+}
+
+func TestCLI_GeminiCertNeedsKey(t *testing.T) {
+	code, _, stderr := runCLI(t, redditclienttest.NewFakeClient(), "gemini", "--cert", "cert.pem")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "--cert and --key must be given together")
+}
+
+func TestCLI_Gopher(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+
+	var stdout, stderr bytes.Buffer
+	app := &cli{
+		stdout: &stdout,
+		stderr: &stderr,
+		connect: func(ctx context.Context, opts ...redditclient.Option) (redditclient.RedditClient, error) {
+			assert.Len(t, opts, 3, "gopher should ask for a cache, coalescing and rate limit waiting")
+			return redditclienttest.NewFakeClient(), nil
+		},
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan int)
+	go func() {
+		done <- app.run(ctx, []string{"gopher", "--listen", addr, "--hostname", "gopher.example.org", "--port", "70"})
+	}()
+
+	var conn net.Conn
+	require.Eventually(t, func() bool {
+		conn, err = net.Dial("tcp", addr)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	defer conn.Close()
+	_, err = io.WriteString(conn, "/r/golang\r\n")
+	require.NoError(t, err)
+	resp, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	assert.Contains(t, string(resp), "0How do you structure a large Go monorepo?\t/r/golang/1c3xk9p\tgopher.example.org\t70\r\n")
+
+	cancel()
+	select {
+	case code := <-done:
+		assert.Equal(t, exitOK, code, stderr.String())
+	case <-time.After(5 * time.Second):
+		t.Fatal("gopher did not shut down")
+	}
+}
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// assertGolden compares got with testdata/name, rewriting it under -update
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(got), 0o644))
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "run go test -update to create the golden file")
+	assert.Equal(t, string(want), got)
+}
+
+func TestCLI_JSONGolden(t *testing.T) {
+	tests := []struct {
+		golden string
+		args   []string
+	}{
+		{"sub.ndjson", []string{"sub", "golang", "--json"}},
+		{"sub.json", []string{"--json-array", "sub", "golang"}},
+		{"post.ndjson", []string{"post", "golang", "1c3xk9p", "--json"}},
+		{"comments.ndjson", []string{"--json", "comments", "1c3xk9p"}},
+		{"comments.json", []string{"comments", "1c3xk9p", "--json-array"}},
+		{"user.ndjson", []string{"user", "--json", "spez"}},
+		{"search.ndjson", []string{"search", "--json", "generics"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, redditclienttest.NewFakeClient(), tt.args...)
+			require.Equal(t, exitOK, code, stderr)
+			assertGolden(t, filepath.Join("golden", tt.golden), stdout)
+
+			if strings.HasSuffix(tt.golden, ".ndjson") {
+				for _, line := range strings.Split(strings.TrimSuffix(stdout, "\n"), "\n") {
+					assert.True(t, json.Valid([]byte(line)), line)
+				}
+			} else {
+				assert.True(t, json.Valid([]byte(stdout)))
+			}
+		})
+	}
+}
+
+// pagingFake adds SubredditPosts to the fake client, yielding the listing
+// fixture's posts and then err, if set
+type pagingFake struct {
+	*redditclienttest.FakeClient
+	err   error
+	calls []redditclient.ListingOptions
+}
+
+func (f *pagingFake) SubredditPosts(ctx context.Context, subreddit, sort string, opts *redditclient.ListingOptions) iter.Seq2[redditclient.Post, error] {
+	f.calls = append(f.calls, *opts)
+	return func(yield func(redditclient.Post, error) bool) {
+		for _, p := range fixturePosts() {
+			if opts.After != "" && p.Name <= opts.After {
+				continue
+			}
+			if !yield(p, nil) {
+				return
+			}
+		}
+		if f.err != nil {
+			yield(redditclient.Post{}, f.err)
+		}
+	}
+}
+
+func runArchiveCLI(t *testing.T, client *pagingFake, args ...string) (int, string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	app := &cli{
+		stdout: &stdout,
+		stderr: &stderr,
+		connect: func(ctx context.Context, opts ...redditclient.Option) (redditclient.RedditClient, error) {
+			return client, nil
+		},
+	}
+	code := app.run(t.Context(), append([]string{"archive"}, args...))
+	return code, stdout.String(), stderr.String()
+}
+
+func TestCLI_Archive(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "reddit.db")
+	client := &pagingFake{FakeClient: redditclienttest.NewFakeClient()}
+
+	code, stdout, stderr := runArchiveCLI(t, client, "r/golang", "--db", dbPath, "--limit", "2", "--comments")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stdout, "run 1: archived 3 posts")
+	assert.Contains(t, stdout, "from r/golang in 2 pages")
+	assert.Equal(t, []redditclient.ListingOptions{{Limit: 2}}, client.calls)
+	assert.Len(t, client.CallsTo("GetAllComments"), 3)
+
+	db, err := archive.Open(t.Context(), dbPath)
+	require.NoError(t, err)
+	defer db.Close()
+	var posts int
+	require.NoError(t, db.SQL().QueryRow("SELECT COUNT(*) FROM posts WHERE subreddit = 'golang'").Scan(&posts))
+	assert.Equal(t, 3, posts)
+}
+
+func TestCLI_ArchiveResumes(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "reddit.db")
+	client := &pagingFake{FakeClient: redditclienttest.NewFakeClient(), err: errors.New("connection reset")}
+
+	code, stdout, stderr := runArchiveCLI(t, client, "golang", "--db", dbPath)
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stdout, "archived 3 posts")
+	assert.Contains(t, stderr, "run 1 interrupted")
+
+	client.err = nil
+	code, stdout, stderr = runArchiveCLI(t, client, "golang", "--db", dbPath)
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stdout, "run 1: resumed run and archived 0 posts")
+	assert.Equal(t, "t3_1c3xk9p", client.calls[1].After)
+}
+
+func TestCLI_ArchiveUsage(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "reddit.db")
+
+	code, _, stderr := runArchiveCLI(t, &pagingFake{FakeClient: redditclienttest.NewFakeClient()}, "golang", "--db", dbPath, "--limit", "500")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "--limit must be between 1 and 100")
+
+	// The plain fake cannot page through listings
+	code, _, stderr = runCLI(t, redditclienttest.NewFakeClient(), "archive", "golang", "--db", dbPath)
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "cannot page")
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+)
+
+// The --json and --json-array output is built from the record types below
+// rather than the client's structs, so fields added to or renamed in
+// redditclient do not change what scripts reading the CLI see. Fields are
+// only ever added to these records, never renamed or removed.
+
+// postRecord is a post in JSON output
+type postRecord struct {
+	Kind        string                 `json:"kind"` // Always "post"
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Subreddit   string                 `json:"subreddit"`
+	Author      string                 `json:"author"`
+	Title       string                 `json:"title"`
+	Score       int                    `json:"score"`
+	UpvoteRatio float64                `json:"upvote_ratio"`
+	NumComments int                    `json:"num_comments"`
+	URL         string                 `json:"url"`
+	Permalink   string                 `json:"permalink"`
+	Domain      string                 `json:"domain"`
+	IsSelf      bool                   `json:"is_self"`
+	SelfText    string                 `json:"selftext"`
+	Flair       string                 `json:"flair"`
+	Over18      bool                   `json:"over_18"`
+	Spoiler     bool                   `json:"spoiler"`
+	Stickied    bool                   `json:"stickied"`
+	Locked      bool                   `json:"locked"`
+	CreatedUTC  redditclient.Timestamp `json:"created_utc"`
+	Created     string                 `json:"created"` // CreatedUTC as RFC 3339
+}
+
+// commentRecord is a comment, or a "more" placeholder, in JSON output
+type commentRecord struct {
+	Kind       string                 `json:"kind"` // "comment" or "more"
+	ID         string                 `json:"id"`
+	ParentID   string                 `json:"parent_id"`
+	Depth      int                    `json:"depth"`
+	Author     string                 `json:"author,omitempty"`
+	Body       string                 `json:"body,omitempty"`
+	Score      int                    `json:"score"`
+	Permalink  string                 `json:"permalink,omitempty"`
+	CreatedUTC redditclient.Timestamp `json:"created_utc,omitempty"`
+	Created    string                 `json:"created,omitempty"`
+	Count      int                    `json:"count,omitempty"` // Comments hidden behind a "more" placeholder
+	Replies    []commentRecord        `json:"replies,omitempty"`
+}
+
+// threadRecord is a post and its comment tree in JSON output
+type threadRecord struct {
+	Kind     string          `json:"kind"` // Always "thread"
+	Post     postRecord      `json:"post"`
+	Comments []commentRecord `json:"comments"`
+}
+
+// userRecord is a user profile in JSON output
+type userRecord struct {
+	Kind         string                 `json:"kind"` // Always "user"
+	Name         string                 `json:"name"`
+	LinkKarma    int                    `json:"link_karma"`
+	CommentKarma int                    `json:"comment_karma"`
+	TotalKarma   int                    `json:"total_karma"`
+	IsGold       bool                   `json:"is_gold"`
+	IsMod        bool                   `json:"is_mod"`
+	IsEmployee   bool                   `json:"is_employee"`
+	Verified     bool                   `json:"verified"`
+	IsSuspended  bool                   `json:"is_suspended"`
+	CreatedUTC   redditclient.Timestamp `json:"created_utc"`
+	Created      string                 `json:"created"`
+}
+
+func newPostRecord(p *redditclient.Post) postRecord {
+	return postRecord{
+		Kind:        "post",
+		ID:          p.ID,
+		Name:        p.Name,
+		Subreddit:   p.Subreddit,
+		Author:      p.Author,
+		Title:       p.Title,
+		Score:       p.Score,
+		UpvoteRatio: p.UpvoteRatio,
+		NumComments: p.NumComments,
+		URL:         p.URL,
+		Permalink:   p.Permalink,
+		Domain:      p.Domain,
+		IsSelf:      p.IsSelf,
+		SelfText:    p.SelfText,
+		Flair:       p.LinkFlairText,
+		Over18:      p.Over18,
+		Spoiler:     p.Spoiler,
+		Stickied:    p.Stickied,
+		Locked:      p.Locked,
+		CreatedUTC:  p.Created,
+		Created:     rfc3339(p.Created),
+	}
+}
+
+// newCommentRecords converts a comment listing, keeping its tree shape
+func newCommentRecords(listing *redditclient.CommentListing) []commentRecord {
+	if listing == nil {
+		return nil
+	}
+
+	var records []commentRecord
+	for _, child := range listing.Data.Children {
+		switch {
+		case child.Comment != nil:
+			c := child.Comment
+			records = append(records, commentRecord{
+				Kind:       "comment",
+				ID:         c.ID,
+				ParentID:   c.ParentID,
+				Depth:      c.Depth,
+				Author:     c.Author,
+				Body:       c.Body,
+				Score:      c.Score,
+				Permalink:  c.Permalink,
+				CreatedUTC: c.Created,
+				Created:    rfc3339(c.Created),
+				Replies:    newCommentRecords(c.Replies),
+			})
+		case child.More != nil:
+			m := child.More
+			records = append(records, commentRecord{
+				Kind:     "more",
+				ID:       m.ID,
+				ParentID: m.ParentID,
+				Depth:    m.Depth,
+				Count:    m.Count,
+			})
+		}
+	}
+	return records
+}
+
+func newThreadRecord(post *redditclient.Post, comments *redditclient.CommentListing) threadRecord {
+	records := newCommentRecords(comments)
+	if records == nil {
+		records = []commentRecord{}
+	}
+	return threadRecord{Kind: "thread", Post: newPostRecord(post), Comments: records}
+}
+
+func newUserRecord(u *redditclient.UserAbout) userRecord {
+	return userRecord{
+		Kind:         "user",
+		Name:         u.Name,
+		LinkKarma:    u.LinkKarma,
+		CommentKarma: u.CommentKarma,
+		TotalKarma:   u.TotalKarma,
+		IsGold:       u.IsGold,
+		IsMod:        u.IsMod,
+		IsEmployee:   u.IsEmployee,
+		Verified:     u.Verified,
+		IsSuspended:  u.IsSuspended,
+		CreatedUTC:   u.Created,
+		Created:      rfc3339(u.Created),
+	}
+}
+
+// rfc3339 formats a Reddit created_utc timestamp, or returns "" for zero
+func rfc3339(created redditclient.Timestamp) string {
+	if created.IsZero() {
+		return ""
+	}
+	return created.Time().Format(time.RFC3339)
+}
+
+// writeRecords writes records as newline-delimited JSON, or as one indented
+// JSON array when array is set
+func writeRecords[T any](w io.Writer, array bool, records []T) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	if array {
+		if records == nil {
+			records = []T{}
+		}
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	}
+
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
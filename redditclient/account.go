@@ -0,0 +1,58 @@
+package redditclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// GetSubscriptions fetches the subreddits the logged-in user subscribes to.
+// It requires AuthenticateScript and fails with ErrRequiresUserAuth otherwise.
+func (c *Client) GetSubscriptions(ctx context.Context, opts *ListingOptions) (*SubscriptionListing, error) {
+	if _, err := c.requireUserAuth(); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	opts.apply(params)
+
+	body, err := c.makeAPIRequest(ctx, "/subreddits/mine/subscriber.json", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var listing SubscriptionListing
+	if err := c.decodeJSON(ctx, body, &listing, "subscriptions"); err != nil {
+		return nil, err
+	}
+
+	return &listing, nil
+}
+
+// GetSaved fetches the posts and comments a user has saved. An empty
+// username means the logged-in user. It requires AuthenticateScript and
+// fails with ErrRequiresUserAuth otherwise.
+func (c *Client) GetSaved(ctx context.Context, username string, opts *ListingOptions) (*ThingListing, error) {
+	me, err := c.requireUserAuth()
+	if err != nil {
+		return nil, err
+	}
+	if username == "" {
+		username = me
+	}
+
+	params := url.Values{}
+	opts.apply(params)
+
+	body, err := c.makeAPIRequest(ctx, fmt.Sprintf("/user/%s/saved.json", url.PathEscape(username)), params)
+	if err != nil {
+		return nil, err
+	}
+
+	var listing ThingListing
+	if err := c.decodeJSON(ctx, body, &listing, "saved"); err != nil {
+		return nil, err
+	}
+
+	return &listing, nil
+}
@@ -0,0 +1,91 @@
+package redditclient
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSubscriptions_Success(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	srv.Respond("/subreddits/mine/subscriber.json", 200, `{"kind": "Listing", "data": {"after": "t5_b", "children": [
+		{"kind": "t5", "data": {"name": "t5_a", "display_name": "golang", "subscribers": 250000}},
+		{"kind": "t5", "data": {"name": "t5_b", "display_name": "rust"}}
+	]}}`)
+	client := newFakeServerClient(t, srv)
+	authenticateScript(t, client)
+
+	result, err := client.GetSubscriptions(t.Context(), &ListingOptions{Limit: 100})
+
+	require.NoError(t, err)
+	require.Len(t, result.Data.Children, 2)
+	assert.Equal(t, "golang", result.Data.Children[0].Data.DisplayName)
+	assert.Equal(t, 250000, result.Data.Children[0].Data.Subscribers)
+	assert.Equal(t, "t5_b", result.Data.After)
+
+	req, ok := srv.LastRequest("/subreddits/mine/subscriber.json")
+	require.True(t, ok)
+	assert.Equal(t, "100", req.Query.Get("limit"))
+}
+
+func TestGetSaved_InterleavesPostsAndComments(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	srv.Respond("/user/"+fakeScriptUsername+"/saved.json", 200, `{"kind": "Listing", "data": {"children": [
+		{"kind": "t3", "data": {"id": "p1", "title": "A saved post"}},
+		{"kind": "t1", "data": {"id": "c1", "body": "A saved comment", "replies": ""}}
+	]}}`)
+	client := newFakeServerClient(t, srv)
+	authenticateScript(t, client)
+
+	result, err := client.GetSaved(t.Context(), "", &ListingOptions{After: "t3_prev"})
+
+	require.NoError(t, err)
+	require.Len(t, result.Data.Children, 2)
+	require.NotNil(t, result.Data.Children[0].Post)
+	assert.Equal(t, "A saved post", result.Data.Children[0].Post.Title)
+	require.NotNil(t, result.Data.Children[1].Comment)
+	assert.Equal(t, "A saved comment", result.Data.Children[1].Comment.Body)
+
+	req, ok := srv.LastRequest("/user/" + fakeScriptUsername + "/saved.json")
+	require.True(t, ok)
+	assert.Equal(t, "t3_prev", req.Query.Get("after"))
+}
+
+func TestGetSaved_ExplicitUsername(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	client := newFakeServerClient(t, srv)
+	authenticateScript(t, client)
+
+	_, err := client.GetSaved(t.Context(), "someone", nil)
+
+	require.NoError(t, err)
+	_, ok := srv.LastRequest("/user/someone/saved.json")
+	assert.True(t, ok)
+}
+
+func TestAccountEndpoints_RequireUserAuth(t *testing.T) {
+	t.Run("anonymous", func(t *testing.T) {
+		client, srv := newAuthenticatedFakeServerClient(t)
+
+		_, err := client.GetSubscriptions(t.Context(), nil)
+		assert.ErrorIs(t, err, ErrRequiresUserAuth)
+
+		_, err = client.GetSaved(t.Context(), "someone", nil)
+		assert.ErrorIs(t, err, ErrRequiresUserAuth)
+
+		// Both fail before any API request is sent
+		assert.Len(t, srv.Requests(), 1)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		srv := newFakeRedditServer(t)
+		client := newFakeServerClient(t, srv)
+
+		_, err := client.GetSubscriptions(t.Context(), nil)
+		assert.ErrorIs(t, err, ErrNotAuthenticated)
+
+		_, err = client.GetSaved(t.Context(), "", nil)
+		assert.ErrorIs(t, err, ErrNotAuthenticated)
+	})
+}
@@ -0,0 +1,56 @@
+package redditclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Vote directions accepted by Vote
+const (
+	VoteDown  = -1
+	VoteClear = 0
+	VoteUp    = 1
+)
+
+// Vote casts the logged-in user's vote on a post or comment. dir is VoteUp,
+// VoteDown or VoteClear to remove an existing vote. It requires
+// AuthenticateScript and fails with ErrRequiresUserAuth otherwise.
+func (c *Client) Vote(ctx context.Context, fullname string, dir int) error {
+	if _, err := c.requireUserAuth(); err != nil {
+		return err
+	}
+	if dir < VoteDown || dir > VoteUp {
+		return fmt.Errorf("%w: vote direction %d, want -1, 0 or 1", ErrInvalidArgument, dir)
+	}
+
+	form := url.Values{
+		"id":  {fullname},
+		"dir": {strconv.Itoa(dir)},
+	}
+	_, err := c.makeFormRequest(ctx, "/api/vote", form)
+	return err
+}
+
+// Save adds a post or comment to the logged-in user's saved items. It
+// requires AuthenticateScript and fails with ErrRequiresUserAuth otherwise.
+func (c *Client) Save(ctx context.Context, fullname string) error {
+	return c.saveAction(ctx, "/api/save", fullname)
+}
+
+// Unsave removes a post or comment from the logged-in user's saved items. It
+// requires AuthenticateScript and fails with ErrRequiresUserAuth otherwise.
+func (c *Client) Unsave(ctx context.Context, fullname string) error {
+	return c.saveAction(ctx, "/api/unsave", fullname)
+}
+
+// saveAction does the work of Save and Unsave
+func (c *Client) saveAction(ctx context.Context, endpoint, fullname string) error {
+	if _, err := c.requireUserAuth(); err != nil {
+		return err
+	}
+
+	_, err := c.makeFormRequest(ctx, endpoint, url.Values{"id": {fullname}})
+	return err
+}
@@ -0,0 +1,139 @@
+package redditclient
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVote_PostsForm(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	client := newFakeServerClient(t, srv)
+	authenticateScript(t, client)
+
+	require.NoError(t, client.Vote(t.Context(), "t3_abc", VoteDown))
+
+	req, ok := srv.LastRequest("/api/vote")
+	require.True(t, ok)
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "application/x-www-form-urlencoded", req.Header.Get("Content-Type"))
+	assert.Equal(t, "Bearer fake-token", req.Header.Get("Authorization"))
+	form, err := url.ParseQuery(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "t3_abc", form.Get("id"))
+	assert.Equal(t, "-1", form.Get("dir"))
+	assert.Equal(t, "json", form.Get("api_type"))
+}
+
+func TestVote_InvalidDirection(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	client := newFakeServerClient(t, srv)
+	authenticateScript(t, client)
+
+	err := client.Vote(t.Context(), "t3_abc", 2)
+
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+	_, sent := srv.LastRequest("/api/vote")
+	assert.False(t, sent)
+}
+
+func TestSaveAndUnsave(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	client := newFakeServerClient(t, srv)
+	authenticateScript(t, client)
+
+	require.NoError(t, client.Save(t.Context(), "t1_c1"))
+	require.NoError(t, client.Unsave(t.Context(), "t3_p1"))
+
+	for path, id := range map[string]string{"/api/save": "t1_c1", "/api/unsave": "t3_p1"} {
+		req, ok := srv.LastRequest(path)
+		require.True(t, ok, path)
+		assert.Equal(t, http.MethodPost, req.Method)
+		form, err := url.ParseQuery(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, id, form.Get("id"))
+	}
+}
+
+func TestActions_RequireUserAuth(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+
+	assert.ErrorIs(t, client.Vote(t.Context(), "t3_abc", VoteUp), ErrRequiresUserAuth)
+	assert.ErrorIs(t, client.Save(t.Context(), "t3_abc"), ErrRequiresUserAuth)
+	assert.ErrorIs(t, client.Unsave(t.Context(), "t3_abc"), ErrRequiresUserAuth)
+
+	// Only the anonymous token request reached the server
+	assert.Len(t, srv.Requests(), 1)
+}
+
+func TestActions_RateLimited(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		wait time.Duration
+	}{
+		{
+			name: "ratelimit field",
+			body: `{"json": {"ratelimit": 312.5, "errors": [["RATELIMIT", "you are doing that too much. try again in 5 minutes.", "ratelimit"]]}}`,
+			wait: 312500 * time.Millisecond,
+		},
+		{
+			name: "message only",
+			body: `{"json": {"errors": [["RATELIMIT", "you are doing that too much. try again in 9 seconds.", "ratelimit"]]}}`,
+			wait: 9 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeRedditServer(t)
+			srv.Respond("/api/vote", http.StatusOK, tt.body)
+			client := newFakeServerClient(t, srv)
+			authenticateScript(t, client)
+
+			err := client.Vote(t.Context(), "t3_abc", VoteUp)
+
+			require.ErrorIs(t, err, ErrRateLimited)
+			var rateErr *RateLimitError
+			require.ErrorAs(t, err, &rateErr)
+			assert.Equal(t, tt.wait, rateErr.RetryAfter)
+			assert.Contains(t, rateErr.Message, "doing that too much")
+		})
+	}
+}
+
+func TestActions_OtherFormError(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	srv.Respond("/api/save", http.StatusOK, `{"json": {"errors": [["THREAD_LOCKED", "that thread is locked", "id"]]}}`)
+	client := newFakeServerClient(t, srv)
+	authenticateScript(t, client)
+
+	err := client.Save(t.Context(), "t3_abc")
+
+	assert.NotErrorIs(t, err, ErrRateLimited)
+	var actionErr *ActionError
+	require.ErrorAs(t, err, &actionErr)
+	assert.Equal(t, "THREAD_LOCKED", actionErr.Code)
+	assert.Equal(t, "id", actionErr.Field)
+	assert.Equal(t, "/api/save", actionErr.Endpoint)
+}
+
+func TestActions_ReauthenticateReplaysForm(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	client := newFakeServerClient(t, srv)
+	authenticateScript(t, client)
+
+	srv.SetToken("rotated-token")
+	require.NoError(t, client.Save(t.Context(), "t3_abc"))
+
+	req, ok := srv.LastRequest("/api/save")
+	require.True(t, ok)
+	assert.Equal(t, "Bearer rotated-token", req.Header.Get("Authorization"))
+	form, err := url.ParseQuery(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "t3_abc", form.Get("id"))
+}
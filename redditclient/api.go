@@ -0,0 +1,218 @@
+package redditclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// GetSubreddit fetches the first page of a subreddit listing. It is a
+// string-typed wrapper around GetSubredditListing.
+func (c *Client) GetSubreddit(ctx context.Context, subreddit, sort string) (*SubredditListing, error) {
+	return c.GetSubredditListing(ctx, subreddit, Sort(sort), nil)
+}
+
+// GetSubredditPage fetches one page of a subreddit listing. It is a
+// string-typed wrapper around GetSubredditListing.
+func (c *Client) GetSubredditPage(ctx context.Context, subreddit, sort string, opts *ListingOptions) (*SubredditListing, error) {
+	return c.GetSubredditListing(ctx, subreddit, Sort(sort), opts)
+}
+
+// GetSubredditListing fetches one page of a subreddit listing in the given
+// order. Unknown sorts are rejected with ErrInvalidArgument before any request
+// is made. SortTop and SortControversial rank posts over DefaultTimeframe, as
+// Reddit does; GetSubredditTop and GetSubredditControversial take another.
+func (c *Client) GetSubredditListing(ctx context.Context, subreddit string, sort Sort, opts *ListingOptions) (*SubredditListing, error) {
+	return c.getSubredditListing(ctx, subreddit, sort, "", opts)
+}
+
+// GetSubredditTop fetches one page of a subreddit's highest scoring posts
+// within tf, such as TimeframeAll for the top posts of all time. An empty tf
+// uses DefaultTimeframe.
+func (c *Client) GetSubredditTop(ctx context.Context, subreddit string, tf Timeframe, opts *ListingOptions) (*SubredditListing, error) {
+	return c.getSubredditListing(ctx, subreddit, SortTop, tf, opts)
+}
+
+// GetSubredditControversial fetches one page of a subreddit's most
+// controversial posts within tf. An empty tf uses DefaultTimeframe.
+func (c *Client) GetSubredditControversial(ctx context.Context, subreddit string, tf Timeframe, opts *ListingOptions) (*SubredditListing, error) {
+	return c.getSubredditListing(ctx, subreddit, SortControversial, tf, opts)
+}
+
+func (c *Client) getSubredditListing(ctx context.Context, subreddit string, sort Sort, tf Timeframe, opts *ListingOptions) (*SubredditListing, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	if err := sort.validate(); err != nil {
+		return nil, err
+	}
+	params, err := listingParams(sort, tf, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("/r/%s/%s.json", url.PathEscape(subreddit), sort)
+
+	return c.getListingPage(ctx, endpoint, params)
+}
+
+// GetCombinedSubreddits fetches one page of the merged listing of several
+// subreddits, using Reddit's /r/sub1+sub2 syntax. Each Post's Subreddit field
+// tells which subreddit it came from. Names containing "+" or "/", or a
+// combination too long for one URL, are rejected with ErrInvalidArgument. An
+// empty sort uses "hot".
+func (c *Client) GetCombinedSubreddits(ctx context.Context, subreddits []string, sort string, opts *ListingOptions) (*SubredditListing, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	if sort == "" {
+		sort = string(SortHot)
+	}
+	if err := Sort(sort).validate(); err != nil {
+		return nil, err
+	}
+
+	combined, err := combineSubreddits(subreddits)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := listingParams(Sort(sort), "", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.getListingPage(ctx, fmt.Sprintf("/r/%s/%s.json", combined, sort), params)
+}
+
+// combineSubreddits validates and escapes subreddit names and joins them with "+"
+func combineSubreddits(subreddits []string) (string, error) {
+	if len(subreddits) == 0 {
+		return "", fmt.Errorf("%w: no subreddits to combine", ErrInvalidArgument)
+	}
+
+	escaped := make([]string, len(subreddits))
+	for i, name := range subreddits {
+		if name == "" || strings.ContainsAny(name, "+/") {
+			return "", fmt.Errorf("%w: subreddit name %q", ErrInvalidArgument, name)
+		}
+		escaped[i] = url.PathEscape(name)
+	}
+
+	combined := strings.Join(escaped, "+")
+	if len(combined) > maxCombinedSubredditsLen {
+		return "", fmt.Errorf("%w: %d subreddits combine to %d bytes, over the %d byte limit",
+			ErrInvalidArgument, len(subreddits), len(combined), maxCombinedSubredditsLen)
+	}
+
+	return combined, nil
+}
+
+// listingParams builds the query for a post listing in sort order. Sorts that
+// rank within a timeframe are given tf, or DefaultTimeframe if it is empty, so
+// the request says which window it wants.
+func listingParams(sort Sort, tf Timeframe, opts *ListingOptions) (url.Values, error) {
+	params := url.Values{}
+	if sort.takesTimeframe() {
+		if tf == "" {
+			tf = DefaultTimeframe
+		}
+		if err := tf.validate(); err != nil {
+			return nil, err
+		}
+		params.Set("t", string(tf))
+	}
+	opts.apply(params)
+
+	return params, nil
+}
+
+// getListingPage fetches one page of a post listing at endpoint
+func (c *Client) getListingPage(ctx context.Context, endpoint string, params url.Values) (*SubredditListing, error) {
+	var listing SubredditListing
+	if err := c.streamAPIRequest(ctx, endpoint, params, &listing, "subreddit listing"); err != nil {
+		return nil, err
+	}
+
+	return &listing, nil
+}
+
+// GetPost fetches a single post. Reddit serves posts from the comments
+// endpoint, so this asks for a single comment and returns the post from the
+// first element of the response; use GetComments for the thread. A post
+// listing without the post returns an error matching ErrNotFound.
+func (c *Client) GetPost(ctx context.Context, subreddit, postID string) (*Post, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	endpoint := fmt.Sprintf("/r/%s/comments/%s.json", url.PathEscape(subreddit), url.PathEscape(postID))
+
+	thread, err := c.getCommentThread(ctx, endpoint, &CommentOptions{Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(thread.PostListing.Data.Children) == 0 {
+		return nil, fmt.Errorf("post %s in r/%s: %w", postID, subreddit, ErrNotFound)
+	}
+
+	return &thread.PostListing.Data.Children[0].Data, nil
+}
+
+// GetUser fetches user information. It returns ErrUserNotFound for deleted,
+// shadowbanned and nonexistent accounts and ErrUserSuspended for suspended ones.
+func (c *Client) GetUser(ctx context.Context, username string) (*UserResponse, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	endpoint := fmt.Sprintf("/user/%s/about.json", url.PathEscape(username))
+
+	body, err := c.makeAPIRequest(ctx, endpoint, nil)
+	if err != nil {
+		return nil, userError(username, err)
+	}
+
+	var user UserResponse
+	if err := c.decodeJSON(ctx, body, &user, "user"); err != nil {
+		return nil, err
+	}
+	if user.Data.IsSuspended {
+		return nil, fmt.Errorf("%w: %s", ErrUserSuspended, username)
+	}
+
+	return &user, nil
+}
+
+// Search performs a Reddit search. sort and timeframe must be SearchSort and
+// Timeframe values or empty; SearchWithOptions is the typed equivalent.
+func (c *Client) Search(ctx context.Context, query, sort, timeframe string) (*SearchResponse, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	if err := validateOptional(SearchSort(sort), SearchSort(sort).validate); err != nil {
+		return nil, err
+	}
+	if err := validateOptional(Timeframe(timeframe), Timeframe(timeframe).validate); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{
+		"q":    []string{query},
+		"sort": []string{sort},
+		"t":    []string{timeframe},
+	}
+
+	body, err := c.makeAPIRequest(ctx, "/search.json", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var search SearchResponse
+	if err := c.decodeJSON(ctx, body, &search, "search results"); err != nil {
+		return nil, err
+	}
+
+	return &search, nil
+}
@@ -0,0 +1,367 @@
+package redditclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCredentials is returned by AuthenticateScript when Reddit rejects
+// the app credentials or the account's username and password
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// ErrRequiresUserAuth is returned without making a request when an endpoint
+// that needs a logged-in account is called outside AuthScript mode
+var ErrRequiresUserAuth = errors.New("endpoint requires a logged-in user - call AuthenticateScript() first")
+
+// AuthMode is how the client obtained its access token
+type AuthMode int
+
+const (
+	// AuthNone means the client has not authenticated yet
+	AuthNone AuthMode = iota
+	// AuthAnonymous is the logged-out Android app flow used by Authenticate
+	AuthAnonymous
+	// AuthScript is the password grant for a Reddit "script" app used by AuthenticateScript
+	AuthScript
+)
+
+func (m AuthMode) String() string {
+	switch m {
+	case AuthAnonymous:
+		return "anonymous"
+	case AuthScript:
+		return "script"
+	default:
+		return "none"
+	}
+}
+
+// scriptCredentials are kept so a script-mode token can be refreshed
+type scriptCredentials struct {
+	clientID     string
+	clientSecret string
+	username     string
+	password     string
+}
+
+// Authenticate performs OAuth authentication as the logged-out Android app,
+// replacing any script credentials
+func (c *Client) Authenticate(ctx context.Context) error {
+	c.authLock.Lock()
+	defer c.authLock.Unlock()
+
+	return c.authenticateLoid(ctx)
+}
+
+// AuthenticateScript logs in with the password grant of a Reddit "script" app.
+// Later requests use plain OAuth headers and refreshes repeat the grant. It
+// replaces any anonymous session and fails with ErrInvalidCredentials when
+// Reddit rejects the credentials.
+func (c *Client) AuthenticateScript(ctx context.Context, clientID, clientSecret, username, password string) error {
+	c.authLock.Lock()
+	defer c.authLock.Unlock()
+
+	return c.authenticateScript(ctx, scriptCredentials{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		username:     username,
+		password:     password,
+	})
+}
+
+// AuthMode reports how the client is currently authenticated
+func (c *Client) AuthMode() AuthMode {
+	return c.credentials().mode
+}
+
+// authenticate renews the token using the current auth mode; callers must hold authLock
+func (c *Client) authenticate(ctx context.Context) error {
+	c.credLock.RLock()
+	mode, script := c.authMode, c.script
+	c.credLock.RUnlock()
+
+	if mode == AuthScript {
+		return c.authenticateScript(ctx, script)
+	}
+	return c.authenticateLoid(ctx)
+}
+
+// authenticateScript does the work of AuthenticateScript; callers must hold authLock
+func (c *Client) authenticateScript(ctx context.Context, script scriptCredentials) error {
+	form := url.Values{
+		"grant_type": {"password"},
+		"username":   {script.username},
+		"password":   {script.password},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.authURL+"/api/v1/access_token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	userAgent := c.sessionUserAgent()
+	req.SetBasicAuth(script.clientID, script.clientSecret)
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("authentication request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// A bad client ID or secret is a 401; a bad password is a 200 with an error field
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("%w: client ID or secret rejected", ErrInvalidCredentials)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("authentication failed with status: %d", resp.StatusCode)
+	}
+
+	var oauthResp struct {
+		OAuthResponse
+		Scope string `json:"scope"` // A space-separated string here, unlike the loid flow
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&oauthResp); err != nil {
+		return fmt.Errorf("failed to decode OAuth response: %w", err)
+	}
+	switch {
+	case oauthResp.Error == "invalid_grant":
+		return fmt.Errorf("%w: username or password rejected", ErrInvalidCredentials)
+	case oauthResp.Error != "":
+		return fmt.Errorf("authentication failed: %s", oauthResp.Error)
+	case oauthResp.AccessToken == "":
+		return fmt.Errorf("authentication failed: no access token in response")
+	}
+
+	c.storeToken(AuthScript, oauthResp.AccessToken, oauthResp.ExpiresIn, "", "", userAgent, script)
+	c.logger.DebugContext(ctx, "authenticated", "mode", AuthScript, "expires_in", oauthResp.ExpiresIn)
+
+	return nil
+}
+
+// authenticateLoid does the work of Authenticate; callers must hold authLock
+func (c *Client) authenticateLoid(ctx context.Context) error {
+	// Installed-app client, so the secret is empty
+	auth := base64.StdEncoding.EncodeToString([]byte(c.clientID + ":"))
+
+	body := map[string]interface{}{
+		"scopes": c.scopes,
+	}
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal json response body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.authURL+"/auth/v2/oauth/access-token/loid", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Required headers for Android app spoofing
+	userAgent := c.sessionUserAgent()
+	headers := map[string]string{
+		"Authorization":         "Basic " + auth,
+		"User-Agent":            userAgent,
+		"X-Reddit-Device-Id":    c.deviceID,
+		"client-vendor-id":      c.deviceID,
+		"Content-Type":          "application/json; charset=UTF-8",
+		"x-reddit-retry":        "algo=no-retries",
+		"x-reddit-compression":  "1",
+		"x-reddit-qos":          c.qos(c.rng),
+		"x-reddit-media-codecs": "available-codecs=video/avc, video/hevc, video/x-vnd.on2.vp9",
+	}
+
+	c.setHeaders(req, headers)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("authentication request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("authentication failed with status: %d", resp.StatusCode)
+	}
+
+	var oauthResp OAuthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&oauthResp); err != nil {
+		return fmt.Errorf("failed to decode OAuth response: %w", err)
+	}
+
+	c.storeToken(AuthAnonymous, oauthResp.AccessToken, oauthResp.ExpiresIn,
+		resp.Header.Get("x-reddit-loid"), resp.Header.Get("x-reddit-session"), userAgent, scriptCredentials{})
+	c.logger.DebugContext(ctx, "authenticated", "mode", AuthAnonymous, "expires_in", oauthResp.ExpiresIn)
+
+	return nil
+}
+
+// sessionUserAgent returns the user agent for a new authentication request,
+// which storeToken then keeps for the session it starts. With rotation enabled
+// every re-authentication moves to the next pick from the pool; callers must
+// hold authLock.
+func (c *Client) sessionUserAgent() string {
+	creds := c.credentials()
+	if !c.rotateUA || !creds.authenticated {
+		return creds.userAgent
+	}
+
+	c.uaGeneration++
+	return pickUserAgent(c.userAgentPool, c.deviceID, c.uaGeneration)
+}
+
+// pickUserAgent chooses a user agent from pool for the given device ID and
+// session generation. The same inputs always give the same user agent, so a
+// device keeps a plausible app version rather than a new one per request.
+func pickUserAgent(pool []string, deviceID string, generation uint64) string {
+	h := fnv.New64a()
+	h.Write([]byte(deviceID))
+	binary.Write(h, binary.LittleEndian, generation)
+	return pool[h.Sum64()%uint64(len(pool))]
+}
+
+// storeToken installs a new token and the state of the mode that issued it,
+// clearing whatever the other mode left behind
+func (c *Client) storeToken(mode AuthMode, token string, expiresIn int, loid, session, userAgent string, script scriptCredentials) {
+	var expiry time.Time
+	if expiresIn > 0 {
+		expiry = c.now().Add(time.Duration(expiresIn) * time.Second)
+	}
+
+	// Swap the whole credential set at once so readers never see a mix
+	c.credLock.Lock()
+	defer c.credLock.Unlock()
+
+	c.authMode = mode
+	c.accessToken = token
+	c.tokenExpiry = expiry
+	c.loid = loid
+	c.session = session
+	c.userAgent = userAgent
+	c.script = script
+	c.authenticated = true
+}
+
+// credentials is a consistent snapshot of the client's credential fields
+type credentials struct {
+	authenticated bool
+	mode          AuthMode
+	accessToken   string
+	loid          string
+	session       string
+	userAgent     string
+	tokenExpiry   time.Time
+	refreshMargin time.Duration
+}
+
+// credentials returns a snapshot of the credential fields taken under credLock
+func (c *Client) credentials() credentials {
+	c.credLock.RLock()
+	defer c.credLock.RUnlock()
+
+	return credentials{
+		authenticated: c.authenticated,
+		mode:          c.authMode,
+		accessToken:   c.accessToken,
+		loid:          c.loid,
+		session:       c.session,
+		userAgent:     c.userAgent,
+		tokenExpiry:   c.tokenExpiry,
+		refreshMargin: c.refreshMargin,
+	}
+}
+
+// isAuthenticated reports whether Authenticate has succeeded
+func (c *Client) isAuthenticated() bool {
+	return c.credentials().authenticated
+}
+
+// requireUserAuth checks that the client is logged in as a user, returning
+// that user's name
+func (c *Client) requireUserAuth() (string, error) {
+	c.credLock.RLock()
+	defer c.credLock.RUnlock()
+
+	if !c.authenticated {
+		return "", ErrNotAuthenticated
+	}
+	if c.authMode != AuthScript {
+		return "", ErrRequiresUserAuth
+	}
+	return c.script.username, nil
+}
+
+// SetTokenRefreshMargin sets how long before expiry the token is proactively refreshed
+func (c *Client) SetTokenRefreshMargin(margin time.Duration) {
+	c.credLock.Lock()
+	defer c.credLock.Unlock()
+
+	c.refreshMargin = margin
+}
+
+// IsTokenValid reports whether the client holds an access token that has not yet expired
+func (c *Client) IsTokenValid() bool {
+	creds := c.credentials()
+	if !creds.authenticated || creds.accessToken == "" {
+		return false
+	}
+
+	// A zero expiry means Reddit didn't tell us when the token lapses
+	return creds.tokenExpiry.IsZero() || c.now().Before(creds.tokenExpiry)
+}
+
+// tokenNeedsRefresh reports whether the token is within the refresh margin of expiring
+func (c *Client) tokenNeedsRefresh() bool {
+	creds := c.credentials()
+	if creds.tokenExpiry.IsZero() {
+		return false
+	}
+
+	return !c.now().Add(creds.refreshMargin).Before(creds.tokenExpiry)
+}
+
+// refreshTokenIfNeeded re-authenticates when the token is close to expiring.
+// Concurrent callers block on authLock so only one refresh request is sent.
+func (c *Client) refreshTokenIfNeeded(ctx context.Context) error {
+	if !c.tokenNeedsRefresh() {
+		return nil
+	}
+
+	c.authLock.Lock()
+	defer c.authLock.Unlock()
+
+	// Another caller may have refreshed while we waited for the lock
+	if !c.tokenNeedsRefresh() {
+		return nil
+	}
+
+	c.logger.DebugContext(ctx, "refreshing access token before expiry")
+	if err := c.authenticate(ctx); err != nil {
+		return fmt.Errorf("failed to refresh access token: %w", err)
+	}
+
+	return nil
+}
+
+// reauthenticate replaces a token the API rejected. If another caller already
+// replaced staleToken while we waited for the lock, no new request is made.
+func (c *Client) reauthenticate(ctx context.Context, staleToken string) error {
+	c.authLock.Lock()
+	defer c.authLock.Unlock()
+
+	if c.credentials().accessToken != staleToken {
+		return nil
+	}
+
+	return c.authenticate(ctx)
+}
@@ -0,0 +1,215 @@
+package redditclient
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// authenticateScript logs client in against srv with the fake script credentials
+func authenticateScript(t *testing.T, client *Client) {
+	t.Helper()
+
+	err := client.AuthenticateScript(t.Context(), fakeScriptClientID, fakeScriptSecret, fakeScriptUsername, fakeScriptPassword)
+	require.NoError(t, err)
+}
+
+func TestAuthenticateScript_Success(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	client := newFakeServerClient(t, srv)
+	assert.Equal(t, AuthNone, client.AuthMode())
+
+	authenticateScript(t, client)
+
+	assert.Equal(t, AuthScript, client.AuthMode())
+	assert.Equal(t, "fake-token", client.accessToken)
+	assert.True(t, client.IsTokenValid())
+
+	req, ok := srv.LastRequest("/api/v1/access_token")
+	require.True(t, ok)
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, client.userAgent, req.Header.Get("User-Agent"))
+	assert.Equal(t, "application/x-www-form-urlencoded", req.Header.Get("Content-Type"))
+	form, err := url.ParseQuery(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "password", form.Get("grant_type"))
+	assert.Equal(t, fakeScriptUsername, form.Get("username"))
+}
+
+func TestAuthenticateScript_InvalidCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		secret   string
+		password string
+	}{
+		{name: "wrong password", secret: fakeScriptSecret, password: "wrong"},
+		{name: "wrong secret", secret: "wrong", password: fakeScriptPassword},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeRedditServer(t)
+			client := newFakeServerClient(t, srv)
+
+			err := client.AuthenticateScript(t.Context(), fakeScriptClientID, tt.secret, fakeScriptUsername, tt.password)
+
+			assert.ErrorIs(t, err, ErrInvalidCredentials)
+			assert.Equal(t, AuthNone, client.AuthMode())
+			assert.False(t, client.IsTokenValid())
+		})
+	}
+}
+
+func TestAuthenticateScript_OtherError(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	srv.Respond("/api/v1/access_token", http.StatusOK, `{"error": "unsupported_grant_type"}`)
+	client := newFakeServerClient(t, srv)
+
+	err := client.AuthenticateScript(t.Context(), fakeScriptClientID, fakeScriptSecret, fakeScriptUsername, fakeScriptPassword)
+
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrInvalidCredentials)
+	assert.Contains(t, err.Error(), "unsupported_grant_type")
+}
+
+func TestAuthenticateScript_SendsPlainOAuthHeaders(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	client := newFakeServerClient(t, srv)
+	authenticateScript(t, client)
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+	require.NoError(t, err)
+
+	req, ok := srv.LastRequest("/r/golang/hot.json")
+	require.True(t, ok)
+	assert.Equal(t, "Bearer fake-token", req.Header.Get("Authorization"))
+	assert.NotContains(t, req.Header, http.CanonicalHeaderKey("x-reddit-loid"))
+	assert.NotContains(t, req.Header, http.CanonicalHeaderKey("x-reddit-session"))
+}
+
+func TestAuthenticateScript_ReauthenticatesWithPasswordGrant(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	client := newFakeServerClient(t, srv)
+	authenticateScript(t, client)
+
+	// Invalidate the token so the next request sees a 401
+	srv.SetToken("rotated-token")
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+	require.NoError(t, err)
+
+	assert.Equal(t, "rotated-token", client.accessToken)
+	assert.Equal(t, AuthScript, client.AuthMode())
+	_, usedLoid := srv.LastRequest("/auth/v2/oauth/access-token/loid")
+	assert.False(t, usedLoid)
+}
+
+func TestAuthMode_Switching(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	client := newFakeServerClient(t, srv)
+
+	require.NoError(t, client.Authenticate(t.Context()))
+	assert.Equal(t, AuthAnonymous, client.AuthMode())
+	assert.Equal(t, "fake-loid", client.loid)
+
+	authenticateScript(t, client)
+	assert.Equal(t, AuthScript, client.AuthMode())
+	assert.Empty(t, client.loid)
+	assert.Empty(t, client.session)
+
+	require.NoError(t, client.Authenticate(t.Context()))
+	assert.Equal(t, AuthAnonymous, client.AuthMode())
+	assert.Equal(t, "fake-loid", client.loid)
+	assert.Equal(t, scriptCredentials{}, client.script)
+}
+
+// sessionUserAgents walks the requests srv saw and returns the User-Agent of
+// each authentication, checking every API request in between used the same one
+func sessionUserAgents(t *testing.T, srv *fakeRedditServer) []string {
+	t.Helper()
+
+	var sessions []string
+	for _, req := range srv.Requests() {
+		userAgent := req.Header.Get("User-Agent")
+		if req.Path == "/auth/v2/oauth/access-token/loid" {
+			sessions = append(sessions, userAgent)
+			continue
+		}
+		require.NotEmpty(t, sessions, "API request before authentication")
+		assert.Equal(t, sessions[len(sessions)-1], userAgent, "API request to %s", req.Path)
+	}
+	return sessions
+}
+
+func TestUserAgent_ConsistentWithinSession(t *testing.T) {
+	pool := []string{
+		"Reddit/2025.10.0/Android 14",
+		"Reddit/2025.09.0/Android 15",
+		"Reddit/2025.08.0/Android 13",
+		"Reddit/2025.07.0/Android 14",
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want func(deviceID string) []string
+	}{
+		{
+			name: "pinned",
+			opts: []Option{WithUserAgent("Reddit/2025.10.0/Android 14")},
+			want: func(string) []string {
+				ua := "Reddit/2025.10.0/Android 14"
+				return []string{ua, ua, ua}
+			},
+		},
+		{
+			name: "pool",
+			opts: []Option{WithUserAgentPool(pool...)},
+			want: func(deviceID string) []string {
+				ua := pickUserAgent(pool, deviceID, 0)
+				return []string{ua, ua, ua}
+			},
+		},
+		{
+			name: "rotating",
+			opts: []Option{WithUserAgentPool(pool...), WithUserAgentRotation()},
+			want: func(deviceID string) []string {
+				return []string{
+					pickUserAgent(pool, deviceID, 0),
+					pickUserAgent(pool, deviceID, 1),
+					pickUserAgent(pool, deviceID, 2),
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeRedditServer(t)
+			client, err := NewClient(append(tt.opts, WithBaseURL(srv.URL), WithDeviceID("device-1234"))...)
+			require.NoError(t, err)
+
+			require.NoError(t, client.Authenticate(t.Context()))
+			_, err = client.GetSubreddit(t.Context(), "golang", "hot")
+			require.NoError(t, err)
+
+			// A rejected token re-authenticates and retries within the new session
+			srv.SetToken("rotated-token")
+			_, err = client.GetSubreddit(t.Context(), "golang", "hot")
+			require.NoError(t, err)
+
+			require.NoError(t, client.Authenticate(t.Context()))
+			_, err = client.GetSubreddit(t.Context(), "golang", "new")
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want("device-1234"), sessionUserAgents(t, srv))
+		})
+	}
+}
+
+func TestPickUserAgent_StablePerDevice(t *testing.T) {
+	assert.Equal(t, pickUserAgent(androidVersions, "device-a", 0), pickUserAgent(androidVersions, "device-a", 0))
+	assert.Contains(t, androidVersions, pickUserAgent(androidVersions, "device-b", 7))
+}
@@ -0,0 +1,51 @@
+package redditclient
+
+import (
+	"encoding/json"
+	"html"
+)
+
+// Awarding is one kind of award given to a post or comment, with how many
+// times it was given
+type Awarding struct {
+	ID            string         `json:"id"`
+	Name          string         `json:"name"`
+	Description   string         `json:"description"`
+	IconURL       string         `json:"icon_url"` // Falls back to a resized or static icon when Reddit sent none
+	IconWidth     int            `json:"icon_width"`
+	IconHeight    int            `json:"icon_height"`
+	StaticIconURL string         `json:"static_icon_url"`
+	ResizedIcons  []PreviewImage `json:"resized_icons,omitempty"` // Missing for some award tiers
+	Count         int            `json:"count"`
+	CoinPrice     int            `json:"coin_price"`
+	AwardType     string         `json:"award_type"` // "global", "community" or "moderator"
+}
+
+// UnmarshalJSON decodes an awarding. Award tiers differ in which icons they
+// carry, so IconURL is filled from the largest resized icon, or else the
+// static icon, when icon_url is missing.
+func (a *Awarding) UnmarshalJSON(data []byte) error {
+	type awardingAlias Awarding
+	if err := json.Unmarshal(data, (*awardingAlias)(a)); err != nil {
+		return err
+	}
+
+	a.IconURL = html.UnescapeString(a.IconURL)
+	a.StaticIconURL = html.UnescapeString(a.StaticIconURL)
+	if len(a.ResizedIcons) == 0 {
+		a.ResizedIcons = nil
+	}
+
+	if a.IconURL == "" {
+		for _, icon := range a.ResizedIcons {
+			if icon.Width > a.IconWidth {
+				a.IconURL, a.IconWidth, a.IconHeight = icon.URL, icon.Width, icon.Height
+			}
+		}
+	}
+	if a.IconURL == "" {
+		a.IconURL = a.StaticIconURL
+	}
+
+	return nil
+}
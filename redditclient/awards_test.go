@@ -0,0 +1,81 @@
+package redditclient
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAwards_HeavilyAwardedPost(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "awarded_post.json"))
+	require.NoError(t, err)
+
+	var thread PostAndCommentsResponse
+	require.NoError(t, json.Unmarshal(data, &thread))
+	post, err := thread.Post()
+	require.NoError(t, err)
+
+	assert.Equal(t, 14, post.TotalAwardsReceived)
+	assert.Equal(t, 3, post.Gilded)
+	require.Len(t, post.AllAwardings, 4)
+
+	gold := post.AllAwardings[0]
+	assert.Equal(t, "Gold", gold.Name)
+	assert.Equal(t, "https://www.redditstatic.com/gold/awards/icon/gold_512.png", gold.IconURL)
+	assert.Equal(t, 3, gold.Count)
+	assert.Equal(t, 500, gold.CoinPrice)
+	assert.Equal(t, "global", gold.AwardType)
+	assert.Len(t, gold.ResizedIcons, 5)
+
+	helpful := post.AllAwardings[1]
+	assert.Equal(t, 8, helpful.Count)
+	assert.Equal(t, "https://preview.redd.it/award_images/t5_22cerq/klvxk1wggfd41_Helpful.png?width=16&height=16&auto=webp&s=a5662dfbdb402bf67866c050aa76c31c147c2f45", helpful.ResizedIcons[0].URL)
+
+	// A community award with null resized_icons and only a static icon
+	community := post.AllAwardings[2]
+	assert.Equal(t, "Gopher", community.Name)
+	assert.Nil(t, community.ResizedIcons)
+	assert.Equal(t, "https://i.redd.it/award_images/t5_2rc7j/gopher_static.png?s=9a8b&v=2", community.IconURL)
+	assert.Equal(t, 50, community.CoinPrice)
+
+	// An award without icon_url uses its largest resized icon
+	wholesome := post.AllAwardings[3]
+	assert.Equal(t, "https://i.redd.it/award_images/t5_22cerq/5izbv4fn0md41_Wholesome.png?width=64", wholesome.IconURL)
+	assert.Equal(t, 64, wholesome.IconWidth)
+
+	comments, err := thread.Comments()
+	require.NoError(t, err)
+	comment := comments.Data.Children[0].Comment
+	require.NotNil(t, comment)
+	assert.Equal(t, 2, comment.TotalAwardsReceived)
+	assert.Equal(t, 1, comment.Gilded)
+	require.Len(t, comment.AllAwardings, 2)
+	assert.Nil(t, comment.AllAwardings[0].ResizedIcons, "an empty resized_icons is nil")
+	assert.Equal(t, "Helpful", comment.AllAwardings[1].Name)
+}
+
+func TestAwards_Unawarded(t *testing.T) {
+	var post Post
+	require.NoError(t, json.Unmarshal([]byte(`{"id": "a", "total_awards_received": 0, "gilded": 0, "all_awardings": []}`), &post))
+
+	assert.Zero(t, post.TotalAwardsReceived)
+	assert.Empty(t, post.AllAwardings)
+}
+
+func TestAwards_RoundTrip(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "awarded_post.json"))
+	require.NoError(t, err)
+
+	var thread PostAndCommentsResponse
+	require.NoError(t, json.Unmarshal(data, &thread))
+	encoded, err := json.Marshal(thread)
+	require.NoError(t, err)
+
+	var decoded PostAndCommentsResponse
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, thread, decoded)
+}
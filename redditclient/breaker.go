@@ -0,0 +1,161 @@
+package redditclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending a request while the circuit
+// breaker is open; see WithCircuitBreaker
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitBreakerPolicy controls when the circuit breaker opens
+type CircuitBreakerPolicy struct {
+	Threshold int           // Consecutive 5xx responses or transport errors that open the breaker
+	Cooldown  time.Duration // How long the breaker stays open before a probe request is let through
+}
+
+// CircuitState is the state of the circuit breaker
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // Requests are sent normally
+	CircuitOpen                         // Requests fail fast with ErrCircuitOpen
+	CircuitHalfOpen                     // The cooldown is over; the next request probes Reddit
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitState returns the state of the circuit breaker. A client without one
+// is always CircuitClosed.
+func (c *Client) CircuitState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	return c.breaker.current(c.now())
+}
+
+// ResetCircuit closes the circuit breaker and clears its failure count
+func (c *Client) ResetCircuit() {
+	if c.breaker != nil {
+		c.breaker.reset()
+	}
+}
+
+// recordCircuit feeds the outcome of a request to the circuit breaker. A
+// request that failed because ctx ended says nothing about Reddit.
+func (c *Client) recordCircuit(ctx context.Context, resp *http.Response, err error) {
+	if err != nil && ctx.Err() != nil {
+		c.breaker.release()
+		return
+	}
+
+	failed := err != nil || resp.StatusCode >= http.StatusInternalServerError
+	if state, changed := c.breaker.record(c.now(), failed); changed {
+		c.logger.DebugContext(ctx, "circuit breaker state changed", "state", state)
+	}
+}
+
+// circuitBreaker stops requests to Reddit after Threshold consecutive
+// failures, so retry loops do not pile onto an outage. Once Cooldown has
+// passed, one probe request is let through: success closes the breaker and
+// failure opens it for another Cooldown. Only 5xx responses and transport
+// errors count as failures; any other response shows Reddit is up.
+type circuitBreaker struct {
+	policy    CircuitBreakerPolicy
+	mu        sync.Mutex
+	state     CircuitState // Stored as CircuitOpen until a probe starts
+	failures  int          // Consecutive failures
+	openUntil time.Time
+	probing   bool // A half-open probe is in flight
+}
+
+// allow returns an error wrapping ErrCircuitOpen if a request must not be
+// sent at now. A request allowed while half-open is the probe, and must be
+// followed by record or release.
+func (b *circuitBreaker) allow(now time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if now.Before(b.openUntil) {
+			return fmt.Errorf("%w: retry in %s", ErrCircuitOpen, b.openUntil.Sub(now).Round(time.Second))
+		}
+		b.state = CircuitHalfOpen
+	case CircuitClosed:
+		return nil
+	}
+
+	if b.probing {
+		return fmt.Errorf("%w: waiting on a probe request", ErrCircuitOpen)
+	}
+	b.probing = true
+	return nil
+}
+
+// record counts the outcome of a request sent at now, returning the new state
+// and whether it changed
+func (b *circuitBreaker) record(now time.Time, failed bool) (CircuitState, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	before := b.state
+	b.probing = false
+	if !failed {
+		b.state = CircuitClosed
+		b.failures = 0
+		return b.state, b.state != before
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.policy.Threshold {
+		b.state = CircuitOpen
+		b.openUntil = now.Add(b.policy.Cooldown)
+	}
+	return b.state, b.state != before
+}
+
+// release gives up a request that ended without an outcome, such as one
+// whose context was cancelled, so a half-open breaker can send another probe
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// current returns the state at now, reporting an open breaker whose cooldown
+// has passed as half-open
+func (b *circuitBreaker) current(now time.Time) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && !now.Before(b.openUntil) {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+func (b *circuitBreaker) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = CircuitClosed
+	b.failures = 0
+	b.probing = false
+}
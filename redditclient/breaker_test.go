@@ -0,0 +1,252 @@
+package redditclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker_Transitions(t *testing.T) {
+	policy := CircuitBreakerPolicy{Threshold: 3, Cooldown: time.Minute}
+
+	// Each step happens at an offset from the start of the test; allow steps
+	// ask to send a request, and the others record its outcome
+	type step struct {
+		at        time.Duration
+		event     string // allow, fail, succeed or release
+		wantAllow bool   // For allow steps
+		wantState CircuitState
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"failures below threshold stay closed", []step{
+			{0, "fail", false, CircuitClosed},
+			{0, "fail", false, CircuitClosed},
+			{0, "allow", true, CircuitClosed},
+		}},
+		{"success resets the count", []step{
+			{0, "fail", false, CircuitClosed},
+			{0, "fail", false, CircuitClosed},
+			{0, "succeed", false, CircuitClosed},
+			{0, "fail", false, CircuitClosed},
+			{0, "fail", false, CircuitClosed},
+			{0, "allow", true, CircuitClosed},
+		}},
+		{"threshold opens", []step{
+			{0, "fail", false, CircuitClosed},
+			{0, "fail", false, CircuitClosed},
+			{0, "fail", false, CircuitOpen},
+			{30 * time.Second, "allow", false, CircuitOpen},
+		}},
+		{"cooldown half-opens for one probe", []step{
+			{0, "fail", false, CircuitClosed},
+			{0, "fail", false, CircuitClosed},
+			{0, "fail", false, CircuitOpen},
+			{time.Minute, "allow", true, CircuitHalfOpen},
+			{time.Minute, "allow", false, CircuitHalfOpen},
+		}},
+		{"successful probe closes", []step{
+			{0, "fail", false, CircuitClosed},
+			{0, "fail", false, CircuitClosed},
+			{0, "fail", false, CircuitOpen},
+			{time.Minute, "allow", true, CircuitHalfOpen},
+			{time.Minute, "succeed", false, CircuitClosed},
+			{time.Minute, "allow", true, CircuitClosed},
+			{time.Minute, "allow", true, CircuitClosed},
+		}},
+		{"failed probe reopens for another cooldown", []step{
+			{0, "fail", false, CircuitClosed},
+			{0, "fail", false, CircuitClosed},
+			{0, "fail", false, CircuitOpen},
+			{2 * time.Minute, "allow", true, CircuitHalfOpen},
+			{2 * time.Minute, "fail", false, CircuitOpen},
+			{2*time.Minute + 59*time.Second, "allow", false, CircuitOpen},
+			{3 * time.Minute, "allow", true, CircuitHalfOpen},
+		}},
+		{"released probe lets another through", []step{
+			{0, "fail", false, CircuitClosed},
+			{0, "fail", false, CircuitClosed},
+			{0, "fail", false, CircuitOpen},
+			{time.Minute, "allow", true, CircuitHalfOpen},
+			{time.Minute, "release", false, CircuitHalfOpen},
+			{time.Minute, "allow", true, CircuitHalfOpen},
+		}},
+	}
+
+	start := time.Date(2024, 4, 13, 0, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &circuitBreaker{policy: policy}
+			for i, s := range tt.steps {
+				now := start.Add(s.at)
+				switch s.event {
+				case "allow":
+					err := b.allow(now)
+					if s.wantAllow {
+						assert.NoError(t, err, "step %d", i)
+					} else {
+						assert.ErrorIs(t, err, ErrCircuitOpen, "step %d", i)
+					}
+				case "fail":
+					b.record(now, true)
+				case "succeed":
+					b.record(now, false)
+				case "release":
+					b.release()
+				}
+				assert.Equal(t, s.wantState, b.current(now), "step %d", i)
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker_CurrentReportsHalfOpenAfterCooldown(t *testing.T) {
+	start := time.Now()
+	b := &circuitBreaker{policy: CircuitBreakerPolicy{Threshold: 1, Cooldown: time.Minute}}
+	b.record(start, true)
+
+	assert.Equal(t, CircuitOpen, b.current(start.Add(59*time.Second)))
+	assert.Equal(t, CircuitHalfOpen, b.current(start.Add(time.Minute)))
+}
+
+// newBreakerTestClient returns an authenticated client with a circuit breaker
+// and a clock the test moves
+func newBreakerTestClient(t *testing.T, policy CircuitBreakerPolicy) (*Client, *MockHTTPClient, *time.Time) {
+	t.Helper()
+
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP), WithCircuitBreaker(policy))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+	client.tokenExpiry = time.Now().Add(time.Hour)
+
+	now := time.Now()
+	client.now = func() time.Time { return now }
+	return client, mockHTTP, &now
+}
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	client, mockHTTP, now := newBreakerTestClient(t, CircuitBreakerPolicy{Threshold: 2, Cooldown: 30 * time.Second})
+
+	mockHTTP.On("Do", mock.Anything).Return(func(*http.Request) *http.Response {
+		return createHTTPResponse(503, "", nil)
+	}, nil).Twice()
+
+	for range 2 {
+		_, err := client.GetSubredditListing(t.Context(), "golang", SortNew, nil)
+		require.ErrorIs(t, err, ErrServerError)
+	}
+	assert.Equal(t, CircuitOpen, client.CircuitState())
+
+	// Open: no request is sent
+	_, err := client.GetSubredditListing(t.Context(), "golang", SortNew, nil)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	mockHTTP.AssertNumberOfCalls(t, "Do", 2)
+
+	// After the cooldown one probe goes through and closes the breaker
+	*now = now.Add(30 * time.Second)
+	assert.Equal(t, CircuitHalfOpen, client.CircuitState())
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(200, emptyListing, nil), nil).Once()
+	_, err = client.GetSubredditListing(t.Context(), "golang", SortNew, nil)
+	require.NoError(t, err)
+	assert.Equal(t, CircuitClosed, client.CircuitState())
+}
+
+func TestClient_CircuitBreakerIgnoresClientErrors(t *testing.T) {
+	client, mockHTTP, _ := newBreakerTestClient(t, CircuitBreakerPolicy{Threshold: 1, Cooldown: time.Minute})
+
+	mockHTTP.On("Do", mock.Anything).Return(func(*http.Request) *http.Response {
+		return createHTTPResponse(404, "", nil)
+	}, nil).Twice()
+
+	for range 2 {
+		_, err := client.GetSubredditListing(t.Context(), "golang", SortNew, nil)
+		require.ErrorIs(t, err, ErrNotFound)
+	}
+	assert.Equal(t, CircuitClosed, client.CircuitState())
+}
+
+func TestClient_CircuitBreakerCountsTransportErrors(t *testing.T) {
+	client, mockHTTP, _ := newBreakerTestClient(t, CircuitBreakerPolicy{Threshold: 1, Cooldown: time.Minute})
+
+	mockHTTP.On("Do", mock.Anything).Return((*http.Response)(nil), errors.New("connection refused")).Once()
+
+	_, err := client.GetSubredditListing(t.Context(), "golang", SortNew, nil)
+	require.Error(t, err)
+	assert.Equal(t, CircuitOpen, client.CircuitState())
+}
+
+func TestClient_CircuitBreakerIgnoresCancellation(t *testing.T) {
+	client, mockHTTP, _ := newBreakerTestClient(t, CircuitBreakerPolicy{Threshold: 1, Cooldown: time.Minute})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	mockHTTP.On("Do", mock.Anything).Run(func(mock.Arguments) { cancel() }).
+		Return((*http.Response)(nil), context.Canceled).Once()
+
+	_, err := client.GetSubredditListing(ctx, "golang", SortNew, nil)
+	require.Error(t, err)
+	assert.Equal(t, CircuitClosed, client.CircuitState())
+}
+
+func TestClient_CircuitBreakerStopsRetries(t *testing.T) {
+	client, mockHTTP, _ := newBreakerTestClient(t, CircuitBreakerPolicy{Threshold: 2, Cooldown: time.Minute})
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 5})
+	client.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+
+	mockHTTP.On("Do", mock.Anything).Return(func(*http.Request) *http.Response {
+		return createHTTPResponse(503, "", nil)
+	}, nil)
+
+	_, err := client.GetSubredditListing(t.Context(), "golang", SortNew, nil)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	mockHTTP.AssertNumberOfCalls(t, "Do", 2)
+}
+
+func TestClient_ResetCircuit(t *testing.T) {
+	client, mockHTTP, _ := newBreakerTestClient(t, CircuitBreakerPolicy{Threshold: 1, Cooldown: time.Hour})
+
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(500, "", nil), nil).Once()
+	_, err := client.GetSubredditListing(t.Context(), "golang", SortNew, nil)
+	require.Error(t, err)
+	require.Equal(t, CircuitOpen, client.CircuitState())
+
+	client.ResetCircuit()
+	assert.Equal(t, CircuitClosed, client.CircuitState())
+
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(200, emptyListing, nil), nil).Once()
+	_, err = client.GetSubredditListing(t.Context(), "golang", SortNew, nil)
+	assert.NoError(t, err)
+}
+
+func TestClient_NoCircuitBreakerByDefault(t *testing.T) {
+	client, _ := newAuthenticatedTestClient(t)
+	assert.Nil(t, client.breaker)
+	assert.Equal(t, CircuitClosed, client.CircuitState())
+	client.ResetCircuit()
+}
+
+func TestWithCircuitBreaker_Validates(t *testing.T) {
+	_, err := NewClient(WithCircuitBreaker(CircuitBreakerPolicy{Threshold: 0, Cooldown: time.Second}))
+	assert.Error(t, err)
+	_, err = NewClient(WithCircuitBreaker(CircuitBreakerPolicy{Threshold: 1}))
+	assert.Error(t, err)
+	_, err = NewClient(WithCircuitBreaker(DefaultCircuitBreakerPolicy))
+	assert.NoError(t, err)
+}
+
+func TestCircuitState_String(t *testing.T) {
+	assert.Equal(t, "closed", CircuitClosed.String())
+	assert.Equal(t, "open", CircuitOpen.String())
+	assert.Equal(t, "half-open", CircuitHalfOpen.String())
+	assert.Equal(t, "CircuitState(7)", CircuitState(7).String())
+}
@@ -0,0 +1,190 @@
+package redditclient
+
+import (
+	"container/list"
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Cache stores successful API responses so repeated requests skip the network.
+// Implementations must be safe for concurrent use. Entries are not scoped to a
+// login, so a Cache should only be shared between clients using the same account.
+type Cache interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry)
+}
+
+// CacheEntry is a cached response body and the validators Reddit sent with it
+type CacheEntry struct {
+	Body         []byte
+	Expires      time.Time // The entry is served without a request until then
+	ETag         string    // Sent as If-None-Match once the entry expires
+	LastModified string    // Sent as If-Modified-Since once the entry expires
+
+	// Restriction is the content warning the client opted past to fetch Body
+	Restriction Restriction
+}
+
+// newCacheEntry builds an entry from a response body and its headers
+func newCacheEntry(body []byte, header http.Header) CacheEntry {
+	return CacheEntry{
+		Body:         body,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+	}
+}
+
+// validators returns the conditional request headers for an expired entry,
+// or nil if it has none
+func (e CacheEntry) validators() map[string]string {
+	if e.ETag == "" && e.LastModified == "" {
+		return nil
+	}
+
+	header := make(map[string]string, 2)
+	if e.ETag != "" {
+		header["If-None-Match"] = e.ETag
+	}
+	if e.LastModified != "" {
+		header["If-Modified-Since"] = e.LastModified
+	}
+	return header
+}
+
+// revalidated returns the entry after a 304, taking any validators the server rotated
+func (e CacheEntry) revalidated(header http.Header) CacheEntry {
+	if etag := header.Get("ETag"); etag != "" {
+		e.ETag = etag
+	}
+	if lastModified := header.Get("Last-Modified"); lastModified != "" {
+		e.LastModified = lastModified
+	}
+	return e
+}
+
+// DefaultCacheEntries is the capacity of a MemoryCache created with a non-positive size
+const DefaultCacheEntries = 1000
+
+// MemoryCache is an in-process Cache that evicts the least recently used entry
+// once it is full
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // Front is most recently used
+	items      map[string]*list.Element
+}
+
+// memoryCacheItem is the value held by each element of MemoryCache.order
+type memoryCacheItem struct {
+	key   string
+	entry CacheEntry
+}
+
+// NewMemoryCache creates a MemoryCache holding at most maxEntries responses
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheEntries
+	}
+
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// Get returns the entry for key, marking it as recently used
+func (m *MemoryCache) Get(key string) (CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.items[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+	m.order.MoveToFront(elem)
+
+	return elem.Value.(*memoryCacheItem).entry, true
+}
+
+// Set stores entry under key, evicting the least recently used entry if full
+func (m *MemoryCache) Set(key string, entry CacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.items[key]; ok {
+		elem.Value.(*memoryCacheItem).entry = entry
+		m.order.MoveToFront(elem)
+		return
+	}
+
+	m.items[key] = m.order.PushFront(&memoryCacheItem{key: key, entry: entry})
+	if m.order.Len() > m.maxEntries {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.items, oldest.Value.(*memoryCacheItem).key)
+	}
+}
+
+// Len returns the number of cached entries
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.order.Len()
+}
+
+// forceFreshKey marks a context whose requests bypass the cache
+type forceFreshKey struct{}
+
+// ForceFresh returns a context whose requests skip cached responses and go to
+// the network. Their results still refresh the cache.
+func ForceFresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceFreshKey{}, true)
+}
+
+// isForceFresh reports whether ctx was created by ForceFresh
+func isForceFresh(ctx context.Context) bool {
+	fresh, _ := ctx.Value(forceFreshKey{}).(bool)
+	return fresh
+}
+
+// cacheKey identifies a GET request by endpoint and query parameters
+func cacheKey(endpoint string, params url.Values) string {
+	if len(params) == 0 {
+		return endpoint
+	}
+	return endpoint + "?" + params.Encode()
+}
+
+// cachedEntry looks up key, reporting whether the entry can be served without
+// a request. An expired entry is still returned so it can be revalidated.
+func (c *Client) cachedEntry(ctx context.Context, key string) (CacheEntry, bool) {
+	if c.cache == nil || isForceFresh(ctx) {
+		return CacheEntry{}, false
+	}
+
+	entry, ok := c.cache.Get(key)
+	if !ok {
+		return CacheEntry{}, false
+	}
+	if !c.now().Before(entry.Expires) {
+		return entry, false
+	}
+
+	c.logger.DebugContext(ctx, "cache hit", "key", key)
+	return entry, true
+}
+
+// storeEntry caches entry under key for the cache TTL, unless ctx carries NoCache
+func (c *Client) storeEntry(ctx context.Context, key string, entry CacheEntry) {
+	if c.cache == nil || callOptionsFrom(ctx).private() {
+		return
+	}
+
+	entry.Expires = c.now().Add(c.cacheTTL)
+	c.cache.Set(key, entry)
+}
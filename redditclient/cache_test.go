@@ -0,0 +1,308 @@
+package redditclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Helper function to create an authenticated client with a memory cache and a controllable clock
+func newCachingTestClient(t *testing.T, ttl time.Duration) (*Client, *MockHTTPClient, *time.Time) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP), WithCache(NewMemoryCache(10), ttl))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return now }
+
+	return client, mockHTTP, &now
+}
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewMemoryCache(2)
+
+	cache.Set("a", CacheEntry{Body: []byte("a")})
+	cache.Set("b", CacheEntry{Body: []byte("b")})
+
+	// Touching a makes b the eviction candidate
+	_, ok := cache.Get("a")
+	require.True(t, ok)
+	cache.Set("c", CacheEntry{Body: []byte("c")})
+
+	assert.Equal(t, 2, cache.Len())
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	entry, ok := cache.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, []byte("a"), entry.Body)
+	_, ok = cache.Get("c")
+	assert.True(t, ok)
+}
+
+func TestMemoryCache_SetReplacesEntry(t *testing.T) {
+	cache := NewMemoryCache(2)
+
+	cache.Set("a", CacheEntry{Body: []byte("old")})
+	cache.Set("a", CacheEntry{Body: []byte("new")})
+
+	entry, ok := cache.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, []byte("new"), entry.Body)
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestMemoryCache_DefaultSize(t *testing.T) {
+	cache := NewMemoryCache(0)
+
+	for i := range DefaultCacheEntries + 1 {
+		cache.Set(fmt.Sprint(i), CacheEntry{})
+	}
+
+	assert.Equal(t, DefaultCacheEntries, cache.Len())
+}
+
+func TestCache_HitSkipsNetworkAndRateLimit(t *testing.T) {
+	client, mockHTTP, now := newCachingTestClient(t, time.Minute)
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, createListingPage([]string{"a"}, ""), nil), nil).Once()
+
+	first, err := client.GetSubreddit(t.Context(), "golang", "hot")
+	require.NoError(t, err)
+
+	// Exhaust the quota; a hit must neither wait nor request
+	client.SetRateLimitBehavior(WaitOnLimit)
+	client.rateLimit = 0
+	client.rateLimitReset = now.Add(5 * time.Minute)
+	client.sleep = func(context.Context, time.Duration) error {
+		t.Fatal("cache hit waited for the rate limit")
+		return nil
+	}
+	*now = now.Add(30 * time.Second)
+
+	second, err := client.GetSubreddit(t.Context(), "golang", "hot")
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	mockHTTP.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestCache_ExpiredEntryRefetches(t *testing.T) {
+	client, mockHTTP, now := newCachingTestClient(t, time.Minute)
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, createListingPage([]string{"old"}, ""), nil), nil).Once()
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, createListingPage([]string{"new"}, ""), nil), nil).Once()
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+	require.NoError(t, err)
+
+	*now = now.Add(time.Minute)
+
+	result, err := client.GetSubreddit(t.Context(), "golang", "hot")
+	require.NoError(t, err)
+	require.Len(t, result.Data.Children, 1)
+	assert.Equal(t, "new", result.Data.Children[0].Data.ID)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestCache_ForceFreshBypassesAndRefreshes(t *testing.T) {
+	client, mockHTTP, _ := newCachingTestClient(t, time.Minute)
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, createListingPage([]string{"old"}, ""), nil), nil).Once()
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, createListingPage([]string{"new"}, ""), nil), nil).Once()
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+	require.NoError(t, err)
+
+	fresh, err := client.GetSubreddit(ForceFresh(t.Context()), "golang", "hot")
+	require.NoError(t, err)
+	assert.Equal(t, "new", fresh.Data.Children[0].Data.ID)
+
+	// The forced fetch replaced the cached entry
+	cached, err := client.GetSubreddit(t.Context(), "golang", "hot")
+	require.NoError(t, err)
+	assert.Equal(t, "new", cached.Data.Children[0].Data.ID)
+	mockHTTP.AssertExpectations(t)
+}
+
+// hasHeader matches requests carrying header with the given value, or lacking it when value is empty
+func hasHeader(header, value string) interface{} {
+	return mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get(header) == value
+	})
+}
+
+func TestCache_RevalidatesWithETag(t *testing.T) {
+	client, mockHTTP, now := newCachingTestClient(t, time.Minute)
+
+	mockHTTP.On("Do", hasHeader("If-None-Match", "")).
+		Return(createHTTPResponse(200, createListingPage([]string{"a"}, ""), map[string]string{
+			"ETag": `"v1"`,
+		}), nil).Once()
+	mockHTTP.On("Do", hasHeader("If-None-Match", `"v1"`)).
+		Return(createHTTPResponse(http.StatusNotModified, "", nil), nil).Once()
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+	require.NoError(t, err)
+
+	*now = now.Add(2 * time.Minute)
+	result, err := client.GetSubreddit(t.Context(), "golang", "hot")
+	require.NoError(t, err)
+	require.Len(t, result.Data.Children, 1)
+	assert.Equal(t, "a", result.Data.Children[0].Data.ID)
+
+	// The 304 renewed the TTL, so this is served without a request
+	*now = now.Add(30 * time.Second)
+	_, err = client.GetSubreddit(t.Context(), "golang", "hot")
+	require.NoError(t, err)
+
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestCache_RevalidatesWithLastModified(t *testing.T) {
+	client, mockHTTP, now := newCachingTestClient(t, time.Minute)
+	lastModified := now.Add(-time.Hour).Format(http.TimeFormat)
+
+	mockHTTP.On("Do", hasHeader("If-Modified-Since", "")).
+		Return(createHTTPResponse(200, createListingPage([]string{"a"}, ""), map[string]string{
+			"Last-Modified": lastModified,
+		}), nil).Once()
+	mockHTTP.On("Do", hasHeader("If-Modified-Since", lastModified)).
+		Return(createHTTPResponse(http.StatusNotModified, "", nil), nil).Once()
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+	require.NoError(t, err)
+
+	*now = now.Add(2 * time.Minute)
+	result, err := client.GetSubreddit(t.Context(), "golang", "hot")
+	require.NoError(t, err)
+	assert.Equal(t, "a", result.Data.Children[0].Data.ID)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestCache_ETagRotation(t *testing.T) {
+	client, mockHTTP, now := newCachingTestClient(t, time.Minute)
+
+	mockHTTP.On("Do", hasHeader("If-None-Match", "")).
+		Return(createHTTPResponse(200, createListingPage([]string{"a"}, ""), map[string]string{
+			"ETag": `"v1"`,
+		}), nil).Once()
+	// Changed content arrives with a new ETag
+	mockHTTP.On("Do", hasHeader("If-None-Match", `"v1"`)).
+		Return(createHTTPResponse(200, createListingPage([]string{"b"}, ""), map[string]string{
+			"ETag": `"v2"`,
+		}), nil).Once()
+	// A 304 may also rotate the ETag
+	mockHTTP.On("Do", hasHeader("If-None-Match", `"v2"`)).
+		Return(createHTTPResponse(http.StatusNotModified, "", map[string]string{
+			"ETag": `"v3"`,
+		}), nil).Once()
+	mockHTTP.On("Do", hasHeader("If-None-Match", `"v3"`)).
+		Return(createHTTPResponse(http.StatusNotModified, "", nil), nil).Once()
+
+	ids := []string{}
+	for range 4 {
+		result, err := client.GetSubreddit(t.Context(), "golang", "hot")
+		require.NoError(t, err)
+		ids = append(ids, result.Data.Children[0].Data.ID)
+		*now = now.Add(2 * time.Minute)
+	}
+
+	assert.Equal(t, []string{"a", "b", "b", "b"}, ids)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestCache_NoValidatorsRefetchesUnconditionally(t *testing.T) {
+	client, mockHTTP, now := newCachingTestClient(t, time.Minute)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("If-None-Match") == "" && req.Header.Get("If-Modified-Since") == ""
+	})).Return(func(*http.Request) *http.Response {
+		return createHTTPResponse(200, emptyListing, nil)
+	}, nil).Twice()
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+	require.NoError(t, err)
+
+	*now = now.Add(2 * time.Minute)
+	_, err = client.GetSubreddit(t.Context(), "golang", "hot")
+	require.NoError(t, err)
+
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestCache_UnexpectedNotModifiedIsAnError(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(http.StatusNotModified, "", nil), nil).Once()
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotModified, apiErr.StatusCode)
+}
+
+func TestCache_KeyedByEndpointAndParams(t *testing.T) {
+	client, mockHTTP, _ := newCachingTestClient(t, time.Minute)
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(func(req *http.Request) *http.Response {
+			return createHTTPResponse(200, createListingPage([]string{req.URL.Path + req.URL.Query().Get("after")}, ""), nil)
+		}, nil).Times(3)
+
+	_, err := client.GetSubredditPage(t.Context(), "golang", "hot", nil)
+	require.NoError(t, err)
+	_, err = client.GetSubredditPage(t.Context(), "golang", "hot", &ListingOptions{After: "t3_x"})
+	require.NoError(t, err)
+	_, err = client.GetSubredditPage(t.Context(), "golang", "new", nil)
+	require.NoError(t, err)
+
+	// Repeats of all three are hits
+	page, err := client.GetSubredditPage(t.Context(), "golang", "hot", &ListingOptions{After: "t3_x"})
+	require.NoError(t, err)
+	assert.Equal(t, "/r/golang/hot.jsont3_x", page.Data.Children[0].Data.ID)
+	_, err = client.GetSubredditPage(t.Context(), "golang", "hot", nil)
+	require.NoError(t, err)
+	_, err = client.GetSubredditPage(t.Context(), "golang", "new", nil)
+	require.NoError(t, err)
+
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestCache_ErrorsAreNotCached(t *testing.T) {
+	client, mockHTTP, _ := newCachingTestClient(t, time.Minute)
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(404, `{"message": "Not Found", "error": 404}`, nil), nil).Once()
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, emptyListing, nil), nil).Once()
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = client.GetSubreddit(t.Context(), "golang", "hot")
+	assert.NoError(t, err)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestWithCache_Invalid(t *testing.T) {
+	_, err := NewClient(WithCache(nil, time.Minute))
+	assert.Error(t, err)
+
+	_, err = NewClient(WithCache(NewMemoryCache(1), 0))
+	assert.Error(t, err)
+}
@@ -0,0 +1,486 @@
+package redditclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
+)
+
+// NewClient creates a new Reddit client configured by opts
+func NewClient(opts ...Option) (*Client, error) {
+	cfg := clientConfig{
+		clientID: ANDROID_CLIENT_ID,
+		scopes:   DefaultScopes,
+		baseURL:  DefaultBaseURL,
+		authURL:  DefaultAuthURL,
+
+		maxRespBytes: DefaultMaxResponseBytes,
+		maxErrorBody: DefaultMaxErrorBodyBytes,
+	}
+	for _, opt := range opts {
+		if err := opt(&cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	rng := newRand(cfg.randSource)
+	if cfg.deviceID == "" {
+		id, err := uuid.NewRandomFromReader(randReader{rng})
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate device ID: %w", err)
+		}
+		cfg.deviceID = id.String()
+	}
+	qos := cfg.qos
+	if qos == nil {
+		qos = UniformQoS
+	}
+
+	userAgentPool := cfg.userAgentPool
+	if userAgentPool == nil {
+		userAgentPool = androidVersions
+	}
+	userAgent := cfg.userAgent
+	if userAgent == "" {
+		userAgent = pickUserAgent(userAgentPool, cfg.deviceID, 0)
+	} else if cfg.rotateUserAgent {
+		return nil, errors.New("WithUserAgentRotation cannot be combined with WithUserAgent")
+	}
+
+	httpClient, err := cfg.buildHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var flight *singleflight.Group
+	if cfg.coalesce {
+		flight = &singleflight.Group{}
+	}
+
+	logger := cfg.logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
+	telemetry, err := newTelemetry(cfg.tracerProvider, cfg.meterProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up telemetry: %w", err)
+	}
+
+	var breaker *circuitBreaker
+	if cfg.breaker != nil {
+		breaker = &circuitBreaker{policy: *cfg.breaker}
+	}
+
+	contentOptIn := cfg.contentOptIn
+	if contentOptIn == nil {
+		contentOptIn = []Restriction{RestrictionGated, RestrictionQuarantined}
+	}
+
+	c := &Client{
+		httpClient:     httpClient,
+		authenticated:  false,
+		deviceID:       cfg.deviceID,
+		clientID:       cfg.clientID,
+		scopes:         cfg.scopes,
+		userAgent:      userAgent,
+		userAgentPool:  userAgentPool,
+		rotateUA:       cfg.rotateUserAgent,
+		baseURL:        cfg.baseURL,
+		authURL:        cfg.authURL,
+		logger:         logger,
+		refreshMargin:  DefaultTokenRefreshMargin,
+		now:            time.Now,
+		sleep:          sleepContext,
+		rng:            rng,
+		qos:            qos,
+		rateLimit:      100, // Start with assumed full rate limit
+		rateLimitMode:  cfg.rateLimitMode,
+		rateLimitFloor: DefaultRateLimitFloor,
+		maxRespBytes:   cfg.maxRespBytes,
+		maxErrorBody:   cfg.maxErrorBody,
+		cache:          cfg.cache,
+		cacheTTL:       cfg.cacheTTL,
+		rawText:        cfg.rawText,
+		contentOptIn:   contentOptIn,
+		flight:         flight,
+		telemetry:      telemetry,
+		breaker:        breaker,
+	}
+	c.SetRetryPolicy(cfg.retryPolicy)
+	return c, nil
+}
+
+// buildHTTPClient returns the configured HTTP client, applying the timeout
+func (cfg *clientConfig) buildHTTPClient() (HTTPClient, error) {
+	timeout := cfg.timeout
+	if cfg.httpClient == nil {
+		if timeout == 0 {
+			timeout = DefaultTimeout
+		}
+		transport := newOrderedTransport()
+		transport.proxy = cfg.proxy
+		if transport.proxy == nil {
+			transport.proxy = http.ProxyFromEnvironment
+		}
+		return &http.Client{Timeout: timeout, Transport: transport}, nil
+	}
+
+	if cfg.proxy != nil {
+		return nil, errors.New("WithProxy and WithProxyFunc cannot be combined with WithHTTPClient")
+	}
+
+	if timeout == 0 {
+		return cfg.httpClient, nil
+	}
+
+	hc, ok := cfg.httpClient.(*http.Client)
+	if !ok {
+		return nil, fmt.Errorf("WithTimeout requires an *http.Client, got %T", cfg.httpClient)
+	}
+
+	withTimeout := *hc
+	withTimeout.Timeout = timeout
+	return &withTimeout, nil
+}
+
+// setHeaders sets headers on req. The default transport writes them in the
+// order and casing of androidHeaderOrder; see orderedTransport.
+func (c *Client) setHeaders(req *http.Request, headers map[string]string) {
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+}
+
+// makeAPIRequest handles common API request logic, serving from the cache when
+// one is configured and revalidating expired entries that carry validators.
+// With coalescing enabled, concurrent identical requests share one fetch.
+func (c *Client) makeAPIRequest(ctx context.Context, endpoint string, params url.Values) ([]byte, error) {
+	entry, err := c.apiEntry(ctx, endpoint, params)
+	return entry.Body, err
+}
+
+// apiEntry is makeAPIRequest returning the whole cache entry, which also
+// records the content warning the body was fetched past
+func (c *Client) apiEntry(ctx context.Context, endpoint string, params url.Values) (entry CacheEntry, err error) {
+	ctx, call := c.startCall(ctx, http.MethodGet, endpoint, params)
+	defer func() { call.end(c, err) }()
+
+	perCall := callOptionsFrom(ctx)
+	ctx, cancel := perCall.withTimeout(ctx)
+	defer cancel()
+
+	key := cacheKey(endpoint, params)
+	if perCall.private() {
+		return c.fetchAPIResponse(ctx, endpoint, params, key, CacheEntry{})
+	}
+
+	cached, fresh := c.cachedEntry(ctx, key)
+	if c.cache != nil {
+		call.cached(fresh)
+	}
+	if fresh {
+		return cached, nil
+	}
+
+	return c.coalesced(ctx, key, func(ctx context.Context) (CacheEntry, error) {
+		return c.fetchAPIResponse(ctx, endpoint, params, key, cached)
+	})
+}
+
+// fetchAPIResponse requests endpoint and caches the result under key. cached is
+// the expired entry to revalidate, if any.
+func (c *Client) fetchAPIResponse(ctx context.Context, endpoint string, params url.Values, key string, cached CacheEntry) (CacheEntry, error) {
+	req, resp, body, err := c.sendAPIRequest(ctx, endpoint, params, requestOptions{header: cached.validators()})
+	if err != nil {
+		return CacheEntry{}, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		c.logger.DebugContext(ctx, "cache revalidated", "key", key)
+		entry := cached.revalidated(resp.Header)
+		c.storeEntry(ctx, key, entry)
+		return entry, nil
+	}
+
+	// Check for restricted content errors
+	if notice := restrictionNotice(body); notice.Reason != "" {
+		body, restriction, err := c.handleRestrictedContent(ctx, req, notice)
+		if err != nil {
+			return CacheEntry{}, err
+		}
+		entry := CacheEntry{Body: body, Restriction: restriction}
+		c.storeEntry(ctx, key, entry)
+		return entry, nil
+	}
+
+	if err := jsonErrors(endpoint, body); err != nil {
+		return CacheEntry{}, c.withErrorBody(err, body)
+	}
+
+	entry := newCacheEntry(body, resp.Header)
+	c.storeEntry(ctx, key, entry)
+	return entry, nil
+}
+
+// makeFormRequest sends a form-encoded POST to endpoint with api_type=json and
+// returns the response body. It bypasses the cache and coalescing, and turns
+// errors Reddit reports in the json.errors array of a 200 response into Go
+// errors, see formErrors.
+func (c *Client) makeFormRequest(ctx context.Context, endpoint string, form url.Values) (_ []byte, err error) {
+	ctx, call := c.startCall(ctx, http.MethodPost, endpoint, nil)
+	defer func() { call.end(c, err) }()
+
+	ctx, cancel := callOptionsFrom(ctx).withTimeout(ctx)
+	defer cancel()
+
+	if form == nil {
+		form = url.Values{}
+	}
+	form.Set("api_type", "json")
+
+	_, _, body, err := c.sendAPIRequest(ctx, endpoint, nil, requestOptions{form: form})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := formErrors(endpoint, body); err != nil {
+		return nil, c.withErrorBody(err, body)
+	}
+
+	return body, nil
+}
+
+// requestOptions adjusts a single API request
+type requestOptions struct {
+	stream   bool              // Leave a 200 body unread for the caller to stream
+	redirect bool              // Return a 3xx response instead of an error, for the caller to follow
+	header   map[string]string // Extra request headers, such as cache validators
+	form     url.Values        // Sent as a form-encoded POST body instead of a GET
+}
+
+// sendAPIRequest sends an authenticated request with retries and a single
+// re-authentication on 401, turning any non-200 status into an APIError. A 304
+// is passed through when the request carried validators, and a 3xx when
+// opts.redirect is set. With opts.stream set,
+// a 200 response is returned with its body unread and the caller must close it;
+// otherwise the body is read and returned.
+func (c *Client) sendAPIRequest(ctx context.Context, endpoint string, params url.Values, opts requestOptions) (*http.Request, *http.Response, []byte, error) {
+	if err := checkEndpoint(endpoint); err != nil {
+		return nil, nil, nil, err
+	}
+	if c.credentials().accessToken == "" {
+		return nil, nil, nil, fmt.Errorf("not authenticated")
+	}
+
+	if err := c.refreshTokenIfNeeded(ctx); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("raw_json", "1")
+
+	fullURL := c.baseURL + endpoint
+	if len(params) > 0 {
+		fullURL += "?" + params.Encode()
+	}
+
+	req, resp, body, err := c.doAPIRequestWithRetry(ctx, fullURL, opts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// The token may have been invalidated server-side; re-authenticate and replay once
+	if resp.StatusCode == http.StatusUnauthorized {
+		staleToken := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+		c.logger.DebugContext(ctx, "re-authenticating after 401", "endpoint", endpoint)
+		if err := c.reauthenticate(ctx, staleToken); err != nil {
+			return nil, nil, nil, fmt.Errorf("re-authentication after 401 failed: %w", err)
+		}
+
+		req, resp, body, err = c.doAPIRequestWithRetry(ctx, fullURL, opts)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	if resp.StatusCode == http.StatusNotModified && len(opts.header) > 0 {
+		return req, resp, nil, nil
+	}
+	if opts.redirect && isRedirect(resp.StatusCode) {
+		return req, resp, body, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, nil, c.statusError(endpoint, resp, body)
+	}
+
+	return req, resp, body, nil
+}
+
+// checkEndpoint rejects an endpoint with a "." or ".." segment. Names are
+// escaped into their segments, so these only come from a name that is a dot
+// segment in whole, which would otherwise climb out of its path.
+func checkEndpoint(endpoint string) error {
+	for segment := range strings.SplitSeq(endpoint, "/") {
+		if segment == "." || segment == ".." {
+			return fmt.Errorf("%w: endpoint %q has a dot segment", ErrInvalidArgument, endpoint)
+		}
+	}
+	return nil
+}
+
+// doAPIRequest builds a fresh authenticated request and reads its response. It
+// is a GET unless opts.form is set, in which case the form is POSTed. With
+// opts.stream set, a 200 response is returned with its body left open.
+func (c *Client) doAPIRequest(ctx context.Context, fullURL string, opts requestOptions) (*http.Request, *http.Response, []byte, error) {
+	if err := c.waitForRateLimit(ctx); err != nil {
+		return nil, nil, nil, err
+	}
+
+	method, reqBody := "GET", io.Reader(nil)
+	if opts.form != nil {
+		method, reqBody = "POST", strings.NewReader(opts.form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, reqBody)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	callOptionsFrom(ctx).setHeaders(req)
+
+	creds := c.credentials()
+	headers := map[string]string{
+		"Authorization":   "Bearer " + creds.accessToken,
+		"User-Agent":      creds.userAgent,
+		"Accept-Encoding": acceptEncoding,
+	}
+	if opts.form != nil {
+		headers["Content-Type"] = "application/x-www-form-urlencoded"
+	}
+	// Only the anonymous app session is tied to a loid; script tokens are plain OAuth
+	if creds.mode != AuthScript {
+		headers["x-reddit-loid"] = creds.loid
+		headers["x-reddit-session"] = creds.session
+	}
+	for k, v := range opts.header {
+		headers[k] = v
+	}
+
+	c.setHeaders(req, headers)
+
+	c.logger.DebugContext(ctx, "reddit request", slog.Any("", RequestInfo{
+		Method:   req.Method,
+		Endpoint: req.URL.Path,
+		Header:   req.Header,
+	}))
+
+	if c.breaker != nil {
+		if err := c.breaker.allow(c.now()); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	start := c.now()
+	resp, err := c.httpClient.Do(req)
+	if c.breaker != nil {
+		c.recordCircuit(ctx, resp, err)
+	}
+	if err != nil {
+		c.logger.DebugContext(ctx, "reddit request failed", "endpoint", req.URL.Path, "error", err)
+		return nil, nil, nil, fmt.Errorf("API request failed: %w", err)
+	}
+	c.updateRateLimit(ctx, resp.Header)
+	if c.telemetry != nil {
+		callFrom(ctx).observe(resp)
+	}
+
+	if opts.stream && resp.StatusCode == http.StatusOK {
+		c.logger.DebugContext(ctx, "reddit response",
+			"endpoint", req.URL.Path,
+			"status", resp.StatusCode,
+			"streamed", true,
+			"ratelimit_remaining", resp.Header.Get("x-ratelimit-remaining"),
+			"duration", c.now().Sub(start))
+
+		return req, resp, nil, nil
+	}
+	defer resp.Body.Close()
+
+	body, err := c.readResponseBody(resp)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	c.logger.DebugContext(ctx, "reddit response",
+		"endpoint", req.URL.Path,
+		"status", resp.StatusCode,
+		"bytes", len(body),
+		"ratelimit_remaining", resp.Header.Get("x-ratelimit-remaining"),
+		"duration", c.now().Sub(start))
+
+	return req, resp, body, nil
+}
+
+// handleRestrictedContent handles a restriction notice in a 200 body. Gated and
+// quarantined content the client opts into is requested again with the
+// matching opt-in cookie, and the result goes back through the same check, so
+// a subreddit that is both gets both cookies. It returns the body and the
+// restriction first opted past. A notice repeated after opting in is reported
+// as a *StillRestrictedError; other reasons become a typed error straight away.
+func (c *Client) handleRestrictedContent(ctx context.Context, originalReq *http.Request, notice ErrorResponse) ([]byte, Restriction, error) {
+	endpoint := originalReq.URL.Path
+
+	var accepted []Restriction
+	for {
+		restriction := Restriction(notice.Reason)
+		if !slices.Contains(c.contentOptIn, restriction) || slices.Contains(accepted, restriction) {
+			err := restrictionError(endpoint, notice)
+			if len(accepted) > 0 {
+				err = &StillRestrictedError{Reason: notice.Reason, Err: err}
+			}
+			return nil, "", err
+		}
+		accepted = append(accepted, restriction)
+
+		c.logger.DebugContext(ctx, "retrying with content opt-in", "endpoint", endpoint, "reason", notice.Reason)
+		body, err := c.retryWithOptIn(ctx, originalReq, accepted)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if notice = restrictionNotice(body); notice.Reason == "" {
+			return body, accepted[0], nil
+		}
+	}
+}
+
+// retryWithOptIn requests originalReq's URL again with the cookie that
+// accepts restrictions and returns the 200 response body. It goes through
+// doAPIRequest, so the retry waits for and updates the rate limit, passes the
+// circuit breaker and is recorded in the call's telemetry. Restriction notices
+// only answer GETs, so the original request has no body to resend.
+func (c *Client) retryWithOptIn(ctx context.Context, originalReq *http.Request, restrictions []Restriction) ([]byte, error) {
+	opts := requestOptions{header: map[string]string{"Cookie": optInCookie(restrictions)}}
+	_, resp, body, err := c.doAPIRequest(ctx, originalReq.URL.String(), opts)
+	if err != nil {
+		return nil, fmt.Errorf("retry request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.statusError(originalReq.URL.Path, resp, body)
+	}
+
+	return body, nil
+}
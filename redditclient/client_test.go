@@ -0,0 +1,1273 @@
+package redditclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockHTTPClient for testing
+type MockHTTPClient struct {
+	mock.Mock
+}
+
+func (m *MockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	args := m.Called(req)
+	// Allow a response factory so repeated calls each get a fresh body
+	if fn, ok := args.Get(0).(func(*http.Request) *http.Response); ok {
+		return fn(req), args.Error(1)
+	}
+	return args.Get(0).(*http.Response), args.Error(1)
+}
+
+// Helper function to create HTTP response
+func createHTTPResponse(statusCode int, body string, headers map[string]string) *http.Response {
+	resp := &http.Response{
+		StatusCode: statusCode,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Header:     make(http.Header),
+	}
+
+	for k, v := range headers {
+		resp.Header.Set(k, v)
+	}
+
+	return resp
+}
+
+// Helper function to create a client that skips the auth handshake
+func newAuthenticatedTestClient(t *testing.T) (*Client, *MockHTTPClient) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+	return client, mockHTTP
+}
+
+func TestNewClient(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+
+	require.NoError(t, err)
+	assert.NotNil(t, client)
+	assert.Equal(t, mockHTTP, client.httpClient)
+	assert.NotEmpty(t, client.deviceID)
+	assert.NotEmpty(t, client.userAgent)
+	assert.Equal(t, 100.0, client.rateLimit)
+}
+
+func TestNewClientWithNilHTTPClient(t *testing.T) {
+	client, err := NewClient(WithHTTPClient(nil))
+
+	require.NoError(t, err)
+	assert.NotNil(t, client)
+	assert.NotNil(t, client.httpClient)
+	assert.IsType(t, &http.Client{}, client.httpClient)
+}
+
+func TestAuthenticate_Success(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	client := newFakeServerClient(t, srv)
+
+	err := client.Authenticate(t.Context())
+
+	require.NoError(t, err)
+	assert.Equal(t, "fake-token", client.accessToken)
+	assert.Equal(t, "fake-loid", client.loid)
+	assert.Equal(t, "fake-session", client.session)
+	assert.True(t, client.IsTokenValid())
+
+	req, ok := srv.LastRequest("/auth/v2/oauth/access-token/loid")
+	require.True(t, ok)
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, client.userAgent, req.Header.Get("User-Agent"))
+	assert.Equal(t, client.deviceID, req.Header.Get("X-Reddit-Device-Id"))
+	assert.Equal(t, client.deviceID, req.Header.Get("Client-Vendor-Id"))
+	assert.JSONEq(t, `{"scopes": ["*", "email", "pii"]}`, req.Body)
+}
+
+func TestAuthenticate_RejectedCredentials(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	srv.Respond("/auth/v2/oauth/access-token/loid", http.StatusUnauthorized, `{"message": "Unauthorized", "error": 401}`)
+	client := newFakeServerClient(t, srv)
+
+	err := client.Authenticate(t.Context())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "authentication failed with status: 401")
+	assert.False(t, client.IsTokenValid())
+}
+
+func TestAuthenticate_HTTPError(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Context() == t.Context()
+	})).Return((*http.Response)(nil), fmt.Errorf("network error"))
+
+	err = client.Authenticate(t.Context())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "authentication request failed")
+	assert.Empty(t, client.accessToken)
+}
+
+func TestAuthenticate_BadStatusCode(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(401, "Unauthorized", nil), nil)
+
+	err = client.Authenticate(t.Context())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "authentication failed with status: 401")
+	assert.Empty(t, client.accessToken)
+}
+
+func TestGetSubreddit_Success(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.RespondWith("/r/golang/hot.json", fakeResponse{
+		Status: http.StatusOK,
+		Body:   createListingPage([]string{"test123"}, "t3_test123"),
+		Header: map[string]string{"x-ratelimit-remaining": "50"},
+	})
+
+	result, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Listing", result.Kind)
+	require.Len(t, result.Data.Children, 1)
+	assert.Equal(t, "Post test123", result.Data.Children[0].Data.Title)
+	assert.Equal(t, "t3_test123", result.Data.After)
+	assert.Equal(t, 50.0, client.RateLimitStatus().Remaining)
+
+	req, ok := srv.LastRequest("/r/golang/hot.json")
+	require.True(t, ok)
+	assert.Equal(t, "Bearer fake-token", req.Header.Get("Authorization"))
+	assert.Equal(t, "fake-loid", req.Header.Get("x-reddit-loid"))
+	assert.Equal(t, "fake-session", req.Header.Get("x-reddit-session"))
+	assert.Equal(t, "1", req.Query.Get("raw_json"))
+}
+
+func TestGetSubreddit_GzipEndToEnd(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.SetGzip(true)
+	srv.Respond("/r/golang/new.json", http.StatusOK, createListingPage([]string{"a", "b"}, ""))
+
+	result, err := client.GetSubredditPage(t.Context(), "golang", "new", &ListingOptions{After: "t3_x y", Limit: 2})
+
+	require.NoError(t, err)
+	require.Len(t, result.Data.Children, 2)
+
+	req, ok := srv.LastRequest("/r/golang/new.json")
+	require.True(t, ok)
+	assert.Contains(t, req.Header.Get("Accept-Encoding"), "gzip")
+	assert.Equal(t, "t3_x y", req.Query.Get("after"))
+	assert.Equal(t, "2", req.Query.Get("limit"))
+}
+
+func TestGetSubreddit_ReauthenticatesEndToEnd(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+
+	// The server rotates the token, so the next API request is rejected
+	srv.SetToken("rotated-token")
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+	require.NoError(t, err)
+	assert.Equal(t, "rotated-token", client.accessToken)
+
+	var paths []string
+	for _, req := range srv.Requests() {
+		paths = append(paths, req.Path)
+	}
+	assert.Equal(t, []string{
+		"/auth/v2/oauth/access-token/loid",
+		"/r/golang/hot.json",
+		"/auth/v2/oauth/access-token/loid",
+		"/r/golang/hot.json",
+	}, paths)
+}
+
+func TestGetSubreddit_NotAuthenticated(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+
+	result, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrNotAuthenticated)
+}
+
+func TestGetPost_NotAuthenticated(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+
+	result, err := client.GetPost(t.Context(), "golang", "abc123")
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrNotAuthenticated)
+}
+
+func TestGetUser_NotAuthenticated(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+
+	result, err := client.GetUser(t.Context(), "testuser")
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrNotAuthenticated)
+}
+
+func TestSearch_NotAuthenticated(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+
+	result, err := client.Search(t.Context(), "golang", "top", "week")
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrNotAuthenticated)
+}
+
+func TestGetPost_Success(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+
+	// Captured from /r/golang/comments/1c3xk9p.json?limit=1: the post's
+	// listing followed by a comment listing, which GetPost must not mistake
+	// for a single object
+	responseBody, err := os.ReadFile(filepath.Join("testdata", "post.json"))
+	require.NoError(t, err)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/r/golang/comments/1c3xk9p.json" && req.URL.Query().Get("limit") == "1"
+	})).Return(createHTTPResponse(200, string(responseBody), nil), nil)
+
+	post, err := client.GetPost(t.Context(), "golang", "1c3xk9p")
+
+	require.NoError(t, err)
+	assert.Equal(t, "t3_1c3xk9p", post.Name)
+	assert.Equal(t, "How do you structure a large Go monorepo?", post.Title)
+	assert.Equal(t, "busy_builder", post.Author)
+	assert.Equal(t, 58, post.Score)
+	assert.Equal(t, 31, post.NumComments)
+	assert.Equal(t, "help", post.LinkFlairText)
+	assert.Equal(t, Edited{Edited: true, At: 1712970000}, post.Edited)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGetPost_EmptyListing(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+
+	body := `[{"kind": "Listing", "data": {"children": []}}, {"kind": "Listing", "data": {"children": []}}]`
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(200, body, nil), nil)
+
+	post, err := client.GetPost(t.Context(), "golang", "1c3xk9p")
+
+	assert.Nil(t, post)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestGetUser_Success(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+
+	userResponse := UserResponse{
+		Kind: "t2",
+		Data: UserAbout{
+			Name:         "testuser",
+			LinkKarma:    100,
+			CommentKarma: 200,
+			Created:      1640995200.0,
+		},
+	}
+	responseBody, _ := json.Marshal(userResponse)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return strings.Contains(req.URL.String(), "/user/testuser/about.json")
+	})).Return(createHTTPResponse(200, string(responseBody), nil), nil)
+
+	result, err := client.GetUser(t.Context(), "testuser")
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, "testuser", result.Data.Name)
+	assert.Equal(t, 100, result.Data.LinkKarma)
+	assert.Equal(t, 200, result.Data.CommentKarma)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestSearch_Success(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+
+	searchResponse := SearchResponse{
+		Kind: "Listing",
+		Data: PostListingData{
+			Children: []PostChild{
+				{
+					Kind: "t3",
+					Data: Post{
+						ID:    "search123",
+						Title: "Search Result",
+						Score: 25,
+					},
+				},
+			},
+		},
+	}
+	responseBody, _ := json.Marshal(searchResponse)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		u, _ := url.Parse(req.URL.String())
+		params := u.Query()
+		return strings.Contains(req.URL.String(), "/search.json") &&
+			params.Get("q") == "golang" &&
+			params.Get("sort") == "top" &&
+			params.Get("t") == "week"
+	})).Return(createHTTPResponse(200, string(responseBody), nil), nil)
+
+	result, err := client.Search(t.Context(), "golang", "top", "week")
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Len(t, result.Data.Children, 1)
+	assert.Equal(t, "Search Result", result.Data.Children[0].Data.Title)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestHandleRestrictedContent_Gated(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+
+	// First response with gated content
+	gatedResponse := `{"reason": "gated"}`
+	// Second response with actual content
+	actualContent := `{"kind": "Listing", "data": {"children": []}}`
+
+	// Mock the initial request that returns gated content
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return !strings.Contains(req.Header.Get("Cookie"), "pref_gated_sr_optin")
+	})).Return(createHTTPResponse(200, gatedResponse, nil), nil).Once()
+
+	// Mock the retry request with cookie
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return strings.Contains(req.Header.Get("Cookie"), "pref_gated_sr_optin")
+	})).Return(createHTTPResponse(200, actualContent, nil), nil).Once()
+
+	result, err := client.GetSubreddit(t.Context(), "gatedsubreddit", "hot")
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestHandleRestrictedContent_Private(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+
+	privateResponse := `{"reason": "private"}`
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, privateResponse, nil), nil)
+
+	result, err := client.GetSubreddit(t.Context(), "privatesubreddit", "hot")
+
+	assert.ErrorIs(t, err, ErrSubredditPrivate)
+	assert.Nil(t, result)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestSetHeaders(t *testing.T) {
+	client, err := NewClient()
+	require.NoError(t, err)
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+
+	headers := map[string]string{
+		"Header1": "Value1",
+		"Header2": "Value2",
+		"Header3": "Value3",
+	}
+
+	client.setHeaders(req, headers)
+
+	// Verify all headers are set
+	for k, v := range headers {
+		assert.Equal(t, v, req.Header.Get(k))
+	}
+}
+
+// Integration-style test for the complete authentication flow
+func TestAuthenticationFlow_Integration(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+
+	// Mock successful authentication
+	oauthResponse := OAuthResponse{
+		AccessToken: "integration-token",
+		TokenType:   "bearer",
+		ExpiresIn:   3600,
+		Scope:       []string{"* email pii"},
+	}
+	responseBody, _ := json.Marshal(oauthResponse)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		// Verify the request carries the caller's context
+		if req.Context() != t.Context() {
+			return false
+		}
+
+		// Verify the request contains all required headers
+		hasAuth := req.Header.Get("Authorization") != ""
+		hasUserAgent := req.Header.Get("User-Agent") != ""
+		hasDeviceID := req.Header.Get("X-Reddit-Device-Id") != ""
+		hasContentType := req.Header.Get("Content-Type") == "application/json; charset=UTF-8"
+
+		// Verify request body
+		body, _ := io.ReadAll(req.Body)
+		req.Body = io.NopCloser(bytes.NewReader(body)) // Reset body for actual use
+		var reqBody map[string]interface{}
+		json.Unmarshal(body, &reqBody)
+		hasCorrectScopes := len(reqBody["scopes"].([]interface{})) == 3
+
+		return hasAuth && hasUserAgent && hasDeviceID && hasContentType && hasCorrectScopes
+	})).Return(createHTTPResponse(200, string(responseBody), map[string]string{
+		"x-reddit-loid":    "integration-loid",
+		"x-reddit-session": "integration-session",
+	}), nil)
+
+	err = client.Authenticate(t.Context())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "integration-token", client.accessToken)
+	assert.Equal(t, "integration-loid", client.loid)
+	assert.Equal(t, "integration-session", client.session)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestAuthenticationFlow_CustomClientIDAndScopes(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP), WithClientID("rotated-id"), WithScopes("read", "identity"))
+	require.NoError(t, err)
+
+	var scopes []string
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		body, _ := io.ReadAll(req.Body)
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		var reqBody struct {
+			Scopes []string `json:"scopes"`
+		}
+		if json.Unmarshal(body, &reqBody) != nil {
+			return false
+		}
+		scopes = reqBody.Scopes
+
+		id, secret, ok := req.BasicAuth()
+		return ok && id == "rotated-id" && secret == ""
+	})).Return(createOAuthResponse("custom-token", 3600), nil).Once()
+
+	require.NoError(t, client.Authenticate(t.Context()))
+
+	assert.Equal(t, []string{"read", "identity"}, scopes)
+	assert.Equal(t, "custom-token", client.accessToken)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestNewClient_Success(t *testing.T) {
+	// Test that NewClient creates a client successfully
+	client, err := NewClient()
+
+	require.NoError(t, err)
+	assert.NotNil(t, client)
+	assert.NotNil(t, client.httpClient)
+	assert.NotEmpty(t, client.deviceID)
+	assert.NotEmpty(t, client.userAgent)
+}
+
+func TestGzipDecompression(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+
+	// Create gzipped response content
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	testContent := `{"kind": "Listing", "data": {"children": [{"kind": "t3", "data": {"id": "test123", "title": "Test Gzip Post", "score": 42}}]}}`
+	_, err = gzipWriter.Write([]byte(testContent))
+	require.NoError(t, err)
+	err = gzipWriter.Close()
+	require.NoError(t, err)
+
+	// Mock gzipped response
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, buf.String(), map[string]string{
+			"Content-Encoding": "gzip",
+		}), nil).Once()
+
+	// Request should properly decompress gzipped content
+	result, err := client.GetSubreddit(t.Context(), "test", "hot")
+	require.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, "Listing", result.Kind)
+	assert.Len(t, result.Data.Children, 1)
+	assert.Equal(t, "Test Gzip Post", result.Data.Children[0].Data.Title)
+	assert.Equal(t, 42, result.Data.Children[0].Data.Score)
+
+	// Mock second gzipped response to test pool reuse
+	var buf2 bytes.Buffer
+	gzipWriter2 := gzip.NewWriter(&buf2)
+	testContent2 := `{"kind": "Listing", "data": {"children": [{"kind": "t3", "data": {"id": "test456", "title": "Another Gzip Post", "score": 84}}]}}`
+	_, err = gzipWriter2.Write([]byte(testContent2))
+	require.NoError(t, err)
+	err = gzipWriter2.Close()
+	require.NoError(t, err)
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, buf2.String(), map[string]string{
+			"Content-Encoding": "gzip",
+		}), nil).Once()
+
+	// Second request should also work with pooled gzip readers
+	result2, err := client.GetSubreddit(t.Context(), "test2", "hot")
+	require.NoError(t, err)
+	assert.NotNil(t, result2)
+	assert.Equal(t, "Listing", result2.Kind)
+	assert.Len(t, result2.Data.Children, 1)
+	assert.Equal(t, "Another Gzip Post", result2.Data.Children[0].Data.Title)
+	assert.Equal(t, 84, result2.Data.Children[0].Data.Score)
+
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestMultipleGzipRequests(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+
+	// Helper function to create gzipped response content
+	createGzippedResponse := func(title string) string {
+		var buf bytes.Buffer
+		gzipWriter := gzip.NewWriter(&buf)
+		testContent := fmt.Sprintf(`{"kind": "Listing", "data": {"children": [{"kind": "t3", "data": {"id": "test", "title": "%s", "score": 100}}]}}`, title)
+		gzipWriter.Write([]byte(testContent))
+		gzipWriter.Close()
+		return buf.String()
+	}
+
+	// Mock each response individually with fresh gzipped content
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, createGzippedResponse("Request 1"), map[string]string{
+			"Content-Encoding": "gzip",
+		}), nil).Once()
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, createGzippedResponse("Request 2"), map[string]string{
+			"Content-Encoding": "gzip",
+		}), nil).Once()
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, createGzippedResponse("Request 3"), map[string]string{
+			"Content-Encoding": "gzip",
+		}), nil).Once()
+
+	// Test sequential requests to verify pool reuse works
+	for i := 1; i <= 3; i++ {
+		result, err := client.GetSubreddit(t.Context(), fmt.Sprintf("test%d", i), "hot")
+		require.NoError(t, err, "Request %d should succeed", i)
+		require.NotNil(t, result, "Result %d should not be nil", i)
+		require.Len(t, result.Data.Children, 1, "Result %d should have one child", i)
+		expectedTitle := fmt.Sprintf("Request %d", i)
+		assert.Equal(t, expectedTitle, result.Data.Children[0].Data.Title, "Request %d should have correct title", i)
+	}
+
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGzipTruncatedBodyDoesNotPoisonPool(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+
+	gzipBody := func(content string) []byte {
+		var buf bytes.Buffer
+		gzipWriter := gzip.NewWriter(&buf)
+		_, err := gzipWriter.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, gzipWriter.Close())
+		return buf.Bytes()
+	}
+
+	truncated := gzipBody(`{"kind": "Listing", "data": {"children": [{"kind": "t3", "data": {"id": "cut", "title": "Truncated Post"}}]}}`)
+	truncated = truncated[:len(truncated)/2]
+	valid := gzipBody(`{"kind": "Listing", "data": {"children": [{"kind": "t3", "data": {"id": "ok", "title": "Valid Post", "score": 7}}]}}`)
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, string(truncated), map[string]string{
+			"Content-Encoding": "gzip",
+		}), nil).Once()
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, string(valid), map[string]string{
+			"Content-Encoding": "gzip",
+		}), nil).Once()
+
+	_, err = client.GetSubreddit(t.Context(), "test", "hot")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// The reader that failed must not be handed to the next response
+	result, err := client.GetSubreddit(t.Context(), "test", "hot")
+	require.NoError(t, err)
+	require.Len(t, result.Data.Children, 1)
+	assert.Equal(t, "Valid Post", result.Data.Children[0].Data.Title)
+	assert.Equal(t, 7, result.Data.Children[0].Data.Score)
+
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestContextCancellation(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+
+	// Create a cancelled context
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel() // Cancel immediately
+
+	// Mock the HTTP client to return context canceled error
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return((*http.Response)(nil), context.Canceled)
+
+	// Test that API call respects cancelled context
+	_, err = client.GetSubreddit(ctx, "golang", "hot")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "context canceled")
+
+	// Test that authentication respects cancelled context
+	err = client.Authenticate(ctx)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "context canceled")
+
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestContextTimeout(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+
+	// Create a context with a very short timeout
+	ctx, cancel := context.WithTimeout(t.Context(), 1*time.Millisecond)
+	defer cancel()
+
+	// Sleep to ensure timeout occurs
+	time.Sleep(5 * time.Millisecond)
+
+	// Mock the HTTP client to return context deadline exceeded error
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return((*http.Response)(nil), context.DeadlineExceeded)
+
+	// Test that API call respects timeout
+	_, err = client.GetSubreddit(ctx, "golang", "hot")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "context deadline exceeded")
+
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestContextPropagation(t *testing.T) {
+	testCtx := t.Context() // Store test context in variable to avoid conflicts
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+
+	listing := SubredditListing{
+		Kind: "Listing",
+		Data: PostListingData{
+			Children: []PostChild{
+				{
+					Kind: "t3",
+					Data: Post{
+						ID:        "test123",
+						Title:     "Test Post",
+						Author:    "testuser",
+						Subreddit: "golang",
+						Score:     42,
+					},
+				},
+			},
+		},
+	}
+	responseBody, _ := json.Marshal(listing)
+
+	// Verify that the context is properly propagated to the HTTP request
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		// Check that the request context is derived from our test context
+		reqCtx := req.Context()
+		assert.NotNil(t, reqCtx)
+
+		// The request context should have the same deadline as our test context (if any)
+		if deadline, ok := testCtx.Deadline(); ok {
+			reqDeadline, reqOk := reqCtx.Deadline()
+			assert.True(t, reqOk)
+			assert.Equal(t, deadline, reqDeadline)
+		}
+
+		return strings.Contains(req.URL.String(), "/r/golang/hot.json")
+	})).Return(createHTTPResponse(200, string(responseBody), map[string]string{
+		"x-ratelimit-remaining": "50",
+	}), nil)
+
+	result, err := client.GetSubreddit(testCtx, "golang", "hot")
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, "Listing", result.Kind)
+	mockHTTP.AssertExpectations(t)
+}
+
+// TestTestContextTimeout demonstrates how test timeouts work with t.Context()
+// Run with: go test -v -run TestTestContextTimeout -timeout 100ms
+func TestTestContextTimeout(t *testing.T) {
+	// Skip this test during normal runs to avoid timeout failures
+	if testing.Short() {
+		t.Skip("skipping timeout test in short mode")
+	}
+
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+
+	// Mock a slow HTTP response that would exceed the test timeout
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		// Check if the context has a deadline (it should from test timeout)
+		deadline, hasDeadline := req.Context().Deadline()
+		if hasDeadline {
+			t.Logf("Request context has deadline: %v", deadline)
+		} else {
+			t.Log("Request context has no deadline")
+		}
+		return true
+	})).Return(createHTTPResponse(200, `{"kind": "Listing", "data": {"children": []}}`, nil), nil)
+
+	// This should work normally, but if you run with a very short timeout,
+	// the test context will have a deadline that gets propagated to the request
+	_, err = client.GetSubreddit(t.Context(), "golang", "hot")
+	assert.NoError(t, err)
+
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestAuthenticate_CancelledContext(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Context().Err() == context.Canceled
+	})).Return((*http.Response)(nil), context.Canceled)
+
+	err = client.Authenticate(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, client.authenticated)
+	mockHTTP.AssertExpectations(t)
+}
+
+// Helper function to create a successful OAuth response
+func createOAuthResponse(token string, expiresIn int) *http.Response {
+	responseBody, _ := json.Marshal(OAuthResponse{
+		AccessToken: token,
+		TokenType:   "bearer",
+		ExpiresIn:   expiresIn,
+		Scope:       []string{"*", "email", "pii"},
+	})
+
+	return createHTTPResponse(200, string(responseBody), map[string]string{
+		"x-reddit-loid":    "test-loid",
+		"x-reddit-session": "test-session",
+	})
+}
+
+func isAuthRequest(req *http.Request) bool {
+	return strings.Contains(req.URL.String(), "/auth/v2/oauth/access-token/loid")
+}
+
+func TestAuthenticate_StoresTokenExpiry(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return now }
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createOAuthResponse("test-token", 3600), nil)
+
+	require.NoError(t, client.Authenticate(t.Context()))
+
+	assert.Equal(t, now.Add(time.Hour), client.tokenExpiry)
+	assert.True(t, client.IsTokenValid())
+
+	now = now.Add(time.Hour)
+	assert.False(t, client.IsTokenValid())
+}
+
+func TestIsTokenValid_NotAuthenticated(t *testing.T) {
+	client, err := NewClient(WithHTTPClient(&MockHTTPClient{}))
+	require.NoError(t, err)
+
+	assert.False(t, client.IsTokenValid())
+}
+
+func TestMakeAPIRequest_RefreshesExpiringToken(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return now }
+	client.accessToken = "old-token"
+	client.authenticated = true
+	client.tokenExpiry = now.Add(30 * time.Second) // inside the default 60s margin
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return isAuthRequest(req) && req.Context() == t.Context()
+	})).Return(createOAuthResponse("new-token", 3600), nil).Once()
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return strings.Contains(req.URL.String(), "/r/golang/hot.json") &&
+			req.Header.Get("Authorization") == "Bearer new-token"
+	})).Return(createHTTPResponse(200, `{"kind": "Listing", "data": {"children": []}}`, nil), nil).Once()
+
+	_, err = client.GetSubreddit(t.Context(), "golang", "hot")
+
+	require.NoError(t, err)
+	assert.Equal(t, "new-token", client.accessToken)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestMakeAPIRequest_NoRefreshOutsideMargin(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return now }
+	client.accessToken = "test-token"
+	client.authenticated = true
+	client.tokenExpiry = now.Add(30 * time.Second)
+	client.SetTokenRefreshMargin(10 * time.Second)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return !isAuthRequest(req)
+	})).Return(createHTTPResponse(200, `{"kind": "Listing", "data": {"children": []}}`, nil), nil).Once()
+
+	_, err = client.GetSubreddit(t.Context(), "golang", "hot")
+
+	require.NoError(t, err)
+	assert.Equal(t, "test-token", client.accessToken)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestMakeAPIRequest_RefreshFailure(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return now }
+	client.accessToken = "old-token"
+	client.authenticated = true
+	client.tokenExpiry = now.Add(-time.Minute)
+
+	mockHTTP.On("Do", mock.MatchedBy(isAuthRequest)).
+		Return(createHTTPResponse(500, "oops", nil), nil).Once()
+
+	_, err = client.GetSubreddit(t.Context(), "golang", "hot")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to refresh access token")
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestMakeAPIRequest_ConcurrentRefreshOnce(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return start }
+	client.accessToken = "old-token"
+	client.authenticated = true
+	client.tokenExpiry = start
+
+	// Only one refresh may happen; any further auth call would fail the test
+	mockHTTP.On("Do", mock.MatchedBy(isAuthRequest)).
+		Return(createOAuthResponse("new-token", 3600), nil).Once()
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return !isAuthRequest(req)
+	})).Return(func(*http.Request) *http.Response {
+		return createHTTPResponse(200, `{"kind": "Listing", "data": {"children": []}}`, nil)
+	}, nil)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	mockHTTP.AssertNumberOfCalls(t, "Do", 11)
+}
+
+func TestMakeAPIRequest_ReauthenticatesOn401(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+	client.accessToken = "stale-token"
+	client.authenticated = true
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("Authorization") == "Bearer stale-token"
+	})).Return(createHTTPResponse(401, `{"message": "Unauthorized", "error": 401}`, nil), nil).Once()
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return isAuthRequest(req) && req.Context() == t.Context()
+	})).Return(createOAuthResponse("fresh-token", 3600), nil).Once()
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return strings.Contains(req.URL.String(), "/r/golang/hot.json") &&
+			req.Header.Get("Authorization") == "Bearer fresh-token"
+	})).Return(createHTTPResponse(200, `{"kind": "Listing", "data": {"children": []}}`, nil), nil).Once()
+
+	result, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Listing", result.Kind)
+	assert.Equal(t, "fresh-token", client.accessToken)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestMakeAPIRequest_401RetriedOnlyOnce(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+	client.accessToken = "stale-token"
+	client.authenticated = true
+
+	mockHTTP.On("Do", mock.MatchedBy(isAuthRequest)).
+		Return(createOAuthResponse("fresh-token", 3600), nil).Once()
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return !isAuthRequest(req)
+	})).Return(func(*http.Request) *http.Response {
+		return createHTTPResponse(401, "Unauthorized", nil)
+	}, nil).Twice()
+
+	_, err = client.GetSubreddit(t.Context(), "golang", "hot")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 401")
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestMakeAPIRequest_401ReauthFailure(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+	client.accessToken = "stale-token"
+	client.authenticated = true
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return !isAuthRequest(req)
+	})).Return(createHTTPResponse(401, "Unauthorized", nil), nil).Once()
+
+	mockHTTP.On("Do", mock.MatchedBy(isAuthRequest)).
+		Return(createHTTPResponse(403, "Forbidden", nil), nil).Once()
+
+	_, err = client.GetSubreddit(t.Context(), "golang", "hot")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "re-authentication after 401 failed")
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGetSubredditPage_Pagination(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+
+	responseBody := `{"kind": "Listing", "data": {"children": [], "after": "t3_next", "before": "t3_prev"}}`
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		params := req.URL.Query()
+		return req.URL.Path == "/r/golang/new.json" &&
+			params.Get("after") == "t3_xxx" &&
+			params.Get("limit") == "100" &&
+			params.Get("count") == "25" &&
+			!params.Has("before")
+	})).Return(createHTTPResponse(200, responseBody, nil), nil)
+
+	result, err := client.GetSubredditPage(t.Context(), "golang", "new", &ListingOptions{
+		After: "t3_xxx",
+		Limit: 100,
+		Count: 25,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "t3_next", result.Data.After)
+	assert.Equal(t, "t3_prev", result.Data.Before)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGetSubredditPage_NilOptions(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		params := req.URL.Query()
+		return !params.Has("after") && !params.Has("before") && !params.Has("limit") && !params.Has("count")
+	})).Return(createHTTPResponse(200, `{"kind": "Listing", "data": {"children": []}}`, nil), nil)
+
+	_, err = client.GetSubredditPage(t.Context(), "golang", "hot", nil)
+
+	require.NoError(t, err)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestHandleRestrictedContent_StillRestricted(t *testing.T) {
+	tests := []struct {
+		name   string
+		retry  string
+		reason string
+		want   error
+	}{
+		{"still gated", `{"reason": "gated"}`, "gated", ErrSubredditGated},
+		{"now private", `{"reason": "private"}`, "private", ErrSubredditPrivate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mockHTTP := newAuthenticatedTestClient(t)
+			mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+				return req.Header.Get("Cookie") == ""
+			})).Return(createHTTPResponse(200, `{"reason": "gated"}`, nil), nil).Once()
+			mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+				return req.Header.Get("Cookie") == GATED_ACCEPT_COOKIE
+			})).Return(createHTTPResponse(200, tt.retry, nil), nil).Once()
+
+			_, err := client.GetSubreddit(t.Context(), "gatedsubreddit", "hot")
+
+			assert.ErrorIs(t, err, ErrStillRestricted)
+			assert.ErrorIs(t, err, tt.want)
+			var stillErr *StillRestrictedError
+			require.ErrorAs(t, err, &stillErr)
+			assert.Equal(t, tt.reason, stillErr.Reason)
+			mockHTTP.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleRestrictedContent_LargeRetryIsNotANotice(t *testing.T) {
+	// A body too large to be a notice is content, on the retry as on the
+	// first response, whatever fields it has
+	listing := `{"kind": "Listing", "reason": "gated", "data": {"children": [], "padding": "` +
+		strings.Repeat("x", maxRestrictionSniff) + `"}}`
+
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("Cookie") == ""
+	})).Return(createHTTPResponse(200, `{"reason": "gated"}`, nil), nil).Once()
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("Cookie") == GATED_ACCEPT_COOKIE
+	})).Return(createHTTPResponse(200, listing, nil), nil).Once()
+
+	_, err := client.GetSubreddit(t.Context(), "gatedsubreddit", "hot")
+
+	require.NoError(t, err)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestRetryWithOptIn_GoesThroughRequestPipeline(t *testing.T) {
+	client, mockHTTP, _, _ := newTelemetryTestClient(t, WithContentOptIn(RestrictionGated))
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("Cookie") == ""
+	})).Return(createHTTPResponse(200, `{"reason": "gated"}`, map[string]string{
+		"x-ratelimit-remaining": "50",
+	}), nil).Once()
+
+	var retried *http.Request
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("Cookie") != ""
+	})).Run(func(args mock.Arguments) { retried = args.Get(0).(*http.Request) }).
+		Return(createHTTPResponse(200, emptyListing, map[string]string{
+			"x-ratelimit-remaining": "49",
+			"x-ratelimit-used":      "551",
+		}), nil).Once()
+
+	_, err := client.GetSubreddit(WithRequestOptions(t.Context(), ExtraHeader("X-Trace", "abc")), "gatedsubreddit", "hot")
+	require.NoError(t, err)
+	mockHTTP.AssertExpectations(t)
+
+	// The retry is built like any other request
+	require.NotNil(t, retried)
+	assert.Equal(t, http.MethodGet, retried.Method)
+	assert.Equal(t, "/r/gatedsubreddit/hot.json", retried.URL.Path)
+	assert.Equal(t, "Bearer test-token", retried.Header.Get("Authorization"))
+	assert.Equal(t, "abc", retried.Header.Get("X-Trace"))
+	assert.Contains(t, retried.Header.Get("Cookie"), "pref_gated_sr_optin")
+	assert.NotNil(t, callFrom(retried.Context()), "the retry carries the call's telemetry")
+
+	// and its rate-limit headers are recorded
+	assert.InDelta(t, 49, client.RateLimitStatus().Remaining, 0)
+}
+
+func TestRetryWithOptIn_CountsTowardCircuitBreaker(t *testing.T) {
+	client, mockHTTP, _ := newBreakerTestClient(t, CircuitBreakerPolicy{Threshold: 1, Cooldown: time.Minute})
+	client.contentOptIn = []Restriction{RestrictionGated}
+
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(200, `{"reason": "gated"}`, nil), nil).Once()
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(503, "", nil), nil).Once()
+
+	_, err := client.GetSubredditListing(t.Context(), "gatedsubreddit", SortHot, nil)
+	require.ErrorIs(t, err, ErrServerError)
+	assert.Equal(t, CircuitOpen, client.CircuitState())
+}
+
+func TestClient_EscapesPathSegments(t *testing.T) {
+	const injected = "golang/../../api/v1/me?"
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context, c *Client) error
+		want string
+	}{
+		{"subreddit", func(ctx context.Context, c *Client) error {
+			_, err := c.GetSubredditListing(ctx, injected, SortHot, nil)
+			return err
+		}, "/r/golang%2F..%2F..%2Fapi%2Fv1%2Fme%3F/hot.json"},
+		{"user", func(ctx context.Context, c *Client) error {
+			_, err := c.GetUser(ctx, injected)
+			return err
+		}, "/user/golang%2F..%2F..%2Fapi%2Fv1%2Fme%3F/about.json"},
+		{"comments", func(ctx context.Context, c *Client) error {
+			_, err := c.GetCommentsWithOptions(ctx, "golang", "abc#frag", nil)
+			return err
+		}, "/r/golang/comments/abc%23frag.json"},
+		{"multireddit", func(ctx context.Context, c *Client) error {
+			_, err := c.GetMultiListing(ctx, "gopher", "a/b", "hot", nil)
+			return err
+		}, "/user/gopher/m/a%2Fb/hot.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mockHTTP := newAuthenticatedTestClient(t)
+
+			var got *http.Request
+			mockHTTP.On("Do", mock.Anything).Return(func(req *http.Request) *http.Response {
+				got = req
+				return createHTTPResponse(404, "", nil)
+			}, nil).Once()
+
+			_ = tt.call(t.Context(), client)
+			require.NotNil(t, got)
+			assert.Equal(t, tt.want, got.URL.EscapedPath())
+			assert.Equal(t, "1", got.URL.Query().Get("raw_json"), "the query is not the injected one")
+		})
+	}
+}
+
+func TestClient_RejectsDotSegments(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	for _, name := range []string{".", ".."} {
+		_, err := client.GetUser(t.Context(), name)
+		assert.ErrorIs(t, err, ErrInvalidArgument, name)
+		_, err = client.GetSubredditListing(t.Context(), name, SortHot, nil)
+		assert.ErrorIs(t, err, ErrInvalidArgument, name)
+	}
+	mockHTTP.AssertNotCalled(t, "Do", mock.Anything)
+}
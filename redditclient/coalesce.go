@@ -0,0 +1,28 @@
+package redditclient
+
+import "context"
+
+// coalesced runs fetch, sharing one call among concurrent callers with the same
+// key when coalescing is enabled. The shared fetch ignores cancellation so one
+// caller giving up does not fail the others; each caller still stops waiting
+// when its own ctx ends.
+func (c *Client) coalesced(ctx context.Context, key string, fetch func(ctx context.Context) (CacheEntry, error)) (CacheEntry, error) {
+	if c.flight == nil {
+		return fetch(ctx)
+	}
+
+	shared := context.WithoutCancel(ctx)
+	ch := c.flight.DoChan(key, func() (interface{}, error) {
+		return fetch(shared)
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return CacheEntry{}, res.Err
+		}
+		return res.Val.(CacheEntry), nil
+	case <-ctx.Done():
+		return CacheEntry{}, ctx.Err()
+	}
+}
@@ -0,0 +1,120 @@
+package redditclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCoalescingFakeServerClient returns an authenticated client with coalescing
+// enabled, pointed at a fake server that answers API requests after delay
+func newCoalescingFakeServerClient(t *testing.T, delay time.Duration) (*Client, *fakeRedditServer) {
+	t.Helper()
+
+	srv := newFakeRedditServer(t)
+	client, err := NewClient(WithBaseURL(srv.URL), WithRequestCoalescing())
+	require.NoError(t, err)
+	require.NoError(t, client.Authenticate(t.Context()))
+
+	srv.SetDelay(delay)
+	return client, srv
+}
+
+// apiRequestCount counts requests the server received for path
+func apiRequestCount(srv *fakeRedditServer, path string) int {
+	count := 0
+	for _, req := range srv.Requests() {
+		if req.Path == path {
+			count++
+		}
+	}
+	return count
+}
+
+func TestCoalescing_ConcurrentCallersShareOneRequest(t *testing.T) {
+	client, srv := newCoalescingFakeServerClient(t, 200*time.Millisecond)
+	srv.Respond("/r/golang/hot.json", 200, createListingPage([]string{"a", "b"}, ""))
+
+	const callers = 10
+	results := make([]*SubredditListing, callers)
+	errs := make([]error, callers)
+
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = client.GetSubreddit(t.Context(), "golang", "hot")
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, apiRequestCount(srv, "/r/golang/hot.json"))
+	for i := range callers {
+		require.NoError(t, errs[i])
+		require.Len(t, results[i].Data.Children, 2)
+	}
+
+	// Every caller decodes its own copy
+	results[0].Data.Children[0].Data.Title = "changed"
+	assert.Equal(t, "Post a", results[1].Data.Children[0].Data.Title)
+}
+
+func TestCoalescing_DifferentRequestsAreNotShared(t *testing.T) {
+	client, srv := newCoalescingFakeServerClient(t, 50*time.Millisecond)
+
+	var wg sync.WaitGroup
+	for _, sort := range []string{"hot", "new"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetSubreddit(t.Context(), "golang", sort)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, apiRequestCount(srv, "/r/golang/hot.json"))
+	assert.Equal(t, 1, apiRequestCount(srv, "/r/golang/new.json"))
+}
+
+func TestCoalescing_CancelledCallerDoesNotAbortOthers(t *testing.T) {
+	client, srv := newCoalescingFakeServerClient(t, 200*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancelledErr := make(chan error, 1)
+	go func() {
+		_, err := client.GetSubreddit(ctx, "golang", "hot")
+		cancelledErr <- err
+	}()
+
+	// Join the in-flight request, then abandon the first caller
+	time.Sleep(50 * time.Millisecond)
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+		done <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	assert.True(t, errors.Is(<-cancelledErr, context.Canceled))
+	assert.NoError(t, <-done)
+	assert.Equal(t, 1, apiRequestCount(srv, "/r/golang/hot.json"))
+}
+
+func TestCoalescing_SequentialCallsRefetch(t *testing.T) {
+	client, srv := newCoalescingFakeServerClient(t, 0)
+
+	for range 2 {
+		_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, apiRequestCount(srv, "/r/golang/hot.json"))
+}
@@ -0,0 +1,286 @@
+package redditclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// GetComments fetches a post and its comment tree using the given sort order.
+// It is a string-typed wrapper around GetCommentsWithOptions.
+func (c *Client) GetComments(ctx context.Context, subreddit, postID, sort string) (*PostAndCommentsResponse, error) {
+	return c.GetCommentsWithOptions(ctx, subreddit, postID, &CommentOptions{Sort: CommentSort(sort)})
+}
+
+// GetCommentsWithOptions fetches a post and its comment tree, limited by opts
+func (c *Client) GetCommentsWithOptions(ctx context.Context, subreddit, postID string, opts *CommentOptions) (*PostAndCommentsResponse, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	if opts != nil {
+		if err := validateOptional(opts.Sort, opts.Sort.validate); err != nil {
+			return nil, err
+		}
+	}
+
+	endpoint := fmt.Sprintf("/r/%s/comments/%s.json", url.PathEscape(subreddit), url.PathEscape(postID))
+
+	return c.getCommentThread(ctx, endpoint, opts)
+}
+
+// GetComment fetches a single comment, such as the target of a permalink
+// returned by ResolveURL, with its replies. The comment listing is rooted at
+// the contextDepth'th ancestor of the comment (at most 8), or at the comment
+// itself when contextDepth is zero.
+func (c *Client) GetComment(ctx context.Context, subreddit, postID, commentID string, contextDepth int) (*PostAndCommentsResponse, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	commentID = strings.TrimPrefix(commentID, KindComment+"_")
+	if commentID == "" {
+		return nil, fmt.Errorf("%w: empty comment ID", ErrInvalidArgument)
+	}
+	if contextDepth < 0 || contextDepth > maxCommentContext {
+		return nil, fmt.Errorf("%w: context depth %d is outside 0-%d", ErrInvalidArgument, contextDepth, maxCommentContext)
+	}
+
+	endpoint := fmt.Sprintf("/r/%s/comments/%s/comment/%s.json", url.PathEscape(subreddit), url.PathEscape(postID), url.PathEscape(commentID))
+
+	return c.getCommentThread(ctx, endpoint, &CommentOptions{Context: contextDepth})
+}
+
+// getCommentThread fetches and decodes a post and comments endpoint
+func (c *Client) getCommentThread(ctx context.Context, endpoint string, opts *CommentOptions) (*PostAndCommentsResponse, error) {
+	params := url.Values{}
+	opts.apply(params)
+
+	var comments PostAndCommentsResponse
+	if err := c.streamAPIRequest(ctx, endpoint, params, &comments, "comments"); err != nil {
+		return nil, err
+	}
+
+	return &comments, nil
+}
+
+// GetMoreComments fetches the comments behind a "more" placeholder. linkID is
+// the fullname of the post (t3_...) and children are the placeholder's IDs.
+func (c *Client) GetMoreComments(ctx context.Context, linkID string, children []string) (*MoreChildrenResponse, error) {
+	return c.GetMoreCommentsWithSort(ctx, linkID, children, "")
+}
+
+// GetMoreCommentsWithSort is GetMoreComments with the comments ordered by
+// sort; an empty sort leaves the order to Reddit. Reddit accepts at most 100
+// IDs per request, so larger placeholders are fetched in sequential batches
+// whose comments are merged in order.
+func (c *Client) GetMoreCommentsWithSort(ctx context.Context, linkID string, children []string, sort CommentSort) (*MoreChildrenResponse, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	if err := validateOptional(sort, sort.validate); err != nil {
+		return nil, err
+	}
+
+	var merged MoreChildrenResponse
+	for start := 0; start < len(children); start += maxMoreChildren {
+		end := min(start+maxMoreChildren, len(children))
+		more, err := c.getMoreChildren(ctx, linkID, children[start:end], sort)
+		if err != nil {
+			return nil, err
+		}
+		merged.JSON.Data.Things = append(merged.JSON.Data.Things, more.JSON.Data.Things...)
+	}
+
+	return &merged, nil
+}
+
+// getMoreChildren makes a single /api/morechildren request
+func (c *Client) getMoreChildren(ctx context.Context, linkID string, children []string, sort CommentSort) (*MoreChildrenResponse, error) {
+	params := url.Values{
+		"api_type":       []string{"json"},
+		"link_id":        []string{linkID},
+		"children":       []string{strings.Join(children, ",")},
+		"limit_children": []string{"false"},
+	}
+	if sort != "" {
+		params.Set("sort", string(sort))
+	}
+
+	body, err := c.makeAPIRequest(ctx, "/api/morechildren.json", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var more MoreChildrenResponse
+	if err := c.decodeJSON(ctx, body, &more, "more comments"); err != nil {
+		return nil, err
+	}
+	return &more, nil
+}
+
+// GetAllComments fetches a thread and resolves its "more" placeholders,
+// returning the expanded comment tree. Each morechildren batch and each
+// "continue this thread" fetch counts against opts.MaxExpansions; any
+// placeholders left when the budget runs out remain in the tree.
+func (c *Client) GetAllComments(ctx context.Context, subreddit, postID string, opts *CommentOptions) (*CommentListing, error) {
+	thread, err := c.GetCommentsWithOptions(ctx, subreddit, postID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.expandComments(ctx, subreddit, postID, &thread.CommentListing, opts); err != nil {
+		return nil, err
+	}
+
+	return &thread.CommentListing, nil
+}
+
+// expandComments does the work of GetAllComments, resolving the "more"
+// placeholders of a fetched thread's comments in place
+func (c *Client) expandComments(ctx context.Context, subreddit, postID string, comments *CommentListing, opts *CommentOptions) error {
+	budget := DefaultMaxCommentExpansions
+	if opts != nil && opts.MaxExpansions > 0 {
+		budget = opts.MaxExpansions
+	}
+	var sort CommentSort
+	if opts != nil {
+		sort = opts.Sort
+	}
+
+	postID = strings.TrimPrefix(postID, KindPost+"_")
+	tree := newCommentTree(comments, KindPost+"_"+postID)
+
+	for len(tree.pending) > 0 && budget > 0 {
+		p := tree.pending[0]
+		tree.pending = tree.pending[1:]
+
+		if len(p.more.Children) == 0 {
+			// "continue this thread": refetch the thread rooted at the parent comment
+			parentID := strings.TrimPrefix(p.more.ParentID, KindComment+"_")
+			endpoint := fmt.Sprintf("/r/%s/comments/%s/comment/%s.json", url.PathEscape(subreddit), url.PathEscape(postID), url.PathEscape(parentID))
+			sub, err := c.getCommentThread(ctx, endpoint, opts)
+			budget--
+			if err != nil {
+				return err
+			}
+
+			tree.remove(p)
+			for _, child := range sub.CommentListing.Data.Children {
+				if child.Comment != nil && child.Comment.Name == p.more.ParentID && child.Comment.Replies != nil {
+					for _, reply := range child.Comment.Replies.Data.Children {
+						tree.attach(reply, p.listing)
+					}
+				}
+			}
+			continue
+		}
+
+		tree.remove(p)
+		for start := 0; start < len(p.more.Children); start += maxMoreChildren {
+			if budget == 0 {
+				// Put the unfetched remainder back as a placeholder
+				rest := *p.more
+				rest.Children = p.more.Children[start:]
+				rest.Count = len(rest.Children)
+				tree.attach(CommentChild{Kind: KindMore, More: &rest}, p.listing)
+				break
+			}
+
+			end := min(start+maxMoreChildren, len(p.more.Children))
+			more, err := c.getMoreChildren(ctx, tree.linkID, p.more.Children[start:end], sort)
+			budget--
+			if err != nil {
+				return err
+			}
+
+			for _, thing := range more.JSON.Data.Things {
+				tree.attach(thing, p.listing)
+			}
+		}
+	}
+
+	return nil
+}
+
+// pendingMore is a "more" placeholder and the listing that holds it
+type pendingMore struct {
+	listing *CommentListing
+	more    *MoreComments
+}
+
+// commentTree indexes a comment listing so flat morechildren results can be
+// spliced back under their parents
+type commentTree struct {
+	root    *CommentListing
+	linkID  string
+	byName  map[string]*Comment
+	pending []pendingMore
+}
+
+func newCommentTree(root *CommentListing, linkID string) *commentTree {
+	t := &commentTree{
+		root:   root,
+		linkID: linkID,
+		byName: make(map[string]*Comment),
+	}
+	t.index(root)
+	return t
+}
+
+// index records every comment and placeholder under listing
+func (t *commentTree) index(listing *CommentListing) {
+	for _, child := range listing.Data.Children {
+		switch {
+		case child.Comment != nil:
+			t.byName[child.Comment.Name] = child.Comment
+			if child.Comment.Replies != nil {
+				t.index(child.Comment.Replies)
+			}
+		case child.More != nil:
+			t.pending = append(t.pending, pendingMore{listing: listing, more: child.More})
+		}
+	}
+}
+
+// attach adds child under its parent, falling back to the given listing when
+// the parent is not part of the tree
+func (t *commentTree) attach(child CommentChild, fallback *CommentListing) {
+	parentID := ""
+	switch {
+	case child.Comment != nil:
+		parentID = child.Comment.ParentID
+	case child.More != nil:
+		parentID = child.More.ParentID
+	}
+
+	target := fallback
+	if parentID == t.linkID {
+		target = t.root
+	} else if parent, ok := t.byName[parentID]; ok {
+		if parent.Replies == nil {
+			parent.Replies = &CommentListing{Kind: KindListing}
+		}
+		target = parent.Replies
+	}
+
+	target.Data.Children = append(target.Data.Children, child)
+
+	// Index the new subtree, including any placeholders it carries
+	t.index(&CommentListing{Data: CommentListingData{Children: []CommentChild{child}}})
+	if child.More != nil {
+		t.pending[len(t.pending)-1].listing = target
+	}
+}
+
+// remove deletes a placeholder from the listing that holds it
+func (t *commentTree) remove(p pendingMore) {
+	children := p.listing.Data.Children[:0]
+	for _, child := range p.listing.Data.Children {
+		if child.More != p.more {
+			children = append(children, child)
+		}
+	}
+	p.listing.Data.Children = children
+}
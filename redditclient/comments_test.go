@@ -0,0 +1,712 @@
+package redditclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const postAndCommentsFixture = `[
+	{"kind": "Listing", "data": {"children": [{"kind": "t3", "data": {"id": "abc123", "title": "Test Post"}}]}},
+	{"kind": "Listing", "data": {"children": [{"kind": "t1", "data": {"id": "c1", "body": "First!"}}]}}
+]`
+
+func TestGetComments_Success(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/r/golang/comments/abc123.json" &&
+			req.URL.Query().Get("sort") == "top"
+	})).Return(createHTTPResponse(200, postAndCommentsFixture, nil), nil)
+
+	result, err := client.GetComments(t.Context(), "golang", "abc123", "top")
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	post, err := result.Post()
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", post.ID)
+	assert.Equal(t, "Test Post", post.Title)
+
+	comments, err := result.Comments()
+	require.NoError(t, err)
+	assert.Len(t, comments.Data.Children, 1)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGetCommentsWithOptions_QueryParams(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		params := req.URL.Query()
+		return req.URL.Path == "/r/golang/comments/abc123.json" &&
+			params.Get("sort") == "new" &&
+			params.Get("limit") == "50" &&
+			params.Get("depth") == "1" &&
+			params.Get("context") == "3" &&
+			params.Get("truncate") == "10" &&
+			params.Get("showmore") == "true"
+	})).Return(createHTTPResponse(200, postAndCommentsFixture, nil), nil)
+
+	_, err := client.GetCommentsWithOptions(t.Context(), "golang", "abc123", &CommentOptions{
+		Sort:     "new",
+		Limit:    50,
+		Depth:    1,
+		Context:  3,
+		Truncate: 10,
+		ShowMore: true,
+	})
+
+	require.NoError(t, err)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGetCommentsWithOptions_NilOptions(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		params := req.URL.Query()
+		return !params.Has("sort") && !params.Has("depth") && !params.Has("limit")
+	})).Return(createHTTPResponse(200, postAndCommentsFixture, nil), nil)
+
+	_, err := client.GetCommentsWithOptions(t.Context(), "golang", "abc123", nil)
+
+	require.NoError(t, err)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGetComments_NotAuthenticated(t *testing.T) {
+	client, err := NewClient(WithHTTPClient(&MockHTTPClient{}))
+	require.NoError(t, err)
+
+	result, err := client.GetComments(t.Context(), "golang", "abc123", "top")
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrNotAuthenticated)
+}
+
+func TestGetComments_NotFound(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(404, `{"message": "Not Found", "error": 404}`, nil), nil)
+
+	_, err := client.GetComments(t.Context(), "golang", "missing", "top")
+
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestPostAndCommentsResponse_Malformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"not an array", `{"kind": "Listing"}`, "not an array"},
+		{"one element", `[{"kind": "Listing", "data": {"children": []}}]`, "has 1 elements, expected 2"},
+		{"three elements", `[{}, {}, {}]`, "has 3 elements, expected 2"},
+		{
+			"wrong post listing kind",
+			`[{"kind": "t3", "data": {}}, {"kind": "Listing", "data": {"children": []}}]`,
+			`post listing has kind "t3"`,
+		},
+		{
+			"wrong post child kind",
+			`[{"kind": "Listing", "data": {"children": [{"kind": "t1", "data": {}}]}}, {"kind": "Listing", "data": {"children": []}}]`,
+			`post listing child 0 has kind "t1"`,
+		},
+		{
+			"wrong comment listing kind",
+			`[{"kind": "Listing", "data": {"children": []}}, {"kind": "t1", "data": {}}]`,
+			`comment listing has kind "t1"`,
+		},
+		{
+			"wrong comment child kind",
+			`[{"kind": "Listing", "data": {"children": []}}, {"kind": "Listing", "data": {"children": [{"kind": "t3", "data": {}}]}}]`,
+			`unexpected comment child kind "t3"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp PostAndCommentsResponse
+			err := json.Unmarshal([]byte(tt.body), &resp)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+
+			var streamed PostAndCommentsResponse
+			err = streamed.decodeStream(json.NewDecoder(strings.NewReader(tt.body)))
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestPostAndCommentsResponse_EmptyPostListing(t *testing.T) {
+	var resp PostAndCommentsResponse
+	err := json.Unmarshal([]byte(`[{"kind": "Listing", "data": {"children": []}}, {"kind": "Listing", "data": {"children": []}}]`), &resp)
+	require.NoError(t, err)
+
+	post, err := resp.Post()
+	assert.Nil(t, post)
+	assert.EqualError(t, err, "post listing is empty")
+
+	comments, err := resp.Comments()
+	require.NoError(t, err)
+	assert.Empty(t, comments.Data.Children)
+}
+
+func TestPostAndCommentsResponse_ZeroValueAccessors(t *testing.T) {
+	var resp PostAndCommentsResponse
+
+	_, err := resp.Post()
+	assert.Error(t, err)
+
+	_, err = resp.Comments()
+	assert.Error(t, err)
+}
+
+func TestPostAndCommentsResponse_MarshalRoundTrip(t *testing.T) {
+	var resp PostAndCommentsResponse
+	require.NoError(t, json.Unmarshal([]byte(postAndCommentsFixture), &resp))
+
+	data, err := json.Marshal(resp)
+	require.NoError(t, err)
+
+	var decoded PostAndCommentsResponse
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	post, err := decoded.Post()
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", post.ID)
+	assert.Len(t, decoded.CommentListing.Data.Children, 1)
+}
+
+func TestGetComments_MalformedResponse(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, `[{"kind": "Listing", "data": {"children": []}}]`, nil), nil)
+
+	_, err := client.GetComments(t.Context(), "golang", "abc123", "top")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode comments")
+}
+
+const nestedCommentsFixture = `[
+	{"kind": "Listing", "data": {"children": [{"kind": "t3", "data": {"id": "abc123", "title": "Nested"}}]}},
+	{"kind": "Listing", "data": {"children": [
+		{"kind": "t1", "data": {"id": "c1", "body": "level 0", "depth": 0, "replies": {"kind": "Listing", "data": {"children": [
+			{"kind": "t1", "data": {"id": "c2", "body": "level 1", "depth": 1, "replies": {"kind": "Listing", "data": {"children": [
+				{"kind": "t1", "data": {"id": "c3", "body": "level 2", "depth": 2, "replies": ""}},
+				{"kind": "more", "data": {"id": "_", "name": "t1__", "parent_id": "t1_c2", "depth": 3, "count": 0, "children": []}}
+			]}}}},
+			{"kind": "more", "data": {"id": "m1", "name": "t1_m1", "parent_id": "t1_c1", "depth": 1, "count": 2, "children": ["c4", "c5"]}}
+		]}}}},
+		{"kind": "t1", "data": {"id": "c6", "body": "no replies", "depth": 0, "replies": ""}},
+		{"kind": "t1", "data": {"id": "c7", "body": "empty object replies", "depth": 0, "replies": {}}},
+		{"kind": "more", "data": {"id": "m2", "name": "t1_m2", "parent_id": "t3_abc123", "depth": 0, "count": 40, "children": ["c8", "c9", "c10"]}}
+	]}}
+]`
+
+func TestCommentChild_DecodesUnion(t *testing.T) {
+	var resp PostAndCommentsResponse
+	require.NoError(t, json.Unmarshal([]byte(nestedCommentsFixture), &resp))
+
+	children := resp.CommentListing.Data.Children
+	require.Len(t, children, 4)
+
+	// Deeply nested replies
+	c1 := children[0].Comment
+	require.NotNil(t, c1)
+	assert.Nil(t, children[0].More)
+	assert.Equal(t, "level 0", c1.Body)
+	require.NotNil(t, c1.Replies)
+	require.Len(t, c1.Replies.Data.Children, 2)
+
+	c2 := c1.Replies.Data.Children[0].Comment
+	require.NotNil(t, c2)
+	assert.Equal(t, "level 1", c2.Body)
+	require.NotNil(t, c2.Replies)
+	require.Len(t, c2.Replies.Data.Children, 2)
+
+	c3 := c2.Replies.Data.Children[0].Comment
+	require.NotNil(t, c3)
+	assert.Equal(t, "level 2", c3.Body)
+	assert.Equal(t, 2, c3.Depth)
+	assert.Nil(t, c3.Replies)
+
+	// "continue this thread" placeholder
+	cont := c2.Replies.Data.Children[1]
+	assert.Equal(t, KindMore, cont.Kind)
+	assert.Nil(t, cont.Comment)
+	require.NotNil(t, cont.More)
+	assert.Empty(t, cont.More.Children)
+
+	// "more" mixed in among nested comments
+	m1 := c1.Replies.Data.Children[1].More
+	require.NotNil(t, m1)
+	assert.Equal(t, []string{"c4", "c5"}, m1.Children)
+	assert.Equal(t, "t1_c1", m1.ParentID)
+
+	// Empty-string and empty-object replies both become nil
+	assert.Nil(t, children[1].Comment.Replies)
+	assert.Nil(t, children[2].Comment.Replies)
+
+	m2 := children[3].More
+	require.NotNil(t, m2)
+	assert.Equal(t, 40, m2.Count)
+	assert.Len(t, m2.Children, 3)
+}
+
+func TestCommentChild_MarshalRoundTrip(t *testing.T) {
+	var resp PostAndCommentsResponse
+	require.NoError(t, json.Unmarshal([]byte(nestedCommentsFixture), &resp))
+
+	data, err := json.Marshal(resp)
+	require.NoError(t, err)
+
+	var decoded PostAndCommentsResponse
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, resp, decoded)
+}
+
+func TestComment_MalformedReplies(t *testing.T) {
+	var comment Comment
+	err := json.Unmarshal([]byte(`{"id": "c1", "replies": 42}`), &comment)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode replies of comment c1")
+
+	// Reddit sends replies ahead of the ID; the error still names the comment
+	err = json.Unmarshal([]byte(`{"replies": 42, "id": "c2"}`), &comment)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode replies of comment c2")
+}
+
+func TestCommentChild_DataBeforeKind(t *testing.T) {
+	var child CommentChild
+	require.NoError(t, json.Unmarshal([]byte(`{"data": {"id": "c1", "body": "hi"}, "kind": "t1"}`), &child))
+	require.NotNil(t, child.Comment)
+	assert.Equal(t, "c1", child.Comment.ID)
+	assert.Equal(t, "hi", child.Comment.Body)
+
+	err := json.Unmarshal([]byte(`{"data": {}, "kind": "t9"}`), &child)
+	assert.ErrorContains(t, err, `unexpected comment child kind "t9"`)
+
+	err = json.Unmarshal([]byte(`{"kind": "t1"}`), &child)
+	assert.ErrorContains(t, err, "failed to decode comment")
+}
+
+func TestComment_RepliesDoNotShareStorage(t *testing.T) {
+	thread := func(ids ...string) string {
+		children := make([]string, len(ids))
+		for i, id := range ids {
+			children[i] = fmt.Sprintf(`{"kind": "t1", "data": {"id": %q, "replies": ""}}`, id)
+		}
+		return `{"id": "root", "replies": {"kind": "Listing", "data": {"children": [` + strings.Join(children, ",") + `]}}}`
+	}
+
+	// Reply children are decoded into pooled scratch space, which a later
+	// decode must not overwrite
+	var first, second Comment
+	require.NoError(t, json.Unmarshal([]byte(thread("a1", "a2", "a3")), &first))
+	require.NoError(t, json.Unmarshal([]byte(thread("b1", "b2")), &second))
+
+	var got []string
+	for _, child := range first.Replies.Data.Children {
+		got = append(got, child.Comment.ID)
+	}
+	assert.Equal(t, []string{"a1", "a2", "a3"}, got)
+	assert.Len(t, second.Replies.Data.Children, 2)
+	assert.Equal(t, 3, cap(first.Replies.Data.Children))
+
+	var more1, more2 CommentChild
+	require.NoError(t, json.Unmarshal([]byte(`{"kind": "more", "data": {"children": ["m1", "m2", "m3"]}}`), &more1))
+	require.NoError(t, json.Unmarshal([]byte(`{"kind": "more", "data": {"children": ["n1"]}}`), &more2))
+	assert.Equal(t, []string{"m1", "m2", "m3"}, more1.More.Children)
+	assert.Equal(t, []string{"n1"}, more2.More.Children)
+
+	var empty Comment
+	require.NoError(t, json.Unmarshal([]byte(`{"id": "c1", "replies": {"kind": "Listing", "data": {"children": []}}}`), &empty))
+	require.NotNil(t, empty.Replies)
+	assert.NotNil(t, empty.Replies.Data.Children)
+	assert.Empty(t, empty.Replies.Data.Children)
+}
+
+// Helper function to build a /api/morechildren response body
+func createMoreChildrenResponse(things ...string) string {
+	return fmt.Sprintf(`{"json": {"errors": [], "data": {"things": [%s]}}}`, strings.Join(things, ","))
+}
+
+func commentThing(id, parentID string) string {
+	return fmt.Sprintf(`{"kind": "t1", "data": {"id": "%s", "name": "t1_%s", "parent_id": "%s", "body": "comment %s", "replies": ""}}`, id, id, parentID, id)
+}
+
+func moreThing(id, parentID string, children ...string) string {
+	ids, _ := json.Marshal(children)
+	if children == nil {
+		ids = []byte("[]")
+	}
+	return fmt.Sprintf(`{"kind": "more", "data": {"id": "%s", "name": "t1_%s", "parent_id": "%s", "count": %d, "children": %s}}`, id, id, parentID, len(children), ids)
+}
+
+func threadFixture(comments ...string) string {
+	return fmt.Sprintf(`[
+		{"kind": "Listing", "data": {"children": [{"kind": "t3", "data": {"id": "abc123", "name": "t3_abc123"}}]}},
+		{"kind": "Listing", "data": {"children": [%s]}}
+	]`, strings.Join(comments, ","))
+}
+
+func isMoreChildrenRequest(req *http.Request) bool {
+	return req.URL.Path == "/api/morechildren.json"
+}
+
+func collectIDs(listing *CommentListing) []string {
+	var ids []string
+	for _, child := range listing.Data.Children {
+		switch {
+		case child.Comment != nil:
+			ids = append(ids, child.Comment.ID)
+			if child.Comment.Replies != nil {
+				for _, id := range collectIDs(child.Comment.Replies) {
+					ids = append(ids, child.Comment.ID+">"+id)
+				}
+			}
+		case child.More != nil:
+			ids = append(ids, "more:"+child.More.ID)
+		}
+	}
+	return ids
+}
+
+func TestGetMoreComments_Success(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		params := req.URL.Query()
+		return isMoreChildrenRequest(req) &&
+			params.Get("link_id") == "t3_abc123" &&
+			params.Get("children") == "c1,c2" &&
+			params.Get("api_type") == "json"
+	})).Return(createHTTPResponse(200, createMoreChildrenResponse(
+		commentThing("c1", "t3_abc123"),
+		commentThing("c2", "t1_c1"),
+	), nil), nil)
+
+	result, err := client.GetMoreComments(t.Context(), "t3_abc123", []string{"c1", "c2"})
+
+	require.NoError(t, err)
+	require.Len(t, result.JSON.Data.Things, 2)
+	assert.Equal(t, "c1", result.JSON.Data.Things[0].Comment.ID)
+	assert.Equal(t, "t1_c1", result.JSON.Data.Things[1].Comment.ParentID)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGetMoreComments_Batches(t *testing.T) {
+	tests := []struct {
+		children     int
+		wantRequests int
+	}{
+		{0, 0},
+		{1, 1},
+		{100, 1},
+		{250, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.children), func(t *testing.T) {
+			client, mockHTTP := newAuthenticatedTestClient(t)
+
+			ids := make([]string, tt.children)
+			for i := range ids {
+				ids[i] = fmt.Sprintf("c%d", i)
+			}
+
+			// Each batch echoes back a comment per requested ID
+			var batches [][]string
+			mockHTTP.On("Do", mock.MatchedBy(isMoreChildrenRequest)).
+				Return(func(req *http.Request) *http.Response {
+					batch := strings.Split(req.URL.Query().Get("children"), ",")
+					batches = append(batches, batch)
+					things := make([]string, len(batch))
+					for i, id := range batch {
+						things[i] = commentThing(id, "t3_abc123")
+					}
+					return createHTTPResponse(200, createMoreChildrenResponse(things...), nil)
+				}, nil)
+
+			result, err := client.GetMoreCommentsWithSort(t.Context(), "t3_abc123", ids, CommentSortNew)
+
+			require.NoError(t, err)
+			require.Len(t, batches, tt.wantRequests)
+			for _, batch := range batches {
+				assert.LessOrEqual(t, len(batch), maxMoreChildren)
+			}
+			require.Len(t, result.JSON.Data.Things, tt.children)
+			for i, thing := range result.JSON.Data.Things {
+				assert.Equal(t, ids[i], thing.Comment.ID)
+			}
+			for _, call := range mockHTTP.Calls {
+				assert.Equal(t, "new", call.Arguments[0].(*http.Request).URL.Query().Get("sort"))
+			}
+		})
+	}
+}
+
+func TestGetMoreComments_BatchError(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	mockHTTP.On("Do", mock.MatchedBy(isMoreChildrenRequest)).
+		Return(createHTTPResponse(200, createMoreChildrenResponse(), nil), nil).Once()
+	mockHTTP.On("Do", mock.MatchedBy(isMoreChildrenRequest)).
+		Return(createHTTPResponse(500, `{}`, nil), nil).Once()
+	client.SetRetryPolicy(RetryPolicy{})
+
+	ids := make([]string, 150)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("c%d", i)
+	}
+	_, err := client.GetMoreComments(t.Context(), "t3_abc123", ids)
+
+	assert.ErrorIs(t, err, ErrServerError)
+}
+
+func TestGetMoreComments_InvalidSort(t *testing.T) {
+	client, _ := newAuthenticatedTestClient(t)
+
+	_, err := client.GetMoreCommentsWithSort(t.Context(), "t3_abc123", []string{"c1"}, "sideways")
+
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+func TestGetMoreComments_Errors(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(isMoreChildrenRequest)).
+		Return(createHTTPResponse(200, `{"json": {"errors": [["RATELIMIT", "you are doing that too much", "ratelimit"]]}}`, nil), nil)
+
+	_, err := client.GetMoreComments(t.Context(), "t3_abc123", []string{"c1"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "RATELIMIT")
+}
+
+func TestGetAllComments_ExpandsPlaceholders(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/r/golang/comments/abc123.json"
+	})).Return(createHTTPResponse(200, threadFixture(
+		commentThing("c1", "t3_abc123"),
+		moreThing("m1", "t3_abc123", "c2", "c3"),
+	), nil), nil).Once()
+
+	// The expansion returns a reply to an existing comment, a new top-level
+	// comment with its own reply, and a nested placeholder
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return isMoreChildrenRequest(req) && req.URL.Query().Get("children") == "c2,c3"
+	})).Return(createHTTPResponse(200, createMoreChildrenResponse(
+		commentThing("c2", "t3_abc123"),
+		commentThing("c3", "t1_c2"),
+		moreThing("m2", "t1_c3", "c4"),
+	), nil), nil).Once()
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return isMoreChildrenRequest(req) && req.URL.Query().Get("children") == "c4"
+	})).Return(createHTTPResponse(200, createMoreChildrenResponse(
+		commentThing("c4", "t1_c3"),
+	), nil), nil).Once()
+
+	result, err := client.GetAllComments(t.Context(), "golang", "abc123", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c1", "c2", "c2>c3", "c2>c3>c4"}, collectIDs(result))
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGetAllComments_BatchesChildren(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	ids := make([]string, 150)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("c%d", i)
+	}
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/r/golang/comments/abc123.json"
+	})).Return(createHTTPResponse(200, threadFixture(moreThing("m1", "t3_abc123", ids...)), nil), nil).Once()
+
+	var batches []int
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		if !isMoreChildrenRequest(req) {
+			return false
+		}
+		batches = append(batches, len(strings.Split(req.URL.Query().Get("children"), ",")))
+		return true
+	})).Return(func(req *http.Request) *http.Response {
+		var things []string
+		for _, id := range strings.Split(req.URL.Query().Get("children"), ",") {
+			things = append(things, commentThing(id, "t3_abc123"))
+		}
+		return createHTTPResponse(200, createMoreChildrenResponse(things...), nil)
+	}, nil).Twice()
+
+	result, err := client.GetAllComments(t.Context(), "golang", "abc123", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{100, 50}, batches)
+	assert.Len(t, result.Data.Children, 150)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGetAllComments_ContinueThisThread(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	parent := `{"kind": "t1", "data": {"id": "c1", "name": "t1_c1", "parent_id": "t3_abc123", "replies": {"kind": "Listing", "data": {"children": [` +
+		moreThing("_", "t1_c1") + `]}}}}`
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/r/golang/comments/abc123.json"
+	})).Return(createHTTPResponse(200, threadFixture(parent), nil), nil).Once()
+
+	continued := `{"kind": "t1", "data": {"id": "c1", "name": "t1_c1", "parent_id": "t3_abc123", "replies": {"kind": "Listing", "data": {"children": [` +
+		commentThing("c2", "t1_c1") + `]}}}}`
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/r/golang/comments/abc123/comment/c1.json"
+	})).Return(createHTTPResponse(200, threadFixture(continued), nil), nil).Once()
+
+	result, err := client.GetAllComments(t.Context(), "golang", "abc123", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c1", "c1>c2"}, collectIDs(result))
+	mockHTTP.AssertExpectations(t)
+}
+
+// commentChain nests a comment for each ID inside the previous one
+func commentChain(ids ...string) string {
+	replies := `""`
+	for i := len(ids) - 1; i >= 0; i-- {
+		parentID := "t3_abc123"
+		if i > 0 {
+			parentID = "t1_" + ids[i-1]
+		}
+		replies = fmt.Sprintf(`{"kind": "Listing", "data": {"children": [{"kind": "t1", "data": {"id": "%s", "name": "t1_%s", "parent_id": "%s", "replies": %s}}]}}`,
+			ids[i], ids[i], parentID, replies)
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(replies, `{"kind": "Listing", "data": {"children": [`), `]}}`)
+}
+
+func TestGetComment_FromPermalink(t *testing.T) {
+	tests := []struct {
+		name    string
+		context int
+		body    string
+		wantIDs []string
+	}{
+		{"no context", 0, commentChain("c3", "c4"), []string{"c3", "c3>c4"}},
+		{"two ancestors", 2, commentChain("c1", "c2", "c3", "c4"), []string{"c1", "c1>c2", "c1>c2>c3", "c1>c2>c3>c4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mockHTTP := newAuthenticatedTestClient(t)
+			mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+				wantContext := ""
+				if tt.context > 0 {
+					wantContext = fmt.Sprint(tt.context)
+				}
+				return req.URL.Path == "/r/golang/comments/abc123/comment/c3.json" &&
+					req.URL.Query().Get("context") == wantContext
+			})).Return(createHTTPResponse(200, threadFixture(tt.body), nil), nil).Once()
+
+			ref, err := ParsePostURL("https://www.reddit.com/r/golang/comments/abc123/title/c3/")
+			require.NoError(t, err)
+
+			result, err := client.GetComment(t.Context(), ref.Subreddit, ref.PostID, ref.CommentID, tt.context)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantIDs, collectIDs(&result.CommentListing))
+
+			// The requested comment sits below exactly context ancestors
+			target := &result.CommentListing.Data.Children[0]
+			for range tt.context {
+				target = &target.Comment.Replies.Data.Children[0]
+			}
+			assert.Equal(t, "c3", target.Comment.ID)
+			mockHTTP.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetComment_InvalidArguments(t *testing.T) {
+	client, _ := newAuthenticatedTestClient(t)
+
+	_, err := client.GetComment(t.Context(), "golang", "abc123", "", 0)
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+
+	_, err = client.GetComment(t.Context(), "golang", "abc123", "c3", 9)
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+
+	_, err = client.GetComment(t.Context(), "golang", "abc123", "c3", -1)
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+func TestGetAllComments_RespectsBudget(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	ids := make([]string, 250)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("c%d", i)
+	}
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/r/golang/comments/abc123.json"
+	})).Return(createHTTPResponse(200, threadFixture(moreThing("m1", "t3_abc123", ids...)), nil), nil).Once()
+
+	mockHTTP.On("Do", mock.MatchedBy(isMoreChildrenRequest)).Return(func(req *http.Request) *http.Response {
+		var things []string
+		for _, id := range strings.Split(req.URL.Query().Get("children"), ",") {
+			things = append(things, commentThing(id, "t3_abc123"))
+		}
+		return createHTTPResponse(200, createMoreChildrenResponse(things...), nil)
+	}, nil).Once()
+
+	result, err := client.GetAllComments(t.Context(), "golang", "abc123", &CommentOptions{MaxExpansions: 1})
+
+	require.NoError(t, err)
+	require.Len(t, result.Data.Children, 101)
+
+	// The unfetched remainder stays in the tree as a placeholder
+	rest := result.Data.Children[100].More
+	require.NotNil(t, rest)
+	assert.Len(t, rest.Children, 150)
+	assert.Equal(t, "c100", rest.Children[0])
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGetAllComments_PropagatesErrors(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/r/golang/comments/abc123.json"
+	})).Return(createHTTPResponse(200, threadFixture(moreThing("m1", "t3_abc123", "c1")), nil), nil).Once()
+
+	mockHTTP.On("Do", mock.MatchedBy(isMoreChildrenRequest)).
+		Return(createHTTPResponse(500, "server error", nil), nil).Once()
+
+	_, err := client.GetAllComments(t.Context(), "golang", "abc123", nil)
+
+	assert.ErrorIs(t, err, ErrServerError)
+	mockHTTP.AssertExpectations(t)
+}
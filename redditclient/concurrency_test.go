@@ -0,0 +1,64 @@
+package redditclient
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ConcurrentRequestsDuringAuthenticate(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP))
+	require.NoError(t, err)
+
+	mockHTTP.On("Do", mock.MatchedBy(isAuthRequest)).Return(func(*http.Request) *http.Response {
+		resp := createOAuthResponse("token", 3600)
+		resp.Header.Set("x-reddit-loid", "loid")
+		resp.Header.Set("x-reddit-session", "session")
+		return resp
+	}, nil)
+
+	// Every API request must carry a complete credential set
+	var torn sync.Map
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return !isAuthRequest(req)
+	})).Return(func(req *http.Request) *http.Response {
+		if req.Header.Get("Authorization") != "Bearer token" || req.Header.Get("x-reddit-loid") != "loid" || req.Header.Get("x-reddit-session") != "session" {
+			torn.Store(req.Header.Get("Authorization"), true)
+		}
+		return createHTTPResponse(200, emptyListing, nil)
+	}, nil)
+
+	require.NoError(t, client.Authenticate(t.Context()))
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 10 {
+				_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, client.Authenticate(t.Context()))
+		}()
+	}
+	wg.Wait()
+
+	torn.Range(func(key, _ any) bool {
+		t.Errorf("request sent with incomplete credentials: %s", strings.TrimSpace(key.(string)))
+		return true
+	})
+	assert.True(t, client.IsTokenValid())
+}
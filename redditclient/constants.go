@@ -0,0 +1,146 @@
+package redditclient
+
+import "time"
+
+const (
+	ANDROID_CLIENT_ID = "ohXpoqrZYub1kg"
+
+	// Cookies that accept Reddit's content warnings. Gated (18+) and
+	// quarantined subreddits each have their own preference;
+	// CONTENT_WARNING_ACCEPT_COOKIE sets both.
+	CONTENT_WARNING_ACCEPT_COOKIE = "_options=%7B%22pref_quarantine_optin%22%3A%20true%2C%20%22pref_gated_sr_optin%22%3A%20true%7D"
+	GATED_ACCEPT_COOKIE           = "_options=%7B%22pref_gated_sr_optin%22%3A%20true%7D"
+	QUARANTINE_ACCEPT_COOKIE      = "_options=%7B%22pref_quarantine_optin%22%3A%20true%7D"
+)
+
+// DefaultScopes are the OAuth scopes Authenticate requests, as the Android app does
+var DefaultScopes = []string{"*", "email", "pii"}
+
+// Default endpoints for API requests and authentication
+const (
+	DefaultBaseURL = "https://oauth.reddit.com"
+	DefaultAuthURL = "https://www.reddit.com"
+)
+
+// androidHeaderOrder is the order and casing in which the Reddit Android app
+// sends request headers. Headers it does not list follow in sorted order.
+var androidHeaderOrder = []string{
+	"Authorization",
+	"client-vendor-id",
+	"x-reddit-device-id",
+	"x-reddit-loid",
+	"x-reddit-session",
+	"x-reddit-retry",
+	"x-reddit-compression",
+	"x-reddit-qos",
+	"x-reddit-media-codecs",
+	"Content-Type",
+	"Content-Length",
+	"Host",
+	"Connection",
+	"Accept-Encoding",
+	"Cookie",
+	"User-Agent",
+}
+
+// DefaultTimeout is the per-request timeout of the default HTTP client
+const DefaultTimeout = 30 * time.Second
+
+// DefaultMaxResponseBytes caps a response body, both as sent and once decompressed
+const DefaultMaxResponseBytes = 20 << 20
+
+// DefaultMaxErrorBodyBytes caps how much of a response body is kept on an APIError
+const DefaultMaxErrorBodyBytes = 512
+
+// DefaultTokenRefreshMargin is how long before expiry the access token is refreshed
+const DefaultTokenRefreshMargin = 60 * time.Second
+
+// rateLimitRefreshThreshold is the remaining request count below which a fresh token is fetched
+const rateLimitRefreshThreshold = 10
+
+// DefaultRateLimitFloor is the remaining quota below which WaitOnLimit blocks
+const DefaultRateLimitFloor = 1
+
+// DefaultRetryPolicy retries transient failures up to three times in total
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// DefaultCircuitBreakerPolicy opens the breaker after 5 consecutive failures
+// and probes Reddit again after 30 seconds
+var DefaultCircuitBreakerPolicy = CircuitBreakerPolicy{
+	Threshold: 5,
+	Cooldown:  30 * time.Second,
+}
+
+// DefaultFetchConcurrency is how many subreddits FetchSubreddits fetches at once by default
+const DefaultFetchConcurrency = 4
+
+// DefaultMaxCommentExpansions caps the API calls GetAllComments makes by default
+const DefaultMaxCommentExpansions = 10
+
+// DefaultWatchInterval is how often WatchSubreddit polls by default
+const DefaultWatchInterval = 30 * time.Second
+
+// maxWatchedComments caps how many comment IDs WatchComments remembers
+const maxWatchedComments = 10000
+
+// maxListingLimit is the most items Reddit returns in one listing page
+const maxListingLimit = 100
+
+// maxCommentContext is the most parent comments Reddit includes above a
+// focused comment
+const maxCommentContext = 8
+
+// maxMoreChildren is the most comment IDs /api/morechildren accepts per request
+const maxMoreChildren = 100
+
+// maxRestrictionSniff bounds how much of a streamed body is buffered to detect a restriction notice
+const maxRestrictionSniff = 4096
+
+// defaultChildrenCap sizes a streamed listing's children when it has no
+// dist; it is Reddit's default page size
+const defaultChildrenCap = 25
+
+// maxChildrenCap bounds how many children a listing's dist can preallocate
+const maxChildrenCap = 1000
+
+// defaultScratchCap sizes the scratch slices comment replies and "more" IDs
+// are decoded into
+const defaultScratchCap = 16
+
+// maxScratchCap is the largest scratch slice kept for reuse, so a rare
+// comment with thousands of replies does not stay pinned in the pool
+const maxScratchCap = 1024
+
+// maxPooledBodyBuffer is the largest response buffer kept for reuse, so one
+// huge response does not stay pinned in the pool
+const maxPooledBodyBuffer = 4 << 20
+
+// maxCombinedSubredditsLen bounds the joined sub1+sub2 path segment of a
+// combined listing, keeping the request URL well inside Reddit's limits
+const maxCombinedSubredditsLen = 2048
+
+// maxStickySlots is how many posts a subreddit can pin
+const maxStickySlots = 2
+
+// maxInfoIDs is the most fullnames /api/info accepts per request
+const maxInfoIDs = 100
+
+// maxSearchResults is about how many results Reddit serves for one search,
+// however many pages they are fetched in
+const maxSearchResults = 250
+
+// backgroundRefreshTimeout bounds how long a background token refresh may take
+const backgroundRefreshTimeout = 30 * time.Second
+
+// Android app versions for User-Agent spoofing; see WithUserAgentPool
+var androidVersions = []string{
+	"Reddit/2023.46.0/Android 12",
+	"Reddit/2023.45.0/Android 11",
+	"Reddit/2023.44.0/Android 13",
+	"Reddit/2023.43.0/Android 12",
+	"Reddit/2023.42.0/Android 11",
+}
@@ -0,0 +1,33 @@
+package redditclient
+
+import "encoding/json"
+
+// UnmarshalJSON decodes a post, normalizing an empty crosspost_parent_list
+// to nil so non-crossposts never carry an empty parent slice
+func (p *Post) UnmarshalJSON(data []byte) error {
+	type postAlias Post
+	if err := json.Unmarshal(data, (*postAlias)(p)); err != nil {
+		return err
+	}
+
+	if len(p.CrosspostParents) == 0 {
+		p.CrosspostParents = nil
+	}
+
+	return nil
+}
+
+// IsCrosspost reports whether the post is a crosspost of another post
+func (p *Post) IsCrosspost() bool {
+	return p.CrosspostParent != "" || len(p.CrosspostParents) > 0
+}
+
+// OriginalPost returns the post this one was crossposted from, or nil if the
+// post is not a crosspost or Reddit did not include the parent
+func (p *Post) OriginalPost() *Post {
+	if len(p.CrosspostParents) == 0 {
+		return nil
+	}
+
+	return &p.CrosspostParents[0]
+}
@@ -0,0 +1,81 @@
+package redditclient
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const crosspostFixture = `{
+	"id": "xp1",
+	"name": "t3_xp1",
+	"title": "Crossposted: interesting thread",
+	"subreddit": "golang",
+	"is_self": false,
+	"selftext": "",
+	"crosspost_parent": "t3_orig1",
+	"crosspost_parent_list": [{
+		"id": "orig1",
+		"name": "t3_orig1",
+		"title": "Interesting thread",
+		"subreddit": "programming",
+		"is_self": true,
+		"selftext": "The original body",
+		"edited": 1700000100,
+		"crosspost_parent_list": []
+	}]
+}`
+
+func TestPost_OriginalPost(t *testing.T) {
+	var post Post
+	require.NoError(t, json.Unmarshal([]byte(crosspostFixture), &post))
+
+	assert.True(t, post.IsCrosspost())
+	assert.Equal(t, "t3_orig1", post.CrosspostParent)
+
+	original := post.OriginalPost()
+	require.NotNil(t, original)
+	assert.Equal(t, "orig1", original.ID)
+	assert.Equal(t, "programming", original.Subreddit)
+	assert.Equal(t, "The original body", original.SelfText)
+	assert.True(t, original.Edited.Edited)
+	assert.Nil(t, original.CrosspostParents)
+	assert.False(t, original.IsCrosspost())
+	assert.Nil(t, original.OriginalPost())
+}
+
+func TestPost_CrosspostParentsEmpty(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+	}{
+		{"missing field", `{"id": "p1"}`},
+		{"null", `{"id": "p1", "crosspost_parent_list": null}`},
+		{"empty list", `{"id": "p1", "crosspost_parent_list": []}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var post Post
+			require.NoError(t, json.Unmarshal([]byte(tt.fixture), &post))
+
+			assert.Nil(t, post.CrosspostParents)
+			assert.Nil(t, post.OriginalPost())
+			assert.False(t, post.IsCrosspost())
+		})
+	}
+}
+
+func TestPost_CrosspostInListing(t *testing.T) {
+	body := `{"kind": "Listing", "data": {"children": [{"kind": "t3", "data": ` + crosspostFixture + `}]}}`
+
+	var listing PostListing
+	require.NoError(t, json.Unmarshal([]byte(body), &listing))
+	require.Len(t, listing.Data.Children, 1)
+
+	original := listing.Data.Children[0].Data.OriginalPost()
+	require.NotNil(t, original)
+	assert.Equal(t, "Interesting thread", original.Title)
+}
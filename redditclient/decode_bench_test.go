@@ -0,0 +1,93 @@
+package redditclient
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Decode benchmarks run against testdata fixtures shaped like real responses:
+// a 100-post subreddit page and a ~250 comment thread nested eight deep,
+// with every field Reddit sends, escaped HTML included.
+
+func readFixture(b *testing.B, name string) string {
+	b.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(b, err)
+	return string(data)
+}
+
+// benchClients returns a client that streams responses and one that buffers
+// them, as caching and coalescing clients do, both serving body
+func benchClients(b testing.TB, body string) map[string]*Client {
+	b.Helper()
+	clients := map[string]*Client{}
+	for name, opts := range map[string][]Option{
+		"streamed": nil,
+		"buffered": {WithRequestCoalescing()},
+	} {
+		client, err := NewClient(append(opts, WithHTTPClient(staticHTTPClient{body: body}))...)
+		require.NoError(b, err)
+		client.accessToken = "test-token"
+		client.authenticated = true
+		clients[name] = client
+	}
+	return clients
+}
+
+func BenchmarkDecodeSubredditListing(b *testing.B) {
+	body := readFixture(b, "subreddit_listing.json")
+
+	for _, mode := range []string{"streamed", "buffered"} {
+		client := benchClients(b, body)[mode]
+		b.Run(mode, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(body)))
+			for b.Loop() {
+				listing, err := client.GetSubredditListing(b.Context(), "golang", SortNew, nil)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(listing.Data.Children) != 100 {
+					b.Fatalf("decoded %d posts, want 100", len(listing.Data.Children))
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecodeCommentTree(b *testing.B) {
+	body := readFixture(b, "comment_tree.json")
+
+	for _, mode := range []string{"streamed", "buffered"} {
+		client := benchClients(b, body)[mode]
+		b.Run(mode, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(body)))
+			for b.Loop() {
+				if _, err := client.GetComments(b.Context(), "golang", "1c4bnch", ""); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkReadGzipBody(b *testing.B) {
+	content := []byte(readFixture(b, "comment_tree.json"))
+	body := string(compress(b, "gzip", content))
+	headers := map[string]string{"Content-Encoding": "gzip"}
+
+	client, err := NewClient()
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(content)))
+	for b.Loop() {
+		if _, err := client.readResponseBody(createHTTPResponse(200, body, headers)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package redditclient
+
+import (
+	"context"
+	"iter"
+	"net/url"
+)
+
+// GetTrendingSubreddits fetches the subreddits Reddit is currently featuring
+// as trending
+func (c *Client) GetTrendingSubreddits(ctx context.Context) (*TrendingSubreddits, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	body, err := c.makeAPIRequest(ctx, "/api/trending_subreddits.json", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var trending TrendingSubreddits
+	if err := c.decodeJSON(ctx, body, &trending, "trending subreddits"); err != nil {
+		return nil, err
+	}
+
+	return &trending, nil
+}
+
+// GetDefaultSubreddits fetches a page of the subreddits logged-out users are
+// shown by default
+func (c *Client) GetDefaultSubreddits(ctx context.Context, opts *ListingOptions) (*SubscriptionListing, error) {
+	return c.getSubredditsPage(ctx, "/subreddits/default.json", opts)
+}
+
+// GetPopularSubreddits fetches a page of subreddits ordered by activity. The
+// listing runs thousands of subreddits deep; follow Data.After, or use
+// PopularSubreddits, to page through it.
+func (c *Client) GetPopularSubreddits(ctx context.Context, opts *ListingOptions) (*SubscriptionListing, error) {
+	return c.getSubredditsPage(ctx, "/subreddits/popular.json", opts)
+}
+
+// PopularSubreddits returns an iterator over the popular subreddits listing,
+// following the same cursor and cap rules as SubredditPosts
+func (c *Client) PopularSubreddits(ctx context.Context, opts *ListingOptions) iter.Seq2[SubredditAbout, error] {
+	return listingItems(ctx, opts, func(page *ListingOptions) (*SubscriptionListing, error) {
+		return c.GetPopularSubreddits(ctx, page)
+	})
+}
+
+// getSubredditsPage fetches one page of a /subreddits listing
+func (c *Client) getSubredditsPage(ctx context.Context, endpoint string, opts *ListingOptions) (*SubscriptionListing, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	params := url.Values{}
+	opts.apply(params)
+
+	var listing SubscriptionListing
+	if err := c.streamAPIRequest(ctx, endpoint, params, &listing, "subreddits"); err != nil {
+		return nil, err
+	}
+
+	return &listing, nil
+}
@@ -0,0 +1,85 @@
+package redditclient
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// subredditsPage builds a /subreddits listing page of the given names
+func subredditsPage(after string, names ...string) string {
+	children := make([]string, len(names))
+	for i, name := range names {
+		children[i] = fmt.Sprintf(`{"kind": "t5", "data": {"name": "t5_%s", "display_name": "%s", "subscribers": %d}}`, name, name, 1000*(i+1))
+	}
+	return fmt.Sprintf(`{"kind": "Listing", "data": {"after": %q, "children": [%s]}}`, after, strings.Join(children, ","))
+}
+
+func TestGetTrendingSubreddits_Success(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.Respond("/api/trending_subreddits.json", http.StatusOK,
+		`{"subreddit_names": ["golang", "rust"], "comment_count": 42, "comment_url": "/r/trendingsubreddits/comments/abc/trending_subreddits/"}`)
+
+	trending, err := client.GetTrendingSubreddits(t.Context())
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"golang", "rust"}, trending.SubredditNames)
+	assert.Equal(t, 42, trending.CommentCount)
+	assert.Equal(t, "/r/trendingsubreddits/comments/abc/trending_subreddits/", trending.CommentURL)
+}
+
+func TestGetDefaultSubreddits_Success(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.Respond("/subreddits/default.json", http.StatusOK, subredditsPage("", "pics", "news"))
+
+	listing, err := client.GetDefaultSubreddits(t.Context(), &ListingOptions{Limit: 2})
+
+	require.NoError(t, err)
+	require.Len(t, listing.Data.Children, 2)
+	assert.Equal(t, "pics", listing.Data.Children[0].Data.DisplayName)
+	assert.Equal(t, 2000, listing.Data.Children[1].Data.Subscribers)
+
+	req, ok := srv.LastRequest("/subreddits/default.json")
+	require.True(t, ok)
+	assert.Equal(t, "2", req.Query.Get("limit"))
+}
+
+func TestPopularSubreddits_Paginates(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	page := func(after string) any {
+		return mock.MatchedBy(func(req *http.Request) bool {
+			return req.URL.Path == "/subreddits/popular.json" && req.URL.Query().Get("after") == after
+		})
+	}
+	mockHTTP.On("Do", page("")).Return(createHTTPResponse(http.StatusOK, subredditsPage("t5_b", "a", "b"), nil), nil).Once()
+	mockHTTP.On("Do", page("t5_b")).Return(createHTTPResponse(http.StatusOK, subredditsPage("t5_d", "c", "d"), nil), nil).Once()
+	mockHTTP.On("Do", page("t5_d")).Return(createHTTPResponse(http.StatusOK, subredditsPage("", "e"), nil), nil).Once()
+
+	var names []string
+	for sub, err := range client.PopularSubreddits(t.Context(), &ListingOptions{Limit: 2}) {
+		require.NoError(t, err)
+		names = append(names, sub.DisplayName)
+	}
+
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, names)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestSubredditDiscovery_NotAuthenticated(t *testing.T) {
+	client, err := NewClient(WithHTTPClient(&MockHTTPClient{}))
+	require.NoError(t, err)
+
+	_, err = client.GetTrendingSubreddits(t.Context())
+	assert.ErrorIs(t, err, ErrNotAuthenticated)
+
+	_, err = client.GetDefaultSubreddits(t.Context(), nil)
+	assert.ErrorIs(t, err, ErrNotAuthenticated)
+
+	_, err = client.GetPopularSubreddits(t.Context(), nil)
+	assert.ErrorIs(t, err, ErrNotAuthenticated)
+}
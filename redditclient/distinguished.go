@@ -0,0 +1,36 @@
+package redditclient
+
+// Values of Comment.Distinguished and Post.Distinguished
+const (
+	DistinguishedModerator = "moderator"
+	DistinguishedAdmin     = "admin"
+)
+
+// Values of Comment.CollapsedReasonCode
+const (
+	CollapsedCrowdControl = "CROWD_CONTROL" // Collapsed by the subreddit's crowd control setting
+	CollapsedLowScore     = "LOW_SCORE"     // Below the reader's score threshold
+	CollapsedDeleted      = "DELETED"       // Deleted or removed; see IsDeleted and IsRemoved
+)
+
+// DisplayScore returns the comment's score and whether it can be shown.
+// While a subreddit hides new comments' scores, Reddit sends a placeholder
+// score of 1 that says nothing about the votes cast.
+func (c *Comment) DisplayScore() (int, bool) {
+	if c.ScoreHidden {
+		return 0, false
+	}
+	return c.Score, true
+}
+
+// IsModerator reports whether the comment was posted as a moderator of the
+// subreddit, such as AutoModerator's stickied notices
+func (c *Comment) IsModerator() bool {
+	return c.Distinguished == DistinguishedModerator
+}
+
+// IsCrowdControlled reports whether the subreddit's crowd control collapsed
+// the comment
+func (c *Comment) IsCrowdControlled() bool {
+	return c.Collapsed && c.CollapsedReasonCode == CollapsedCrowdControl
+}
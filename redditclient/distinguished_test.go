@@ -0,0 +1,116 @@
+package redditclient
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// moderatedThreadComments decodes the comments of testdata/moderated_thread.json
+// keyed by ID
+func moderatedThreadComments(t *testing.T) (*CommentListing, map[string]*Comment) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "moderated_thread.json"))
+	require.NoError(t, err)
+
+	var thread PostAndCommentsResponse
+	require.NoError(t, json.Unmarshal(data, &thread))
+	comments, err := thread.Comments()
+	require.NoError(t, err)
+
+	byID := map[string]*Comment{}
+	WalkComments(comments, func(c *Comment, depth int) bool {
+		byID[c.ID] = c
+		return true
+	})
+	return comments, byID
+}
+
+func TestComment_AutoModeratorSticky(t *testing.T) {
+	comments, byID := moderatedThreadComments(t)
+
+	sticky := comments.Data.Children[0].Comment
+	require.NotNil(t, sticky)
+	assert.Equal(t, "AutoModerator", sticky.Author)
+	assert.True(t, sticky.Stickied)
+	assert.Equal(t, DistinguishedModerator, sticky.Distinguished)
+	assert.True(t, sticky.IsModerator())
+	assert.False(t, sticky.IsSubmitter)
+
+	// A moderator speaking as one, without pinning the comment
+	mod := byID["l0bbb03"]
+	assert.True(t, mod.IsModerator())
+	assert.False(t, mod.Stickied)
+
+	// Reddit sends null for undistinguished comments
+	assert.Empty(t, byID["l0bbb01"].Distinguished)
+	assert.False(t, byID["l0bbb01"].IsModerator())
+}
+
+func TestComment_IsSubmitter(t *testing.T) {
+	_, byID := moderatedThreadComments(t)
+
+	var op []string
+	for id, c := range byID {
+		if c.IsSubmitter {
+			op = append(op, id)
+		}
+	}
+	assert.ElementsMatch(t, []string{"l0bbb02", "l0ccc03"}, op)
+}
+
+func TestComment_CrowdControlChain(t *testing.T) {
+	_, byID := moderatedThreadComments(t)
+
+	for _, id := range []string{"l0ccc01", "l0ccc02"} {
+		c := byID[id]
+		assert.True(t, c.Collapsed, id)
+		assert.Equal(t, CollapsedCrowdControl, c.CollapsedReasonCode, id)
+		assert.Empty(t, c.CollapsedReason, id)
+		assert.True(t, c.IsCrowdControlled(), id)
+	}
+
+	// The OP's reply at the end of the chain is not collapsed itself
+	assert.False(t, byID["l0ccc03"].Collapsed)
+	assert.False(t, byID["l0ccc03"].IsCrowdControlled())
+
+	lowScore := byID["l0eee01"]
+	assert.True(t, lowScore.Collapsed)
+	assert.Equal(t, CollapsedLowScore, lowScore.CollapsedReasonCode)
+	assert.Equal(t, "comment score below threshold", lowScore.CollapsedReason)
+	assert.False(t, lowScore.IsCrowdControlled())
+}
+
+func TestComment_DisplayScore(t *testing.T) {
+	_, byID := moderatedThreadComments(t)
+
+	tests := []struct {
+		id        string
+		wantScore int
+		wantOK    bool
+	}{
+		{"l0bbb01", 57, true},
+		{"l0eee01", -6, true},
+		{"l0aaa01", 1, true},
+		{"l0ddd01", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			c := byID[tt.id]
+			require.NotNil(t, c)
+			score, ok := c.DisplayScore()
+			assert.Equal(t, tt.wantScore, score)
+			assert.Equal(t, tt.wantOK, ok)
+		})
+	}
+
+	// The placeholder score is still decoded for callers that want it
+	assert.Equal(t, 1, byID["l0ddd01"].Score)
+	assert.True(t, byID["l0ddd01"].ScoreHidden)
+}
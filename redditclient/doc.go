@@ -0,0 +1,11 @@
+// Package redditclient is a client for Reddit's OAuth API that authenticates
+// as the official Android app, so no registered API key is needed.
+//
+// Create a client with NewClient, call Authenticate once, and then use the
+// listing, comment, user and search methods. A Client is safe for concurrent
+// use.
+//
+// Endpoints without a method of their own can be reached with GetJSON, or
+// GetRaw for the undecoded body. They share the authentication, rate limiting
+// and error handling of the rest of the client.
+package redditclient
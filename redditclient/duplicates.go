@@ -0,0 +1,129 @@
+package redditclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// GetDuplicates fetches a post and the other posts of the same link, as shown
+// on its "other discussions" tab. Use GetDuplicatesWithOptions to sort the
+// duplicates or read the cursor for the next page.
+func (c *Client) GetDuplicates(ctx context.Context, postID string, opts *ListingOptions) (*Post, []Post, error) {
+	var dupOpts *DuplicatesOptions
+	if opts != nil {
+		dupOpts = &DuplicatesOptions{ListingOptions: *opts}
+	}
+
+	resp, err := c.GetDuplicatesWithOptions(ctx, postID, dupOpts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	post, err := resp.Post()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	duplicates := make([]Post, len(resp.DuplicateListing.Data.Children))
+	for i, child := range resp.DuplicateListing.Data.Children {
+		duplicates[i] = child.Data
+	}
+	return post, duplicates, nil
+}
+
+// GetDuplicatesWithOptions fetches a post and a page of its duplicates
+func (c *Client) GetDuplicatesWithOptions(ctx context.Context, postID string, opts *DuplicatesOptions) (*DuplicatesResponse, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	if opts == nil {
+		opts = &DuplicatesOptions{}
+	}
+	if err := validateOptional(opts.Sort, opts.Sort.validate); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	opts.ListingOptions.apply(params)
+	if opts.Sort != "" {
+		params.Set("sort", string(opts.Sort))
+	}
+	if opts.CrosspostsOnly {
+		params.Set("crossposts_only", "true")
+	}
+
+	endpoint := fmt.Sprintf("/duplicates/%s.json", url.PathEscape(postID))
+
+	var resp DuplicatesResponse
+	if err := c.streamAPIRequest(ctx, endpoint, params, &resp, "duplicates"); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// UnmarshalJSON decodes the [post listing, duplicates listing] array
+func (r *DuplicatesResponse) UnmarshalJSON(data []byte) error {
+	raw, err := splitPair(data, "duplicates response")
+	if err != nil {
+		return err
+	}
+
+	var listings [2]PostListing
+	for i := range listings {
+		if err := json.Unmarshal(raw[i], &listings[i]); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", duplicatesElems[i], err)
+		}
+	}
+
+	return r.set(listings)
+}
+
+// decodeStream decodes the [post listing, duplicates listing] array
+func (r *DuplicatesResponse) decodeStream(dec *json.Decoder) error {
+	var listings [2]PostListing
+	err := decodePairStream(dec, "duplicates response", func(i int) error {
+		if err := listings[i].decodeStream(dec); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", duplicatesElems[i], err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.set(listings)
+}
+
+// duplicatesElems names the elements of a duplicates response in errors
+var duplicatesElems = [2]string{"post listing", "duplicates listing"}
+
+// set checks that both listings hold posts before storing them
+func (r *DuplicatesResponse) set(listings [2]PostListing) error {
+	for i := range listings {
+		if err := checkPostListing(&listings[i]); err != nil {
+			return fmt.Errorf("%s: %w", duplicatesElems[i], err)
+		}
+	}
+
+	r.PostListing, r.DuplicateListing = listings[0], listings[1]
+	return nil
+}
+
+// MarshalJSON encodes the response back into Reddit's two-element array shape
+func (r DuplicatesResponse) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]interface{}{r.PostListing, r.DuplicateListing})
+}
+
+// Post returns the post whose duplicates were fetched
+func (r *DuplicatesResponse) Post() (*Post, error) {
+	if len(r.PostListing.Data.Children) == 0 {
+		return nil, errors.New("post listing is empty")
+	}
+
+	return &r.PostListing.Data.Children[0].Data, nil
+}
@@ -0,0 +1,90 @@
+package redditclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const duplicatesBody = `[
+	{"kind": "Listing", "data": {"children": [{"kind": "t3", "data": {"id": "abc", "name": "t3_abc",
+		"title": "Go 1.24 released", "url": "https://go.dev/blog/go1.24", "subreddit": "golang"}}]}},
+	{"kind": "Listing", "data": {"after": "t3_d2", "children": [
+		{"kind": "t3", "data": {"id": "d1", "name": "t3_d1", "url": "https://go.dev/blog/go1.24", "subreddit": "programming", "num_comments": 80}},
+		{"kind": "t3", "data": {"id": "d2", "name": "t3_d2", "url": "https://go.dev/blog/go1.24", "subreddit": "hackernews", "num_comments": 3}}
+	]}}
+]`
+
+func TestGetDuplicates_Success(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.Respond("/duplicates/abc.json", http.StatusOK, duplicatesBody)
+
+	post, duplicates, err := client.GetDuplicates(t.Context(), "abc", &ListingOptions{Limit: 2, After: "t3_prev"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Go 1.24 released", post.Title)
+	require.Len(t, duplicates, 2)
+	assert.Equal(t, "programming", duplicates[0].Subreddit)
+	assert.Equal(t, "hackernews", duplicates[1].Subreddit)
+
+	req, ok := srv.LastRequest("/duplicates/abc.json")
+	require.True(t, ok)
+	assert.Equal(t, "2", req.Query.Get("limit"))
+	assert.Equal(t, "t3_prev", req.Query.Get("after"))
+	assert.False(t, req.Query.Has("sort"))
+}
+
+func TestGetDuplicatesWithOptions_SortAndCursor(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.Respond("/duplicates/abc.json", http.StatusOK, duplicatesBody)
+
+	resp, err := client.GetDuplicatesWithOptions(t.Context(), "abc", &DuplicatesOptions{
+		Sort:           DuplicatesSortComments,
+		CrosspostsOnly: true,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "t3_d2", resp.DuplicateListing.Data.After)
+
+	req, ok := srv.LastRequest("/duplicates/abc.json")
+	require.True(t, ok)
+	assert.Equal(t, "num_comments", req.Query.Get("sort"))
+	assert.Equal(t, "true", req.Query.Get("crossposts_only"))
+}
+
+func TestGetDuplicates_BufferedDecode(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	srv.Respond("/duplicates/abc.json", http.StatusOK, duplicatesBody)
+	client, err := NewClient(WithBaseURL(srv.URL), WithCache(NewMemoryCache(10), time.Minute))
+	require.NoError(t, err)
+	require.NoError(t, client.Authenticate(t.Context()))
+
+	post, duplicates, err := client.GetDuplicates(t.Context(), "abc", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "abc", post.ID)
+	assert.Len(t, duplicates, 2)
+}
+
+func TestGetDuplicates_Errors(t *testing.T) {
+	client, _ := newAuthenticatedFakeServerClient(t)
+	_, err := client.GetDuplicatesWithOptions(t.Context(), "abc", &DuplicatesOptions{Sort: "top"})
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+
+	unauthenticated, err := NewClient(WithHTTPClient(&MockHTTPClient{}))
+	require.NoError(t, err)
+	_, _, err = unauthenticated.GetDuplicates(t.Context(), "abc", nil)
+	assert.ErrorIs(t, err, ErrNotAuthenticated)
+}
+
+func TestDuplicatesResponse_RejectsComments(t *testing.T) {
+	var resp DuplicatesResponse
+	err := json.Unmarshal([]byte(`[{"kind": "Listing", "data": {"children": []}},
+		{"kind": "Listing", "data": {"children": [{"kind": "t1", "data": {"id": "c1"}}]}}]`), &resp)
+
+	assert.ErrorContains(t, err, "duplicates listing")
+}
@@ -0,0 +1,237 @@
+package redditclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// acceptEncoding lists every content coding openResponseBody can decode
+const acceptEncoding = "gzip, br, zstd"
+
+// ErrUnsupportedEncoding is returned when a response uses a Content-Encoding the client cannot decode
+var ErrUnsupportedEncoding = errors.New("unsupported content encoding")
+
+// readResponseBody reads the response body, decoding it according to its Content-Encoding
+func (c *Client) readResponseBody(resp *http.Response) ([]byte, error) {
+	body, err := c.openResponseBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bodyBufferPool.Get().(*bytes.Buffer)
+	defer putBodyBuffer(buf)
+
+	_, err = buf.ReadFrom(body)
+	closeErr := body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if closeErr != nil {
+		return nil, closeErr
+	}
+
+	// The buffer goes back to the pool, so the caller gets an exact-size copy
+	return bytes.Clone(buf.Bytes()), nil
+}
+
+// bodyBufferPool holds the buffers readResponseBody reads into. Reading into
+// a fresh slice grows it a dozen times over for a large listing; a pooled
+// buffer is already big enough.
+var bodyBufferPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+func putBodyBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBodyBuffer {
+		return
+	}
+	buf.Reset()
+	bodyBufferPool.Put(buf)
+}
+
+// openResponseBody returns a reader that decodes resp.Body according to its
+// Content-Encoding. The caller must Close it to return pooled decoders; it does
+// not close resp.Body.
+func (c *Client) openResponseBody(resp *http.Response) (io.ReadCloser, error) {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+
+	// The limit applies to the bytes on the wire and again to the decoded
+	// output, so a small compressed bomb cannot expand without bound
+	raw := limitBody(resp.Body, c.maxRespBytes)
+
+	var decoded io.ReadCloser
+	var err error
+	switch encoding {
+	case "", "identity":
+		return io.NopCloser(raw), nil
+	case "gzip", "x-gzip":
+		decoded, err = c.openGzip(raw)
+	case "br":
+		decoded, err = c.openBrotli(raw)
+	case "zstd":
+		decoded, err = c.openZstd(raw)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedEncoding, encoding)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{limitBody(decoded, c.maxRespBytes), decoded}, nil
+}
+
+// limitedBody fails with ErrResponseTooLarge once more than limit bytes are read,
+// where a bare io.LimitReader would silently truncate the body. The error is
+// sticky: later reads return it without touching r.
+type limitedBody struct {
+	r     io.Reader // Limited to one byte past limit to detect overflow
+	read  int64
+	limit int64
+	err   error
+}
+
+// limitBody caps r at limit bytes; a limit of zero or less leaves r unbounded
+func limitBody(r io.Reader, limit int64) io.Reader {
+	if limit <= 0 {
+		return r
+	}
+	return &limitedBody{r: io.LimitReader(r, limit+1), limit: limit}
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if overflow := l.read - l.limit; overflow > 0 {
+		l.err = fmt.Errorf("%w: exceeds %d bytes", ErrResponseTooLarge, l.limit)
+		return max(n-int(overflow), 0), l.err
+	}
+	return n, err
+}
+
+// decodedBody streams a decompressed body. Its decoder goes back to the pool on
+// Close only if the stream was read cleanly to the end; a decoder that failed or
+// was abandoned mid-stream is dropped.
+type decodedBody struct {
+	r        io.Reader
+	encoding string
+	eof      bool
+	err      error
+	release  func() error // Pools the decoder after a clean read
+	discard  func()       // Frees a decoder that will not be pooled; may be nil
+}
+
+func (b *decodedBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	switch {
+	case err == io.EOF:
+		b.eof = true
+	case err != nil:
+		b.err = err
+		err = fmt.Errorf("failed to decompress %s body: %w", b.encoding, err)
+	}
+	return n, err
+}
+
+func (b *decodedBody) Close() error {
+	if b.eof && b.err == nil {
+		return b.release()
+	}
+	if b.discard != nil {
+		b.discard()
+	}
+	return nil
+}
+
+// openGzip decompresses a gzip body with a pooled reader
+func (c *Client) openGzip(body io.Reader) (io.ReadCloser, error) {
+	gr, _ := c.gzipReaderPool.Get().(*gzip.Reader)
+	if gr == nil {
+		var err error
+		gr, err = gzip.NewReader(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+	} else if err := gr.Reset(body); err != nil {
+		return nil, fmt.Errorf("failed to reset gzip reader: %w", err)
+	}
+
+	return &decodedBody{
+		r:        gr,
+		encoding: "gzip",
+		release: func() error {
+			if err := gr.Close(); err != nil {
+				return fmt.Errorf("failed to close gzip reader: %w", err)
+			}
+			// Only a cleanly finished reader is safe to Reset onto the next body
+			c.gzipReaderPool.Put(gr)
+			return nil
+		},
+	}, nil
+}
+
+// openBrotli decompresses a brotli body with a pooled reader
+func (c *Client) openBrotli(body io.Reader) (io.ReadCloser, error) {
+	br, _ := c.brotliPool.Get().(*brotli.Reader)
+	if br == nil {
+		br = brotli.NewReader(body)
+	} else if err := br.Reset(body); err != nil {
+		return nil, fmt.Errorf("failed to reset brotli reader: %w", err)
+	}
+
+	return &decodedBody{
+		r:        br,
+		encoding: "brotli",
+		release: func() error {
+			// Drop the reference to the body before pooling
+			if err := br.Reset(nil); err == nil {
+				c.brotliPool.Put(br)
+			}
+			return nil
+		},
+	}, nil
+}
+
+// openZstd decompresses a zstd body with a pooled decoder
+func (c *Client) openZstd(body io.Reader) (io.ReadCloser, error) {
+	zr, _ := c.zstdPool.Get().(*zstd.Decoder)
+	if zr == nil {
+		// A concurrency of 1 decodes synchronously, so pooled decoders hold no goroutines
+		var err error
+		zr, err = zstd.NewReader(body, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd reader: %w", err)
+		}
+	} else if err := zr.Reset(body); err != nil {
+		zr.Close()
+		return nil, fmt.Errorf("failed to reset zstd reader: %w", err)
+	}
+
+	return &decodedBody{
+		r:        zr,
+		encoding: "zstd",
+		release: func() error {
+			// Drop the reference to the body before pooling
+			if err := zr.Reset(nil); err == nil {
+				c.zstdPool.Put(zr)
+			} else {
+				zr.Close()
+			}
+			return nil
+		},
+		discard: zr.Close,
+	}, nil
+}
@@ -0,0 +1,255 @@
+package redditclient
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// compress encodes content with the named content coding
+func compress(t testing.TB, encoding string, content []byte) []byte {
+	var buf bytes.Buffer
+	switch encoding {
+	case "gzip":
+		w := gzip.NewWriter(&buf)
+		_, err := w.Write(content)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	case "br":
+		w := brotli.NewWriter(&buf)
+		_, err := w.Write(content)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	case "zstd":
+		w, err := zstd.NewWriter(&buf)
+		require.NoError(t, err)
+		_, err = w.Write(content)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	default:
+		buf.Write(content)
+	}
+	return buf.Bytes()
+}
+
+func TestReadResponseBody_Encodings(t *testing.T) {
+	content := []byte(createListingPage([]string{"a", "b", "c"}, "t3_c"))
+
+	for _, encoding := range []string{"", "identity", "gzip", "br", "zstd"} {
+		t.Run(encoding, func(t *testing.T) {
+			client, err := NewClient()
+			require.NoError(t, err)
+			body := string(compress(t, encoding, content))
+
+			// Decode twice so the second pass goes through a pooled reader
+			for range 2 {
+				resp := createHTTPResponse(200, body, map[string]string{"Content-Encoding": encoding})
+				data, err := client.readResponseBody(resp)
+				require.NoError(t, err)
+				assert.Equal(t, content, data)
+			}
+		})
+	}
+}
+
+func TestReadResponseBody_CorruptBodyThenValid(t *testing.T) {
+	content := []byte(createListingPage([]string{"a"}, ""))
+
+	for _, encoding := range []string{"br", "zstd"} {
+		t.Run(encoding, func(t *testing.T) {
+			client, err := NewClient()
+			require.NoError(t, err)
+			valid := compress(t, encoding, content)
+			truncated := valid[:len(valid)/2]
+
+			_, err = client.readResponseBody(createHTTPResponse(200, string(truncated), map[string]string{"Content-Encoding": encoding}))
+			require.Error(t, err)
+
+			data, err := client.readResponseBody(createHTTPResponse(200, string(valid), map[string]string{"Content-Encoding": encoding}))
+			require.NoError(t, err)
+			assert.Equal(t, content, data)
+		})
+	}
+}
+
+func TestReadResponseBody_UnsupportedEncoding(t *testing.T) {
+	client, err := NewClient()
+	require.NoError(t, err)
+
+	resp := createHTTPResponse(200, "data", map[string]string{"Content-Encoding": "compress"})
+	_, err = client.readResponseBody(resp)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrUnsupportedEncoding)
+	assert.Contains(t, err.Error(), `"compress"`)
+}
+
+func TestReadResponseBody_DecompressionBomb(t *testing.T) {
+	// 24MB of zeros compresses to well under the default limit
+	bomb := bytes.Repeat([]byte{0}, 24<<20)
+
+	for _, encoding := range []string{"gzip", "br", "zstd"} {
+		t.Run(encoding, func(t *testing.T) {
+			client, err := NewClient()
+			require.NoError(t, err)
+			compressed := compress(t, encoding, bomb)
+			require.Less(t, len(compressed), DefaultMaxResponseBytes)
+
+			resp := createHTTPResponse(200, string(compressed), map[string]string{"Content-Encoding": encoding})
+			data, err := client.readResponseBody(resp)
+
+			assert.ErrorIs(t, err, ErrResponseTooLarge)
+			assert.Nil(t, data)
+		})
+	}
+}
+
+// randomBytes returns n deterministic, incompressible bytes
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	rand.New(rand.NewSource(1)).Read(b)
+	return b
+}
+
+func TestReadResponseBody_MaxResponseBytes(t *testing.T) {
+	const limit = 1024
+
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+		tooLarge bool
+	}{
+		{name: "identity at limit", body: bytes.Repeat([]byte("a"), limit)},
+		{name: "identity over limit", body: bytes.Repeat([]byte("a"), limit+1), tooLarge: true},
+		{name: "gzip expands past limit", encoding: "gzip", body: bytes.Repeat([]byte("a"), limit+1), tooLarge: true},
+		{name: "gzip within limit", encoding: "gzip", body: bytes.Repeat([]byte("a"), limit)},
+		// Random bytes do not compress, so only the wire size exceeds the limit
+		{name: "compressed stream over limit", encoding: "gzip", body: randomBytes(limit - 8), tooLarge: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(WithMaxResponseBytes(limit))
+			require.NoError(t, err)
+
+			resp := createHTTPResponse(200, string(compress(t, tt.encoding, tt.body)), map[string]string{"Content-Encoding": tt.encoding})
+			data, err := client.readResponseBody(resp)
+
+			if tt.tooLarge {
+				assert.ErrorIs(t, err, ErrResponseTooLarge)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, data)
+		})
+	}
+}
+
+func TestLimitBody_ReadAfterTooLarge(t *testing.T) {
+	src := bytes.NewReader([]byte("abcdefgh"))
+	body := limitBody(src, 4)
+
+	p := make([]byte, 2)
+	for range 2 {
+		n, err := body.Read(p)
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+	}
+
+	// The fifth byte crosses the limit, and every later read fails the same
+	// way without reading src
+	for range 3 {
+		n, err := body.Read(p)
+		assert.Equal(t, 0, n)
+		assert.ErrorIs(t, err, ErrResponseTooLarge)
+	}
+	assert.Equal(t, 3, src.Len())
+}
+
+func TestLimitBody_Buffered(t *testing.T) {
+	// bufio panics on a negative count from Read
+	r := bufio.NewReaderSize(limitBody(bytes.NewReader(bytes.Repeat([]byte("a"), 100)), 20), 16)
+
+	data, err := io.ReadAll(r)
+	assert.ErrorIs(t, err, ErrResponseTooLarge)
+	assert.Len(t, data, 20)
+
+	// bufio hands the error out once, then reads again
+	_, err = r.Read(make([]byte, 8))
+	assert.ErrorIs(t, err, ErrResponseTooLarge)
+}
+
+func TestGetComments_ResponseTooLarge(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP), WithMaxResponseBytes(4096))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, string(compress(t, "zstd", []byte(largeCommentThread(100)))), map[string]string{
+			"Content-Encoding": "zstd",
+		}), nil).Once()
+
+	_, err = client.GetComments(t.Context(), "golang", "abc123", "")
+
+	assert.ErrorIs(t, err, ErrResponseTooLarge)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGetSubreddit_AdvertisesSupportedEncodings(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	var acceptEncodingHeader string
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Run(func(args mock.Arguments) {
+			acceptEncodingHeader = args.Get(0).(*http.Request).Header.Get("Accept-Encoding")
+		}).
+		Return(createHTTPResponse(200, string(compress(t, "zstd", []byte(createListingPage([]string{"a"}, "")))), map[string]string{
+			"Content-Encoding": "zstd",
+		}), nil).Once()
+
+	result, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+	require.NoError(t, err)
+	require.Len(t, result.Data.Children, 1)
+	assert.Equal(t, "gzip, br, zstd", acceptEncodingHeader)
+	mockHTTP.AssertExpectations(t)
+}
+
+func BenchmarkReadResponseBody(b *testing.B) {
+	ids := make([]string, 1000)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("post%d", i)
+	}
+	content := []byte(createListingPage(ids, "t3_post999"))
+
+	for _, encoding := range []string{"identity", "gzip", "br", "zstd"} {
+		b.Run(encoding, func(b *testing.B) {
+			client, err := NewClient()
+			require.NoError(b, err)
+			body := string(compress(b, encoding, content))
+			headers := map[string]string{"Content-Encoding": encoding}
+
+			b.ReportAllocs()
+			b.SetBytes(int64(len(content)))
+			for b.Loop() {
+				if _, err := client.readResponseBody(createHTTPResponse(200, body, headers)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,388 @@
+package redditclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sentinel errors matched by APIError via errors.Is
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServerError  = errors.New("reddit server error")
+)
+
+// Sentinel errors for subreddits Reddit refuses to serve. They match whether
+// Reddit signals the restriction with a 403/404 status or a {"reason": ...}
+// notice in a 200 body.
+var (
+	ErrSubredditPrivate = errors.New("subreddit is private")
+	ErrSubredditBanned  = errors.New("subreddit is banned")
+	ErrSubredditGated   = errors.New("subreddit is gated")
+)
+
+// Sentinel errors returned by GetUser for accounts Reddit will not show.
+// Shadowbanned accounts are indistinguishable from missing ones and return
+// ErrUserNotFound.
+var (
+	ErrUserNotFound  = errors.New("user not found")
+	ErrUserSuspended = errors.New("user is suspended")
+)
+
+// ErrInvalidArgument is returned before any request is made when an argument,
+// such as a sort or timeframe, is not one Reddit accepts
+var ErrInvalidArgument = errors.New("invalid argument")
+
+// ErrResponseTooLarge is returned when a response body, compressed or not,
+// exceeds the client's maximum response size
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// ErrSearchExhausted is yielded by SearchAll when it stops because Reddit
+// will not page past maxSearchResults results, rather than because the
+// search has no more matches
+var ErrSearchExhausted = errors.New("reddit search result limit reached")
+
+// ErrSubredditNotAllowed is matched by an ActionError when Reddit refuses a
+// submission or comment because the user is banned from, or may not post in,
+// the subreddit
+var ErrSubredditNotAllowed = errors.New("not allowed to post in subreddit")
+
+// APIError describes a non-200 response from the Reddit API
+type APIError struct {
+	StatusCode int
+	Endpoint   string
+	Body       string // Response body with credentials masked, truncated; see WithMaxErrorBodyBytes
+	Message    string // Parsed human-readable message, if Reddit sent one
+	Reason     string // Parsed "reason" field, if Reddit sent one
+	Code       string // Upper-case error code, e.g. "SUBREDDIT_NOEXIST", if Reddit sent one
+	Field      string // Request parameter the error refers to, if any
+}
+
+// newAPIError builds an APIError from a response, parsing whichever of
+// Reddit's error shapes the body holds:
+//
+//	{"message": "Not Found", "error": 404}
+//	{"reason": "private", "message": "Forbidden", "error": 403}
+//	{"reason": "USER_DOESNT_EXIST", "explanation": "...", "fields": ["id"]}
+//	{"json": {"errors": [["SUBREDDIT_NOEXIST", "that subreddit doesn't exist", "sr"]]}}
+func newAPIError(endpoint string, statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Endpoint:   endpoint,
+		Body:       truncateBody(body, DefaultMaxErrorBodyBytes),
+	}
+
+	var parsed struct {
+		Message     string   `json:"message"`
+		Reason      string   `json:"reason"`
+		Explanation string   `json:"explanation"`
+		Fields      []string `json:"fields"`
+		JSON        struct {
+			Errors [][]string `json:"errors"`
+		} `json:"json"`
+	}
+	if json.Unmarshal(body, &parsed) != nil {
+		return apiErr
+	}
+
+	apiErr.Message = parsed.Message
+	apiErr.Reason = parsed.Reason
+	if parsed.Explanation != "" {
+		apiErr.Message = parsed.Explanation
+	}
+	if isErrorCode(parsed.Reason) {
+		apiErr.Code = parsed.Reason
+	}
+	if len(parsed.Fields) > 0 {
+		apiErr.Field = parsed.Fields[0]
+	}
+	if len(parsed.JSON.Errors) > 0 {
+		apiErr.Code, apiErr.Message, apiErr.Field = errorTriple(parsed.JSON.Errors[0])
+	}
+
+	return apiErr
+}
+
+// isErrorCode reports whether a reason is an error code like
+// "USER_DOESNT_EXIST" rather than a restriction like "private"
+func isErrorCode(reason string) bool {
+	return reason != "" && reason == strings.ToUpper(reason)
+}
+
+// errorTriple unpacks one [code, message, field] entry of a json.errors array
+func errorTriple(entry []string) (code, message, field string) {
+	entry = append(entry, "", "", "")
+	return entry[0], entry[1], entry[2]
+}
+
+func (e *APIError) Error() string {
+	detail := e.Body
+	if e.Message != "" {
+		detail = e.Message
+	}
+	if e.Code != "" {
+		detail = e.Code + ": " + detail
+	}
+	return fmt.Sprintf("API request to %s failed with status %d: %s", e.Endpoint, e.StatusCode, detail)
+}
+
+// RateLimitError is returned for a 429 response. It wraps the APIError, so it
+// matches ErrRateLimited, and carries how long Reddit asked the client to wait.
+type RateLimitError struct {
+	*APIError
+	RetryAfter time.Duration // Zero when the response gave no Retry-After or x-ratelimit-reset
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter <= 0 {
+		return e.APIError.Error()
+	}
+	return fmt.Sprintf("%s (retry after %s)", e.APIError.Error(), e.RetryAfter)
+}
+
+// Unwrap returns the underlying APIError
+func (e *RateLimitError) Unwrap() error {
+	return e.APIError
+}
+
+// Is matches ErrRateLimited even when Reddit reported the limit in the body of
+// a 200 response rather than with a 429
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// ActionError is an error Reddit reported in the json.errors array of a 200
+// response, such as SUBREDDIT_NOTALLOWED from a form POST or SUBREDDIT_NOEXIST
+// from an api_type=json GET. RATELIMIT errors are returned as a
+// *RateLimitError instead. Errors in the same shape with a non-200 status are
+// returned as an *APIError with Code and Field set.
+type ActionError struct {
+	Endpoint string
+	Code     string // Upper-case error code, e.g. "THREAD_LOCKED"
+	Message  string // Human-readable explanation
+	Field    string // Form field the error refers to, if any
+}
+
+func (e *ActionError) Error() string {
+	return fmt.Sprintf("API request to %s failed: %s: %s", e.Endpoint, e.Code, e.Message)
+}
+
+// Is matches ErrSubredditNotAllowed, and ErrForbidden, for SUBREDDIT_NOTALLOWED errors
+func (e *ActionError) Is(target error) bool {
+	switch target {
+	case ErrSubredditNotAllowed, ErrForbidden:
+		return e.Code == "SUBREDDIT_NOTALLOWED"
+	}
+	return false
+}
+
+// formErrors returns the first error in the json.errors array of a 200
+// response, or nil if there is none
+func formErrors(endpoint string, body []byte) error {
+	var parsed struct {
+		JSON struct {
+			Errors    [][]string `json:"errors"`
+			RateLimit float64    `json:"ratelimit"` // Seconds until the action is allowed again
+		} `json:"json"`
+	}
+	if json.Unmarshal(body, &parsed) != nil || len(parsed.JSON.Errors) == 0 {
+		return nil
+	}
+
+	code, message, field := errorTriple(parsed.JSON.Errors[0])
+
+	if code != "RATELIMIT" {
+		return &ActionError{Endpoint: endpoint, Code: code, Message: message, Field: field}
+	}
+
+	wait := time.Duration(parsed.JSON.RateLimit * float64(time.Second))
+	if wait <= 0 {
+		wait = parseRateLimitMessage(message)
+	}
+	return &RateLimitError{
+		APIError: &APIError{
+			StatusCode: http.StatusOK,
+			Endpoint:   endpoint,
+			Body:       truncateBody(body, DefaultMaxErrorBodyBytes),
+			Message:    message,
+			Code:       code,
+			Field:      field,
+		},
+		RetryAfter: wait,
+	}
+}
+
+// jsonErrors is formErrors for responses that are usually something other
+// than a {"json": ...} envelope, such as listings. It only parses body when it
+// starts like an envelope.
+func jsonErrors(endpoint string, body []byte) error {
+	if !bytes.HasPrefix(bytes.TrimLeft(body, " \t\r\n"), []byte(`{"json"`)) {
+		return nil
+	}
+	return formErrors(endpoint, body)
+}
+
+// rateLimitMessagePattern finds the wait in messages like "you are doing that
+// too much. try again in 5 minutes."
+var rateLimitMessagePattern = regexp.MustCompile(`(\d+) (second|minute|hour)s?`)
+
+// parseRateLimitMessage returns the wait named in a RATELIMIT message, or zero
+func parseRateLimitMessage(message string) time.Duration {
+	match := rateLimitMessagePattern.FindStringSubmatch(message)
+	if match == nil {
+		return 0
+	}
+
+	n, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0
+	}
+
+	unit := map[string]time.Duration{"second": time.Second, "minute": time.Minute, "hour": time.Hour}[match[2]]
+	return time.Duration(n) * unit
+}
+
+// QuarantineError is returned for a quarantined subreddit whose content could
+// not be opted into
+type QuarantineError struct {
+	Message  string    // Reddit's quarantine notice, if it sent one
+	APIError *APIError // The 403 response; nil when the notice came in a 200 body
+}
+
+func (e *QuarantineError) Error() string {
+	if e.Message == "" {
+		return "subreddit is quarantined"
+	}
+	return "subreddit is quarantined: " + e.Message
+}
+
+// Unwrap returns the underlying APIError, if any
+func (e *QuarantineError) Unwrap() error {
+	if e.APIError == nil {
+		return nil
+	}
+	return e.APIError
+}
+
+// ErrStillRestricted is matched by a StillRestrictedError
+var ErrStillRestricted = errors.New("content still restricted after opting in")
+
+// StillRestrictedError is returned when content stays restricted after the
+// client opted into it. Err is the typed error for the final reason, such as
+// one matching ErrSubredditGated.
+type StillRestrictedError struct {
+	Reason string // Final restriction reason Reddit gave
+	Err    error
+}
+
+func (e *StillRestrictedError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrStillRestricted, e.Err)
+}
+
+// Unwrap returns the typed error for the final reason
+func (e *StillRestrictedError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches ErrStillRestricted
+func (e *StillRestrictedError) Is(target error) bool {
+	return target == ErrStillRestricted
+}
+
+// restrictionError turns a restriction notice from a 200 body into the
+// matching typed error
+func restrictionError(endpoint string, notice ErrorResponse) error {
+	switch notice.Reason {
+	case "private":
+		return fmt.Errorf("%s: %w", endpoint, ErrSubredditPrivate)
+	case "banned":
+		return fmt.Errorf("%s: %w", endpoint, ErrSubredditBanned)
+	case "gated":
+		return fmt.Errorf("%s: %w", endpoint, ErrSubredditGated)
+	case "quarantined":
+		return &QuarantineError{Message: notice.QuarantineMessage}
+	default:
+		return fmt.Errorf("%s: unknown content restriction: %s", endpoint, notice.Reason)
+	}
+}
+
+// statusError builds the error for a non-200 response: a *RateLimitError for
+// 429s, a *QuarantineError for quarantine notices and an *APIError otherwise
+func (c *Client) statusError(endpoint string, resp *http.Response, body []byte) error {
+	apiErr := newAPIError(endpoint, resp.StatusCode, body)
+	apiErr.Body = c.errorBody(body)
+
+	if apiErr.Reason == "quarantined" {
+		var notice ErrorResponse
+		_ = json.Unmarshal(body, &notice)
+		return &QuarantineError{Message: notice.QuarantineMessage, APIError: apiErr}
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return apiErr
+	}
+
+	wait, _ := retryAfter(resp.Header, c.now())
+	return &RateLimitError{APIError: apiErr, RetryAfter: wait}
+}
+
+// Is lets errors.Is match an APIError against the status sentinels
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound || e.Reason == "PAGE_NOT_CREATED"
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServerError:
+		return e.StatusCode >= http.StatusInternalServerError
+	case ErrSubredditPrivate:
+		return e.Reason == "private"
+	case ErrSubredditBanned:
+		return e.Reason == "banned"
+	case ErrSubredditGated:
+		return e.Reason == "gated"
+	case ErrUserNotFound:
+		return e.Reason == "USER_DOESNT_EXIST"
+	}
+	return false
+}
+
+// withErrorBody replaces the body on an APIError within err, which package
+// level helpers fill in with the default limit, with c.errorBody(body)
+func (c *Client) withErrorBody(err error, body []byte) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		apiErr.Body = c.errorBody(body)
+	}
+	return err
+}
+
+// errorBody returns body for an APIError: with the client's credentials masked
+// and cut to its maximum error body length
+func (c *Client) errorBody(body []byte) string {
+	return truncateBody([]byte(c.redactCredentials(string(body))), c.maxErrorBody)
+}
+
+// truncateBody returns body as a string cut to at most limit bytes
+func truncateBody(body []byte, limit int) string {
+	if limit == 0 {
+		return ""
+	}
+	if len(body) <= limit {
+		return string(body)
+	}
+	return string(body[:limit]) + "..."
+}
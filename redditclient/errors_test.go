@@ -0,0 +1,258 @@
+package redditclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIError_StatusSentinels(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		sentinel error
+		message  string
+	}{
+		{"forbidden", 403, `{"message": "Forbidden", "error": 403}`, ErrForbidden, "Forbidden"},
+		{"not found", 404, `{"message": "Not Found", "error": 404}`, ErrNotFound, "Not Found"},
+		{"rate limited", 429, `{"message": "Too Many Requests", "error": 429}`, ErrRateLimited, "Too Many Requests"},
+		{"server error", 500, `<html>Internal Server Error</html>`, ErrServerError, ""},
+	}
+
+	calls := map[string]func(*Client) error{
+		"GetSubreddit": func(c *Client) error {
+			_, err := c.GetSubreddit(t.Context(), "golang", "hot")
+			return err
+		},
+		"GetPost": func(c *Client) error {
+			_, err := c.GetPost(t.Context(), "golang", "abc123")
+			return err
+		},
+		"GetUser": func(c *Client) error {
+			_, err := c.GetUser(t.Context(), "testuser")
+			return err
+		},
+		"Search": func(c *Client) error {
+			_, err := c.Search(t.Context(), "golang", "top", "week")
+			return err
+		},
+	}
+
+	for _, tt := range tests {
+		for method, call := range calls {
+			t.Run(tt.name+"/"+method, func(t *testing.T) {
+				client, mockHTTP := newAuthenticatedTestClient(t)
+				mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+					Return(createHTTPResponse(tt.status, tt.body, nil), nil).Once()
+
+				err := call(client)
+
+				require.Error(t, err)
+				assert.ErrorIs(t, err, tt.sentinel)
+
+				var apiErr *APIError
+				require.True(t, errors.As(err, &apiErr))
+				assert.Equal(t, tt.status, apiErr.StatusCode)
+				assert.Equal(t, tt.message, apiErr.Message)
+				assert.True(t, strings.HasPrefix(apiErr.Endpoint, "/"))
+				mockHTTP.AssertExpectations(t)
+			})
+		}
+	}
+}
+
+func TestRateLimitError_RetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   time.Duration
+	}{
+		{"seconds", map[string]string{"Retry-After": "7"}, 7 * time.Second},
+		{"http date", map[string]string{"Retry-After": now.Add(2 * time.Minute).Format(http.TimeFormat)}, 2 * time.Minute},
+		{"ratelimit reset", map[string]string{"x-ratelimit-reset": "30"}, 30 * time.Second},
+		{"missing", nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mockHTTP := newAuthenticatedTestClient(t)
+			client.now = func() time.Time { return now }
+			mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+				Return(createHTTPResponse(429, `{"message": "Too Many Requests", "error": 429}`, tt.header), nil).Once()
+
+			_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+			assert.ErrorIs(t, err, ErrRateLimited)
+			var rateErr *RateLimitError
+			require.True(t, errors.As(err, &rateErr))
+			assert.Equal(t, tt.want, rateErr.RetryAfter)
+			assert.Equal(t, http.StatusTooManyRequests, rateErr.StatusCode)
+			if tt.want > 0 {
+				assert.Contains(t, err.Error(), "retry after "+tt.want.String())
+			}
+			mockHTTP.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPIError_DoesNotMatchOtherSentinels(t *testing.T) {
+	err := error(&APIError{StatusCode: http.StatusNotFound})
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NotErrorIs(t, err, ErrForbidden)
+	assert.NotErrorIs(t, err, ErrRateLimited)
+	assert.NotErrorIs(t, err, ErrServerError)
+}
+
+func TestAPIError_TruncatesBody(t *testing.T) {
+	apiErr := newAPIError("/r/golang/hot.json", 500, []byte(strings.Repeat("x", 2000)))
+
+	assert.Len(t, apiErr.Body, DefaultMaxErrorBodyBytes+len("..."))
+	assert.Contains(t, apiErr.Error(), "/r/golang/hot.json")
+	assert.Contains(t, apiErr.Error(), "status 500")
+}
+
+func TestAPIError_RedactsCredentials(t *testing.T) {
+	client, mockHTTP, logs := newLoggingTestClient(t)
+
+	echo := `{"message": "bad request", "echo": "Bearer secret-token; loid=secret-loid; session=secret-session"}`
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(400, echo, nil), nil).Once()
+
+	_, err := client.Search(t.Context(), "golang", "", "")
+	require.Error(t, err)
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Contains(t, apiErr.Body, "secr[REDACTED]")
+	for _, secret := range []string{"secret-token", "secret-loid", "secret-session"} {
+		assert.NotContains(t, err.Error(), secret)
+		assert.NotContains(t, fmt.Sprintf("%+v", err), secret)
+		assert.NotContains(t, apiErr.Body, secret)
+		assert.NotContains(t, logs.String(), secret)
+	}
+}
+
+func TestAPIError_MaxErrorBodyBytes(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		want  string
+	}{
+		{"truncated", 8, `{"messag...`},
+		{"dropped", 0, ""},
+		{"beyond default", 1000, `{"message": "` + strings.Repeat("x", 600) + `"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHTTP := &MockHTTPClient{}
+			client, err := NewClient(WithHTTPClient(mockHTTP), WithMaxErrorBodyBytes(tt.limit))
+			require.NoError(t, err)
+			client.accessToken = "test-token"
+			client.authenticated = true
+
+			body := `{"message": "` + strings.Repeat("x", 600) + `"}`
+			mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(500, body, nil), nil).Once()
+
+			_, err = client.Search(t.Context(), "golang", "", "")
+
+			var apiErr *APIError
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tt.want, apiErr.Body)
+		})
+	}
+}
+
+func TestNewAPIError_Shapes(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantCode    string
+		wantMessage string
+		wantField   string
+		wantReason  string
+	}{
+		{"status message", 404, `{"message": "Not Found", "error": 404}`, "", "Not Found", "", ""},
+		{"restriction reason", 403, `{"reason": "private", "message": "Forbidden", "error": 403}`, "", "Forbidden", "", "private"},
+		{"explained code", 400, `{"fields": ["id"], "explanation": "that user doesn't exist", "message": "Bad Request", "reason": "USER_DOESNT_EXIST"}`,
+			"USER_DOESNT_EXIST", "that user doesn't exist", "id", "USER_DOESNT_EXIST"},
+		{"json errors", 400, `{"json": {"errors": [["SUBREDDIT_NOEXIST", "that subreddit doesn't exist", "sr"]]}}`,
+			"SUBREDDIT_NOEXIST", "that subreddit doesn't exist", "sr", ""},
+		{"short json error", 400, `{"json": {"errors": [["BAD_SR_NAME"]]}}`, "BAD_SR_NAME", "", "", ""},
+		{"not json", 502, `<html>Bad Gateway</html>`, "", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := newAPIError("/api/test", tt.status, []byte(tt.body))
+
+			assert.Equal(t, tt.wantCode, apiErr.Code)
+			assert.Equal(t, tt.wantMessage, apiErr.Message)
+			assert.Equal(t, tt.wantField, apiErr.Field)
+			assert.Equal(t, tt.wantReason, apiErr.Reason)
+			if tt.wantCode != "" {
+				assert.Contains(t, apiErr.Error(), tt.wantCode)
+			}
+		})
+	}
+}
+
+const noSubredditBody = `{"json": {"errors": [["SUBREDDIT_NOEXIST", "that subreddit doesn't exist", "sr"]]}}`
+
+func TestMakeAPIRequest_JSONErrorsOn200(t *testing.T) {
+	t.Run("streamed", func(t *testing.T) {
+		client, srv := newAuthenticatedFakeServerClient(t)
+		srv.Respond("/r/nope/hot.json", http.StatusOK, noSubredditBody)
+
+		_, err := client.GetSubreddit(t.Context(), "nope", "hot")
+
+		var actionErr *ActionError
+		require.ErrorAs(t, err, &actionErr)
+		assert.Equal(t, "SUBREDDIT_NOEXIST", actionErr.Code)
+		assert.Equal(t, "sr", actionErr.Field)
+	})
+
+	t.Run("buffered", func(t *testing.T) {
+		client, srv := newAuthenticatedFakeServerClient(t)
+		srv.Respond("/user/nope/about.json", http.StatusOK, noSubredditBody)
+
+		_, err := client.GetUser(t.Context(), "nope")
+
+		var actionErr *ActionError
+		require.ErrorAs(t, err, &actionErr)
+		assert.Equal(t, "SUBREDDIT_NOEXIST", actionErr.Code)
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		client, srv := newAuthenticatedFakeServerClient(t)
+		srv.Respond("/api/morechildren.json", http.StatusOK,
+			`{"json": {"errors": [["RATELIMIT", "you are doing that too much. try again in 2 minutes.", "ratelimit"]]}}`)
+
+		_, err := client.GetMoreComments(t.Context(), "t3_abc", []string{"c1"})
+
+		var rateErr *RateLimitError
+		require.ErrorAs(t, err, &rateErr)
+		assert.Equal(t, "RATELIMIT", rateErr.Code)
+		assert.Equal(t, 2*time.Minute, rateErr.RetryAfter)
+	})
+
+	t.Run("empty errors", func(t *testing.T) {
+		client, srv := newAuthenticatedFakeServerClient(t)
+		srv.Respond("/api/morechildren.json", http.StatusOK, `{"json": {"errors": [], "data": {"things": []}}}`)
+
+		_, err := client.GetMoreComments(t.Context(), "t3_abc", []string{"c1"})
+
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,327 @@
+package redditclient
+
+import (
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeResponse is a canned reply served by fakeRedditServer
+type fakeResponse struct {
+	Status int
+	Body   string
+	Header map[string]string
+}
+
+// recordedRequest is what fakeRedditServer saw for one request
+type recordedRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   string
+}
+
+// fakeRedditServer emulates the Reddit endpoints the client uses, so tests
+// exercise real URL routing, query encoding and gzip handling. API requests
+// must carry the bearer token handed out by the auth endpoint.
+type fakeRedditServer struct {
+	*httptest.Server
+	t *testing.T
+
+	mu        sync.Mutex
+	token     string // Access token issued by the auth endpoint
+	loid      string
+	session   string
+	gzip      bool          // Gzip responses when the client accepts it
+	delay     time.Duration // Pause before answering API requests
+	responses map[string]fakeResponse
+	requests  []recordedRequest
+}
+
+// newFakeRedditServer starts a fake server that is closed when the test ends
+func newFakeRedditServer(t *testing.T) *fakeRedditServer {
+	t.Helper()
+
+	srv := &fakeRedditServer{
+		t:         t,
+		token:     "fake-token",
+		loid:      "fake-loid",
+		session:   "fake-session",
+		responses: make(map[string]fakeResponse),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /auth/v2/oauth/access-token/loid", srv.handleAuth)
+	mux.HandleFunc("POST /api/v1/access_token", srv.handleScriptAuth)
+	mux.HandleFunc("GET /r/{subreddit}/{sort}", srv.api(emptyListing))
+	mux.HandleFunc("GET /r/{subreddit}/comments/{id}", srv.api(`[`+emptyListing+`,`+emptyListing+`]`))
+	mux.HandleFunc("GET /r/{subreddit}/comments/{id}/{rest...}", srv.api(`[`+emptyListing+`,`+emptyListing+`]`))
+	mux.HandleFunc("GET /r/{subreddit}/api/link_flair_v2.json", srv.api(`[]`))
+	mux.HandleFunc("GET /r/{subreddit}/wiki/{page...}", srv.api(`{"kind": "wikipage", "data": {}}`))
+	mux.HandleFunc("GET /duplicates/{id}", srv.api(`[`+emptyListing+`,`+emptyListing+`]`))
+	mux.HandleFunc("GET /user/{username}/about.json", srv.api(`{"kind": "t2", "data": {}}`))
+	mux.HandleFunc("GET /api/v1/user/{username}/trophies", srv.api(`{"kind": "TrophyList", "data": {"trophies": []}}`))
+	mux.HandleFunc("GET /user/{username}/saved.json", srv.api(emptyListing))
+	mux.HandleFunc("GET /subreddits/mine/subscriber.json", srv.api(emptyListing))
+	mux.HandleFunc("GET /subreddits/{where}", srv.api(emptyListing))
+	mux.HandleFunc("GET /api/trending_subreddits.json", srv.api(`{"subreddit_names": []}`))
+	mux.HandleFunc("GET /api/multi/user/{username}", srv.api(`[]`))
+	mux.HandleFunc("GET /user/{username}/m/{multi}/{sort}", srv.api(emptyListing))
+	mux.HandleFunc("GET /search.json", srv.api(emptyListing))
+	mux.HandleFunc("GET /message/{folder}", srv.api(emptyListing))
+	mux.HandleFunc("POST /api/vote", srv.api(`{}`))
+	mux.HandleFunc("POST /api/save", srv.api(`{}`))
+	mux.HandleFunc("POST /api/unsave", srv.api(`{}`))
+	mux.HandleFunc("POST /api/read_message", srv.api(`{}`))
+	mux.HandleFunc("POST /api/comment", srv.api(`{"json": {"errors": [], "data": {"things": [{"kind": "t1", "data": {"id": "new"}}]}}}`))
+	mux.HandleFunc("POST /api/submit", srv.api(`{"json": {"errors": [], "data": {"id": "new", "name": "t3_new", "url": "https://www.reddit.com/r/test/comments/new/title/"}}}`))
+	mux.HandleFunc("GET /api/morechildren.json", srv.api(`{"json": {"errors": [], "data": {"things": []}}}`))
+
+	srv.Server = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// newFakeServerClient returns a client pointed at srv using a real http.Client
+func newFakeServerClient(t *testing.T, srv *fakeRedditServer) *Client {
+	t.Helper()
+
+	client, err := NewClient(WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+// newAuthenticatedFakeServerClient starts a fake server and returns a client
+// that has authenticated against it
+func newAuthenticatedFakeServerClient(t *testing.T) (*Client, *fakeRedditServer) {
+	t.Helper()
+
+	srv := newFakeRedditServer(t)
+	client := newFakeServerClient(t, srv)
+	if err := client.Authenticate(t.Context()); err != nil {
+		t.Fatalf("failed to authenticate against fake server: %v", err)
+	}
+	return client, srv
+}
+
+// SetToken changes the access token the server issues and accepts, which
+// invalidates any token handed out before
+func (s *fakeRedditServer) SetToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token = token
+}
+
+// SetGzip toggles gzip compression of responses
+func (s *fakeRedditServer) SetGzip(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.gzip = enabled
+}
+
+// SetDelay makes API endpoints wait d before answering, emulating a slow upstream
+func (s *fakeRedditServer) SetDelay(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.delay = d
+}
+
+// Respond serves body with status for requests to path, replacing the default
+func (s *fakeRedditServer) Respond(path string, status int, body string) {
+	s.RespondWith(path, fakeResponse{Status: status, Body: body})
+}
+
+// RespondWith serves resp for requests to path
+func (s *fakeRedditServer) RespondWith(path string, resp fakeResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.responses[path] = resp
+}
+
+// Requests returns every request received so far
+func (s *fakeRedditServer) Requests() []recordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]recordedRequest(nil), s.requests...)
+}
+
+// LastRequest returns the most recent request to path
+func (s *fakeRedditServer) LastRequest(path string) (recordedRequest, bool) {
+	requests := s.Requests()
+	for i := len(requests) - 1; i >= 0; i-- {
+		if requests[i].Path == path {
+			return requests[i], true
+		}
+	}
+	return recordedRequest{}, false
+}
+
+func (s *fakeRedditServer) record(r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.t.Errorf("failed to read request body: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, recordedRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   string(body),
+	})
+}
+
+func (s *fakeRedditServer) response(path string) (fakeResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp, ok := s.responses[path]
+	return resp, ok
+}
+
+// Credentials accepted by the script-app token endpoint
+const (
+	fakeScriptClientID = "script-id"
+	fakeScriptSecret   = "script-secret"
+	fakeScriptUsername = "script-user"
+	fakeScriptPassword = "hunter2"
+)
+
+func (s *fakeRedditServer) handleScriptAuth(w http.ResponseWriter, r *http.Request) {
+	s.record(r)
+
+	if resp, ok := s.response(r.URL.Path); ok {
+		s.write(w, r, resp)
+		return
+	}
+
+	if id, secret, ok := r.BasicAuth(); !ok || id != fakeScriptClientID || secret != fakeScriptSecret {
+		s.write(w, r, fakeResponse{Status: http.StatusUnauthorized, Body: `{"message": "Unauthorized", "error": 401}`})
+		return
+	}
+
+	req, _ := s.LastRequest(r.URL.Path)
+	form, err := url.ParseQuery(req.Body)
+	if err != nil || form.Get("grant_type") != "password" ||
+		form.Get("username") != fakeScriptUsername || form.Get("password") != fakeScriptPassword {
+		s.write(w, r, fakeResponse{Status: http.StatusOK, Body: `{"error": "invalid_grant"}`})
+		return
+	}
+
+	s.mu.Lock()
+	token := s.token
+	s.mu.Unlock()
+
+	s.write(w, r, fakeResponse{
+		Status: http.StatusOK,
+		Body:   `{"access_token": "` + token + `", "token_type": "bearer", "expires_in": 86400, "scope": "*"}`,
+	})
+}
+
+func (s *fakeRedditServer) handleAuth(w http.ResponseWriter, r *http.Request) {
+	s.record(r)
+
+	if resp, ok := s.response(r.URL.Path); ok {
+		s.write(w, r, resp)
+		return
+	}
+
+	want := "Basic " + base64.StdEncoding.EncodeToString([]byte(ANDROID_CLIENT_ID+":"))
+	if r.Header.Get("Authorization") != want {
+		s.write(w, r, fakeResponse{Status: http.StatusUnauthorized, Body: `{"message": "Unauthorized", "error": 401}`})
+		return
+	}
+
+	s.mu.Lock()
+	token, loid, session := s.token, s.loid, s.session
+	s.mu.Unlock()
+
+	body, err := json.Marshal(OAuthResponse{AccessToken: token, TokenType: "bearer", ExpiresIn: 3600})
+	if err != nil {
+		s.t.Errorf("failed to encode OAuth response: %v", err)
+	}
+	s.write(w, r, fakeResponse{
+		Status: http.StatusOK,
+		Body:   string(body),
+		Header: map[string]string{"x-reddit-loid": loid, "x-reddit-session": session},
+	})
+}
+
+// api returns a handler for an authenticated API endpoint that serves the
+// configured response for the path, or defaultBody
+func (s *fakeRedditServer) api(defaultBody string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.record(r)
+
+		s.mu.Lock()
+		token, delay := s.token, s.delay
+		s.mu.Unlock()
+
+		time.Sleep(delay)
+
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			s.write(w, r, fakeResponse{Status: http.StatusUnauthorized, Body: `{"message": "Unauthorized", "error": 401}`})
+			return
+		}
+
+		resp, ok := s.response(r.URL.Path)
+		if !ok {
+			resp = fakeResponse{Status: http.StatusOK, Body: defaultBody}
+		}
+		s.write(w, r, resp)
+	}
+}
+
+func (s *fakeRedditServer) write(w http.ResponseWriter, r *http.Request, resp fakeResponse) {
+	for k, v := range resp.Header {
+		w.Header().Set(k, v)
+	}
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	s.mu.Lock()
+	gzipped := s.gzip
+	s.mu.Unlock()
+
+	if gzipped && strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(status)
+		gz := gzip.NewWriter(w)
+		if _, err := gz.Write([]byte(resp.Body)); err != nil {
+			s.t.Errorf("failed to write gzip body: %v", err)
+		}
+		if err := gz.Close(); err != nil {
+			s.t.Errorf("failed to close gzip body: %v", err)
+		}
+		return
+	}
+
+	w.WriteHeader(status)
+	if _, err := w.Write([]byte(resp.Body)); err != nil {
+		s.t.Errorf("failed to write body: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package redditclient
+
+import (
+	"context"
+	"sync"
+)
+
+// FetchSubreddits fetches the first page of each subreddit in subs using at
+// most concurrency requests at a time; zero or less means
+// DefaultFetchConcurrency. Requests go through the client's usual rate
+// limiting. A failure, such as a private or banned subreddit, is recorded in
+// the error map under that subreddit's name and does not stop the others.
+// Once ctx is cancelled no new fetches start and the subreddits not yet
+// fetched are reported with ctx's error.
+func (c *Client) FetchSubreddits(ctx context.Context, subs []string, sort string, concurrency int) (map[string]*SubredditListing, map[string]error) {
+	if concurrency <= 0 {
+		concurrency = DefaultFetchConcurrency
+	}
+
+	results := make(map[string]*SubredditListing, len(subs))
+	errs := make(map[string]error)
+	var mu sync.Mutex
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(subs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for sub := range jobs {
+				listing, err := c.GetSubreddit(ctx, sub, sort)
+
+				mu.Lock()
+				if err != nil {
+					errs[sub] = err
+				} else {
+					results[sub] = listing
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	seen := make(map[string]bool, len(subs))
+	for i, sub := range subs {
+		if seen[sub] {
+			continue
+		}
+		seen[sub] = true
+
+		select {
+		case jobs <- sub:
+			continue
+		case <-ctx.Done():
+		}
+
+		// Cancelled: report everything not handed to a worker
+		mu.Lock()
+		errs[sub] = ctx.Err()
+		for _, rest := range subs[i+1:] {
+			if !seen[rest] {
+				errs[rest] = ctx.Err()
+			}
+		}
+		mu.Unlock()
+		break
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, errs
+}
@@ -0,0 +1,90 @@
+package redditclient
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchSubreddits_AggregatesResultsAndErrors(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	client.SetRetryPolicy(RetryPolicy{})
+	srv.Respond("/r/golang/hot.json", http.StatusOK, createListingPage([]string{"g1"}, ""))
+	srv.Respond("/r/rust/hot.json", http.StatusOK, createListingPage([]string{"r1", "r2"}, ""))
+	srv.Respond("/r/secret/hot.json", http.StatusForbidden, `{"reason": "private", "message": "Forbidden", "error": 403}`)
+	srv.Respond("/r/gone/hot.json", http.StatusNotFound, `{"reason": "banned", "message": "Not Found", "error": 404}`)
+
+	results, errs := client.FetchSubreddits(t.Context(), []string{"golang", "secret", "rust", "gone", "golang"}, "hot", 2)
+
+	require.Len(t, results, 2)
+	assert.Len(t, results["golang"].Data.Children, 1)
+	assert.Len(t, results["rust"].Data.Children, 2)
+
+	require.Len(t, errs, 2)
+	assert.ErrorIs(t, errs["secret"], ErrForbidden)
+	assert.ErrorIs(t, errs["gone"], ErrNotFound)
+
+	// The duplicate golang entry is fetched once
+	var golangFetches int
+	for _, req := range srv.Requests() {
+		if req.Path == "/r/golang/hot.json" {
+			golangFetches++
+		}
+	}
+	assert.Equal(t, 1, golangFetches)
+}
+
+func TestFetchSubreddits_BoundsConcurrency(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	var inFlight, peak atomic.Int32
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Run(func(mock.Arguments) {
+			n := inFlight.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			inFlight.Add(-1)
+		}).
+		Return(func(*http.Request) *http.Response {
+			return createHTTPResponse(http.StatusOK, createListingPage([]string{"p"}, ""), nil)
+		}, nil)
+
+	subs := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	results, errs := client.FetchSubreddits(t.Context(), subs, "hot", 3)
+
+	assert.Len(t, results, len(subs))
+	assert.Empty(t, errs)
+	assert.LessOrEqual(t, peak.Load(), int32(3))
+	assert.Greater(t, peak.Load(), int32(1))
+}
+
+func TestFetchSubreddits_StopsOnCancel(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.SetDelay(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+
+	subs := []string{"a", "b", "c", "d", "e", "f"}
+	results, errs := client.FetchSubreddits(ctx, subs, "hot", 1)
+
+	assert.Empty(t, results)
+	require.Len(t, errs, len(subs))
+	for _, sub := range subs {
+		assert.ErrorIs(t, errs[sub], context.DeadlineExceeded, sub)
+	}
+
+	// Only the first subreddit was handed to the single worker
+	assert.LessOrEqual(t, len(srv.Requests()), 2)
+}
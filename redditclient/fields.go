@@ -0,0 +1,89 @@
+package redditclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Timestamp is a unix time in seconds as Reddit sends it, usually a float
+// such as 1707000000.0 but sometimes an integer or null. It encodes as a
+// plain JSON number, so re-marshaled output is unchanged.
+type Timestamp float64
+
+// UnmarshalJSON accepts a number, integer or fractional, or null for zero
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		*t = 0
+		return nil
+	}
+
+	sec, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %s", data)
+	}
+
+	*t = Timestamp(sec)
+	return nil
+}
+
+// Time returns t in UTC. A zero Timestamp is the unix epoch.
+func (t Timestamp) Time() time.Time {
+	sec, frac := math.Modf(float64(t))
+	return time.Unix(int64(sec), int64(frac*float64(time.Second))).UTC()
+}
+
+// IsZero reports whether t is unset
+func (t Timestamp) IsZero() bool {
+	return t == 0
+}
+
+// Age returns how long before now t was
+func (t Timestamp) Age(now time.Time) time.Duration {
+	return now.Sub(t.Time())
+}
+
+// Edited is Reddit's edited field, which is either false or the unix time of
+// the last edit
+type Edited struct {
+	Edited bool
+	At     Timestamp // Unix time of the last edit; zero if unknown
+}
+
+// UnmarshalJSON accepts false, true, null, or a unix timestamp
+func (e *Edited) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	switch string(data) {
+	case "null", "false":
+		*e = Edited{}
+		return nil
+	case "true":
+		*e = Edited{Edited: true}
+		return nil
+	}
+
+	at, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("invalid edited value %s", data)
+	}
+
+	*e = Edited{Edited: true, At: Timestamp(at)}
+	return nil
+}
+
+// MarshalJSON encodes the value back into Reddit's false-or-timestamp form
+func (e Edited) MarshalJSON() ([]byte, error) {
+	switch {
+	case !e.Edited:
+		return []byte("false"), nil
+	case e.At == 0:
+		return []byte("true"), nil
+	}
+
+	return json.Marshal(e.At)
+}
@@ -0,0 +1,166 @@
+package redditclient
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const richPostFixture = `{
+	"id": "abc123",
+	"name": "t3_abc123",
+	"title": "Go 1.22 is released",
+	"author": "gopher",
+	"subreddit": "golang",
+	"score": 1234,
+	"hide_score": false,
+	"upvote_ratio": 0.97,
+	"url": "https://go.dev/blog/go1.22",
+	"permalink": "/r/golang/comments/abc123/go_122_is_released/",
+	"domain": "go.dev",
+	"selftext": "",
+	"is_self": false,
+	"thumbnail": "https://b.thumbs.redditmedia.com/thumb.jpg",
+	"num_comments": 321,
+	"link_flair_text": "news",
+	"link_flair_background_color": "#ff4500",
+	"over_18": false,
+	"spoiler": true,
+	"stickied": true,
+	"locked": true,
+	"distinguished": "moderator",
+	"edited": 1707000000.5,
+	"created_utc": 1706990000.0
+}`
+
+func TestPost_RichFields(t *testing.T) {
+	var post Post
+	require.NoError(t, json.Unmarshal([]byte(richPostFixture), &post))
+
+	assert.Equal(t, "t3_abc123", post.Name)
+	assert.Equal(t, "/r/golang/comments/abc123/go_122_is_released/", post.Permalink)
+	assert.Equal(t, "news", post.LinkFlairText)
+	assert.Equal(t, "#ff4500", post.LinkFlairBackgroundColor)
+	assert.False(t, post.Over18)
+	assert.True(t, post.Spoiler)
+	assert.True(t, post.Stickied)
+	assert.True(t, post.Locked)
+	assert.Equal(t, "moderator", post.Distinguished)
+	assert.Equal(t, 0.97, post.UpvoteRatio)
+	assert.Equal(t, "go.dev", post.Domain)
+	assert.False(t, post.IsSelf)
+	assert.Equal(t, "https://b.thumbs.redditmedia.com/thumb.jpg", post.Thumbnail)
+	assert.Equal(t, Edited{Edited: true, At: 1707000000.5}, post.Edited)
+}
+
+func TestPost_HiddenScore(t *testing.T) {
+	var post Post
+	require.NoError(t, json.Unmarshal([]byte(`{"id": "new1", "score": 1, "hide_score": true, "edited": false}`), &post))
+
+	assert.True(t, post.ScoreHidden)
+	assert.Equal(t, 1, post.Score)
+}
+
+func TestEdited_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want Edited
+	}{
+		{"false", `false`, Edited{}},
+		{"null", `null`, Edited{}},
+		{"true", `true`, Edited{Edited: true}},
+		{"timestamp", `1707000000.5`, Edited{Edited: true, At: 1707000000.5}},
+		{"integer timestamp", `1707000000`, Edited{Edited: true, At: 1707000000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var post Post
+			require.NoError(t, json.Unmarshal([]byte(`{"edited": `+tt.json+`}`), &post))
+			assert.Equal(t, tt.want, post.Edited)
+		})
+	}
+}
+
+func TestEdited_MissingField(t *testing.T) {
+	var post Post
+	require.NoError(t, json.Unmarshal([]byte(`{"id": "abc"}`), &post))
+
+	assert.False(t, post.Edited.Edited)
+}
+
+func TestEdited_Invalid(t *testing.T) {
+	var post Post
+	err := json.Unmarshal([]byte(`{"edited": "yesterday"}`), &post)
+
+	assert.Error(t, err)
+}
+
+func TestEdited_MarshalRoundTrip(t *testing.T) {
+	for _, e := range []Edited{{}, {Edited: true}, {Edited: true, At: 1707000000.5}} {
+		data, err := json.Marshal(e)
+		require.NoError(t, err)
+
+		var decoded Edited
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, e, decoded)
+	}
+
+	data, err := json.Marshal(Edited{})
+	require.NoError(t, err)
+	assert.Equal(t, "false", string(data))
+}
+
+func TestTimestamp_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want Timestamp
+	}{
+		{"float", `1712966400.0`, 1712966400},
+		{"fractional", `1712966400.25`, 1712966400.25},
+		{"integer", `1712966400`, 1712966400},
+		{"null", `null`, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var post Post
+			require.NoError(t, json.Unmarshal([]byte(`{"created_utc": `+tt.json+`}`), &post))
+			assert.Equal(t, tt.want, post.Created)
+		})
+	}
+
+	var post Post
+	assert.Error(t, json.Unmarshal([]byte(`{"created_utc": "today"}`), &post))
+}
+
+func TestTimestamp_MarshalUnchanged(t *testing.T) {
+	// A Timestamp encodes exactly as the float64 it replaced
+	for _, v := range []float64{0, 1712966400, 1712966400.25} {
+		want, err := json.Marshal(v)
+		require.NoError(t, err)
+		got, err := json.Marshal(Timestamp(v))
+		require.NoError(t, err)
+		assert.Equal(t, string(want), string(got))
+	}
+
+	data, err := json.Marshal(Comment{Created: 1712966400})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"created_utc":1712966400,`)
+}
+
+func TestTimestamp_Time(t *testing.T) {
+	assert.Equal(t, time.Date(2024, 4, 13, 0, 0, 0, 0, time.UTC), Timestamp(1712966400).Time())
+	assert.Equal(t, time.Date(2024, 4, 13, 0, 0, 0, int(250*time.Millisecond), time.UTC), Timestamp(1712966400.25).Time())
+	assert.Equal(t, time.Unix(0, 0).UTC(), Timestamp(0).Time())
+	assert.True(t, Timestamp(0).IsZero())
+
+	now := time.Date(2024, 4, 13, 3, 0, 0, 0, time.UTC)
+	assert.Equal(t, 3*time.Hour, Timestamp(1712966400).Age(now))
+	assert.Equal(t, -time.Hour, Timestamp(1712966400).Age(now.Add(-4*time.Hour)))
+}
@@ -0,0 +1,122 @@
+package redditclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Kinds of FlairRun
+const (
+	FlairRunText  = "text"
+	FlairRunEmoji = "emoji"
+)
+
+// FlairRun is one element of a richtext flair: a run of text, or an emoji
+// that is shown as an image
+type FlairRun struct {
+	Kind  string `json:"e"`           // FlairRunText or FlairRunEmoji
+	Text  string `json:"t,omitempty"` // Text of a text run
+	Emoji string `json:"a,omitempty"` // Shortcode of an emoji run, e.g. ":verified:"
+	URL   string `json:"u,omitempty"` // Image of an emoji run
+}
+
+// UnmarshalJSON decodes a run, unescaping its text and image URL in case
+// Reddit ignored raw_json=1
+func (r *FlairRun) UnmarshalJSON(data []byte) error {
+	type flairRunAlias FlairRun
+	if err := json.Unmarshal(data, (*flairRunAlias)(r)); err != nil {
+		return err
+	}
+
+	r.Text = html.UnescapeString(r.Text)
+	r.URL = html.UnescapeString(r.URL)
+	return nil
+}
+
+// FlairPlainText returns the author's flair as a display string, with emoji
+// as their shortcodes
+func (p *Post) FlairPlainText() string {
+	return flairPlainText(p.AuthorFlairRichText, p.AuthorFlairText)
+}
+
+// FlairPlainText returns the author's flair as a display string, with emoji
+// as their shortcodes
+func (c *Comment) FlairPlainText() string {
+	return flairPlainText(c.AuthorFlairRichText, c.AuthorFlairText)
+}
+
+// flairPlainText flattens richtext runs, falling back to the plain text for
+// flair that has none
+func flairPlainText(runs []FlairRun, text string) string {
+	if len(runs) == 0 {
+		return strings.TrimSpace(html.UnescapeString(text))
+	}
+
+	var b strings.Builder
+	for _, run := range runs {
+		switch run.Kind {
+		case FlairRunText:
+			b.WriteString(run.Text)
+		case FlairRunEmoji:
+			b.WriteString(run.Emoji)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// FlairTemplate is a link flair a post in a subreddit can be given
+type FlairTemplate struct {
+	ID              string     `json:"id"`
+	Text            string     `json:"text"`
+	TextEditable    bool       `json:"text_editable"` // The submitter may change the text
+	Type            string     `json:"type"`          // "text" or "richtext"
+	RichText        []FlairRun `json:"richtext,omitempty"`
+	BackgroundColor string     `json:"background_color"`
+	TextColor       string     `json:"text_color"` // "dark" or "light"
+	ModOnly         bool       `json:"mod_only"`
+}
+
+// GetLinkFlairs returns the link flair templates of a subreddit, whose IDs
+// SubmitOptions.FlairID takes. Many subreddits only list them to logged-in
+// users, so a 403 to an anonymous client matches ErrRequiresUserAuth.
+func (c *Client) GetLinkFlairs(ctx context.Context, subreddit string) ([]FlairTemplate, error) {
+	creds := c.credentials()
+	if !creds.authenticated {
+		return nil, ErrNotAuthenticated
+	}
+
+	body, err := c.makeAPIRequest(ctx, fmt.Sprintf("/r/%s/api/link_flair_v2.json", url.PathEscape(subreddit)), nil)
+	if err != nil {
+		if errors.Is(err, ErrForbidden) && creds.mode != AuthScript {
+			return nil, fmt.Errorf("%w: %w", ErrRequiresUserAuth, err)
+		}
+		return nil, err
+	}
+
+	var templates []FlairTemplate
+	if err := c.decodeJSON(ctx, body, &templates, "link flairs"); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// checkFlair returns an ErrInvalidArgument error unless flairID is one of
+// subreddit's link flair templates
+func (c *Client) checkFlair(ctx context.Context, subreddit, flairID string) error {
+	templates, err := c.GetLinkFlairs(ctx, subreddit)
+	if err != nil {
+		return fmt.Errorf("failed to check flair template: %w", err)
+	}
+
+	if !slices.ContainsFunc(templates, func(t FlairTemplate) bool { return t.ID == flairID }) {
+		return fmt.Errorf("%w: r/%s has no link flair template %q", ErrInvalidArgument, subreddit, flairID)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package redditclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const flairPostFixture = `{
+	"id": "1bq0w2e",
+	"title": "Self-hosted photo library [OC]",
+	"author": "shutterbug",
+	"author_flair_type": "richtext",
+	"author_flair_text": ":verified: Verified &amp; OC",
+	"author_flair_background_color": "#46d160",
+	"author_flair_text_color": "light",
+	"author_flair_richtext": [
+		{"a": ":verified:", "e": "emoji", "u": "https://emoji.redditmedia.com/abc/verified?width=16&amp;s=1"},
+		{"e": "text", "t": " Verified &amp; OC"},
+		{"e": "sparkle", "t": "ignored"}
+	]
+}`
+
+func TestFlair_RichText(t *testing.T) {
+	var post Post
+	require.NoError(t, json.Unmarshal([]byte(flairPostFixture), &post))
+
+	assert.Equal(t, "#46d160", post.AuthorFlairBackgroundColor)
+	assert.Equal(t, "light", post.AuthorFlairTextColor)
+	require.Len(t, post.AuthorFlairRichText, 3)
+	assert.Equal(t, FlairRun{Kind: FlairRunEmoji, Emoji: ":verified:", URL: "https://emoji.redditmedia.com/abc/verified?width=16&s=1"}, post.AuthorFlairRichText[0])
+	assert.Equal(t, FlairRun{Kind: FlairRunText, Text: " Verified & OC"}, post.AuthorFlairRichText[1])
+
+	// Runs of unknown kinds are kept but not displayed
+	assert.Equal(t, ":verified: Verified & OC", post.FlairPlainText())
+}
+
+func TestFlair_PlainText(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{"text flair", `{"author_flair_type": "text", "author_flair_text": "Gopher since 2012 ", "author_flair_richtext": []}`, "Gopher since 2012"},
+		{"no flair", `{"author_flair_text": null, "author_flair_richtext": null}`, ""},
+		{"emoji only", `{"author_flair_richtext": [{"e": "emoji", "a": ":snoo:", "u": "https://emoji.redditmedia.com/snoo"}]}`, ":snoo:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var comment Comment
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &comment))
+			assert.Equal(t, tt.want, comment.FlairPlainText())
+		})
+	}
+}
+
+func TestFlair_CommentRoundTrip(t *testing.T) {
+	data := `{"id": "k1", "author_flair_text_color": "dark", "author_flair_richtext": [{"e": "text", "t": "Moderator"}], "replies": ""}`
+
+	var comment Comment
+	require.NoError(t, json.Unmarshal([]byte(data), &comment))
+	assert.Equal(t, "Moderator", comment.FlairPlainText())
+
+	encoded, err := json.Marshal(comment)
+	require.NoError(t, err)
+	var decoded Comment
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, comment, decoded)
+}
+
+const linkFlairsBody = `[
+	{"id": "flair-1", "text": "Discussion", "text_editable": false, "type": "text", "richtext": [],
+	 "background_color": "#0079d3", "text_color": "light", "mod_only": false, "css_class": "discussion", "allowable_content": "all", "max_emojis": 10},
+	{"id": "flair-2", "text": ":gopher: Show &amp; tell", "text_editable": true, "type": "richtext",
+	 "richtext": [{"e": "emoji", "a": ":gopher:", "u": "https://emoji.redditmedia.com/gopher"}, {"e": "text", "t": " Show &amp; tell"}],
+	 "background_color": "", "text_color": "dark", "mod_only": false, "css_class": "", "allowable_content": "all", "max_emojis": 10},
+	{"id": "flair-3", "text": "Announcement", "text_editable": false, "type": "text", "background_color": "#ff4500", "text_color": "light", "mod_only": true}
+]`
+
+func TestGetLinkFlairs(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.Respond("/r/golang/api/link_flair_v2.json", http.StatusOK, linkFlairsBody)
+
+	templates, err := client.GetLinkFlairs(t.Context(), "golang")
+
+	require.NoError(t, err)
+	require.Len(t, templates, 3)
+	assert.Equal(t, FlairTemplate{
+		ID:              "flair-1",
+		Text:            "Discussion",
+		Type:            "text",
+		RichText:        []FlairRun{},
+		BackgroundColor: "#0079d3",
+		TextColor:       "light",
+	}, templates[0])
+	assert.True(t, templates[1].TextEditable)
+	assert.Equal(t, ":gopher: Show & tell", flairPlainText(templates[1].RichText, templates[1].Text))
+	assert.True(t, templates[2].ModOnly)
+}
+
+func TestGetLinkFlairs_Forbidden(t *testing.T) {
+	const forbidden = `{"message": "Forbidden", "error": 403}`
+
+	t.Run("anonymous", func(t *testing.T) {
+		client, srv := newAuthenticatedFakeServerClient(t)
+		srv.Respond("/r/golang/api/link_flair_v2.json", http.StatusForbidden, forbidden)
+
+		_, err := client.GetLinkFlairs(t.Context(), "golang")
+
+		assert.ErrorIs(t, err, ErrRequiresUserAuth)
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("logged in", func(t *testing.T) {
+		srv := newFakeRedditServer(t)
+		srv.Respond("/r/golang/api/link_flair_v2.json", http.StatusForbidden, forbidden)
+		client := newFakeServerClient(t, srv)
+		authenticateScript(t, client)
+
+		_, err := client.GetLinkFlairs(t.Context(), "golang")
+
+		assert.ErrorIs(t, err, ErrForbidden)
+		assert.NotErrorIs(t, err, ErrRequiresUserAuth)
+	})
+}
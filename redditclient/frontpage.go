@@ -0,0 +1,80 @@
+package redditclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Sorts accepted by the root front page listing
+var frontpageSorts = map[Sort]bool{
+	SortBest:          true,
+	SortHot:           true,
+	SortNew:           true,
+	SortTop:           true,
+	SortRising:        true,
+	SortControversial: true,
+}
+
+// Sorts accepted by r/popular and r/all, which have no "best" ordering
+var aggregateSorts = map[Sort]bool{
+	SortHot:           true,
+	SortNew:           true,
+	SortTop:           true,
+	SortRising:        true,
+	SortControversial: true,
+}
+
+// GetFrontpage fetches one page of the front page listing (/best.json,
+// /hot.json, ...). An empty sort uses "best".
+func (c *Client) GetFrontpage(ctx context.Context, sort string, opts *ListingOptions) (*SubredditListing, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	if sort == "" {
+		sort = "best"
+	}
+	if !frontpageSorts[Sort(sort)] {
+		return nil, fmt.Errorf("%w: front page sort %q", ErrInvalidArgument, sort)
+	}
+
+	params, err := listingParams(Sort(sort), "", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.getListingPage(ctx, fmt.Sprintf("/%s.json", url.PathEscape(sort)), params)
+}
+
+// GetPopular fetches one page of r/popular. Set opts.GeoFilter to pick a
+// region. An empty sort uses "hot".
+func (c *Client) GetPopular(ctx context.Context, sort string, opts *ListingOptions) (*SubredditListing, error) {
+	return c.getAggregatePage(ctx, "popular", sort, opts)
+}
+
+// GetAll fetches one page of r/all. An empty sort uses "hot".
+func (c *Client) GetAll(ctx context.Context, sort string, opts *ListingOptions) (*SubredditListing, error) {
+	return c.getAggregatePage(ctx, "all", sort, opts)
+}
+
+// getAggregatePage fetches one page of the r/popular or r/all pseudo-subreddit
+func (c *Client) getAggregatePage(ctx context.Context, subreddit, sort string, opts *ListingOptions) (*SubredditListing, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	if sort == "" {
+		sort = "hot"
+	}
+	if !aggregateSorts[Sort(sort)] {
+		return nil, fmt.Errorf("%w: r/%s sort %q", ErrInvalidArgument, subreddit, sort)
+	}
+
+	params, err := listingParams(Sort(sort), "", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.getListingPage(ctx, fmt.Sprintf("/r/%s/%s.json", url.PathEscape(subreddit), url.PathEscape(sort)), params)
+}
@@ -0,0 +1,80 @@
+package redditclient
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFrontpage(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/best.json" && req.URL.Query().Get("limit") == "10"
+	})).Return(createHTTPResponse(200, createListingPage([]string{"a", "b"}, "t3_b"), nil), nil).Once()
+
+	listing, err := client.GetFrontpage(t.Context(), "", &ListingOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, listing.Data.Children, 2)
+	assert.Equal(t, "t3_b", listing.Data.After)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGetFrontpage_InvalidSort(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	_, err := client.GetFrontpage(t.Context(), "controversal", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "controversal")
+	mockHTTP.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestGetPopular_GeoFilter(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/r/popular/hot.json" && req.URL.Query().Get("geo_filter") == "GLOBAL"
+	})).Return(createHTTPResponse(200, createListingPage([]string{"a"}, ""), nil), nil).Once()
+
+	listing, err := client.GetPopular(t.Context(), "", &ListingOptions{GeoFilter: "GLOBAL"})
+	require.NoError(t, err)
+	require.Len(t, listing.Data.Children, 1)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGetAll(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/r/all/top.json"
+	})).Return(createHTTPResponse(200, createListingPage([]string{"a"}, ""), nil), nil).Once()
+
+	_, err := client.GetAll(t.Context(), "top", nil)
+	require.NoError(t, err)
+
+	// r/all has no best ordering
+	_, err = client.GetAll(t.Context(), "best", nil)
+	require.Error(t, err)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestFrontpagePosts_FollowsAfterCursor(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", matchAfter("")).
+		Return(createHTTPResponse(200, createListingPage([]string{"a", "b"}, "t3_b"), nil), nil).Once()
+	mockHTTP.On("Do", matchAfter("t3_b")).
+		Return(createHTTPResponse(200, createListingPage([]string{"c"}, ""), nil), nil).Once()
+
+	var ids []string
+	for post, err := range client.FrontpagePosts(t.Context(), "hot", nil) {
+		require.NoError(t, err)
+		ids = append(ids, post.ID)
+	}
+
+	assert.Equal(t, []string{"a", "b", "c"}, ids)
+	mockHTTP.AssertExpectations(t)
+}
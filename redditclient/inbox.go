@@ -0,0 +1,60 @@
+package redditclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// GetInbox fetches the logged-in user's inbox. Private messages decode as
+// ThingChild.Message; comment replies and mentions decode as
+// ThingChild.Comment. It requires AuthenticateScript and fails with
+// ErrRequiresUserAuth otherwise.
+func (c *Client) GetInbox(ctx context.Context, opts *ListingOptions) (*ThingListing, error) {
+	return c.getInboxListing(ctx, "/message/inbox.json", opts, "inbox")
+}
+
+// GetUnread fetches the unread items in the logged-in user's inbox. Fetching
+// does not mark them read; call MarkRead for that. It requires
+// AuthenticateScript and fails with ErrRequiresUserAuth otherwise.
+func (c *Client) GetUnread(ctx context.Context, opts *ListingOptions) (*ThingListing, error) {
+	return c.getInboxListing(ctx, "/message/unread.json", opts, "unread messages")
+}
+
+// getInboxListing does the work of GetInbox and GetUnread
+func (c *Client) getInboxListing(ctx context.Context, endpoint string, opts *ListingOptions, what string) (*ThingListing, error) {
+	if _, err := c.requireUserAuth(); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	opts.apply(params)
+
+	// Inbox contents change as messages are read, so never serve them from the cache
+	body, err := c.makeAPIRequest(ForceFresh(ctx), endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var listing ThingListing
+	if err := c.decodeJSON(ctx, body, &listing, what); err != nil {
+		return nil, err
+	}
+
+	return &listing, nil
+}
+
+// MarkRead marks inbox items, given by fullname, as read. It requires
+// AuthenticateScript and fails with ErrRequiresUserAuth otherwise.
+func (c *Client) MarkRead(ctx context.Context, fullnames []string) error {
+	if _, err := c.requireUserAuth(); err != nil {
+		return err
+	}
+	if len(fullnames) == 0 {
+		return fmt.Errorf("%w: no messages to mark read", ErrInvalidArgument)
+	}
+
+	_, err := c.makeFormRequest(ctx, "/api/read_message", url.Values{"id": {strings.Join(fullnames, ",")}})
+	return err
+}
@@ -0,0 +1,119 @@
+package redditclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const inboxBody = `{"kind": "Listing", "data": {"after": "t4_m1", "children": [
+	{"kind": "t1", "data": {"id": "c1", "name": "t1_c1", "author": "replier", "body": "Replying to you",
+		"parent_id": "t1_mine", "link_id": "t3_abc", "replies": ""}},
+	{"kind": "t4", "data": {"id": "m1", "name": "t4_m1", "author": "friend", "dest": "script-user",
+		"subject": "hello", "body": "Hi there", "new": true, "was_comment": false, "created_utc": 1700000000}}
+]}}`
+
+func TestGetInbox_MixesMessagesAndReplies(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	srv.Respond("/message/inbox.json", http.StatusOK, inboxBody)
+	client := newFakeServerClient(t, srv)
+	authenticateScript(t, client)
+
+	inbox, err := client.GetInbox(t.Context(), &ListingOptions{Limit: 50})
+
+	require.NoError(t, err)
+	require.Len(t, inbox.Data.Children, 2)
+
+	reply := inbox.Data.Children[0]
+	assert.Equal(t, KindComment, reply.Kind)
+	require.NotNil(t, reply.Comment)
+	assert.Equal(t, "Replying to you", reply.Comment.Body)
+	assert.Nil(t, reply.Message)
+
+	msg := inbox.Data.Children[1]
+	assert.Equal(t, KindMessage, msg.Kind)
+	require.NotNil(t, msg.Message)
+	assert.Equal(t, "hello", msg.Message.Subject)
+	assert.Equal(t, "script-user", msg.Message.Dest)
+	assert.True(t, msg.Message.New)
+	assert.Nil(t, msg.Comment)
+
+	req, ok := srv.LastRequest("/message/inbox.json")
+	require.True(t, ok)
+	assert.Equal(t, "50", req.Query.Get("limit"))
+}
+
+func TestGetUnread_BypassesCache(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	srv.Respond("/message/unread.json", http.StatusOK, inboxBody)
+	client, err := NewClient(WithBaseURL(srv.URL), WithCache(NewMemoryCache(10), time.Minute))
+	require.NoError(t, err)
+	authenticateScript(t, client)
+
+	for range 2 {
+		unread, err := client.GetUnread(t.Context(), nil)
+		require.NoError(t, err)
+		assert.Len(t, unread.Data.Children, 2)
+	}
+
+	var fetches int
+	for _, req := range srv.Requests() {
+		if req.Path == "/message/unread.json" {
+			fetches++
+		}
+	}
+	assert.Equal(t, 2, fetches)
+}
+
+func TestMarkRead_PostsJoinedIDs(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	client := newFakeServerClient(t, srv)
+	authenticateScript(t, client)
+
+	require.NoError(t, client.MarkRead(t.Context(), []string{"t4_m1", "t1_c1"}))
+
+	req, ok := srv.LastRequest("/api/read_message")
+	require.True(t, ok)
+	assert.Equal(t, http.MethodPost, req.Method)
+	form, err := url.ParseQuery(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "t4_m1,t1_c1", form.Get("id"))
+}
+
+func TestMarkRead_Empty(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	client := newFakeServerClient(t, srv)
+	authenticateScript(t, client)
+
+	assert.ErrorIs(t, client.MarkRead(t.Context(), nil), ErrInvalidArgument)
+}
+
+func TestInbox_RequiresUserAuth(t *testing.T) {
+	client, _ := newAuthenticatedFakeServerClient(t)
+
+	_, err := client.GetInbox(t.Context(), nil)
+	assert.ErrorIs(t, err, ErrRequiresUserAuth)
+
+	_, err = client.GetUnread(t.Context(), nil)
+	assert.ErrorIs(t, err, ErrRequiresUserAuth)
+
+	assert.ErrorIs(t, client.MarkRead(t.Context(), []string{"t4_m1"}), ErrRequiresUserAuth)
+}
+
+func TestThingChild_MessageRoundTrip(t *testing.T) {
+	var child ThingChild
+	require.NoError(t, json.Unmarshal([]byte(`{"kind": "t4", "data": {"id": "m1", "subject": "hi"}}`), &child))
+
+	data, err := json.Marshal(child)
+	require.NoError(t, err)
+
+	var again ThingChild
+	require.NoError(t, json.Unmarshal(data, &again))
+	require.NotNil(t, again.Message)
+	assert.Equal(t, "hi", again.Message.Subject)
+}
@@ -0,0 +1,60 @@
+package redditclient
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// GetPostsByID hydrates posts from their fullnames (t3_abc) or bare IDs (abc)
+// via /api/info, batching 100 IDs per request. Results follow the input
+// order; posts Reddit doesn't return, such as deleted ones, are omitted.
+func (c *Client) GetPostsByID(ctx context.Context, fullnames []string) ([]Post, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	names := make([]string, len(fullnames))
+	for i, name := range fullnames {
+		names[i] = postFullname(name)
+	}
+
+	found := make(map[string]Post, len(names))
+	for start := 0; start < len(names); start += maxInfoIDs {
+		end := min(start+maxInfoIDs, len(names))
+
+		params := url.Values{"id": []string{strings.Join(names[start:end], ",")}}
+		body, err := c.makeAPIRequest(ctx, "/api/info.json", params)
+		if err != nil {
+			return nil, err
+		}
+
+		var listing PostListing
+		if err := c.decodeJSON(ctx, body, &listing, "post info"); err != nil {
+			return nil, err
+		}
+
+		for _, child := range listing.Data.Children {
+			if child.Kind == KindPost {
+				found[child.Data.Name] = child.Data
+			}
+		}
+	}
+
+	posts := make([]Post, 0, len(found))
+	for _, name := range names {
+		if post, ok := found[name]; ok {
+			posts = append(posts, post)
+		}
+	}
+
+	return posts, nil
+}
+
+// postFullname adds the t3_ prefix to a bare post ID
+func postFullname(id string) string {
+	if strings.HasPrefix(id, KindPost+"_") {
+		return id
+	}
+	return KindPost + "_" + id
+}
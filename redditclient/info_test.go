@@ -0,0 +1,83 @@
+package redditclient
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Helper function to build an /api/info listing for the given fullnames
+func createInfoListing(names []string) string {
+	children := make([]string, len(names))
+	for i, name := range names {
+		id := strings.TrimPrefix(name, "t3_")
+		children[i] = fmt.Sprintf(`{"kind": "t3", "data": {"id": "%s", "name": "%s", "title": "Post %s"}}`, id, name, id)
+	}
+	return fmt.Sprintf(`{"kind": "Listing", "data": {"children": [%s], "after": null}}`, strings.Join(children, ","))
+}
+
+func isInfoRequest(req *http.Request) bool {
+	return req.URL.Path == "/api/info.json"
+}
+
+func TestGetPostsByID(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	// Reddit answers out of order and drops the deleted post
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return isInfoRequest(req) && req.URL.Query().Get("id") == "t3_abc,t3_def,t3_gone"
+	})).Return(createHTTPResponse(200, createInfoListing([]string{"t3_def", "t3_abc"}), nil), nil).Once()
+
+	posts, err := client.GetPostsByID(t.Context(), []string{"t3_abc", "def", "gone"})
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+	assert.Equal(t, "abc", posts[0].ID)
+	assert.Equal(t, "def", posts[1].ID)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGetPostsByID_Chunks(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	names := make([]string, 250)
+	for i := range names {
+		names[i] = fmt.Sprintf("t3_p%d", i)
+	}
+
+	for _, chunk := range [][]string{names[:100], names[100:200], names[200:]} {
+		want := strings.Join(chunk, ",")
+		mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+			return isInfoRequest(req) && req.URL.Query().Get("id") == want
+		})).Return(createHTTPResponse(200, createInfoListing(chunk), nil), nil).Once()
+	}
+
+	posts, err := client.GetPostsByID(t.Context(), names)
+	require.NoError(t, err)
+	require.Len(t, posts, 250)
+	for i, post := range posts {
+		assert.Equal(t, names[i], post.Name)
+	}
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGetPostsByID_Empty(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	posts, err := client.GetPostsByID(t.Context(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, posts)
+	mockHTTP.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestGetPostsByID_NotAuthenticated(t *testing.T) {
+	client, err := NewClient(WithHTTPClient(&MockHTTPClient{}))
+	require.NoError(t, err)
+
+	_, err = client.GetPostsByID(t.Context(), []string{"abc"})
+	assert.ErrorIs(t, err, ErrNotAuthenticated)
+}
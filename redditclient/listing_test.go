@@ -0,0 +1,119 @@
+package redditclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListing_WireShape(t *testing.T) {
+	listing := SubredditListing{
+		Kind: KindListing,
+		Data: PostListingData{
+			Children: []PostChild{{Kind: KindPost, Data: Post{ID: "abc", Title: "Hello"}}},
+			After:    "t3_abc",
+		},
+	}
+
+	data, err := json.Marshal(listing)
+	require.NoError(t, err)
+
+	var raw struct {
+		Kind string `json:"kind"`
+		Data struct {
+			Children []struct {
+				Kind string          `json:"kind"`
+				Data json.RawMessage `json:"data"`
+			} `json:"children"`
+			After  string `json:"after"`
+			Before string `json:"before"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.Equal(t, "Listing", raw.Kind)
+	assert.Equal(t, "t3_abc", raw.Data.After)
+	require.Len(t, raw.Data.Children, 1)
+	assert.Equal(t, "t3", raw.Data.Children[0].Kind)
+
+	var decoded SubredditListing
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "Hello", decoded.Data.Children[0].Data.Title)
+}
+
+func TestListing_NamedTypesInterchange(t *testing.T) {
+	// Helpers written against the generic type accept every post listing
+	countPosts := func(l *Listing[PostChild]) int { return len(l.Data.Children) }
+
+	subreddit := &SubredditListing{Data: PostListingData{Children: make([]PostChild, 2)}}
+	search := &SearchResponse{Data: PostListingData{Children: make([]PostChild, 3)}}
+	user := &PostListing{Data: PostListingData{Children: make([]PostChild, 1)}}
+
+	assert.Equal(t, 2, countPosts(subreddit))
+	assert.Equal(t, 3, countPosts(search))
+	assert.Equal(t, 1, countPosts(user))
+}
+
+func TestUserResponse_Thing(t *testing.T) {
+	var user UserResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"kind": "t2", "data": {"name": "spez", "link_karma": 10}}`), &user))
+
+	assert.Equal(t, KindUser, user.Kind)
+	assert.Equal(t, UserAbout{Name: "spez", LinkKarma: 10}, user.Data)
+}
+
+func TestListing_Metadata(t *testing.T) {
+	const body = `{"kind": "Listing", "data": {"after": "t3_b", "dist": 2, "modhash": "", "geo_filter": "GLOBAL",
+		"children": [{"kind": "t3", "data": {"id": "a"}}, {"kind": "t3", "data": {"id": "b"}}], "before": null}}`
+
+	want := PostListingData{After: "t3_b", Dist: 2, GeoFilter: "GLOBAL"}
+
+	for name, client := range benchClients(t, body) {
+		t.Run(name, func(t *testing.T) {
+			listing, err := client.GetPopular(t.Context(), "hot", &ListingOptions{GeoFilter: "GLOBAL"})
+			require.NoError(t, err)
+
+			got := listing.Data
+			got.Children = nil
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestSearchWithOptions_Metadata(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(200, `{"kind": "Listing", "data": {
+		"after": "t3_b", "before": "t3_a", "dist": 3, "children": [
+			{"kind": "t3", "data": {"id": "a"}},
+			{"kind": "t5", "data": {"display_name": "golang"}},
+			{"kind": "t3", "data": {"id": "b"}}
+		]}}`, nil), nil).Once()
+
+	results, err := client.SearchWithOptions(t.Context(), "go", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "t3_b", results.After)
+	assert.Equal(t, "t3_a", results.Before)
+	assert.Equal(t, 3, results.Dist)
+	assert.Len(t, results.Posts, 2)
+}
+
+func TestUserListings_Metadata(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	mockHTTP.On("Do", mock.Anything).Return(func(*http.Request) *http.Response {
+		return createHTTPResponse(200, `{"kind": "Listing", "data": {"after": "t1_z", "before": null, "dist": 1,
+			"children": [{"kind": "t1", "data": {"id": "z", "replies": ""}}]}}`, nil)
+	}, nil)
+
+	comments, err := client.GetUserComments(t.Context(), "gopher", "new", "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "t1_z", comments.Data.After)
+	assert.Equal(t, 1, comments.Data.Dist)
+
+	overview, err := client.GetUserOverview(t.Context(), "gopher", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "t1_z", overview.Data.After)
+	assert.Equal(t, 1, overview.Data.Dist)
+}
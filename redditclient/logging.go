@@ -0,0 +1,165 @@
+package redditclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// maxLogBodySample caps how much of a response body is included in log events
+const maxLogBodySample = 256
+
+// sensitiveHeaders are replaced with a placeholder in log output
+var sensitiveHeaders = map[string]bool{
+	"Authorization":    true,
+	"Cookie":           true,
+	"Set-Cookie":       true,
+	"X-Reddit-Loid":    true,
+	"X-Reddit-Session": true,
+}
+
+// redactedHeaders logs an http.Header with credentials masked. Redaction
+// happens lazily, so it costs nothing when debug logging is disabled.
+type redactedHeaders http.Header
+
+// LogValue implements slog.LogValuer
+func (h redactedHeaders) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, len(h))
+	for name, values := range h {
+		value := strings.Join(values, ", ")
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			value = "[REDACTED]"
+		}
+		attrs = append(attrs, slog.String(name, value))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// RequestInfo describes an outgoing request for logging. Its LogValue masks
+// credential headers, so it is safe to pass to any logger.
+type RequestInfo struct {
+	Method   string
+	Endpoint string
+	Header   http.Header
+}
+
+// LogValue implements slog.LogValuer
+func (r RequestInfo) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("method", r.Method),
+		slog.String("endpoint", r.Endpoint),
+		slog.Any("headers", redactedHeaders(r.Header)),
+	)
+}
+
+// secretPrefixLen is how many characters of a credential survive masking
+const secretPrefixLen = 4
+
+// maskSecret keeps the first few characters of a credential, enough to tell
+// two tokens apart in a bug report without making either usable
+func maskSecret(s string) string {
+	if s == "" {
+		return ""
+	}
+	if len(s) <= secretPrefixLen {
+		return "[REDACTED]"
+	}
+	return s[:secretPrefixLen] + "[REDACTED]"
+}
+
+// redactCredentials masks every occurrence of the client's access token, loid
+// and session in s
+func (c *Client) redactCredentials(s string) string {
+	creds := c.credentials()
+	for _, secret := range []string{creds.accessToken, creds.loid, creds.session} {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, maskSecret(secret))
+		}
+	}
+	return s
+}
+
+// LogValue implements slog.LogValuer, logging the client's state with its
+// credentials masked
+func (c *Client) LogValue() slog.Value {
+	creds := c.credentials()
+	return slog.GroupValue(
+		slog.String("base_url", c.baseURL),
+		slog.String("auth_mode", creds.mode.String()),
+		slog.Bool("authenticated", creds.authenticated),
+		slog.String("access_token", maskSecret(creds.accessToken)),
+		slog.String("loid", maskSecret(creds.loid)),
+		slog.String("session", maskSecret(creds.session)),
+		slog.Time("token_expiry", creds.tokenExpiry),
+	)
+}
+
+// String describes the client with its credentials masked, so that printing
+// it with %v or %+v does not leak them
+func (c *Client) String() string {
+	creds := c.credentials()
+	return fmt.Sprintf("redditclient.Client{base_url: %s, auth_mode: %s, authenticated: %t, access_token: %q, loid: %q, session: %q}",
+		c.baseURL, creds.mode, creds.authenticated,
+		maskSecret(creds.accessToken), maskSecret(creds.loid), maskSecret(creds.session))
+}
+
+// GoString is String for the %#v verb
+func (c *Client) GoString() string {
+	return c.String()
+}
+
+// bodySample returns the start of body for logging
+func bodySample(body []byte) string {
+	if len(body) <= maxLogBodySample {
+		return string(body)
+	}
+	return string(body[:maxLogBodySample]) + "..."
+}
+
+// decodeJSON unmarshals body into v, logging a sample of the body on failure.
+// what names the payload in the returned error. HTML entities in post and
+// comment text are unescaped unless the client was built WithRawText.
+func (c *Client) decodeJSON(ctx context.Context, body []byte, v any, what string) error {
+	if err := json.Unmarshal(body, v); err != nil {
+		c.logger.DebugContext(ctx, "failed to decode response",
+			"payload", what,
+			"error", err,
+			"body_sample", c.redactCredentials(bodySample(body)))
+		return fmt.Errorf("failed to decode %s: %w", what, err)
+	}
+
+	if !c.rawText {
+		unescapeText(v)
+	}
+	return nil
+}
+
+// decodeJSONStream decodes one JSON value from r into v. sample is the start of
+// the stream, logged in place of the body on failure.
+func (c *Client) decodeJSONStream(ctx context.Context, r io.Reader, v any, what string, sample []byte) error {
+	dec := json.NewDecoder(r)
+
+	var err error
+	if sd, ok := v.(streamDecoder); ok {
+		err = sd.decodeStream(dec)
+	} else {
+		err = dec.Decode(v)
+	}
+	if err != nil {
+		c.logger.DebugContext(ctx, "failed to decode response",
+			"payload", what,
+			"error", err,
+			"body_sample", c.redactCredentials(bodySample(sample)))
+		return fmt.Errorf("failed to decode %s: %w", what, err)
+	}
+
+	if !c.rawText {
+		unescapeText(v)
+	}
+	return nil
+}
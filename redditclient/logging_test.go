@@ -0,0 +1,233 @@
+package redditclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newLoggingTestClient returns an authenticated client logging JSON debug events to the returned buffer
+func newLoggingTestClient(t *testing.T) (*Client, *MockHTTPClient, *bytes.Buffer) {
+	t.Helper()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP), WithLogger(logger))
+	require.NoError(t, err)
+	client.accessToken = "secret-token"
+	client.loid = "secret-loid"
+	client.session = "secret-session"
+	client.authenticated = true
+	client.sleep = func(_ context.Context, _ time.Duration) error { return nil }
+
+	return client, mockHTTP, &logs
+}
+
+// logEvents decodes each JSON log line
+func logEvents(t *testing.T, logs *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var events []map[string]any
+	for line := range strings.SplitSeq(strings.TrimSpace(logs.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var event map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+		events = append(events, event)
+	}
+	return events
+}
+
+func findEvent(events []map[string]any, msg string) map[string]any {
+	for _, event := range events {
+		if event["msg"] == msg {
+			return event
+		}
+	}
+	return nil
+}
+
+func TestLogging_RequestAndResponse(t *testing.T) {
+	client, mockHTTP, logs := newLoggingTestClient(t)
+
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(200, emptyListing, map[string]string{
+		"x-ratelimit-remaining": "95.0",
+	}), nil).Once()
+
+	_, err := client.Search(t.Context(), "golang", "", "")
+	require.NoError(t, err)
+
+	events := logEvents(t, logs)
+
+	request := findEvent(events, "reddit request")
+	require.NotNil(t, request)
+	assert.Equal(t, "GET", request["method"])
+	assert.Equal(t, "/search.json", request["endpoint"])
+	headers, ok := request["headers"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "[REDACTED]", headers["Authorization"])
+	assert.Equal(t, client.userAgent, headers["User-Agent"])
+
+	response := findEvent(events, "reddit response")
+	require.NotNil(t, response)
+	assert.EqualValues(t, 200, response["status"])
+	assert.EqualValues(t, len(emptyListing), response["bytes"])
+	assert.Equal(t, "95.0", response["ratelimit_remaining"])
+	assert.Contains(t, response, "duration")
+
+	for _, secret := range []string{"secret-token", "secret-loid", "secret-session"} {
+		assert.NotContains(t, logs.String(), secret)
+	}
+}
+
+func TestLogging_DurationUsesClientClock(t *testing.T) {
+	client, mockHTTP, logs := newLoggingTestClient(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return now }
+
+	mockHTTP.On("Do", mock.Anything).Return(func(*http.Request) *http.Response {
+		now = now.Add(1500 * time.Millisecond)
+		return createHTTPResponse(200, emptyListing, nil)
+	}, nil).Once()
+
+	_, err := client.Search(t.Context(), "golang", "", "")
+	require.NoError(t, err)
+
+	response := findEvent(logEvents(t, logs), "reddit response")
+	require.NotNil(t, response)
+	assert.InDelta(t, float64(1500*time.Millisecond), response["duration"], 0)
+}
+
+func TestLogging_StreamedResponse(t *testing.T) {
+	client, mockHTTP, logs := newLoggingTestClient(t)
+
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(200, emptyListing, nil), nil).Once()
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+	require.NoError(t, err)
+
+	response := findEvent(logEvents(t, logs), "reddit response")
+	require.NotNil(t, response)
+	assert.EqualValues(t, 200, response["status"])
+	assert.Equal(t, true, response["streamed"])
+	assert.NotContains(t, response, "bytes")
+}
+
+func TestLogging_RetryAndDecodeFailure(t *testing.T) {
+	client, mockHTTP, logs := newLoggingTestClient(t)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	mockHTTP.On("Do", mock.Anything).Return((*http.Response)(nil), errors.New("connection reset")).Once()
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(200, `{"kind": "Listing", "data": [`+strings.Repeat(`"x",`, 200), nil), nil).Once()
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+	require.Error(t, err)
+
+	events := logEvents(t, logs)
+
+	retry := findEvent(events, "retrying request")
+	require.NotNil(t, retry)
+	assert.EqualValues(t, 1, retry["attempt"])
+	assert.Contains(t, retry["reason"], "connection reset")
+
+	decode := findEvent(events, "failed to decode response")
+	require.NotNil(t, decode)
+	assert.Equal(t, "subreddit listing", decode["payload"])
+	sample, ok := decode["body_sample"].(string)
+	require.True(t, ok)
+	assert.LessOrEqual(t, len(sample), maxLogBodySample+len("..."))
+	assert.True(t, strings.HasSuffix(sample, "..."))
+}
+
+func TestLogging_Reauthenticate(t *testing.T) {
+	client, mockHTTP, logs := newLoggingTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(isAuthRequest)).Return(createOAuthResponse("fresh-token", 3600), nil).Once()
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return !isAuthRequest(req)
+	})).Return(createHTTPResponse(401, `{"message": "Unauthorized", "error": 401}`, nil), nil).Once()
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return !isAuthRequest(req)
+	})).Return(createHTTPResponse(200, emptyListing, nil), nil).Once()
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+	require.NoError(t, err)
+
+	events := logEvents(t, logs)
+	assert.NotNil(t, findEvent(events, "re-authenticating after 401"))
+	assert.NotNil(t, findEvent(events, "authenticated"))
+	assert.NotContains(t, logs.String(), "fresh-token")
+}
+
+func TestLogging_SilentByDefault(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	assert.False(t, client.logger.Enabled(t.Context(), slog.LevelError))
+
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(200, `not json`, nil), nil).Once()
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+	require.Error(t, err)
+}
+
+func TestClient_LogValueMasksCredentials(t *testing.T) {
+	client, _, logs := newLoggingTestClient(t)
+	logger := slog.New(slog.NewJSONHandler(logs, nil))
+
+	logger.Info("client state", "client", client)
+
+	event := findEvent(logEvents(t, logs), "client state")
+	require.NotNil(t, event)
+	state, ok := event["client"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "secr[REDACTED]", state["access_token"])
+	assert.Equal(t, "secr[REDACTED]", state["loid"])
+	assert.Equal(t, "secr[REDACTED]", state["session"])
+	assert.Equal(t, true, state["authenticated"])
+
+	for _, secret := range []string{"secret-token", "secret-loid", "secret-session"} {
+		assert.NotContains(t, logs.String(), secret)
+		for _, verb := range []string{"%v", "%+v", "%#v", "%s"} {
+			assert.NotContains(t, fmt.Sprintf(verb, client), secret, verb)
+		}
+	}
+}
+
+func TestRequestInfo_LogValue(t *testing.T) {
+	info := RequestInfo{
+		Method:   http.MethodGet,
+		Endpoint: "/api/v1/me",
+		Header: http.Header{
+			"Authorization": {"Bearer secret-token"},
+			"Cookie":        {"reddit_session=secret-session"},
+			"User-Agent":    {"grapeddit-test"},
+		},
+	}
+
+	var logs bytes.Buffer
+	slog.New(slog.NewJSONHandler(&logs, nil)).Info("request", "request", info)
+
+	assert.NotContains(t, logs.String(), "secret-token")
+	assert.NotContains(t, logs.String(), "secret-session")
+	assert.Contains(t, logs.String(), `"endpoint":"/api/v1/me"`)
+	assert.Contains(t, logs.String(), `"User-Agent":"grapeddit-test"`)
+}
+
+func TestMaskSecret(t *testing.T) {
+	assert.Equal(t, "", maskSecret(""))
+	assert.Equal(t, "[REDACTED]", maskSecret("abcd"))
+	assert.Equal(t, "abcd[REDACTED]", maskSecret("abcdefgh"))
+}
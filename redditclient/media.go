@@ -0,0 +1,217 @@
+package redditclient
+
+import (
+	"encoding/json"
+	"html"
+	"strings"
+)
+
+// Preview holds the preview images Reddit generates for a post
+type Preview struct {
+	Images  []PreviewImageSet `json:"images"`
+	Enabled bool              `json:"enabled"`
+}
+
+// PreviewImageSet is one image at its source size plus downscaled resolutions
+type PreviewImageSet struct {
+	ID          string         `json:"id"`
+	Source      PreviewImage   `json:"source"`
+	Resolutions []PreviewImage `json:"resolutions"`
+}
+
+// PreviewImage is a single image variant
+type PreviewImage struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// UnmarshalJSON decodes the image, unescaping the URL in case Reddit ignored
+// raw_json=1 and sent it with &amp; entities
+func (p *PreviewImage) UnmarshalJSON(data []byte) error {
+	type previewImageAlias PreviewImage
+	if err := json.Unmarshal(data, (*previewImageAlias)(p)); err != nil {
+		return err
+	}
+
+	p.URL = html.UnescapeString(p.URL)
+	return nil
+}
+
+// BestPreview returns the largest preview image of the post that is no wider
+// than maxWidth, or the source image if maxWidth is not positive. It returns
+// nil when the post has no preview or no variant fits.
+func (p *Post) BestPreview(maxWidth int) *PreviewImage {
+	if p.Preview == nil || len(p.Preview.Images) == 0 {
+		return nil
+	}
+
+	set := &p.Preview.Images[0]
+	if maxWidth <= 0 {
+		return &set.Source
+	}
+
+	var best *PreviewImage
+	consider := func(img *PreviewImage) {
+		if img.URL == "" || img.Width > maxWidth {
+			return
+		}
+		if best == nil || img.Width > best.Width {
+			best = img
+		}
+	}
+
+	consider(&set.Source)
+	for i := range set.Resolutions {
+		consider(&set.Resolutions[i])
+	}
+
+	return best
+}
+
+// GalleryData gives the order and captions of a gallery post's media
+type GalleryData struct {
+	Items []GalleryDataItem `json:"items"`
+}
+
+type GalleryDataItem struct {
+	MediaID string `json:"media_id"`
+	Caption string `json:"caption"`
+}
+
+// MediaMetadata describes one uploaded media item, keyed by media ID on the post
+type MediaMetadata struct {
+	Status   string            `json:"status"` // "valid" once processed; "failed" or "unprocessed" otherwise
+	Type     string            `json:"e"`      // Image or AnimatedImage
+	MIMEType string            `json:"m"`
+	Source   MediaMetadataItem `json:"s"`
+}
+
+// MediaMetadataItem is the source variant of a media item. Reddit uses single
+// letter keys: x/y for dimensions and u for the (preview) URL.
+type MediaMetadataItem struct {
+	Width  int    `json:"x"`
+	Height int    `json:"y"`
+	URL    string `json:"u"`
+	GIF    string `json:"gif"`
+	MP4    string `json:"mp4"`
+}
+
+// GalleryItem is a single image in a gallery post
+type GalleryItem struct {
+	MediaID  string
+	Caption  string
+	URL      string
+	Width    int
+	Height   int
+	MIMEType string
+}
+
+// GalleryItems returns the images of a gallery post in display order. Media
+// that failed processing or is missing from the metadata is skipped.
+func (p *Post) GalleryItems() []GalleryItem {
+	if p.GalleryData == nil {
+		return nil
+	}
+
+	items := make([]GalleryItem, 0, len(p.GalleryData.Items))
+	for _, item := range p.GalleryData.Items {
+		meta, ok := p.MediaMetadata[item.MediaID]
+		if !ok || meta.Status != "valid" {
+			continue
+		}
+
+		items = append(items, GalleryItem{
+			MediaID:  item.MediaID,
+			Caption:  item.Caption,
+			URL:      galleryMediaURL(item.MediaID, meta),
+			Width:    meta.Source.Width,
+			Height:   meta.Source.Height,
+			MIMEType: meta.MIMEType,
+		})
+	}
+
+	return items
+}
+
+// galleryMediaURL derives the direct i.redd.it URL for a media item, falling
+// back to the preview URL when the MIME type is unknown
+func galleryMediaURL(mediaID string, meta MediaMetadata) string {
+	ext := ""
+	if _, subtype, ok := strings.Cut(meta.MIMEType, "/"); ok {
+		ext = subtype
+	}
+	if ext == "" {
+		if meta.Source.GIF != "" {
+			return html.UnescapeString(meta.Source.GIF)
+		}
+		return html.UnescapeString(meta.Source.URL)
+	}
+
+	return "https://i.redd.it/" + mediaID + "." + ext
+}
+
+// Media is the embedded media of a post
+type Media struct {
+	RedditVideo *RedditVideo `json:"reddit_video,omitempty"`
+}
+
+// RedditVideo is a video hosted on v.redd.it
+type RedditVideo struct {
+	FallbackURL string `json:"fallback_url"` // MP4 without audio
+	DashURL     string `json:"dash_url"`
+	HLSURL      string `json:"hls_url"`
+	Duration    int    `json:"duration"` // Seconds
+	IsGIF       bool   `json:"is_gif"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+}
+
+// UnmarshalJSON decodes the video, unescaping URLs in case raw_json=1 was ignored
+func (v *RedditVideo) UnmarshalJSON(data []byte) error {
+	type redditVideoAlias RedditVideo
+	if err := json.Unmarshal(data, (*redditVideoAlias)(v)); err != nil {
+		return err
+	}
+
+	v.FallbackURL = html.UnescapeString(v.FallbackURL)
+	v.DashURL = html.UnescapeString(v.DashURL)
+	v.HLSURL = html.UnescapeString(v.HLSURL)
+	return nil
+}
+
+// Video returns the post's v.redd.it video, looking through crosspost parents
+// when the post itself is a crosspost. It returns nil for non-video posts.
+func (p *Post) Video() *RedditVideo {
+	for _, media := range []*Media{p.SecureMedia, p.Media} {
+		if media != nil && media.RedditVideo != nil {
+			return media.RedditVideo
+		}
+	}
+
+	for i := range p.CrosspostParents {
+		if video := p.CrosspostParents[i].Video(); video != nil {
+			return video
+		}
+	}
+
+	return nil
+}
+
+// VideoURL returns the best playable URL for the post's video: HLS (which
+// carries audio) if available, then the fallback MP4, then DASH. It returns
+// an empty string for non-video posts.
+func (p *Post) VideoURL() string {
+	video := p.Video()
+	if video == nil {
+		return ""
+	}
+
+	for _, u := range []string{video.HLSURL, video.FallbackURL, video.DashURL} {
+		if u != "" {
+			return u
+		}
+	}
+
+	return ""
+}
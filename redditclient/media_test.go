@@ -0,0 +1,210 @@
+package redditclient
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const previewPostFixture = `{
+	"id": "img1",
+	"title": "A picture",
+	"preview": {
+		"enabled": true,
+		"images": [{
+			"id": "preview-id",
+			"source": {"url": "https://preview.redd.it/source.jpg?width=1920&amp;s=abc", "width": 1920, "height": 1080},
+			"resolutions": [
+				{"url": "https://preview.redd.it/a.jpg?width=108&amp;s=1", "width": 108, "height": 60},
+				{"url": "https://preview.redd.it/a.jpg?width=320&amp;s=2", "width": 320, "height": 180},
+				{"url": "https://preview.redd.it/a.jpg?width=640&amp;s=3", "width": 640, "height": 360},
+				{"url": "https://preview.redd.it/a.jpg?width=1080&amp;s=4", "width": 1080, "height": 608}
+			]
+		}]
+	}
+}`
+
+func TestPost_PreviewDecoding(t *testing.T) {
+	var post Post
+	require.NoError(t, json.Unmarshal([]byte(previewPostFixture), &post))
+
+	require.NotNil(t, post.Preview)
+	assert.True(t, post.Preview.Enabled)
+	require.Len(t, post.Preview.Images, 1)
+	assert.Equal(t, "https://preview.redd.it/source.jpg?width=1920&s=abc", post.Preview.Images[0].Source.URL)
+	assert.Len(t, post.Preview.Images[0].Resolutions, 4)
+	assert.Equal(t, "https://preview.redd.it/a.jpg?width=108&s=1", post.Preview.Images[0].Resolutions[0].URL)
+}
+
+func TestPost_BestPreview(t *testing.T) {
+	var post Post
+	require.NoError(t, json.Unmarshal([]byte(previewPostFixture), &post))
+
+	tests := []struct {
+		maxWidth  int
+		wantWidth int
+	}{
+		{700, 640},
+		{640, 640},
+		{1080, 1080},
+		{5000, 1920},
+		{0, 1920},
+		{200, 108},
+	}
+
+	for _, tt := range tests {
+		img := post.BestPreview(tt.maxWidth)
+		require.NotNil(t, img, "maxWidth %d", tt.maxWidth)
+		assert.Equal(t, tt.wantWidth, img.Width, "maxWidth %d", tt.maxWidth)
+	}
+
+	assert.Nil(t, post.BestPreview(50))
+}
+
+func TestPost_BestPreviewWithoutPreview(t *testing.T) {
+	var post Post
+	require.NoError(t, json.Unmarshal([]byte(`{"id": "gallery1", "is_gallery": true}`), &post))
+
+	assert.Nil(t, post.Preview)
+	assert.Nil(t, post.BestPreview(640))
+
+	post.Preview = &Preview{}
+	assert.Nil(t, post.BestPreview(640))
+}
+
+const galleryPostFixture = `{
+	"id": "gal1",
+	"title": "My gallery",
+	"is_gallery": true,
+	"url": "https://www.reddit.com/gallery/gal1",
+	"gallery_data": {"items": [
+		{"media_id": "second", "id": 2, "caption": "Second image first"},
+		{"media_id": "broken", "id": 3},
+		{"media_id": "first", "id": 1, "caption": ""},
+		{"media_id": "missing", "id": 4},
+		{"media_id": "anim", "id": 5, "caption": "Moving"}
+	]},
+	"media_metadata": {
+		"first": {"status": "valid", "e": "Image", "m": "image/png", "s": {"x": 800, "y": 600, "u": "https://preview.redd.it/first.png?width=800&amp;s=x"}},
+		"second": {"status": "valid", "e": "Image", "m": "image/jpg", "s": {"x": 1920, "y": 1080, "u": "https://preview.redd.it/second.jpg?width=1920&amp;s=y"}},
+		"broken": {"status": "failed"},
+		"anim": {"status": "valid", "e": "AnimatedImage", "m": "image/gif", "s": {"x": 320, "y": 240, "gif": "https://i.redd.it/anim.gif", "mp4": "https://preview.redd.it/anim.gif?format=mp4&amp;s=z"}}
+	}
+}`
+
+func TestPost_GalleryItems(t *testing.T) {
+	var post Post
+	require.NoError(t, json.Unmarshal([]byte(galleryPostFixture), &post))
+
+	assert.True(t, post.IsGallery)
+	assert.Equal(t, []GalleryItem{
+		{MediaID: "second", Caption: "Second image first", URL: "https://i.redd.it/second.jpg", Width: 1920, Height: 1080, MIMEType: "image/jpg"},
+		{MediaID: "first", URL: "https://i.redd.it/first.png", Width: 800, Height: 600, MIMEType: "image/png"},
+		{MediaID: "anim", Caption: "Moving", URL: "https://i.redd.it/anim.gif", Width: 320, Height: 240, MIMEType: "image/gif"},
+	}, post.GalleryItems())
+}
+
+func TestPost_GalleryItemsNonGallery(t *testing.T) {
+	var post Post
+	require.NoError(t, json.Unmarshal([]byte(`{"id": "self1", "is_self": true, "media_metadata": null}`), &post))
+
+	assert.False(t, post.IsGallery)
+	assert.Nil(t, post.GalleryItems())
+}
+
+func TestPost_GalleryItemsUnknownMIMEType(t *testing.T) {
+	var post Post
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"gallery_data": {"items": [{"media_id": "m1"}]},
+		"media_metadata": {"m1": {"status": "valid", "s": {"x": 10, "y": 10, "u": "https://preview.redd.it/m1?a=1&amp;b=2"}}}
+	}`), &post))
+
+	items := post.GalleryItems()
+	require.Len(t, items, 1)
+	assert.Equal(t, "https://preview.redd.it/m1?a=1&b=2", items[0].URL)
+}
+
+const nativeVideoFixture = `{
+	"id": "vid1",
+	"is_video": true,
+	"url": "https://v.redd.it/abcdef",
+	"secure_media": {"reddit_video": {
+		"fallback_url": "https://v.redd.it/abcdef/DASH_720.mp4?source=fallback",
+		"dash_url": "https://v.redd.it/abcdef/DASHPlaylist.mpd?a=1&amp;v=1",
+		"hls_url": "https://v.redd.it/abcdef/HLSPlaylist.m3u8?a=1&amp;v=1",
+		"duration": 42,
+		"is_gif": false,
+		"width": 1280,
+		"height": 720
+	}},
+	"media": {"reddit_video": {"fallback_url": "https://v.redd.it/abcdef/DASH_720.mp4?source=fallback"}}
+}`
+
+const gifVideoFixture = `{
+	"id": "gif1",
+	"is_video": true,
+	"media": {"reddit_video": {
+		"fallback_url": "https://v.redd.it/gifgif/DASH_480.mp4?source=fallback",
+		"duration": 3,
+		"is_gif": true,
+		"width": 480,
+		"height": 270
+	}},
+	"secure_media": null
+}`
+
+const crosspostedVideoFixture = `{
+	"id": "xpost1",
+	"is_video": false,
+	"url": "/r/videos/comments/vid1/",
+	"secure_media": null,
+	"media": null,
+	"crosspost_parent_list": [` + nativeVideoFixture + `]
+}`
+
+func TestPost_VideoURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		wantURL string
+		wantGIF bool
+	}{
+		{"native video", nativeVideoFixture, "https://v.redd.it/abcdef/HLSPlaylist.m3u8?a=1&v=1", false},
+		{"gif video", gifVideoFixture, "https://v.redd.it/gifgif/DASH_480.mp4?source=fallback", true},
+		{"crossposted video", crosspostedVideoFixture, "https://v.redd.it/abcdef/HLSPlaylist.m3u8?a=1&v=1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var post Post
+			require.NoError(t, json.Unmarshal([]byte(tt.fixture), &post))
+
+			video := post.Video()
+			require.NotNil(t, video)
+			assert.Equal(t, tt.wantGIF, video.IsGIF)
+			assert.Equal(t, tt.wantURL, post.VideoURL())
+		})
+	}
+}
+
+func TestPost_VideoFields(t *testing.T) {
+	var post Post
+	require.NoError(t, json.Unmarshal([]byte(nativeVideoFixture), &post))
+
+	video := post.Video()
+	require.NotNil(t, video)
+	assert.Equal(t, 42, video.Duration)
+	assert.Equal(t, 1280, video.Width)
+	assert.Equal(t, 720, video.Height)
+	assert.Equal(t, "https://v.redd.it/abcdef/DASHPlaylist.mpd?a=1&v=1", video.DashURL)
+}
+
+func TestPost_VideoURLNonVideo(t *testing.T) {
+	var post Post
+	require.NoError(t, json.Unmarshal([]byte(`{"id": "link1", "url": "https://go.dev", "media": {"oembed": {}}}`), &post))
+
+	assert.Nil(t, post.Video())
+	assert.Empty(t, post.VideoURL())
+}
@@ -0,0 +1,82 @@
+package redditclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// GetMultireddits fetches the multireddits owned by username
+func (c *Client) GetMultireddits(ctx context.Context, username string) ([]Multireddit, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	body, err := c.makeAPIRequest(ctx, fmt.Sprintf("/api/multi/user/%s", url.PathEscape(username)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var things []Thing[Multireddit]
+	if err := c.decodeJSON(ctx, body, &things, "multireddits"); err != nil {
+		return nil, err
+	}
+
+	multis := make([]Multireddit, len(things))
+	for i, thing := range things {
+		multis[i] = thing.Data
+	}
+
+	return multis, nil
+}
+
+// GetMultiListing fetches one page of the combined listing of a user's
+// multireddit. Another user's private multi fails with an error matching
+// ErrForbidden.
+func (c *Client) GetMultiListing(ctx context.Context, username, multiName, sort string, opts *ListingOptions) (*PostListing, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	if sort == "" {
+		sort = string(SortHot)
+	}
+	if err := Sort(sort).validate(); err != nil {
+		return nil, err
+	}
+
+	params, err := listingParams(Sort(sort), "", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.getListingPage(ctx, fmt.Sprintf("/user/%s/m/%s/%s.json", url.PathEscape(username), url.PathEscape(multiName), sort), params)
+}
+
+// UnmarshalJSON flattens Reddit's [{"name": ...}] subreddit list into names.
+// Plain strings are accepted too, so a marshaled Multireddit decodes again.
+func (m *Multireddit) UnmarshalJSON(data []byte) error {
+	type multiAlias Multireddit
+	aux := struct {
+		*multiAlias
+		Subreddits []json.RawMessage `json:"subreddits"`
+	}{multiAlias: (*multiAlias)(m)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	m.Subreddits = make([]string, 0, len(aux.Subreddits))
+	for _, raw := range aux.Subreddits {
+		var sub struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(raw, &sub); err != nil {
+			if err := json.Unmarshal(raw, &sub.Name); err != nil {
+				return fmt.Errorf("failed to decode multireddit subreddit: %w", err)
+			}
+		}
+		m.Subreddits = append(m.Subreddits, sub.Name)
+	}
+
+	return nil
+}
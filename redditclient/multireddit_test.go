@@ -0,0 +1,84 @@
+package redditclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const multisBody = `[
+	{"kind": "LabeledMulti", "data": {"name": "news", "display_name": "News", "path": "/user/spez/m/news/",
+		"owner": "spez", "visibility": "public", "icon_url": "https://example.com/icon.png",
+		"subreddits": [{"name": "worldnews"}, {"name": "technology"}]}},
+	{"kind": "LabeledMulti", "data": {"name": "empty", "display_name": "Empty", "visibility": "private", "subreddits": []}}
+]`
+
+func TestGetMultireddits_Success(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.Respond("/api/multi/user/spez", http.StatusOK, multisBody)
+
+	multis, err := client.GetMultireddits(t.Context(), "spez")
+
+	require.NoError(t, err)
+	require.Len(t, multis, 2)
+	assert.Equal(t, "News", multis[0].DisplayName)
+	assert.Equal(t, []string{"worldnews", "technology"}, multis[0].Subreddits)
+	assert.Equal(t, "public", multis[0].Visibility)
+	assert.Equal(t, "https://example.com/icon.png", multis[0].IconURL)
+	assert.Equal(t, "private", multis[1].Visibility)
+	assert.Empty(t, multis[1].Subreddits)
+}
+
+func TestMultireddit_RoundTrip(t *testing.T) {
+	data, err := json.Marshal(Multireddit{Name: "news", Subreddits: []string{"worldnews"}})
+	require.NoError(t, err)
+
+	var multi Multireddit
+	require.NoError(t, json.Unmarshal(data, &multi))
+	assert.Equal(t, []string{"worldnews"}, multi.Subreddits)
+}
+
+func TestGetMultiListing_Success(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.Respond("/user/spez/m/news/top.json", http.StatusOK, createListingPage([]string{"p1", "p2"}, "t3_p2"))
+
+	listing, err := client.GetMultiListing(t.Context(), "spez", "news", "top", &ListingOptions{Limit: 10})
+
+	require.NoError(t, err)
+	require.Len(t, listing.Data.Children, 2)
+	assert.Equal(t, "t3_p2", listing.Data.After)
+
+	req, ok := srv.LastRequest("/user/spez/m/news/top.json")
+	require.True(t, ok)
+	assert.Equal(t, "10", req.Query.Get("limit"))
+}
+
+func TestGetMultiListing_DefaultsToHot(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+
+	_, err := client.GetMultiListing(t.Context(), "spez", "news", "", nil)
+
+	require.NoError(t, err)
+	_, ok := srv.LastRequest("/user/spez/m/news/hot.json")
+	assert.True(t, ok)
+}
+
+func TestGetMultiListing_PrivateMultiForbidden(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.Respond("/user/spez/m/secret/hot.json", http.StatusForbidden, `{"message": "Forbidden", "error": 403}`)
+
+	_, err := client.GetMultiListing(t.Context(), "spez", "secret", "hot", nil)
+
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestGetMultiListing_InvalidSort(t *testing.T) {
+	client, _ := newAuthenticatedFakeServerClient(t)
+
+	_, err := client.GetMultiListing(t.Context(), "spez", "news", "sideways", nil)
+
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+}
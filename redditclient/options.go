@@ -0,0 +1,385 @@
+package redditclient
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option configures a Client created by NewClient
+type Option func(*clientConfig) error
+
+// clientConfig collects options before the Client is assembled
+type clientConfig struct {
+	httpClient      HTTPClient
+	proxy           func(*http.Request) (*url.URL, error)
+	timeout         time.Duration
+	userAgent       string
+	userAgentPool   []string
+	rotateUserAgent bool
+	deviceID        string
+	randSource      rand.Source
+	qos             QoSGenerator
+	clientID        string
+	scopes          []string
+	baseURL         string
+	authURL         string
+	logger          *slog.Logger
+	retryPolicy     RetryPolicy
+	rateLimitMode   RateLimitBehavior
+	maxRespBytes    int64
+	maxErrorBody    int
+	cache           Cache
+	cacheTTL        time.Duration
+	coalesce        bool
+	rawText         bool
+	contentOptIn    []Restriction // nil means both restrictions
+	tracerProvider  trace.TracerProvider
+	meterProvider   metric.MeterProvider
+	breaker         *CircuitBreakerPolicy
+}
+
+// WithHTTPClient sets the HTTP client used for all requests. A nil client
+// keeps the default, which sends headers in the Android app's order over
+// HTTP/1.1 and, unless WithProxy is given, uses the proxy named by
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY. A custom client such as
+// &http.Client{} speaks HTTP/2 but canonicalizes header order.
+func WithHTTPClient(httpClient HTTPClient) Option {
+	return func(cfg *clientConfig) error {
+		cfg.httpClient = httpClient
+		return nil
+	}
+}
+
+// WithTimeout sets the per-request timeout of the default HTTP client. When
+// combined with WithHTTPClient the client must be an *http.Client, which is
+// copied rather than modified.
+func WithTimeout(timeout time.Duration) Option {
+	return func(cfg *clientConfig) error {
+		if timeout < 0 {
+			return fmt.Errorf("timeout must not be negative, got %s", timeout)
+		}
+		cfg.timeout = timeout
+		return nil
+	}
+}
+
+// WithUserAgent pins the User-Agent in place of one picked from the pool of
+// Android app versions
+func WithUserAgent(userAgent string) Option {
+	return func(cfg *clientConfig) error {
+		if userAgent == "" {
+			return errors.New("user agent must not be empty")
+		}
+		cfg.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithUserAgentPool replaces the built-in list of Android app user agents the
+// client picks from. The pick is derived from the device ID, so a client with
+// a fixed WithDeviceID presents the same user agent every time it starts.
+func WithUserAgentPool(userAgents ...string) Option {
+	return func(cfg *clientConfig) error {
+		if len(userAgents) == 0 {
+			return errors.New("user agent pool must not be empty")
+		}
+		if slices.Contains(userAgents, "") {
+			return errors.New("user agent pool must not contain an empty user agent")
+		}
+		cfg.userAgentPool = slices.Clone(userAgents)
+		return nil
+	}
+}
+
+// WithUserAgentRotation picks a new user agent from the pool each time the
+// client re-authenticates. Within a session the authentication request and
+// every API call share one user agent, as Reddit flags sessions whose user
+// agent changes. It cannot be combined with WithUserAgent.
+func WithUserAgentRotation() Option {
+	return func(cfg *clientConfig) error {
+		cfg.rotateUserAgent = true
+		return nil
+	}
+}
+
+// WithDeviceID overrides the randomly generated device ID sent to Reddit
+func WithDeviceID(deviceID string) Option {
+	return func(cfg *clientConfig) error {
+		if deviceID == "" {
+			return errors.New("device ID must not be empty")
+		}
+		cfg.deviceID = deviceID
+		return nil
+	}
+}
+
+// WithRandSource makes the client draw its randomness from src: the default
+// device ID, and so the user agent picked for it, the x-reddit-qos header and
+// retry jitter. A seeded source such as rand.NewPCG(1, 2) makes these the
+// same on every run, for reproducible tests. The default is a ChaCha8
+// generator seeded from crypto/rand.
+func WithRandSource(src rand.Source) Option {
+	return func(cfg *clientConfig) error {
+		if src == nil {
+			return errors.New("rand source must not be nil")
+		}
+		cfg.randSource = src
+		return nil
+	}
+}
+
+// WithQoSGenerator replaces UniformQoS as the generator of the x-reddit-qos
+// header value sent when authenticating anonymously
+func WithQoSGenerator(gen QoSGenerator) Option {
+	return func(cfg *clientConfig) error {
+		if gen == nil {
+			return errors.New("QoS generator must not be nil")
+		}
+		cfg.qos = gen
+		return nil
+	}
+}
+
+// WithClientID sets the installed-app OAuth client ID Authenticate uses in
+// place of ANDROID_CLIENT_ID, e.g. after Reddit rotates the Android app's ID
+func WithClientID(clientID string) Option {
+	return func(cfg *clientConfig) error {
+		if clientID == "" {
+			return errors.New("client ID must not be empty")
+		}
+		cfg.clientID = clientID
+		return nil
+	}
+}
+
+// WithScopes sets the OAuth scopes Authenticate requests in place of
+// DefaultScopes
+func WithScopes(scopes ...string) Option {
+	return func(cfg *clientConfig) error {
+		if len(scopes) == 0 {
+			return errors.New("scopes must not be empty")
+		}
+		if slices.Contains(scopes, "") {
+			return errors.New("scopes must not contain an empty scope")
+		}
+		cfg.scopes = slices.Clone(scopes)
+		return nil
+	}
+}
+
+// WithBaseURL points both the API and authentication endpoints at rawURL,
+// e.g. an httptest server or a self-hosted mirror
+func WithBaseURL(rawURL string) Option {
+	return func(cfg *clientConfig) error {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return fmt.Errorf("invalid base URL: %w", err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid base URL %q: scheme and host are required", rawURL)
+		}
+
+		base := strings.TrimSuffix(u.String(), "/")
+		cfg.baseURL = base
+		cfg.authURL = base
+		return nil
+	}
+}
+
+// WithProxy sends every request through the proxy at rawURL, which may be an
+// http://, socks5:// or socks5h:// URL with optional credentials. HTTPS
+// requests are tunneled through HTTP proxies with CONNECT. SOCKS5 proxies
+// always resolve host names themselves, whether the scheme is socks5 or
+// socks5h. It replaces the proxy from the environment, and cannot be combined
+// with WithHTTPClient; configure the proxy on that client instead.
+func WithProxy(rawURL string) Option {
+	return func(cfg *clientConfig) error {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		if err := validateProxyURL(u); err != nil {
+			return err
+		}
+		cfg.proxy = func(*http.Request) (*url.URL, error) { return u, nil }
+		return nil
+	}
+}
+
+// WithProxyFunc picks the proxy for each request, e.g. to rotate through a
+// pool. proxy returns a URL as accepted by WithProxy, or nil to connect
+// directly. It cannot be combined with WithHTTPClient.
+func WithProxyFunc(proxy func(*http.Request) (*url.URL, error)) Option {
+	return func(cfg *clientConfig) error {
+		if proxy == nil {
+			return errors.New("proxy func must not be nil")
+		}
+		cfg.proxy = proxy
+		return nil
+	}
+}
+
+// WithLogger sets the logger the client reports to. Without it the client
+// logs nothing.
+func WithLogger(logger *slog.Logger) Option {
+	return func(cfg *clientConfig) error {
+		cfg.logger = logger
+		return nil
+	}
+}
+
+// WithRetryPolicy enables automatic retries; see SetRetryPolicy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(cfg *clientConfig) error {
+		cfg.retryPolicy = policy
+		return nil
+	}
+}
+
+// WithMaxResponseBytes caps how large a response body may be, both on the wire
+// and after decompression. Larger responses fail with ErrResponseTooLarge.
+func WithMaxResponseBytes(n int64) Option {
+	return func(cfg *clientConfig) error {
+		if n <= 0 {
+			return fmt.Errorf("max response bytes must be positive, got %d", n)
+		}
+		cfg.maxRespBytes = n
+		return nil
+	}
+}
+
+// WithMaxErrorBodyBytes sets how much of a response body an APIError keeps in
+// its Body field, DefaultMaxErrorBodyBytes by default. Zero drops the body.
+func WithMaxErrorBodyBytes(n int) Option {
+	return func(cfg *clientConfig) error {
+		if n < 0 {
+			return fmt.Errorf("max error body bytes must not be negative, got %d", n)
+		}
+		cfg.maxErrorBody = n
+		return nil
+	}
+}
+
+// WithCache serves repeated GET requests from cache for ttl after each
+// successful response. Once an entry expires, an ETag or Last-Modified sent
+// with it is used to revalidate rather than refetch it. Cache hits use no rate
+// limit quota; see ForceFresh to bypass the cache for a single call.
+func WithCache(cache Cache, ttl time.Duration) Option {
+	return func(cfg *clientConfig) error {
+		if cache == nil {
+			return errors.New("cache must not be nil")
+		}
+		if ttl <= 0 {
+			return fmt.Errorf("cache TTL must be positive, got %s", ttl)
+		}
+		cfg.cache = cache
+		cfg.cacheTTL = ttl
+		return nil
+	}
+}
+
+// WithRequestCoalescing makes concurrent identical GET requests share a single
+// round trip. Each caller decodes its own copy of the result, and a caller
+// whose context ends stops waiting without cancelling the fetch for the others.
+func WithRequestCoalescing() Option {
+	return func(cfg *clientConfig) error {
+		cfg.coalesce = true
+		return nil
+	}
+}
+
+// WithRawText leaves post titles, selftext and comment bodies exactly as Reddit
+// sent them. By default HTML entities in them are unescaped, since some
+// endpoints ignore raw_json and return text with "&amp;"-style escapes.
+func WithRawText() Option {
+	return func(cfg *clientConfig) error {
+		cfg.rawText = true
+		return nil
+	}
+}
+
+// WithContentOptIn sets which content warnings the client accepts by retrying
+// with the matching opt-in cookie. By default it accepts both gated and
+// quarantined subreddits; WithContentOptIn() with no restrictions accepts
+// neither, so their content returns ErrSubredditGated or a *QuarantineError.
+func WithContentOptIn(restrictions ...Restriction) Option {
+	return func(cfg *clientConfig) error {
+		for _, r := range restrictions {
+			if r != RestrictionGated && r != RestrictionQuarantined {
+				return fmt.Errorf("%w: unknown restriction %q", ErrInvalidArgument, r)
+			}
+		}
+		cfg.contentOptIn = append([]Restriction{}, restrictions...)
+		return nil
+	}
+}
+
+// WithRateLimitBehavior sets what the client does when the rate limit runs
+// out; see SetRateLimitBehavior
+func WithRateLimitBehavior(behavior RateLimitBehavior) Option {
+	return func(cfg *clientConfig) error {
+		cfg.rateLimitMode = behavior
+		return nil
+	}
+}
+
+// WithTracerProvider makes every API call emit a client span from tp, named
+// after the endpoint's template, such as reddit.get_subreddit or
+// reddit.get_comments. Spans carry the subreddit and sort where the call has
+// them, the response status code, the rate limit remaining, the retry count
+// and, with WithCache, whether the cache was hit. Without this option the
+// client does no tracing work at all.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(cfg *clientConfig) error {
+		if tp == nil {
+			return errors.New("tracer provider must not be nil")
+		}
+		cfg.tracerProvider = tp
+		return nil
+	}
+}
+
+// WithMeterProvider records metrics for every API call with mp: the
+// reddit.client.requests and reddit.client.retries counters and the
+// reddit.client.duration histogram, in seconds. They carry the operation,
+// sort, status code and cache attributes of the spans of WithTracerProvider
+// but not the subreddit, which would make a time series per subreddit
+// requested.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(cfg *clientConfig) error {
+		if mp == nil {
+			return errors.New("meter provider must not be nil")
+		}
+		cfg.meterProvider = mp
+		return nil
+	}
+}
+
+// WithCircuitBreaker stops sending requests once Reddit fails
+// policy.Threshold times in a row with a 5xx response or a transport error;
+// 4xx responses do not count. Calls then fail fast with ErrCircuitOpen until
+// policy.Cooldown has passed, when a single probe request decides whether to
+// close the breaker or keep it open. See DefaultCircuitBreakerPolicy,
+// Client.CircuitState and Client.ResetCircuit.
+func WithCircuitBreaker(policy CircuitBreakerPolicy) Option {
+	return func(cfg *clientConfig) error {
+		if policy.Threshold < 1 {
+			return fmt.Errorf("circuit breaker threshold must be at least 1, got %d", policy.Threshold)
+		}
+		if policy.Cooldown <= 0 {
+			return fmt.Errorf("circuit breaker cooldown must be positive, got %s", policy.Cooldown)
+		}
+		cfg.breaker = &policy
+		return nil
+	}
+}
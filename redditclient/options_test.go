@@ -0,0 +1,116 @@
+package redditclient
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient_Defaults(t *testing.T) {
+	client, err := NewClient()
+	require.NoError(t, err)
+
+	hc, ok := client.httpClient.(*http.Client)
+	require.True(t, ok)
+	assert.Equal(t, DefaultTimeout, hc.Timeout)
+	assert.Equal(t, DefaultBaseURL, client.baseURL)
+	assert.Equal(t, DefaultAuthURL, client.authURL)
+	assert.Contains(t, androidVersions, client.userAgent)
+	assert.Equal(t, RetryPolicy{}, *client.retryPolicy.Load())
+	assert.Equal(t, IgnoreLimit, client.rateLimitMode)
+	assert.EqualValues(t, DefaultMaxResponseBytes, client.maxRespBytes)
+	assert.Equal(t, DefaultMaxErrorBodyBytes, client.maxErrorBody)
+	assert.Equal(t, ANDROID_CLIENT_ID, client.clientID)
+	assert.Equal(t, DefaultScopes, client.scopes)
+}
+
+func TestNewClient_Options(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	client, err := NewClient(
+		WithUserAgent("grapeddit-test/1.0"),
+		WithDeviceID("device-123"),
+		WithBaseURL("http://127.0.0.1:8080/"),
+		WithLogger(logger),
+		WithRetryPolicy(DefaultRetryPolicy),
+		WithRateLimitBehavior(WaitOnLimit),
+		WithTimeout(5*time.Second),
+		WithMaxResponseBytes(1<<20),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "grapeddit-test/1.0", client.userAgent)
+	assert.Equal(t, "device-123", client.deviceID)
+	assert.Equal(t, "http://127.0.0.1:8080", client.baseURL)
+	assert.Equal(t, "http://127.0.0.1:8080", client.authURL)
+	assert.Same(t, logger, client.logger)
+	assert.Equal(t, DefaultRetryPolicy, *client.retryPolicy.Load())
+	assert.Equal(t, WaitOnLimit, client.rateLimitMode)
+	assert.Equal(t, 5*time.Second, client.httpClient.(*http.Client).Timeout)
+	assert.EqualValues(t, 1<<20, client.maxRespBytes)
+}
+
+func TestNewClient_TimeoutCopiesHTTPClient(t *testing.T) {
+	hc := &http.Client{Timeout: time.Minute}
+
+	client, err := NewClient(WithHTTPClient(hc), WithTimeout(time.Second))
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Second, client.httpClient.(*http.Client).Timeout)
+	assert.Equal(t, time.Minute, hc.Timeout, "caller's client must not be modified")
+}
+
+func TestNewClient_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"relative base URL", []Option{WithBaseURL("/api")}},
+		{"unparseable base URL", []Option{WithBaseURL("http://[::1")}},
+		{"empty user agent", []Option{WithUserAgent("")}},
+		{"empty device ID", []Option{WithDeviceID("")}},
+		{"negative timeout", []Option{WithTimeout(-time.Second)}},
+		{"zero max response bytes", []Option{WithMaxResponseBytes(0)}},
+		{"negative max error body bytes", []Option{WithMaxErrorBodyBytes(-1)}},
+		{"empty client ID", []Option{WithClientID("")}},
+		{"empty user agent pool", []Option{WithUserAgentPool()}},
+		{"empty user agent in pool", []Option{WithUserAgentPool("Reddit/2025.10.0/Android 14", "")}},
+		{"rotation with pinned user agent", []Option{WithUserAgent("grapeddit-test/1.0"), WithUserAgentRotation()}},
+		{"no scopes", []Option{WithScopes()}},
+		{"empty scope", []Option{WithScopes("read", "")}},
+		{"timeout on custom client", []Option{WithHTTPClient(&MockHTTPClient{}), WithTimeout(time.Second)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(tt.opts...)
+			assert.Error(t, err)
+			assert.Nil(t, client)
+		})
+	}
+}
+
+func TestWithBaseURL_RoutesRequests(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP), WithBaseURL("http://localhost:9999"))
+	require.NoError(t, err)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == "http://localhost:9999/auth/v2/oauth/access-token/loid"
+	})).Return(createOAuthResponse("test-token", 3600), nil).Once()
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Host == "localhost:9999" && req.URL.Path == "/r/golang/hot.json"
+	})).Return(createHTTPResponse(200, emptyListing, nil), nil).Once()
+
+	require.NoError(t, client.Authenticate(t.Context()))
+	_, err = client.GetSubreddit(t.Context(), "golang", "hot")
+	require.NoError(t, err)
+	mockHTTP.AssertExpectations(t)
+}
@@ -0,0 +1,149 @@
+package redditclient
+
+import (
+	"context"
+	"iter"
+)
+
+// SubredditPosts returns an iterator over every post in a subreddit listing.
+// It follows the After cursor until Reddit returns an empty page, the
+// MaxPages/MaxItems caps in opts are reached, or ctx is cancelled. Iteration
+// stops after the first error is yielded.
+func (c *Client) SubredditPosts(ctx context.Context, subreddit, sort string, opts *ListingOptions) iter.Seq2[Post, error] {
+	return listingItems(ctx, opts, func(page *ListingOptions) (*SubredditListing, error) {
+		return c.GetSubredditPage(ctx, subreddit, sort, page)
+	})
+}
+
+// FrontpagePosts returns an iterator over every post on the front page,
+// following the same cursor and cap rules as SubredditPosts
+func (c *Client) FrontpagePosts(ctx context.Context, sort string, opts *ListingOptions) iter.Seq2[Post, error] {
+	return listingItems(ctx, opts, func(page *ListingOptions) (*SubredditListing, error) {
+		return c.GetFrontpage(ctx, sort, page)
+	})
+}
+
+// UserPosts returns an iterator over every post a user has submitted,
+// following the same cursor and cap rules as SubredditPosts
+func (c *Client) UserPosts(ctx context.Context, username, sort, timeframe string, opts *ListingOptions) iter.Seq2[Post, error] {
+	return listingItems(ctx, opts, func(page *ListingOptions) (*PostListing, error) {
+		return c.GetUserPosts(ctx, username, sort, timeframe, page)
+	})
+}
+
+// UserComments returns an iterator over every comment a user has made,
+// following the same cursor and cap rules as SubredditPosts
+func (c *Client) UserComments(ctx context.Context, username, sort, timeframe string, opts *ListingOptions) iter.Seq2[Comment, error] {
+	return pageItems(ctx, opts, func(page *ListingOptions) ([]Comment, string, error) {
+		listing, err := c.GetUserComments(ctx, username, sort, timeframe, page)
+		if err != nil {
+			return nil, "", err
+		}
+		comments := make([]Comment, 0, len(listing.Data.Children))
+		for _, child := range listing.Data.Children {
+			if child.Comment != nil {
+				comments = append(comments, *child.Comment)
+			}
+		}
+		return comments, listing.Data.After, nil
+	})
+}
+
+// UserOverviewItems returns an iterator over a user's combined history of
+// posts and comments, following the same cursor and cap rules as
+// SubredditPosts
+func (c *Client) UserOverviewItems(ctx context.Context, username string, opts *ListingOptions) iter.Seq2[ThingChild, error] {
+	return pageItems(ctx, opts, func(page *ListingOptions) ([]ThingChild, string, error) {
+		listing, err := c.GetUserOverview(ctx, username, page)
+		if err != nil {
+			return nil, "", err
+		}
+		return listing.Data.Children, listing.Data.After, nil
+	})
+}
+
+// SearchPosts returns an iterator over every post matching a search,
+// following the same cursor and cap rules as SubredditPosts. The pagination
+// fields and caps come from opts.ListingOptions. Subreddit and user results
+// are not yielded.
+func (c *Client) SearchPosts(ctx context.Context, query string, opts *SearchOptions) iter.Seq2[Post, error] {
+	var search SearchOptions
+	if opts != nil {
+		search = *opts
+	}
+	return pageItems(ctx, &search.ListingOptions, func(page *ListingOptions) ([]Post, string, error) {
+		pageOpts := search
+		pageOpts.ListingOptions = *page
+		results, err := c.SearchWithOptions(ctx, query, &pageOpts)
+		if err != nil {
+			return nil, "", err
+		}
+		return results.Posts, results.After, nil
+	})
+}
+
+// listingItems is pageItems over any listing of things, yielding each
+// child's data
+func listingItems[T any](ctx context.Context, opts *ListingOptions, fetch func(page *ListingOptions) (*Listing[Thing[T]], error)) iter.Seq2[T, error] {
+	return pageItems(ctx, opts, func(page *ListingOptions) ([]T, string, error) {
+		listing, err := fetch(page)
+		if err != nil {
+			return nil, "", err
+		}
+		items := make([]T, len(listing.Data.Children))
+		for i, child := range listing.Data.Children {
+			items[i] = child.Data
+		}
+		return items, listing.Data.After, nil
+	})
+}
+
+// pageItems drives fetch page by page, yielding the items of each page and
+// advancing the After cursor fetch returns with them. It is shared by every
+// listing iterator, whatever the shape of its pages.
+func pageItems[T any](ctx context.Context, opts *ListingOptions, fetch func(page *ListingOptions) (items []T, after string, err error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		var page ListingOptions
+		if opts != nil {
+			page = *opts
+		}
+
+		pages, count := 0, 0
+		for {
+			if page.MaxPages > 0 && pages >= page.MaxPages {
+				return
+			}
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			items, after, err := fetch(&page)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			pages++
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+				count++
+				if page.MaxItems > 0 && count >= page.MaxItems {
+					return
+				}
+			}
+
+			// Stop at the end of the listing or if Reddit hands back the same cursor
+			if len(items) == 0 || after == "" || after == page.After {
+				return
+			}
+
+			page.After = after
+			page.Before = ""
+			page.Count += len(items)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package redditclient
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Helper function to build a listing page body with sequential post IDs
+func createListingPage(ids []string, after string) string {
+	children := ""
+	for i, id := range ids {
+		if i > 0 {
+			children += ","
+		}
+		children += fmt.Sprintf(`{"kind": "t3", "data": {"id": "%s", "title": "Post %s"}}`, id, id)
+	}
+	return fmt.Sprintf(`{"kind": "Listing", "data": {"children": [%s], "after": "%s"}}`, children, after)
+}
+
+func matchAfter(after string) interface{} {
+	return mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("after") == after
+	})
+}
+
+func TestSubredditPosts_FollowsAfterCursor(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", matchAfter("")).
+		Return(createHTTPResponse(200, createListingPage([]string{"a", "b"}, "t3_b"), nil), nil).Once()
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("after") == "t3_b" && req.URL.Query().Get("count") == "2"
+	})).Return(createHTTPResponse(200, createListingPage([]string{"c"}, "t3_c"), nil), nil).Once()
+	mockHTTP.On("Do", matchAfter("t3_c")).
+		Return(createHTTPResponse(200, createListingPage(nil, ""), nil), nil).Once()
+
+	var ids []string
+	for post, err := range client.SubredditPosts(t.Context(), "golang", "new", nil) {
+		require.NoError(t, err)
+		ids = append(ids, post.ID)
+	}
+
+	assert.Equal(t, []string{"a", "b", "c"}, ids)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestSubredditPosts_StopsAtMissingAfter(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", matchAfter("")).
+		Return(createHTTPResponse(200, createListingPage([]string{"a"}, ""), nil), nil).Once()
+
+	var ids []string
+	for post, err := range client.SubredditPosts(t.Context(), "golang", "new", nil) {
+		require.NoError(t, err)
+		ids = append(ids, post.ID)
+	}
+
+	assert.Equal(t, []string{"a"}, ids)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestSubredditPosts_MaxPages(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", matchAfter("")).
+		Return(createHTTPResponse(200, createListingPage([]string{"a", "b"}, "t3_b"), nil), nil).Once()
+
+	var ids []string
+	for post, err := range client.SubredditPosts(t.Context(), "golang", "new", &ListingOptions{MaxPages: 1}) {
+		require.NoError(t, err)
+		ids = append(ids, post.ID)
+	}
+
+	assert.Equal(t, []string{"a", "b"}, ids)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestSubredditPosts_MaxItems(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", matchAfter("")).
+		Return(createHTTPResponse(200, createListingPage([]string{"a", "b"}, "t3_b"), nil), nil).Once()
+	mockHTTP.On("Do", matchAfter("t3_b")).
+		Return(createHTTPResponse(200, createListingPage([]string{"c", "d"}, "t3_d"), nil), nil).Once()
+
+	var ids []string
+	for post, err := range client.SubredditPosts(t.Context(), "golang", "new", &ListingOptions{MaxItems: 3}) {
+		require.NoError(t, err)
+		ids = append(ids, post.ID)
+	}
+
+	assert.Equal(t, []string{"a", "b", "c"}, ids)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestSubredditPosts_StopsOnError(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", matchAfter("")).
+		Return(createHTTPResponse(200, createListingPage([]string{"a"}, "t3_a"), nil), nil).Once()
+	mockHTTP.On("Do", matchAfter("t3_a")).
+		Return(createHTTPResponse(500, "server error", nil), nil).Once()
+
+	var ids []string
+	var errs []error
+	for post, err := range client.SubredditPosts(t.Context(), "golang", "new", nil) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, post.ID)
+	}
+
+	assert.Equal(t, []string{"a"}, ids)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "status 500")
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestSubredditPosts_ContextCancelled(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	mockHTTP.On("Do", matchAfter("")).
+		Return(createHTTPResponse(200, createListingPage([]string{"a"}, "t3_a"), nil), nil).Once()
+
+	var errs []error
+	for _, err := range client.SubredditPosts(ctx, "golang", "new", nil) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		cancel()
+	}
+
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], context.Canceled)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestSubredditPosts_EarlyBreak(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", matchAfter("")).
+		Return(createHTTPResponse(200, createListingPage([]string{"a", "b"}, "t3_b"), nil), nil).Once()
+
+	for post, err := range client.SubredditPosts(t.Context(), "golang", "new", nil) {
+		require.NoError(t, err)
+		assert.Equal(t, "a", post.ID)
+		break
+	}
+
+	mockHTTP.AssertExpectations(t)
+}
+
+// Helper function to build a user comments page body with sequential comment IDs
+func createCommentPage(ids []string, after string) string {
+	children := ""
+	for i, id := range ids {
+		if i > 0 {
+			children += ","
+		}
+		children += fmt.Sprintf(`{"kind": "t1", "data": {"id": "%s", "body": "Comment %s", "replies": ""}}`, id, id)
+	}
+	return fmt.Sprintf(`{"kind": "Listing", "data": {"children": [%s], "after": "%s"}}`, children, after)
+}
+
+func TestListingIterators_Uniform(t *testing.T) {
+	tests := []struct {
+		name string
+		page func(ids []string, after string) string
+		ids  func(c *Client, opts *ListingOptions) ([]string, error)
+	}{
+		{"subreddit", createListingPage, func(c *Client, opts *ListingOptions) ([]string, error) {
+			return drainIDs(c.SubredditPosts(t.Context(), "golang", "new", opts), func(p Post) string { return p.ID })
+		}},
+		{"search", createListingPage, func(c *Client, opts *ListingOptions) ([]string, error) {
+			search := &SearchOptions{ListingOptions: *opts, Sort: SearchSortNew}
+			return drainIDs(c.SearchPosts(t.Context(), "generics", search), func(p Post) string { return p.ID })
+		}},
+		{"user posts", createListingPage, func(c *Client, opts *ListingOptions) ([]string, error) {
+			return drainIDs(c.UserPosts(t.Context(), "gopher", "new", "", opts), func(p Post) string { return p.ID })
+		}},
+		{"user comments", createCommentPage, func(c *Client, opts *ListingOptions) ([]string, error) {
+			return drainIDs(c.UserComments(t.Context(), "gopher", "new", "", opts), func(c Comment) string { return c.ID })
+		}},
+		{"user overview", createCommentPage, func(c *Client, opts *ListingOptions) ([]string, error) {
+			return drainIDs(c.UserOverviewItems(t.Context(), "gopher", opts), func(c ThingChild) string { return c.Comment.ID })
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mockHTTP := newAuthenticatedTestClient(t)
+
+			mockHTTP.On("Do", matchAfter("")).
+				Return(createHTTPResponse(200, tt.page([]string{"a", "b"}, "t3_b"), nil), nil).Once()
+			mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+				return req.URL.Query().Get("after") == "t3_b" && req.URL.Query().Get("count") == "2"
+			})).Return(createHTTPResponse(200, tt.page([]string{"c", "d"}, "t3_d"), nil), nil).Once()
+
+			ids, err := tt.ids(client, &ListingOptions{MaxItems: 3})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"a", "b", "c"}, ids)
+			mockHTTP.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSearchPosts_KeepsSearchOptions(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		q := req.URL.Query()
+		return req.URL.Path == "/r/golang/search.json" && q.Get("after") == "" &&
+			q.Get("sort") == "top" && q.Get("t") == "year" && q.Get("limit") == "2"
+	})).Return(createHTTPResponse(200, createListingPage([]string{"a", "b"}, "t3_b"), nil), nil).Once()
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		q := req.URL.Query()
+		return req.URL.Path == "/r/golang/search.json" && q.Get("after") == "t3_b" &&
+			q.Get("sort") == "top" && q.Get("t") == "year" && q.Get("restrict_sr") == "1"
+	})).Return(createHTTPResponse(200, createListingPage(nil, ""), nil), nil).Once()
+
+	opts := &SearchOptions{
+		ListingOptions: ListingOptions{Limit: 2},
+		Subreddit:      "golang",
+		Sort:           SearchSortTop,
+		Timeframe:      TimeframeYear,
+	}
+	ids, err := drainIDs(client.SearchPosts(t.Context(), "generics", opts), func(p Post) string { return p.ID })
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, ids)
+	assert.Empty(t, opts.After, "the caller's options are not modified")
+	mockHTTP.AssertExpectations(t)
+}
+
+// drainIDs drains seq, returning the ID of each item or the first error
+func drainIDs[T any](seq iter.Seq2[T, error], id func(T) string) ([]string, error) {
+	var ids []string
+	for item, err := range seq {
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, id(item))
+	}
+	return ids, nil
+}
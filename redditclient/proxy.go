@@ -0,0 +1,234 @@
+package redditclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// validateProxyURL checks that u names a proxy orderedTransport can use
+func validateProxyURL(u *url.URL) error {
+	switch u.Scheme {
+	case "http", "socks5", "socks5h":
+	default:
+		return fmt.Errorf("unsupported proxy scheme %q, want http, socks5 or socks5h", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("proxy URL %q has no host", u.Redacted())
+	}
+	return nil
+}
+
+// isHTTPProxy reports whether proxy is an HTTP proxy, as opposed to SOCKS
+func isHTTPProxy(proxy *url.URL) bool {
+	return proxy != nil && proxy.Scheme == "http"
+}
+
+// proxyAuthorization returns the Proxy-Authorization value for proxy's
+// credentials, or "" if it has none
+func proxyAuthorization(proxy *url.URL) string {
+	if proxy.User == nil {
+		return ""
+	}
+	password, _ := proxy.User.Password()
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(proxy.User.Username()+":"+password))
+}
+
+// dialProxy connects to target, a host:port, through proxy. HTTP proxies
+// tunnel the connection with CONNECT when tunnel is set; otherwise the caller
+// speaks to the proxy directly.
+func (t *orderedTransport) dialProxy(ctx context.Context, proxy *url.URL, target string, tunnel bool) (net.Conn, error) {
+	proxyAddr := proxy.Host
+	if proxy.Port() == "" {
+		port := "1080"
+		if isHTTPProxy(proxy) {
+			port = "80"
+		}
+		proxyAddr = net.JoinHostPort(proxy.Hostname(), port)
+	}
+
+	conn, err := t.dialer.DialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach proxy %s: %w", proxy.Redacted(), err)
+	}
+
+	// Bound the handshake by ctx, as neither protocol has its own timeout
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	switch {
+	case !isHTTPProxy(proxy):
+		err = socks5Connect(conn, proxy, target)
+	case tunnel:
+		err = httpConnect(conn, proxy, target)
+	}
+	if err != nil {
+		conn.Close()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, fmt.Errorf("proxy %s: %w", proxy.Redacted(), err)
+	}
+	return conn, nil
+}
+
+// httpConnect opens a tunnel to target through an HTTP proxy
+func httpConnect(conn net.Conn, proxy *url.URL, target string) error {
+	req := "CONNECT " + target + " HTTP/1.1\r\nHost: " + target + "\r\n"
+	if auth := proxyAuthorization(proxy); auth != "" {
+		req += "Proxy-Authorization: " + auth + "\r\n"
+	}
+	if _, err := io.WriteString(conn, req+"\r\n"); err != nil {
+		return err
+	}
+
+	// Read byte by byte so nothing sent after the response is lost to a buffer
+	resp, err := http.ReadResponse(bufio.NewReader(byteReader{conn}), &http.Request{Method: http.MethodConnect})
+	if err != nil {
+		return fmt.Errorf("failed to read CONNECT response: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("CONNECT %s refused: %s", target, resp.Status)
+	}
+	return nil
+}
+
+// byteReader reads at most one byte per call
+type byteReader struct {
+	r io.Reader
+}
+
+func (b byteReader) Read(p []byte) (int, error) {
+	if len(p) > 1 {
+		p = p[:1]
+	}
+	return b.r.Read(p)
+}
+
+// SOCKS5 protocol constants from RFC 1928 and RFC 1929
+const (
+	socks5Version      = 0x05
+	socks5NoAuth       = 0x00
+	socks5PasswordAuth = 0x02
+	socks5NoAcceptable = 0xff
+	socks5CmdConnect   = 0x01
+	socks5DomainName   = 0x03
+	socks5Succeeded    = 0x00
+)
+
+var errSOCKS5Reply = errors.New("malformed SOCKS5 reply")
+
+// socks5Connect asks a SOCKS5 proxy to connect to target. The host name is
+// always sent for the proxy to resolve, so socks5 and socks5h behave alike.
+func socks5Connect(conn net.Conn, proxy *url.URL, target string) error {
+	host, portStr, err := net.SplitHostPort(target)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return fmt.Errorf("invalid port in %q", target)
+	}
+	if len(host) > 255 {
+		return fmt.Errorf("host name too long for SOCKS5: %q", host)
+	}
+
+	methods := []byte{socks5NoAuth}
+	if proxy.User != nil {
+		methods = []byte{socks5PasswordAuth}
+	}
+	greeting := append([]byte{socks5Version, byte(len(methods))}, methods...)
+	if _, err := conn.Write(greeting); err != nil {
+		return err
+	}
+
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return err
+	}
+	if reply[0] != socks5Version {
+		return errSOCKS5Reply
+	}
+	switch reply[1] {
+	case socks5NoAuth:
+	case socks5PasswordAuth:
+		if err := socks5Authenticate(conn, proxy.User); err != nil {
+			return err
+		}
+	case socks5NoAcceptable:
+		return errors.New("SOCKS5 proxy accepted none of the offered authentication methods")
+	default:
+		return errSOCKS5Reply
+	}
+
+	req := []byte{socks5Version, socks5CmdConnect, 0x00, socks5DomainName, byte(len(host))}
+	req = append(req, host...)
+	req = append(req, byte(port>>8), byte(port))
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	// VER REP RSV ATYP, then the bound address, which is of no use here
+	head := make([]byte, 4)
+	if _, err := io.ReadFull(conn, head); err != nil {
+		return err
+	}
+	if head[0] != socks5Version {
+		return errSOCKS5Reply
+	}
+	if head[1] != socks5Succeeded {
+		return fmt.Errorf("SOCKS5 connect to %s failed with code %d", target, head[1])
+	}
+
+	var addrLen int
+	switch head[3] {
+	case 0x01:
+		addrLen = net.IPv4len
+	case 0x04:
+		addrLen = net.IPv6len
+	case socks5DomainName:
+		n := make([]byte, 1)
+		if _, err := io.ReadFull(conn, n); err != nil {
+			return err
+		}
+		addrLen = int(n[0])
+	default:
+		return errSOCKS5Reply
+	}
+	_, err = io.ReadFull(conn, make([]byte, addrLen+2))
+	return err
+}
+
+// socks5Authenticate runs the username/password subnegotiation of RFC 1929
+func socks5Authenticate(conn net.Conn, user *url.Userinfo) error {
+	username := user.Username()
+	password, _ := user.Password()
+	if len(username) > 255 || len(password) > 255 {
+		return errors.New("SOCKS5 username or password too long")
+	}
+
+	req := []byte{0x01, byte(len(username))}
+	req = append(req, username...)
+	req = append(req, byte(len(password)))
+	req = append(req, password...)
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return err
+	}
+	if reply[1] != 0x00 {
+		return errors.New("SOCKS5 proxy rejected the username or password")
+	}
+	return nil
+}
@@ -0,0 +1,365 @@
+package redditclient
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testProxy records what passed through a proxy started by a test
+type testProxy struct {
+	URL *url.URL
+
+	mu      sync.Mutex
+	targets []string // "METHOD host" for HTTP proxies, "CONNECT host:port" for SOCKS5
+	auth    []string // Credentials presented with each request
+}
+
+func (p *testProxy) record(target, auth string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.targets = append(p.targets, target)
+	p.auth = append(p.auth, auth)
+}
+
+func (p *testProxy) Targets() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.targets...)
+}
+
+func (p *testProxy) Auth() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.auth...)
+}
+
+// pipe copies between a and b until either side closes
+func pipe(a, b net.Conn) {
+	done := make(chan struct{}, 2)
+	go func() { io.Copy(a, b); done <- struct{}{} }()
+	go func() { io.Copy(b, a); done <- struct{}{} }()
+	<-done
+	a.Close()
+	b.Close()
+}
+
+// newHTTPProxy starts an HTTP proxy that tunnels CONNECT requests and
+// forwards absolute-form ones
+func newHTTPProxy(t *testing.T) *testProxy {
+	t.Helper()
+
+	proxy := &testProxy{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxy.record(r.Method+" "+r.Host, r.Header.Get("Proxy-Authorization"))
+
+		if r.Method == http.MethodConnect {
+			upstream, err := net.Dial("tcp", r.Host)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			conn, _, err := http.NewResponseController(w).Hijack()
+			if err != nil {
+				upstream.Close()
+				return
+			}
+			io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+			pipe(conn, upstream)
+			return
+		}
+
+		r.RequestURI = ""
+		r.Header.Del("Proxy-Authorization")
+		resp, err := http.DefaultTransport.RoundTrip(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		for k, v := range resp.Header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(resp.StatusCode)
+		io.Copy(w, resp.Body)
+	}))
+	t.Cleanup(srv.Close)
+
+	proxy.URL, _ = url.Parse(srv.URL)
+	return proxy
+}
+
+// newSOCKS5Proxy starts a SOCKS5 proxy that requires user and password when
+// user is not empty
+func newSOCKS5Proxy(t *testing.T, user, password string) *testProxy {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	proxy := &testProxy{URL: &url.URL{Scheme: "socks5", Host: ln.Addr().String()}}
+	if user != "" {
+		proxy.URL.User = url.UserPassword(user, password)
+	}
+
+	handle := func(conn net.Conn) {
+		defer conn.Close()
+
+		head := make([]byte, 2)
+		if _, err := io.ReadFull(conn, head); err != nil {
+			return
+		}
+		methods := make([]byte, head[1])
+		if _, err := io.ReadFull(conn, methods); err != nil {
+			return
+		}
+
+		auth := ""
+		if user == "" {
+			conn.Write([]byte{5, 0})
+		} else {
+			conn.Write([]byte{5, 2})
+			lens := make([]byte, 2)
+			if _, err := io.ReadFull(conn, lens); err != nil {
+				return
+			}
+			u := make([]byte, lens[1])
+			io.ReadFull(conn, u)
+			plen := make([]byte, 1)
+			io.ReadFull(conn, plen)
+			p := make([]byte, plen[0])
+			io.ReadFull(conn, p)
+			if string(u) != user || string(p) != password {
+				conn.Write([]byte{1, 1})
+				return
+			}
+			conn.Write([]byte{1, 0})
+			auth = string(u) + ":" + string(p)
+		}
+
+		req := make([]byte, 5)
+		if _, err := io.ReadFull(conn, req); err != nil || req[3] != 3 {
+			return
+		}
+		rest := make([]byte, int(req[4])+2)
+		if _, err := io.ReadFull(conn, rest); err != nil {
+			return
+		}
+		host := string(rest[:req[4]])
+		port := binary.BigEndian.Uint16(rest[req[4]:])
+		target := net.JoinHostPort(host, strconv.Itoa(int(port)))
+		proxy.record("CONNECT "+target, auth)
+
+		upstream, err := net.Dial("tcp", target)
+		if err != nil {
+			conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+			return
+		}
+		conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+		pipe(conn, upstream)
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go handle(conn)
+		}
+	}()
+
+	return proxy
+}
+
+// newFakeRedditTLSServer serves srv's routes over TLS and returns its URL and
+// a TLS config that trusts it
+func newFakeRedditTLSServer(t *testing.T, srv *fakeRedditServer) (string, *tls.Config) {
+	t.Helper()
+
+	tlsSrv := httptest.NewTLSServer(srv.Config.Handler)
+	t.Cleanup(tlsSrv.Close)
+
+	return tlsSrv.URL, tlsSrv.Client().Transport.(*http.Transport).TLSClientConfig
+}
+
+// exerciseProxiedClient authenticates client and makes one API request
+func exerciseProxiedClient(t *testing.T, client *Client) {
+	t.Helper()
+
+	require.NoError(t, client.Authenticate(t.Context()))
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+	require.NoError(t, err)
+}
+
+func TestWithProxy_HTTP(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	proxy := newHTTPProxy(t)
+	proxyURL := *proxy.URL
+	proxyURL.User = url.UserPassword("scraper", "hunter2")
+
+	client, err := NewClient(WithBaseURL(srv.URL), WithProxy(proxyURL.String()))
+	require.NoError(t, err)
+	exerciseProxiedClient(t, client)
+
+	host := srv.Listener.Addr().String()
+	assert.Equal(t, []string{"POST " + host, "GET " + host}, proxy.Targets())
+	wantAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte("scraper:hunter2"))
+	assert.Equal(t, []string{wantAuth, wantAuth}, proxy.Auth())
+	assert.Len(t, srv.Requests(), 2)
+}
+
+func TestWithProxy_HTTPSTunnel(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	tlsURL, tlsConfig := newFakeRedditTLSServer(t, srv)
+	proxy := newHTTPProxy(t)
+
+	client, err := NewClient(WithBaseURL(tlsURL), WithProxy(proxy.URL.String()))
+	require.NoError(t, err)
+	client.httpClient.(*http.Client).Transport.(*orderedTransport).tlsConfig = tlsConfig
+	exerciseProxiedClient(t, client)
+
+	// Both requests share one kept-alive tunnel
+	u, _ := url.Parse(tlsURL)
+	assert.Equal(t, []string{"CONNECT " + u.Host}, proxy.Targets())
+	assert.Len(t, srv.Requests(), 2)
+}
+
+func TestWithProxy_SOCKS5(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	proxy := newSOCKS5Proxy(t, "scraper", "hunter2")
+
+	client, err := NewClient(WithBaseURL(srv.URL), WithProxy(proxy.URL.String()))
+	require.NoError(t, err)
+	exerciseProxiedClient(t, client)
+
+	assert.Equal(t, []string{"CONNECT " + srv.Listener.Addr().String()}, proxy.Targets())
+	assert.Equal(t, []string{"scraper:hunter2"}, proxy.Auth())
+	assert.Len(t, srv.Requests(), 2)
+}
+
+func TestWithProxy_SOCKS5RejectsCredentials(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	proxy := newSOCKS5Proxy(t, "scraper", "hunter2")
+	proxy.URL.User = url.UserPassword("scraper", "wrong")
+
+	client, err := NewClient(WithBaseURL(srv.URL), WithProxy(proxy.URL.String()))
+	require.NoError(t, err)
+
+	err = client.Authenticate(t.Context())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rejected the username or password")
+	assert.NotContains(t, err.Error(), "wrong", "proxy password must not appear in errors")
+	assert.Empty(t, srv.Requests())
+}
+
+func TestWithProxyFunc_Rotates(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	proxies := []*testProxy{newHTTPProxy(t), newSOCKS5Proxy(t, "", "")}
+
+	var next atomic.Int64
+	client, err := NewClient(WithBaseURL(srv.URL), WithProxyFunc(func(*http.Request) (*url.URL, error) {
+		return proxies[next.Add(1)%int64(len(proxies))].URL, nil
+	}))
+	require.NoError(t, err)
+
+	require.NoError(t, client.Authenticate(t.Context()))
+	for range 3 {
+		_, err = client.GetSubreddit(t.Context(), "golang", "hot")
+		require.NoError(t, err)
+	}
+
+	assert.Len(t, proxies[0].Targets(), 2, "HTTP proxy sees every request it carries")
+	assert.Len(t, proxies[1].Targets(), 1, "SOCKS5 proxy carries its requests over one connection")
+	assert.Len(t, srv.Requests(), 4)
+}
+
+func TestWithProxy_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"unsupported scheme", []Option{WithProxy("ftp://proxy.example:21")}},
+		{"missing host", []Option{WithProxy("http://")}},
+		{"nil func", []Option{WithProxyFunc(nil)}},
+		{"custom HTTP client", []Option{WithProxy("http://proxy.example:8080"), WithHTTPClient(&MockHTTPClient{})}},
+		{"custom HTTP client first", []Option{WithHTTPClient(&http.Client{}), WithProxy("socks5://proxy.example:1080")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(tt.opts...)
+			assert.Error(t, err)
+			assert.Nil(t, client)
+		})
+	}
+}
+
+func TestDefaultClient_UsesEnvironmentProxy(t *testing.T) {
+	// http.ProxyFromEnvironment reads the environment once per process, so
+	// the check runs in a child process that sets it first
+	if os.Getenv("GRAPEDDIT_ENV_PROXY_CHILD") == "" {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestDefaultClient_UsesEnvironmentProxy$", "-test.count=1")
+		cmd.Env = append(os.Environ(), "GRAPEDDIT_ENV_PROXY_CHILD=1")
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return
+	}
+
+	var seen []string
+	var mu sync.Mutex
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.Method+" "+r.RequestURI)
+		mu.Unlock()
+		io.WriteString(w, emptyListing)
+	}))
+	t.Cleanup(proxy.Close)
+
+	for _, name := range []string{"HTTP_PROXY", "http_proxy", "NO_PROXY", "no_proxy", "REQUEST_METHOD"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("HTTP_PROXY", proxy.URL)
+
+	client, err := NewClient(WithBaseURL("http://reddit.invalid"))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+
+	_, err = client.GetSubredditListing(t.Context(), "golang", SortNew, nil)
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"GET http://reddit.invalid/r/golang/new.json?raw_json=1"}, seen)
+}
+
+func TestWithProxy_OverridesEnvironment(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	proxy := newHTTPProxy(t)
+
+	client, err := NewClient(WithBaseURL(srv.URL), WithProxy(proxy.URL.String()))
+	require.NoError(t, err)
+	transport := client.httpClient.(*http.Client).Transport.(*orderedTransport)
+
+	// The explicit proxy is used even for a request the environment would not proxy
+	req := httptest.NewRequest(http.MethodGet, srv.URL, nil)
+	got, err := transport.proxy(req)
+	require.NoError(t, err)
+	assert.Equal(t, proxy.URL.String(), got.String())
+}
@@ -0,0 +1,88 @@
+package redditclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxRandomRedirects bounds the redirects GetRandomPost follows. r/random
+// takes two: one to a random subreddit and one to a post in it.
+const maxRandomRedirects = 5
+
+// GetRandomPost fetches a random post from subreddit along with its comments.
+// The pseudo-subreddits "random" and "randnsfw" pick the subreddit at random
+// too. Reddit answers with a redirect to the chosen thread, which is followed
+// whether or not the HTTPClient follows redirects itself. The random pick is
+// never cached; the thread it leads to is, like any other.
+func (c *Client) GetRandomPost(ctx context.Context, subreddit string) (*Post, *CommentListing, error) {
+	if !c.isAuthenticated() {
+		return nil, nil, ErrNotAuthenticated
+	}
+	if subreddit == "" || strings.ContainsAny(subreddit, "/?#") {
+		return nil, nil, fmt.Errorf("%w: subreddit name %q", ErrInvalidArgument, subreddit)
+	}
+
+	thread, err := c.getRandomThread(ctx, fmt.Sprintf("/r/%s/random.json", url.PathEscape(subreddit)))
+	if err != nil {
+		return nil, nil, err
+	}
+	post, err := thread.Post()
+	if err != nil {
+		return nil, nil, fmt.Errorf("random post in r/%s: %w", subreddit, err)
+	}
+
+	return post, &thread.CommentListing, nil
+}
+
+// getRandomThread requests a random endpoint and follows its redirects to a
+// thread. An HTTPClient that follows redirects returns the thread itself; one
+// that doesn't hands back each 3xx, which is followed here: to the thread if
+// it names a post, or to the random endpoint of the subreddit it names.
+func (c *Client) getRandomThread(ctx context.Context, endpoint string) (thread *PostAndCommentsResponse, err error) {
+	ctx, call := c.startCall(ctx, http.MethodGet, endpoint, nil)
+	defer func() { call.end(c, err) }()
+
+	ctx, cancel := callOptionsFrom(ctx).withTimeout(ctx)
+	defer cancel()
+
+	for range maxRandomRedirects {
+		_, resp, body, err := c.sendAPIRequest(ctx, endpoint, nil, requestOptions{redirect: true})
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			if err := jsonErrors(endpoint, body); err != nil {
+				return nil, c.withErrorBody(err, body)
+			}
+			var thread PostAndCommentsResponse
+			if err := c.decodeJSON(ctx, body, &thread, "random post"); err != nil {
+				return nil, err
+			}
+			return &thread, nil
+		}
+
+		location, err := resp.Location()
+		if err != nil {
+			return nil, fmt.Errorf("redirect from %s has no location: %w", endpoint, err)
+		}
+		if ref, ok := parsePostPath(location.Path); ok {
+			thread := fmt.Sprintf("/comments/%s.json", url.PathEscape(strings.TrimSuffix(ref.PostID, ".json")))
+			if ref.Subreddit != "" {
+				thread = "/r/" + url.PathEscape(ref.Subreddit) + thread
+			}
+			return c.getCommentThread(ctx, thread, nil)
+		}
+
+		segments := pathSegments(location.Path)
+		if len(segments) < 2 || segments[0] != "r" {
+			return nil, fmt.Errorf("%s redirected to %s, which is neither a post nor a subreddit", endpoint, location)
+		}
+		endpoint = fmt.Sprintf("/r/%s/random.json", url.PathEscape(segments[1]))
+	}
+
+	return nil, fmt.Errorf("too many redirects fetching a random post from %s", endpoint)
+}
@@ -0,0 +1,143 @@
+package redditclient
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const randomThread = `[
+	{"kind": "Listing", "data": {"children": [{"kind": "t3", "data": {"id": "abc123", "subreddit": "golang", "title": "Surprise"}}]}},
+	{"kind": "Listing", "data": {"children": [{"kind": "t1", "data": {"id": "c1", "body": "first", "replies": ""}}]}}
+]`
+
+// newNoRedirectFakeServerClient returns a client authenticated against srv
+// whose HTTPClient hands redirects back instead of following them
+func newNoRedirectFakeServerClient(t *testing.T, srv *fakeRedditServer) *Client {
+	t.Helper()
+
+	client, err := NewClient(WithBaseURL(srv.URL), WithHTTPClient(&http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}))
+	require.NoError(t, err)
+	require.NoError(t, client.Authenticate(t.Context()))
+	return client
+}
+
+// requestPaths returns the paths of the API requests srv received, skipping
+// authentication
+func requestPaths(srv *fakeRedditServer) []string {
+	var paths []string
+	for _, req := range srv.Requests() {
+		if req.Method == http.MethodGet {
+			paths = append(paths, req.Path)
+		}
+	}
+	return paths
+}
+
+func TestGetRandomPost_FollowedByHTTPClient(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.RespondWith("/r/golang/random.json", fakeResponse{
+		Status: http.StatusFound,
+		Header: map[string]string{"Location": "/r/golang/comments/abc123/surprise/.json"},
+	})
+	srv.Respond("/r/golang/comments/abc123/surprise/.json", http.StatusOK, randomThread)
+
+	post, comments, err := client.GetRandomPost(t.Context(), "golang")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", post.ID)
+	assert.Equal(t, "Surprise", post.Title)
+	require.Len(t, comments.Data.Children, 1)
+	assert.Equal(t, "first", comments.Data.Children[0].Comment.Body)
+
+	assert.Equal(t, []string{"/r/golang/random.json", "/r/golang/comments/abc123/surprise/.json"}, requestPaths(srv))
+}
+
+func TestGetRandomPost_RedirectsDisabled(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	client := newNoRedirectFakeServerClient(t, srv)
+	srv.RespondWith("/r/golang/random.json", fakeResponse{
+		Status: http.StatusFound,
+		Header: map[string]string{"Location": "/r/golang/comments/abc123/surprise/.json"},
+	})
+	srv.Respond("/r/golang/comments/abc123.json", http.StatusOK, randomThread)
+
+	post, comments, err := client.GetRandomPost(t.Context(), "golang")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", post.ID)
+	require.Len(t, comments.Data.Children, 1)
+
+	assert.Equal(t, []string{"/r/golang/random.json", "/r/golang/comments/abc123.json"}, requestPaths(srv))
+}
+
+func TestGetRandomPost_RandomSubreddit(t *testing.T) {
+	for _, subreddit := range []string{"random", "randnsfw"} {
+		t.Run(subreddit, func(t *testing.T) {
+			srv := newFakeRedditServer(t)
+			client := newNoRedirectFakeServerClient(t, srv)
+
+			// Reddit first picks a subreddit, then a post in it
+			srv.RespondWith("/r/"+subreddit+"/random.json", fakeResponse{
+				Status: http.StatusFound,
+				Header: map[string]string{"Location": "https://oauth.reddit.com/r/golang/"},
+			})
+			srv.RespondWith("/r/golang/random.json", fakeResponse{
+				Status: http.StatusFound,
+				Header: map[string]string{"Location": "/r/golang/comments/abc123/surprise/"},
+			})
+			srv.Respond("/r/golang/comments/abc123.json", http.StatusOK, randomThread)
+
+			post, _, err := client.GetRandomPost(t.Context(), subreddit)
+			require.NoError(t, err)
+			assert.Equal(t, "golang", post.Subreddit)
+
+			assert.Equal(t, []string{
+				"/r/" + subreddit + "/random.json",
+				"/r/golang/random.json",
+				"/r/golang/comments/abc123.json",
+			}, requestPaths(srv))
+		})
+	}
+}
+
+func TestGetRandomPost_RedirectLoop(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	client := newNoRedirectFakeServerClient(t, srv)
+	srv.RespondWith("/r/golang/random.json", fakeResponse{
+		Status: http.StatusFound,
+		Header: map[string]string{"Location": "/r/golang/"},
+	})
+
+	_, _, err := client.GetRandomPost(t.Context(), "golang")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "too many redirects")
+	assert.Len(t, requestPaths(srv), maxRandomRedirects)
+}
+
+func TestGetRandomPost_NotCached(t *testing.T) {
+	client, mockHTTP, _ := newCachingTestClient(t, time.Minute)
+	mockHTTP.On("Do", mock.Anything).Return(func(*http.Request) *http.Response {
+		return createHTTPResponse(http.StatusOK, randomThread, nil)
+	}, nil)
+
+	for range 2 {
+		_, _, err := client.GetRandomPost(t.Context(), "golang")
+		require.NoError(t, err)
+	}
+	mockHTTP.AssertNumberOfCalls(t, "Do", 2)
+}
+
+func TestGetRandomPost_InvalidSubreddit(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	for _, subreddit := range []string{"", "golang/comments"} {
+		_, _, err := client.GetRandomPost(t.Context(), subreddit)
+		assert.ErrorIs(t, err, ErrInvalidArgument)
+	}
+	mockHTTP.AssertNotCalled(t, "Do", mock.Anything)
+}
@@ -0,0 +1,58 @@
+package redditclient
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+)
+
+// QoSGenerator returns the x-reddit-qos header value sent when authenticating
+// anonymously, drawing any randomness it needs from r; see WithQoSGenerator
+type QoSGenerator func(r *rand.Rand) string
+
+// UniformQoS is the default QoSGenerator: a value drawn uniformly from
+// [0, 100) and formatted to three decimals
+func UniformQoS(r *rand.Rand) string {
+	return fmt.Sprintf("%.3f", r.Float64()*100)
+}
+
+// newRand returns a Rand drawing from src, or from a ChaCha8 generator seeded
+// from crypto/rand when src is nil. The Rand is safe for concurrent use.
+func newRand(src rand.Source) *rand.Rand {
+	if src == nil {
+		var seed [32]byte
+		crand.Read(seed[:])
+		src = rand.NewChaCha8(seed)
+	}
+	return rand.New(&lockedSource{src: src})
+}
+
+// lockedSource serializes calls to a Source, as none of the math/rand/v2
+// sources is safe for concurrent use. A Rand keeps no state of its own, so
+// one over a lockedSource can be shared.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+// randReader reads bytes from a Rand, for generating a device ID with it
+type randReader struct {
+	r *rand.Rand
+}
+
+func (rr randReader) Read(p []byte) (int, error) {
+	var buf [8]byte
+	for i := 0; i < len(p); i += len(buf) {
+		binary.LittleEndian.PutUint64(buf[:], rr.r.Uint64())
+		copy(p[i:], buf[:])
+	}
+	return len(p), nil
+}
@@ -0,0 +1,102 @@
+package redditclient
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// authHeaders authenticates a new client made with opts against a fake server
+// and returns the headers of its authentication request
+func authHeaders(t *testing.T, opts ...Option) http.Header {
+	t.Helper()
+
+	srv := newFakeRedditServer(t)
+	client, err := NewClient(append(opts, WithBaseURL(srv.URL))...)
+	require.NoError(t, err)
+	require.NoError(t, client.Authenticate(t.Context()))
+
+	requests := srv.Requests()
+	require.NotEmpty(t, requests)
+	require.Equal(t, "/auth/v2/oauth/access-token/loid", requests[0].Path)
+	return requests[0].Header
+}
+
+func TestWithRandSource_Reproducible(t *testing.T) {
+	first := authHeaders(t, WithRandSource(rand.NewPCG(1, 2)))
+	second := authHeaders(t, WithRandSource(rand.NewPCG(1, 2)))
+
+	for _, name := range []string{"X-Reddit-Device-Id", "User-Agent", "x-reddit-qos"} {
+		assert.NotEmpty(t, first.Get(name), name)
+		assert.Equal(t, first.Get(name), second.Get(name), name)
+	}
+
+	other := authHeaders(t, WithRandSource(rand.NewPCG(3, 4)))
+	assert.NotEqual(t, first.Get("X-Reddit-Device-Id"), other.Get("X-Reddit-Device-Id"))
+	assert.NotEqual(t, first.Get("x-reddit-qos"), other.Get("x-reddit-qos"))
+}
+
+func TestWithRandSource_DefaultDiffersPerClient(t *testing.T) {
+	a, err := NewClient()
+	require.NoError(t, err)
+	b, err := NewClient()
+	require.NoError(t, err)
+
+	assert.NotEqual(t, a.deviceID, b.deviceID)
+	assert.Len(t, a.deviceID, 36)
+}
+
+func TestWithRandSource_PinnedDeviceID(t *testing.T) {
+	headers := authHeaders(t, WithRandSource(rand.NewPCG(1, 2)), WithDeviceID("device-1234"))
+	assert.Equal(t, "device-1234", headers.Get("X-Reddit-Device-Id"))
+}
+
+func TestWithQoSGenerator(t *testing.T) {
+	var calls int
+	headers := authHeaders(t, WithQoSGenerator(func(r *rand.Rand) string {
+		calls++
+		return "42.000"
+	}))
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "42.000", headers.Get("x-reddit-qos"))
+}
+
+func TestUniformQoS(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for range 1000 {
+		value := UniformQoS(r)
+		assert.Regexp(t, `^\d{1,2}\.\d{3}$`, value)
+
+		qos, err := strconv.ParseFloat(value, 64)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, qos, 0.0)
+		assert.Less(t, qos, 100.0)
+	}
+}
+
+func TestRandOptions_RejectNil(t *testing.T) {
+	_, err := NewClient(WithRandSource(nil))
+	assert.Error(t, err)
+	_, err = NewClient(WithQoSGenerator(nil))
+	assert.Error(t, err)
+}
+
+func TestRetryPolicy_BackoffReproducible(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	delays := func() []time.Duration {
+		r := newRand(rand.NewPCG(1, 2))
+		var got []time.Duration
+		for attempt := 1; attempt < policy.MaxAttempts; attempt++ {
+			got = append(got, policy.backoff(attempt, r))
+		}
+		return got
+	}
+	assert.Equal(t, delays(), delays())
+}
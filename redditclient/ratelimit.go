@@ -0,0 +1,131 @@
+package redditclient
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitStatus returns the rate limit state reported by the most recent API response
+func (c *Client) RateLimitStatus() RateLimitStatus {
+	c.rateLimitLock.RLock()
+	defer c.rateLimitLock.RUnlock()
+
+	status := RateLimitStatus{
+		Remaining: c.rateLimit,
+		Used:      c.rateLimitUsed,
+	}
+	if !c.rateLimitReset.IsZero() {
+		status.ResetIn = max(c.rateLimitReset.Sub(c.now()), 0)
+	}
+
+	return status
+}
+
+// SetRateLimitBehavior selects what happens when the remaining quota drops below the floor
+func (c *Client) SetRateLimitBehavior(behavior RateLimitBehavior) {
+	c.rateLimitLock.Lock()
+	defer c.rateLimitLock.Unlock()
+
+	c.rateLimitMode = behavior
+}
+
+// SetRateLimitFloor sets the remaining quota below which WaitOnLimit blocks requests
+func (c *Client) SetRateLimitFloor(floor float64) {
+	c.rateLimitLock.Lock()
+	defer c.rateLimitLock.Unlock()
+
+	c.rateLimitFloor = floor
+}
+
+// waitForRateLimit blocks until the rate limit window resets when WaitOnLimit is
+// enabled and the quota is exhausted. The lock is released before sleeping.
+func (c *Client) waitForRateLimit(ctx context.Context) error {
+	c.rateLimitLock.RLock()
+	exhausted := c.rateLimitMode == WaitOnLimit && c.rateLimit < c.rateLimitFloor
+	reset := c.rateLimitReset
+	c.rateLimitLock.RUnlock()
+
+	if !exhausted || reset.IsZero() {
+		return nil
+	}
+
+	wait := reset.Sub(c.now())
+	if wait <= 0 {
+		return nil
+	}
+
+	return c.sleep(ctx, wait)
+}
+
+// updateRateLimit records the x-ratelimit-* headers and, when the remaining quota
+// runs low, fetches a fresh token in the background since each token carries its own quota
+func (c *Client) updateRateLimit(ctx context.Context, header http.Header) {
+	remaining, hasRemaining := parseRateLimitHeader(header.Get("x-ratelimit-remaining"))
+	used, hasUsed := parseRateLimitHeader(header.Get("x-ratelimit-used"))
+	reset, hasReset := parseRateLimitHeader(header.Get("x-ratelimit-reset"))
+
+	if !hasRemaining && !hasUsed && !hasReset {
+		return
+	}
+
+	c.rateLimitLock.Lock()
+	if hasRemaining {
+		c.rateLimit = remaining
+	}
+	if hasUsed {
+		c.rateLimitUsed = used
+	}
+	if hasReset {
+		c.rateLimitReset = c.now().Add(time.Duration(reset * float64(time.Second)))
+	}
+	c.rateLimitLock.Unlock()
+
+	if hasRemaining && remaining < rateLimitRefreshThreshold {
+		c.refreshInBackground(ctx)
+	}
+}
+
+// refreshInBackground re-authenticates without blocking the caller; only one
+// background refresh runs at a time
+func (c *Client) refreshInBackground(ctx context.Context) {
+	if !c.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+
+	staleToken := c.credentials().accessToken
+	go func() {
+		defer c.refreshing.Store(false)
+
+		// Outlive the triggering request, but not forever
+		refreshCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), backgroundRefreshTimeout)
+		defer cancel()
+
+		c.logger.DebugContext(refreshCtx, "refreshing access token in background for a fresh rate limit")
+		if err := c.reauthenticate(refreshCtx, staleToken); err != nil {
+			c.logger.DebugContext(refreshCtx, "background token refresh failed", "error", err)
+		} else {
+			c.rateLimitLock.Lock()
+			c.rateLimit = 100
+			c.rateLimitUsed = 0
+			c.rateLimitLock.Unlock()
+		}
+	}()
+}
+
+// parseRateLimitHeader parses the float values Reddit sends, e.g. "96.0"
+func parseRateLimitHeader(value string) (float64, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return v, true
+}
@@ -0,0 +1,191 @@
+package redditclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateRateLimit_ParsesHeaders(t *testing.T) {
+	client, err := NewClient(WithHTTPClient(&MockHTTPClient{}))
+	require.NoError(t, err)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return now }
+
+	header := http.Header{}
+	header.Set("x-ratelimit-remaining", "96.0")
+	header.Set("x-ratelimit-used", "4")
+	header.Set("x-ratelimit-reset", "360")
+
+	client.updateRateLimit(t.Context(), header)
+
+	status := client.RateLimitStatus()
+	assert.Equal(t, 96.0, status.Remaining)
+	assert.Equal(t, 4.0, status.Used)
+	assert.Equal(t, 360*time.Second, status.ResetIn)
+
+	now = now.Add(400 * time.Second)
+	assert.Equal(t, time.Duration(0), client.RateLimitStatus().ResetIn)
+}
+
+func TestUpdateRateLimit_IgnoresMissingAndMalformed(t *testing.T) {
+	client, err := NewClient(WithHTTPClient(&MockHTTPClient{}))
+	require.NoError(t, err)
+
+	header := http.Header{}
+	header.Set("x-ratelimit-remaining", "not-a-number")
+
+	client.updateRateLimit(t.Context(), header)
+	client.updateRateLimit(t.Context(), http.Header{})
+
+	status := client.RateLimitStatus()
+	assert.Equal(t, 100.0, status.Remaining)
+	assert.Equal(t, time.Duration(0), status.ResetIn)
+}
+
+func TestMakeAPIRequest_RecordsRateLimit(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, `{"kind": "Listing", "data": {"children": []}}`, map[string]string{
+			"x-ratelimit-remaining": "42.0",
+			"x-ratelimit-used":      "58",
+			"x-ratelimit-reset":     "120",
+		}), nil)
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+	require.NoError(t, err)
+	assert.Equal(t, 42.0, client.RateLimitStatus().Remaining)
+	assert.Equal(t, 58.0, client.RateLimitStatus().Used)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestUpdateRateLimit_LowQuotaRefreshesToken(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(isAuthRequest)).
+		Return(createOAuthResponse("fresh-token", 3600), nil).Once()
+
+	header := http.Header{}
+	header.Set("x-ratelimit-remaining", "9.0")
+
+	client.updateRateLimit(t.Context(), header)
+
+	assert.Eventually(t, func() bool {
+		client.authLock.Lock()
+		defer client.authLock.Unlock()
+		return client.accessToken == "fresh-token"
+	}, time.Second, 5*time.Millisecond)
+	assert.Eventually(t, func() bool { return !client.refreshing.Load() }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, 100.0, client.RateLimitStatus().Remaining)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestUpdateRateLimit_HealthyQuotaDoesNotRefresh(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	header := http.Header{}
+	header.Set("x-ratelimit-remaining", "10.0")
+
+	client.updateRateLimit(t.Context(), header)
+
+	assert.False(t, client.refreshing.Load())
+	mockHTTP.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestWaitOnLimit_DelaysRequest(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	client.SetRateLimitBehavior(WaitOnLimit)
+
+	// An exhausted quota that resets shortly
+	client.rateLimitLock.Lock()
+	client.rateLimit = 0
+	client.rateLimitReset = time.Now().Add(100 * time.Millisecond)
+	client.rateLimitLock.Unlock()
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, `{"kind": "Listing", "data": {"children": []}}`, nil), nil).Once()
+
+	start := time.Now()
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestWaitOnLimit_InterruptedByContext(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	client.SetRateLimitBehavior(WaitOnLimit)
+
+	client.rateLimitLock.Lock()
+	client.rateLimit = 0
+	client.rateLimitReset = time.Now().Add(time.Hour)
+	client.rateLimitLock.Unlock()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetSubreddit(ctx, "golang", "hot")
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	mockHTTP.AssertNotCalled(t, "Do", mock.Anything)
+
+	// The lock must not be held while waiting
+	done := make(chan struct{})
+	go func() {
+		client.SetRateLimitFloor(5)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("rateLimitLock held after wait returned")
+	}
+}
+
+func TestIgnoreLimit_DoesNotWait(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	client.rateLimitLock.Lock()
+	client.rateLimit = 0
+	client.rateLimitReset = time.Now().Add(time.Hour)
+	client.rateLimitLock.Unlock()
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, `{"kind": "Listing", "data": {"children": []}}`, nil), nil).Once()
+
+	ctx, cancel := context.WithTimeout(t.Context(), time.Second)
+	defer cancel()
+
+	_, err := client.GetSubreddit(ctx, "golang", "hot")
+
+	require.NoError(t, err)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestWaitOnLimit_AboveFloorDoesNotWait(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	client.SetRateLimitBehavior(WaitOnLimit)
+	client.SetRateLimitFloor(5)
+
+	client.rateLimitLock.Lock()
+	client.rateLimit = 5
+	client.rateLimitReset = time.Now().Add(time.Hour)
+	client.rateLimitLock.Unlock()
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, `{"kind": "Listing", "data": {"children": []}}`, nil), nil).Once()
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+	require.NoError(t, err)
+	mockHTTP.AssertExpectations(t)
+}
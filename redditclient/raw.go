@@ -0,0 +1,66 @@
+package redditclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// GetJSON requests an API endpoint the client has no method for and decodes
+// the response into v. It is the supported way to reach the rest of Reddit's
+// API: the request gets the same authentication, rate limiting, retries,
+// caching, decompression and error mapping as the built-in methods.
+//
+// endpoint is a path on the API host, such as "/r/golang/about.json", with the
+// query passed separately in params. A URL with a scheme or host, a
+// protocol-relative "//host" path or a path with its own query is rejected with
+// ErrInvalidArgument, so requests cannot leave the API host by accident.
+func (c *Client) GetJSON(ctx context.Context, endpoint string, params url.Values, v any) error {
+	if !c.isAuthenticated() {
+		return ErrNotAuthenticated
+	}
+	if err := validateEndpoint(endpoint); err != nil {
+		return err
+	}
+
+	return c.streamAPIRequest(ctx, endpoint, params, v, endpoint)
+}
+
+// GetRaw is GetJSON for callers that decode the response themselves. The
+// returned body is already decompressed and belongs to the caller; it is a
+// copy of any cached or shared response, so modifying it is safe.
+func (c *Client) GetRaw(ctx context.Context, endpoint string, params url.Values) ([]byte, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+	if err := validateEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+
+	body, err := c.makeAPIRequest(ctx, endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Clone(body), nil
+}
+
+// validateEndpoint checks that endpoint is a bare path on the API host
+func validateEndpoint(endpoint string) error {
+	if !strings.HasPrefix(endpoint, "/") || strings.HasPrefix(endpoint, "//") || strings.Contains(endpoint, `\`) {
+		return fmt.Errorf("%w: endpoint %q must be a path starting with a single \"/\"", ErrInvalidArgument, endpoint)
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("%w: endpoint %q: %w", ErrInvalidArgument, endpoint, err)
+	}
+	if u.Scheme != "" || u.Host != "" || u.User != nil {
+		return fmt.Errorf("%w: endpoint %q must not name a host", ErrInvalidArgument, endpoint)
+	}
+	if u.RawQuery != "" || u.Fragment != "" || strings.ContainsAny(endpoint, "?#") {
+		return fmt.Errorf("%w: endpoint %q must not contain a query; pass params instead", ErrInvalidArgument, endpoint)
+	}
+	return nil
+}
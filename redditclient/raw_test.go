@@ -0,0 +1,112 @@
+package redditclient
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// communityRules is a caller-defined shape for an endpoint the client has no
+// type for
+type communityRules struct {
+	Kind string `json:"kind"`
+	Data struct {
+		DisplayName string `json:"display_name"`
+		Subscribers int    `json:"subscribers"`
+		Wiki        bool   `json:"wiki_enabled"`
+	} `json:"data"`
+}
+
+func TestGetJSON_DecodesIntoCallerType(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.Respond("/r/golang/about.json", http.StatusOK, `{"kind": "t5", "data": {"display_name": "golang", "subscribers": 250000, "wiki_enabled": true}}`)
+
+	var about communityRules
+	err := client.GetJSON(t.Context(), "/r/golang/about.json", url.Values{"sr_detail": {"1"}}, &about)
+
+	require.NoError(t, err)
+	assert.Equal(t, "t5", about.Kind)
+	assert.Equal(t, "golang", about.Data.DisplayName)
+	assert.Equal(t, 250000, about.Data.Subscribers)
+	assert.True(t, about.Data.Wiki)
+
+	req, ok := srv.LastRequest("/r/golang/about.json")
+	require.True(t, ok)
+	assert.Equal(t, "Bearer fake-token", req.Header.Get("Authorization"))
+	assert.Equal(t, "1", req.Query.Get("sr_detail"))
+	assert.Equal(t, "1", req.Query.Get("raw_json"))
+}
+
+func TestGetRaw_ReturnsBody(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.SetGzip(true)
+	srv.Respond("/r/golang/about.json", http.StatusOK, subredditAboutFixture)
+
+	body, err := client.GetRaw(t.Context(), "/r/golang/about.json", nil)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, subredditAboutFixture, string(body))
+}
+
+func TestGetRaw_ReturnsCopy(t *testing.T) {
+	client, mockHTTP, _ := newCachingTestClient(t, time.Hour)
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(200, emptyListing, nil), nil).Once()
+
+	first, err := client.GetRaw(t.Context(), "/r/golang/new.json", nil)
+	require.NoError(t, err)
+	for i := range first {
+		first[i] = 'x'
+	}
+
+	// The second call is served from the cache, which the first must not share
+	second, err := client.GetRaw(t.Context(), "/r/golang/new.json", nil)
+	require.NoError(t, err)
+	assert.Equal(t, emptyListing, string(second))
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGetJSON_MapsErrors(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.Respond("/r/doesnotexist/about.json", http.StatusNotFound, `{"message": "Not Found", "error": 404}`)
+
+	var v map[string]any
+	err := client.GetJSON(t.Context(), "/r/doesnotexist/about.json", nil, &v)
+
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestGetJSON_RejectsOffHostEndpoints(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+
+	for _, endpoint := range []string{
+		"",
+		"r/golang/about.json",
+		"https://evil.example/r/golang/about.json",
+		"//evil.example/r/golang/about.json",
+		`/\evil.example/`,
+		"/r/golang/about.json?limit=5",
+		"/r/golang/about.json#top",
+	} {
+		t.Run(endpoint, func(t *testing.T) {
+			var v map[string]any
+			assert.ErrorIs(t, client.GetJSON(t.Context(), endpoint, nil, &v), ErrInvalidArgument)
+
+			_, err := client.GetRaw(t.Context(), endpoint, nil)
+			assert.ErrorIs(t, err, ErrInvalidArgument)
+		})
+	}
+	assert.Len(t, srv.Requests(), 1, "only the authentication request should be sent")
+}
+
+func TestGetJSON_RequiresAuthentication(t *testing.T) {
+	client, err := NewClient()
+	require.NoError(t, err)
+
+	var v map[string]any
+	assert.ErrorIs(t, client.GetJSON(t.Context(), "/r/golang/about.json", nil, &v), ErrNotAuthenticated)
+}
@@ -0,0 +1,769 @@
+// Package redditclienttest provides a fake redditclient.RedditClient for
+// testing code that depends on the client without touching the network.
+package redditclienttest
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"slices"
+	"sync"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+)
+
+var _ redditclient.RedditClient = (*FakeClient)(nil)
+
+// Call records a single method invocation on a FakeClient. Args holds the
+// arguments after ctx, in order.
+type Call struct {
+	Method string
+	Args   []any
+}
+
+// FakeClient implements redditclient.RedditClient from canned data. Each
+// method calls its Fn field when set; otherwise it returns Err if set, or a
+// default built from the embedded fixtures. Methods that wrap another in the
+// real client share its Fn, e.g. GetSubreddit and GetSubredditPage both use
+// SubredditFn. All calls are recorded. A FakeClient is safe for concurrent
+// use, but Fn fields must not be changed while it is in use.
+type FakeClient struct {
+	// Err is returned by every method without an Fn
+	Err error
+
+	AuthenticateFn       func(ctx context.Context) error
+	AuthenticateScriptFn func(ctx context.Context, clientID, clientSecret, username, password string) error
+	VerifyFn             func(ctx context.Context) error
+	SubredditFn          func(ctx context.Context, subreddit string, sort redditclient.Sort, opts *redditclient.ListingOptions) (*redditclient.SubredditListing, error)
+	CombinedFn           func(ctx context.Context, subreddits []string, sort string, opts *redditclient.ListingOptions) (*redditclient.SubredditListing, error)
+	SubredditAboutFn     func(ctx context.Context, subreddit string) (*redditclient.SubredditAbout, error)
+	StickiesFn           func(ctx context.Context, subreddit string) ([]redditclient.Post, error)
+	FrontpageFn          func(ctx context.Context, sort string, opts *redditclient.ListingOptions) (*redditclient.SubredditListing, error)
+	PopularFn            func(ctx context.Context, sort string, opts *redditclient.ListingOptions) (*redditclient.SubredditListing, error)
+	AllFn                func(ctx context.Context, sort string, opts *redditclient.ListingOptions) (*redditclient.SubredditListing, error)
+	PostFn               func(ctx context.Context, subreddit, postID string) (*redditclient.Post, error)
+	RandomPostFn         func(ctx context.Context, subreddit string) (*redditclient.Post, *redditclient.CommentListing, error)
+	PostsByIDFn          func(ctx context.Context, fullnames []string) ([]redditclient.Post, error)
+	ResolveURLFn         func(ctx context.Context, rawURL string) (*redditclient.PostRef, error)
+	UserFn               func(ctx context.Context, username string) (*redditclient.UserResponse, error)
+	UserPostsFn          func(ctx context.Context, username, sort, timeframe string, opts *redditclient.ListingOptions) (*redditclient.PostListing, error)
+	UserCommentsFn       func(ctx context.Context, username, sort, timeframe string, opts *redditclient.ListingOptions) (*redditclient.CommentListing, error)
+	UserOverviewFn       func(ctx context.Context, username string, opts *redditclient.ListingOptions) (*redditclient.ThingListing, error)
+	UserTrophiesFn       func(ctx context.Context, username string) ([]redditclient.Trophy, error)
+	WikiPageFn           func(ctx context.Context, subreddit, page string) (*redditclient.WikiPage, error)
+	WikiPagesFn          func(ctx context.Context, subreddit string) ([]string, error)
+	MultiredditsFn       func(ctx context.Context, username string) ([]redditclient.Multireddit, error)
+	MultiListingFn       func(ctx context.Context, username, multiName, sort string, opts *redditclient.ListingOptions) (*redditclient.PostListing, error)
+	SubscriptionsFn      func(ctx context.Context, opts *redditclient.ListingOptions) (*redditclient.SubscriptionListing, error)
+	TrendingFn           func(ctx context.Context) (*redditclient.TrendingSubreddits, error)
+	SubredditsFn         func(ctx context.Context, where string, opts *redditclient.ListingOptions) (*redditclient.SubscriptionListing, error)
+	SavedFn              func(ctx context.Context, username string, opts *redditclient.ListingOptions) (*redditclient.ThingListing, error)
+	VoteFn               func(ctx context.Context, fullname string, dir int) error
+	SaveFn               func(ctx context.Context, fullname string, saved bool) error
+	InboxFn              func(ctx context.Context, unread bool, opts *redditclient.ListingOptions) (*redditclient.ThingListing, error)
+	MarkReadFn           func(ctx context.Context, fullnames []string) error
+	PostCommentFn        func(ctx context.Context, parentFullname, markdown string) (*redditclient.Comment, error)
+	SubmitPostFn         func(ctx context.Context, subreddit string, opts redditclient.SubmitOptions) (*redditclient.Post, error)
+	LinkFlairsFn         func(ctx context.Context, subreddit string) ([]redditclient.FlairTemplate, error)
+	SearchFn             func(ctx context.Context, query, sort, timeframe string) (*redditclient.SearchResponse, error)
+	SearchWithOptionsFn  func(ctx context.Context, query string, opts *redditclient.SearchOptions) (*redditclient.SearchResults, error)
+	SearchSubredditsFn   func(ctx context.Context, query string, opts *redditclient.SubredditSearchOptions) ([]redditclient.SubredditSuggestion, error)
+	CommentsFn           func(ctx context.Context, subreddit, postID string, opts *redditclient.CommentOptions) (*redditclient.PostAndCommentsResponse, error)
+	CommentFn            func(ctx context.Context, subreddit, postID, commentID string, contextDepth int) (*redditclient.PostAndCommentsResponse, error)
+	MoreCommentsFn       func(ctx context.Context, linkID string, children []string) (*redditclient.MoreChildrenResponse, error)
+	DuplicatesFn         func(ctx context.Context, postID string, opts *redditclient.DuplicatesOptions) (*redditclient.DuplicatesResponse, error)
+	AllCommentsFn        func(ctx context.Context, subreddit, postID string, opts *redditclient.CommentOptions) (*redditclient.CommentListing, error)
+	RawFn                func(ctx context.Context, endpoint string, params url.Values) ([]byte, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// NewFakeClient returns a FakeClient serving the default fixtures
+func NewFakeClient() *FakeClient {
+	return &FakeClient{}
+}
+
+// Calls returns every recorded call in order
+func (f *FakeClient) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.calls)
+}
+
+// CallsTo returns the recorded calls to method, e.g. "GetSubreddit"
+func (f *FakeClient) CallsTo(method string) []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var calls []Call
+	for _, call := range f.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset clears the recorded calls
+func (f *FakeClient) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = nil
+}
+
+func (f *FakeClient) record(method string, args ...any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, Call{Method: method, Args: args})
+}
+
+// Authenticate records the call and succeeds unless Err is set
+func (f *FakeClient) Authenticate(ctx context.Context) error {
+	f.record("Authenticate")
+	if f.AuthenticateFn != nil {
+		return f.AuthenticateFn(ctx)
+	}
+	return f.Err
+}
+
+// AuthenticateScript records the call and succeeds unless Err is set. The
+// password is not recorded.
+func (f *FakeClient) AuthenticateScript(ctx context.Context, clientID, clientSecret, username, password string) error {
+	f.record("AuthenticateScript", clientID, username)
+	if f.AuthenticateScriptFn != nil {
+		return f.AuthenticateScriptFn(ctx, clientID, clientSecret, username, password)
+	}
+	return f.Err
+}
+
+// Verify records the call and succeeds unless Err is set
+func (f *FakeClient) Verify(ctx context.Context) error {
+	f.record("Verify")
+	if f.VerifyFn != nil {
+		return f.VerifyFn(ctx)
+	}
+	return f.Err
+}
+
+// GetSubreddit delegates to SubredditFn
+func (f *FakeClient) GetSubreddit(ctx context.Context, subreddit, sort string) (*redditclient.SubredditListing, error) {
+	f.record("GetSubreddit", subreddit, sort)
+	return f.subreddit(ctx, subreddit, redditclient.Sort(sort), nil)
+}
+
+// GetSubredditPage delegates to SubredditFn
+func (f *FakeClient) GetSubredditPage(ctx context.Context, subreddit, sort string, opts *redditclient.ListingOptions) (*redditclient.SubredditListing, error) {
+	f.record("GetSubredditPage", subreddit, sort, opts)
+	return f.subreddit(ctx, subreddit, redditclient.Sort(sort), opts)
+}
+
+// GetSubredditListing delegates to SubredditFn
+func (f *FakeClient) GetSubredditListing(ctx context.Context, subreddit string, sort redditclient.Sort, opts *redditclient.ListingOptions) (*redditclient.SubredditListing, error) {
+	f.record("GetSubredditListing", subreddit, sort, opts)
+	return f.subreddit(ctx, subreddit, sort, opts)
+}
+
+// GetSubredditTop delegates to SubredditFn with SortTop
+func (f *FakeClient) GetSubredditTop(ctx context.Context, subreddit string, tf redditclient.Timeframe, opts *redditclient.ListingOptions) (*redditclient.SubredditListing, error) {
+	f.record("GetSubredditTop", subreddit, tf, opts)
+	return f.subreddit(ctx, subreddit, redditclient.SortTop, opts)
+}
+
+// GetSubredditControversial delegates to SubredditFn with SortControversial
+func (f *FakeClient) GetSubredditControversial(ctx context.Context, subreddit string, tf redditclient.Timeframe, opts *redditclient.ListingOptions) (*redditclient.SubredditListing, error) {
+	f.record("GetSubredditControversial", subreddit, tf, opts)
+	return f.subreddit(ctx, subreddit, redditclient.SortControversial, opts)
+}
+
+// GetCombinedSubreddits returns the subreddit listing fixture by default
+func (f *FakeClient) GetCombinedSubreddits(ctx context.Context, subreddits []string, sort string, opts *redditclient.ListingOptions) (*redditclient.SubredditListing, error) {
+	f.record("GetCombinedSubreddits", subreddits, sort, opts)
+	if f.CombinedFn != nil {
+		return f.CombinedFn(ctx, subreddits, sort, opts)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return SubredditListing(), nil
+}
+
+func (f *FakeClient) subreddit(ctx context.Context, subreddit string, sort redditclient.Sort, opts *redditclient.ListingOptions) (*redditclient.SubredditListing, error) {
+	if f.SubredditFn != nil {
+		return f.SubredditFn(ctx, subreddit, sort, opts)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return SubredditListing(), nil
+}
+
+// GetSubredditAbout returns minimal metadata named after subreddit by default
+func (f *FakeClient) GetSubredditAbout(ctx context.Context, subreddit string) (*redditclient.SubredditAbout, error) {
+	f.record("GetSubredditAbout", subreddit)
+	if f.SubredditAboutFn != nil {
+		return f.SubredditAboutFn(ctx, subreddit)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return &redditclient.SubredditAbout{
+		DisplayName:         subreddit,
+		DisplayNamePrefixed: "r/" + subreddit,
+		URL:                 "/r/" + subreddit + "/",
+		SubredditType:       "public",
+	}, nil
+}
+
+// GetStickies returns no stickies by default
+func (f *FakeClient) GetStickies(ctx context.Context, subreddit string) ([]redditclient.Post, error) {
+	f.record("GetStickies", subreddit)
+	if f.StickiesFn != nil {
+		return f.StickiesFn(ctx, subreddit)
+	}
+	return nil, f.Err
+}
+
+// GetFrontpage returns the subreddit listing fixture by default
+func (f *FakeClient) GetFrontpage(ctx context.Context, sort string, opts *redditclient.ListingOptions) (*redditclient.SubredditListing, error) {
+	f.record("GetFrontpage", sort, opts)
+	return f.listing(ctx, f.FrontpageFn, sort, opts)
+}
+
+// GetPopular returns the subreddit listing fixture by default
+func (f *FakeClient) GetPopular(ctx context.Context, sort string, opts *redditclient.ListingOptions) (*redditclient.SubredditListing, error) {
+	f.record("GetPopular", sort, opts)
+	return f.listing(ctx, f.PopularFn, sort, opts)
+}
+
+// GetAll returns the subreddit listing fixture by default
+func (f *FakeClient) GetAll(ctx context.Context, sort string, opts *redditclient.ListingOptions) (*redditclient.SubredditListing, error) {
+	f.record("GetAll", sort, opts)
+	return f.listing(ctx, f.AllFn, sort, opts)
+}
+
+func (f *FakeClient) listing(ctx context.Context, fn func(context.Context, string, *redditclient.ListingOptions) (*redditclient.SubredditListing, error), sort string, opts *redditclient.ListingOptions) (*redditclient.SubredditListing, error) {
+	if fn != nil {
+		return fn(ctx, sort, opts)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return SubredditListing(), nil
+}
+
+// GetPost returns the post from the comments fixture by default
+func (f *FakeClient) GetPost(ctx context.Context, subreddit, postID string) (*redditclient.Post, error) {
+	f.record("GetPost", subreddit, postID)
+	if f.PostFn != nil {
+		return f.PostFn(ctx, subreddit, postID)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return Comments().Post()
+}
+
+// GetRandomPost returns the comments fixture's post and comments by default
+func (f *FakeClient) GetRandomPost(ctx context.Context, subreddit string) (*redditclient.Post, *redditclient.CommentListing, error) {
+	f.record("GetRandomPost", subreddit)
+	if f.RandomPostFn != nil {
+		return f.RandomPostFn(ctx, subreddit)
+	}
+	if f.Err != nil {
+		return nil, nil, f.Err
+	}
+	thread := Comments()
+	post, err := thread.Post()
+	if err != nil {
+		return nil, nil, err
+	}
+	return post, &thread.CommentListing, nil
+}
+
+// GetPostsByID returns the fixture listing's posts matching fullnames, in
+// input order, by default
+func (f *FakeClient) GetPostsByID(ctx context.Context, fullnames []string) ([]redditclient.Post, error) {
+	f.record("GetPostsByID", fullnames)
+	if f.PostsByIDFn != nil {
+		return f.PostsByIDFn(ctx, fullnames)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	var posts []redditclient.Post
+	children := SubredditListing().Data.Children
+	for _, name := range fullnames {
+		for _, child := range children {
+			if child.Data.Name == name || child.Data.ID == name {
+				posts = append(posts, child.Data)
+			}
+		}
+	}
+	return posts, nil
+}
+
+// ResolvePostURL delegates to ResolveURLFn
+func (f *FakeClient) ResolvePostURL(ctx context.Context, rawURL string) (subreddit, postID string, err error) {
+	f.record("ResolvePostURL", rawURL)
+	ref, err := f.resolveURL(ctx, rawURL)
+	if err != nil {
+		return "", "", err
+	}
+	return ref.Subreddit, ref.PostID, nil
+}
+
+// ResolveURL parses canonical URLs locally by default; share links fail
+func (f *FakeClient) ResolveURL(ctx context.Context, rawURL string) (*redditclient.PostRef, error) {
+	f.record("ResolveURL", rawURL)
+	return f.resolveURL(ctx, rawURL)
+}
+
+func (f *FakeClient) resolveURL(ctx context.Context, rawURL string) (*redditclient.PostRef, error) {
+	if f.ResolveURLFn != nil {
+		return f.ResolveURLFn(ctx, rawURL)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return redditclient.ParsePostURL(rawURL)
+}
+
+// GetUser returns the user fixture by default
+func (f *FakeClient) GetUser(ctx context.Context, username string) (*redditclient.UserResponse, error) {
+	f.record("GetUser", username)
+	if f.UserFn != nil {
+		return f.UserFn(ctx, username)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return User(), nil
+}
+
+// GetUserPosts returns the subreddit listing fixture by default
+func (f *FakeClient) GetUserPosts(ctx context.Context, username, sort, timeframe string, opts *redditclient.ListingOptions) (*redditclient.PostListing, error) {
+	f.record("GetUserPosts", username, sort, timeframe, opts)
+	if f.UserPostsFn != nil {
+		return f.UserPostsFn(ctx, username, sort, timeframe, opts)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return SubredditListing(), nil
+}
+
+// GetUserComments returns the comments fixture's top-level listing by default
+func (f *FakeClient) GetUserComments(ctx context.Context, username, sort, timeframe string, opts *redditclient.ListingOptions) (*redditclient.CommentListing, error) {
+	f.record("GetUserComments", username, sort, timeframe, opts)
+	if f.UserCommentsFn != nil {
+		return f.UserCommentsFn(ctx, username, sort, timeframe, opts)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return Comments().Comments()
+}
+
+// GetUserOverview returns an empty listing by default
+func (f *FakeClient) GetUserOverview(ctx context.Context, username string, opts *redditclient.ListingOptions) (*redditclient.ThingListing, error) {
+	f.record("GetUserOverview", username, opts)
+	if f.UserOverviewFn != nil {
+		return f.UserOverviewFn(ctx, username, opts)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return &redditclient.ThingListing{Kind: redditclient.KindListing}, nil
+}
+
+// GetUserTrophies returns no trophies by default
+func (f *FakeClient) GetUserTrophies(ctx context.Context, username string) ([]redditclient.Trophy, error) {
+	f.record("GetUserTrophies", username)
+	if f.UserTrophiesFn != nil {
+		return f.UserTrophiesFn(ctx, username)
+	}
+	return nil, f.Err
+}
+
+// GetWikiPage returns an empty page by default
+func (f *FakeClient) GetWikiPage(ctx context.Context, subreddit, page string) (*redditclient.WikiPage, error) {
+	f.record("GetWikiPage", subreddit, page)
+	if f.WikiPageFn != nil {
+		return f.WikiPageFn(ctx, subreddit, page)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return &redditclient.WikiPage{}, nil
+}
+
+// ListWikiPages returns no pages by default
+func (f *FakeClient) ListWikiPages(ctx context.Context, subreddit string) ([]string, error) {
+	f.record("ListWikiPages", subreddit)
+	if f.WikiPagesFn != nil {
+		return f.WikiPagesFn(ctx, subreddit)
+	}
+	return nil, f.Err
+}
+
+// GetTrendingSubreddits returns no subreddits by default
+func (f *FakeClient) GetTrendingSubreddits(ctx context.Context) (*redditclient.TrendingSubreddits, error) {
+	f.record("GetTrendingSubreddits")
+	if f.TrendingFn != nil {
+		return f.TrendingFn(ctx)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return &redditclient.TrendingSubreddits{}, nil
+}
+
+// GetDefaultSubreddits delegates to SubredditsFn with where "default"
+func (f *FakeClient) GetDefaultSubreddits(ctx context.Context, opts *redditclient.ListingOptions) (*redditclient.SubscriptionListing, error) {
+	f.record("GetDefaultSubreddits", opts)
+	return f.subreddits(ctx, "default", opts)
+}
+
+// GetPopularSubreddits delegates to SubredditsFn with where "popular"
+func (f *FakeClient) GetPopularSubreddits(ctx context.Context, opts *redditclient.ListingOptions) (*redditclient.SubscriptionListing, error) {
+	f.record("GetPopularSubreddits", opts)
+	return f.subreddits(ctx, "popular", opts)
+}
+
+// subreddits returns an empty listing by default
+func (f *FakeClient) subreddits(ctx context.Context, where string, opts *redditclient.ListingOptions) (*redditclient.SubscriptionListing, error) {
+	if f.SubredditsFn != nil {
+		return f.SubredditsFn(ctx, where, opts)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return &redditclient.SubscriptionListing{Kind: redditclient.KindListing}, nil
+}
+
+// GetMultireddits returns no multireddits by default
+func (f *FakeClient) GetMultireddits(ctx context.Context, username string) ([]redditclient.Multireddit, error) {
+	f.record("GetMultireddits", username)
+	if f.MultiredditsFn != nil {
+		return f.MultiredditsFn(ctx, username)
+	}
+	return nil, f.Err
+}
+
+// GetMultiListing returns the subreddit listing fixture by default
+func (f *FakeClient) GetMultiListing(ctx context.Context, username, multiName, sort string, opts *redditclient.ListingOptions) (*redditclient.PostListing, error) {
+	f.record("GetMultiListing", username, multiName, sort, opts)
+	if f.MultiListingFn != nil {
+		return f.MultiListingFn(ctx, username, multiName, sort, opts)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return SubredditListing(), nil
+}
+
+// GetSubscriptions returns an empty listing by default
+func (f *FakeClient) GetSubscriptions(ctx context.Context, opts *redditclient.ListingOptions) (*redditclient.SubscriptionListing, error) {
+	f.record("GetSubscriptions", opts)
+	if f.SubscriptionsFn != nil {
+		return f.SubscriptionsFn(ctx, opts)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return &redditclient.SubscriptionListing{Kind: redditclient.KindListing}, nil
+}
+
+// GetSaved returns an empty listing by default
+func (f *FakeClient) GetSaved(ctx context.Context, username string, opts *redditclient.ListingOptions) (*redditclient.ThingListing, error) {
+	f.record("GetSaved", username, opts)
+	if f.SavedFn != nil {
+		return f.SavedFn(ctx, username, opts)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return &redditclient.ThingListing{Kind: redditclient.KindListing}, nil
+}
+
+// Vote records the call and succeeds unless Err is set
+func (f *FakeClient) Vote(ctx context.Context, fullname string, dir int) error {
+	f.record("Vote", fullname, dir)
+	if f.VoteFn != nil {
+		return f.VoteFn(ctx, fullname, dir)
+	}
+	return f.Err
+}
+
+// Save delegates to SaveFn with saved set to true
+func (f *FakeClient) Save(ctx context.Context, fullname string) error {
+	f.record("Save", fullname)
+	return f.save(ctx, fullname, true)
+}
+
+// Unsave delegates to SaveFn with saved set to false
+func (f *FakeClient) Unsave(ctx context.Context, fullname string) error {
+	f.record("Unsave", fullname)
+	return f.save(ctx, fullname, false)
+}
+
+func (f *FakeClient) save(ctx context.Context, fullname string, saved bool) error {
+	if f.SaveFn != nil {
+		return f.SaveFn(ctx, fullname, saved)
+	}
+	return f.Err
+}
+
+// GetInbox delegates to InboxFn with unread set to false
+func (f *FakeClient) GetInbox(ctx context.Context, opts *redditclient.ListingOptions) (*redditclient.ThingListing, error) {
+	f.record("GetInbox", opts)
+	return f.inbox(ctx, false, opts)
+}
+
+// GetUnread delegates to InboxFn with unread set to true
+func (f *FakeClient) GetUnread(ctx context.Context, opts *redditclient.ListingOptions) (*redditclient.ThingListing, error) {
+	f.record("GetUnread", opts)
+	return f.inbox(ctx, true, opts)
+}
+
+func (f *FakeClient) inbox(ctx context.Context, unread bool, opts *redditclient.ListingOptions) (*redditclient.ThingListing, error) {
+	if f.InboxFn != nil {
+		return f.InboxFn(ctx, unread, opts)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return &redditclient.ThingListing{Kind: redditclient.KindListing}, nil
+}
+
+// MarkRead records the call and succeeds unless Err is set
+func (f *FakeClient) MarkRead(ctx context.Context, fullnames []string) error {
+	f.record("MarkRead", fullnames)
+	if f.MarkReadFn != nil {
+		return f.MarkReadFn(ctx, fullnames)
+	}
+	return f.Err
+}
+
+// PostComment returns a comment echoing markdown by default
+func (f *FakeClient) PostComment(ctx context.Context, parentFullname, markdown string) (*redditclient.Comment, error) {
+	f.record("PostComment", parentFullname, markdown)
+	if f.PostCommentFn != nil {
+		return f.PostCommentFn(ctx, parentFullname, markdown)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return &redditclient.Comment{ParentID: parentFullname, Body: markdown}, nil
+}
+
+// SubmitPost returns a post echoing opts by default
+func (f *FakeClient) SubmitPost(ctx context.Context, subreddit string, opts redditclient.SubmitOptions) (*redditclient.Post, error) {
+	f.record("SubmitPost", subreddit, opts)
+	if f.SubmitPostFn != nil {
+		return f.SubmitPostFn(ctx, subreddit, opts)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return &redditclient.Post{
+		Subreddit: subreddit,
+		Title:     opts.Title,
+		SelfText:  opts.Text,
+		URL:       opts.URL,
+		IsSelf:    opts.Kind == redditclient.SubmitSelf,
+	}, nil
+}
+
+// GetLinkFlairs returns no templates by default
+func (f *FakeClient) GetLinkFlairs(ctx context.Context, subreddit string) ([]redditclient.FlairTemplate, error) {
+	f.record("GetLinkFlairs", subreddit)
+	if f.LinkFlairsFn != nil {
+		return f.LinkFlairsFn(ctx, subreddit)
+	}
+	return nil, f.Err
+}
+
+// Search returns the subreddit listing fixture by default
+func (f *FakeClient) Search(ctx context.Context, query, sort, timeframe string) (*redditclient.SearchResponse, error) {
+	f.record("Search", query, sort, timeframe)
+	if f.SearchFn != nil {
+		return f.SearchFn(ctx, query, sort, timeframe)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return SubredditListing(), nil
+}
+
+// SearchWithOptions returns the subreddit listing fixture's posts by default
+func (f *FakeClient) SearchWithOptions(ctx context.Context, query string, opts *redditclient.SearchOptions) (*redditclient.SearchResults, error) {
+	f.record("SearchWithOptions", query, opts)
+	if f.SearchWithOptionsFn != nil {
+		return f.SearchWithOptionsFn(ctx, query, opts)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	listing := SubredditListing()
+	results := &redditclient.SearchResults{After: listing.Data.After, Before: listing.Data.Before}
+	for _, child := range listing.Data.Children {
+		results.Posts = append(results.Posts, child.Data)
+	}
+	return results, nil
+}
+
+// SearchSubreddits returns no suggestions by default
+func (f *FakeClient) SearchSubreddits(ctx context.Context, query string, opts *redditclient.SubredditSearchOptions) ([]redditclient.SubredditSuggestion, error) {
+	f.record("SearchSubreddits", query, opts)
+	if f.SearchSubredditsFn != nil {
+		return f.SearchSubredditsFn(ctx, query, opts)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return []redditclient.SubredditSuggestion{}, nil
+}
+
+// GetComments delegates to CommentsFn
+func (f *FakeClient) GetComments(ctx context.Context, subreddit, postID, sort string) (*redditclient.PostAndCommentsResponse, error) {
+	f.record("GetComments", subreddit, postID, sort)
+	return f.comments(ctx, subreddit, postID, &redditclient.CommentOptions{Sort: redditclient.CommentSort(sort)})
+}
+
+// GetCommentsWithOptions delegates to CommentsFn
+func (f *FakeClient) GetCommentsWithOptions(ctx context.Context, subreddit, postID string, opts *redditclient.CommentOptions) (*redditclient.PostAndCommentsResponse, error) {
+	f.record("GetCommentsWithOptions", subreddit, postID, opts)
+	return f.comments(ctx, subreddit, postID, opts)
+}
+
+func (f *FakeClient) comments(ctx context.Context, subreddit, postID string, opts *redditclient.CommentOptions) (*redditclient.PostAndCommentsResponse, error) {
+	if f.CommentsFn != nil {
+		return f.CommentsFn(ctx, subreddit, postID, opts)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return Comments(), nil
+}
+
+// GetComment returns the comments fixture by default
+func (f *FakeClient) GetComment(ctx context.Context, subreddit, postID, commentID string, contextDepth int) (*redditclient.PostAndCommentsResponse, error) {
+	f.record("GetComment", subreddit, postID, commentID, contextDepth)
+	if f.CommentFn != nil {
+		return f.CommentFn(ctx, subreddit, postID, commentID, contextDepth)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return Comments(), nil
+}
+
+// GetDuplicates delegates to DuplicatesFn
+func (f *FakeClient) GetDuplicates(ctx context.Context, postID string, opts *redditclient.ListingOptions) (*redditclient.Post, []redditclient.Post, error) {
+	f.record("GetDuplicates", postID, opts)
+
+	var dupOpts *redditclient.DuplicatesOptions
+	if opts != nil {
+		dupOpts = &redditclient.DuplicatesOptions{ListingOptions: *opts}
+	}
+	resp, err := f.duplicates(ctx, postID, dupOpts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	post, err := resp.Post()
+	if err != nil {
+		return nil, nil, err
+	}
+	var duplicates []redditclient.Post
+	for _, child := range resp.DuplicateListing.Data.Children {
+		duplicates = append(duplicates, child.Data)
+	}
+	return post, duplicates, nil
+}
+
+// GetDuplicatesWithOptions delegates to DuplicatesFn
+func (f *FakeClient) GetDuplicatesWithOptions(ctx context.Context, postID string, opts *redditclient.DuplicatesOptions) (*redditclient.DuplicatesResponse, error) {
+	f.record("GetDuplicatesWithOptions", postID, opts)
+	return f.duplicates(ctx, postID, opts)
+}
+
+// duplicates returns the comments fixture's post with no duplicates by default
+func (f *FakeClient) duplicates(ctx context.Context, postID string, opts *redditclient.DuplicatesOptions) (*redditclient.DuplicatesResponse, error) {
+	if f.DuplicatesFn != nil {
+		return f.DuplicatesFn(ctx, postID, opts)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return &redditclient.DuplicatesResponse{
+		PostListing:      Comments().PostListing,
+		DuplicateListing: redditclient.PostListing{Kind: redditclient.KindListing},
+	}, nil
+}
+
+// GetMoreComments returns no comments by default
+func (f *FakeClient) GetMoreComments(ctx context.Context, linkID string, children []string) (*redditclient.MoreChildrenResponse, error) {
+	f.record("GetMoreComments", linkID, children)
+	return f.moreComments(ctx, linkID, children)
+}
+
+// GetMoreCommentsWithSort delegates to MoreCommentsFn, ignoring sort
+func (f *FakeClient) GetMoreCommentsWithSort(ctx context.Context, linkID string, children []string, sort redditclient.CommentSort) (*redditclient.MoreChildrenResponse, error) {
+	f.record("GetMoreCommentsWithSort", linkID, children, sort)
+	return f.moreComments(ctx, linkID, children)
+}
+
+func (f *FakeClient) moreComments(ctx context.Context, linkID string, children []string) (*redditclient.MoreChildrenResponse, error) {
+	if f.MoreCommentsFn != nil {
+		return f.MoreCommentsFn(ctx, linkID, children)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return &redditclient.MoreChildrenResponse{}, nil
+}
+
+// GetAllComments returns the comments fixture's tree, "more" placeholders
+// included, by default
+func (f *FakeClient) GetAllComments(ctx context.Context, subreddit, postID string, opts *redditclient.CommentOptions) (*redditclient.CommentListing, error) {
+	f.record("GetAllComments", subreddit, postID, opts)
+	if f.AllCommentsFn != nil {
+		return f.AllCommentsFn(ctx, subreddit, postID, opts)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return Comments().Comments()
+}
+
+// GetJSON decodes the body RawFn returns into v. Without RawFn it returns Err
+// and leaves v untouched.
+func (f *FakeClient) GetJSON(ctx context.Context, endpoint string, params url.Values, v any) error {
+	f.record("GetJSON", endpoint, params, v)
+	body, err := f.raw(ctx, endpoint, params)
+	if err != nil || body == nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+// GetRaw returns a nil body by default
+func (f *FakeClient) GetRaw(ctx context.Context, endpoint string, params url.Values) ([]byte, error) {
+	f.record("GetRaw", endpoint, params)
+	return f.raw(ctx, endpoint, params)
+}
+
+func (f *FakeClient) raw(ctx context.Context, endpoint string, params url.Values) ([]byte, error) {
+	if f.RawFn != nil {
+		return f.RawFn(ctx, endpoint, params)
+	}
+	return nil, f.Err
+}
@@ -0,0 +1,156 @@
+package redditclienttest
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixturesDecode(t *testing.T) {
+	listing := SubredditListing()
+	require.Len(t, listing.Data.Children, 3)
+	assert.Equal(t, "t3_1c3xk9p", listing.Data.After)
+	assert.True(t, listing.Data.Children[1].Data.Edited.Edited)
+
+	thread := Comments()
+	post, err := thread.Post()
+	require.NoError(t, err)
+	assert.Equal(t, "1c3xk9p", post.ID)
+
+	comments, err := thread.Comments()
+	require.NoError(t, err)
+	require.Len(t, comments.Data.Children, 3)
+	assert.Equal(t, redditclient.KindMore, comments.Data.Children[2].Kind)
+	require.NotNil(t, comments.Data.Children[0].Comment.Replies)
+	nested := comments.Data.Children[0].Comment.Replies.Data.Children
+	require.Len(t, nested, 2)
+	assert.Equal(t, []string{"kz1a005", "kz1a006"}, nested[1].More.Children)
+
+	user := User()
+	assert.Equal(t, "gopher_jane", user.Data.Name)
+	assert.Equal(t, 48211, user.Data.CommentKarma)
+}
+
+func TestFixturesAreFreshCopies(t *testing.T) {
+	SubredditListing().Data.Children[0].Data.Title = "changed"
+	assert.NotEqual(t, "changed", SubredditListing().Data.Children[0].Data.Title)
+
+	SubredditListingJSON()[0] = 'x'
+	assert.Equal(t, byte('{'), SubredditListingJSON()[0])
+}
+
+// fetchTitles stands in for consumer code written against the interface
+func fetchTitles(ctx context.Context, client redditclient.RedditClient, subreddit string) ([]string, error) {
+	listing, err := client.GetSubreddit(ctx, subreddit, "hot")
+	if err != nil {
+		return nil, err
+	}
+
+	var titles []string
+	for _, child := range listing.Data.Children {
+		titles = append(titles, child.Data.Title)
+	}
+	return titles, nil
+}
+
+func TestFakeClient_Defaults(t *testing.T) {
+	fake := NewFakeClient()
+
+	titles, err := fetchTitles(t.Context(), fake, "golang")
+	require.NoError(t, err)
+	assert.Len(t, titles, 3)
+
+	user, err := fake.GetUser(t.Context(), "gopher_jane")
+	require.NoError(t, err)
+	assert.Equal(t, "gopher_jane", user.Data.Name)
+
+	post, err := fake.GetPost(t.Context(), "golang", "1c3xk9p")
+	require.NoError(t, err)
+	assert.Equal(t, "How do you structure a large Go monorepo?", post.Title)
+
+	posts, err := fake.GetPostsByID(t.Context(), []string{"t3_1c3xk9p", "t3_missing", "1c3xk7a"})
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+	assert.Equal(t, "1c3xk9p", posts[0].ID)
+	assert.Equal(t, "1c3xk7a", posts[1].ID)
+
+	subreddit, postID, err := fake.ResolvePostURL(t.Context(), "https://old.reddit.com/r/golang/comments/1c3xk9p/slug/")
+	require.NoError(t, err)
+	assert.Equal(t, "golang", subreddit)
+	assert.Equal(t, "1c3xk9p", postID)
+}
+
+func TestFakeClient_CustomFn(t *testing.T) {
+	fake := NewFakeClient()
+	fake.SubredditFn = func(_ context.Context, subreddit string, sort redditclient.Sort, _ *redditclient.ListingOptions) (*redditclient.SubredditListing, error) {
+		return &redditclient.SubredditListing{Data: redditclient.PostListingData{
+			Children: []redditclient.PostChild{{Kind: redditclient.KindPost, Data: redditclient.Post{Title: subreddit + "/" + string(sort)}}},
+		}}, nil
+	}
+
+	titles, err := fetchTitles(t.Context(), fake, "rust")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rust/hot"}, titles)
+
+	// The paged and typed variants share SubredditFn
+	listing, err := fake.GetSubredditListing(t.Context(), "go", redditclient.SortNew, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "go/new", listing.Data.Children[0].Data.Title)
+}
+
+func TestFakeClient_Err(t *testing.T) {
+	errBoom := errors.New("boom")
+	fake := &FakeClient{Err: errBoom}
+
+	_, err := fake.GetSubreddit(t.Context(), "golang", "hot")
+	assert.ErrorIs(t, err, errBoom)
+	_, err = fake.GetAllComments(t.Context(), "golang", "abc", nil)
+	assert.ErrorIs(t, err, errBoom)
+
+	// An Fn takes precedence over Err
+	fake.UserFn = func(context.Context, string) (*redditclient.UserResponse, error) { return User(), nil }
+	_, err = fake.GetUser(t.Context(), "gopher_jane")
+	assert.NoError(t, err)
+}
+
+func TestFakeClient_GetJSON(t *testing.T) {
+	fake := NewFakeClient()
+
+	var v struct {
+		Kind string `json:"kind"`
+	}
+	require.NoError(t, fake.GetJSON(t.Context(), "/r/golang/about.json", nil, &v))
+	assert.Empty(t, v.Kind, "v is untouched without RawFn")
+
+	fake.RawFn = func(context.Context, string, url.Values) ([]byte, error) {
+		return []byte(`{"kind": "t5"}`), nil
+	}
+	require.NoError(t, fake.GetJSON(t.Context(), "/r/golang/about.json", nil, &v))
+	assert.Equal(t, "t5", v.Kind)
+	assert.Len(t, fake.CallsTo("GetJSON"), 2)
+}
+
+func TestFakeClient_RecordsCalls(t *testing.T) {
+	fake := NewFakeClient()
+	opts := &redditclient.ListingOptions{Limit: 10}
+
+	_, _ = fake.GetSubredditPage(t.Context(), "golang", "new", opts)
+	_, _ = fake.GetComments(t.Context(), "golang", "abc123", "top")
+	_, _ = fake.GetSubredditPage(t.Context(), "rust", "hot", nil)
+
+	calls := fake.Calls()
+	require.Len(t, calls, 3)
+	assert.Equal(t, Call{Method: "GetSubredditPage", Args: []any{"golang", "new", opts}}, calls[0])
+	assert.Equal(t, Call{Method: "GetComments", Args: []any{"golang", "abc123", "top"}}, calls[1])
+
+	assert.Len(t, fake.CallsTo("GetSubredditPage"), 2)
+	assert.Empty(t, fake.CallsTo("GetUser"))
+
+	fake.Reset()
+	assert.Empty(t, fake.Calls())
+}
@@ -0,0 +1,56 @@
+package redditclienttest
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+)
+
+//go:embed fixtures/subreddit_listing.json
+var subredditListingJSON []byte
+
+//go:embed fixtures/comments.json
+var commentsJSON []byte
+
+//go:embed fixtures/user.json
+var userJSON []byte
+
+// SubredditListingJSON returns a captured r/golang hot listing with three posts
+func SubredditListingJSON() []byte { return clone(subredditListingJSON) }
+
+// CommentsJSON returns a captured comment thread whose tree includes "more"
+// placeholders at the top level and inside a reply chain
+func CommentsJSON() []byte { return clone(commentsJSON) }
+
+// UserJSON returns a captured /user/{name}/about.json response
+func UserJSON() []byte { return clone(userJSON) }
+
+// SubredditListing decodes SubredditListingJSON. Each call returns a fresh
+// copy that the caller may modify.
+func SubredditListing() *redditclient.SubredditListing {
+	return mustDecode[redditclient.SubredditListing](subredditListingJSON)
+}
+
+// Comments decodes CommentsJSON. Each call returns a fresh copy.
+func Comments() *redditclient.PostAndCommentsResponse {
+	return mustDecode[redditclient.PostAndCommentsResponse](commentsJSON)
+}
+
+// User decodes UserJSON. Each call returns a fresh copy.
+func User() *redditclient.UserResponse {
+	return mustDecode[redditclient.UserResponse](userJSON)
+}
+
+func mustDecode[T any](data []byte) *T {
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		panic(fmt.Sprintf("redditclienttest: invalid embedded fixture: %v", err))
+	}
+	return &v
+}
+
+func clone(data []byte) []byte {
+	return append([]byte(nil), data...)
+}
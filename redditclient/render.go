@@ -0,0 +1,134 @@
+package redditclient
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// RenderHTML returns the post's selftext as HTML, or an empty string for link
+// posts. With sanitize set, only a small set of formatting tags and http,
+// https and mailto links are kept, so the result can be embedded in a page.
+func (p *Post) RenderHTML(sanitize bool) string {
+	return renderHTML(p.SelfTextHTML, sanitize)
+}
+
+// RenderHTML returns the comment body as HTML; see Post.RenderHTML
+func (c *Comment) RenderHTML(sanitize bool) string {
+	return renderHTML(c.BodyHTML, sanitize)
+}
+
+// renderHTML unescapes one of Reddit's *_html fields. They come back
+// entity-escaped ("&lt;div class=...") unless raw_json was honored, in which
+// case they are already HTML and unescaping again would corrupt them.
+func renderHTML(s string, sanitize bool) string {
+	if strings.HasPrefix(s, "&lt;") {
+		s = html.UnescapeString(s)
+	}
+	if sanitize {
+		s = sanitizeHTML(s)
+	}
+	return s
+}
+
+// allowedTags are the elements Reddit's markdown renderer produces
+var allowedTags = map[string]bool{
+	"a": true, "blockquote": true, "br": true, "code": true, "del": true,
+	"div": true, "em": true, "h1": true, "h2": true, "h3": true, "h4": true,
+	"h5": true, "h6": true, "hr": true, "li": true, "ol": true, "p": true,
+	"pre": true, "span": true, "strong": true, "sup": true, "table": true,
+	"tbody": true, "td": true, "th": true, "thead": true, "tr": true, "ul": true,
+}
+
+// droppedContentTags are removed along with everything inside them
+var droppedContentTags = map[string]bool{"script": true, "style": true}
+
+// allowedLinkPrefixes are the href values a sanitized link may start with
+var allowedLinkPrefixes = []string{"http://", "https://", "mailto:", "/", "#"}
+
+var (
+	tagPattern  = regexp.MustCompile(`^<(/?)([a-zA-Z][a-zA-Z0-9]*)((?:\s+[^\s"'>/=]+(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'>]+))?)*)\s*/?>`)
+	attrPattern = regexp.MustCompile(`([^\s"'>/=]+)(?:\s*=\s*("[^"]*"|'[^']*'|[^\s"'>]+))?`)
+)
+
+// sanitizeHTML keeps the tags in allowedTags, dropping every attribute except
+// a safe href on links, and escapes anything else that looks like markup
+func sanitizeHTML(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '<')
+		if i < 0 {
+			b.WriteString(s)
+			break
+		}
+		b.WriteString(s[:i])
+		s = s[i:]
+
+		if strings.HasPrefix(s, "<!--") {
+			end := strings.Index(s, "-->")
+			if end < 0 {
+				break
+			}
+			s = s[end+len("-->"):]
+			continue
+		}
+
+		m := tagPattern.FindStringSubmatch(s)
+		if m == nil {
+			b.WriteString("&lt;")
+			s = s[1:]
+			continue
+		}
+		s = s[len(m[0]):]
+
+		closing, name := m[1] == "/", strings.ToLower(m[2])
+		switch {
+		case droppedContentTags[name] && !closing:
+			s = skipElement(s, name)
+		case !allowedTags[name]:
+		case closing:
+			b.WriteString("</" + name + ">")
+		default:
+			b.WriteString("<" + name)
+			if name == "a" {
+				writeSafeHref(&b, m[3])
+			}
+			b.WriteString(">")
+		}
+	}
+
+	return b.String()
+}
+
+// skipElement returns s after the closing tag of name, or "" if it never closes
+func skipElement(s, name string) string {
+	end := strings.Index(strings.ToLower(s), "</"+name)
+	if end < 0 {
+		return ""
+	}
+	s = s[end:]
+	if gt := strings.IndexByte(s, '>'); gt >= 0 {
+		return s[gt+1:]
+	}
+	return ""
+}
+
+// writeSafeHref writes the href from attrs if it uses an allowed scheme
+func writeSafeHref(b *strings.Builder, attrs string) {
+	for _, attr := range attrPattern.FindAllStringSubmatch(attrs, -1) {
+		if !strings.EqualFold(attr[1], "href") {
+			continue
+		}
+
+		href := html.UnescapeString(strings.Trim(attr[2], `"'`))
+		for _, prefix := range allowedLinkPrefixes {
+			if strings.HasPrefix(strings.ToLower(href), prefix) {
+				b.WriteString(` href="` + html.EscapeString(href) + `"`)
+				return
+			}
+		}
+		return
+	}
+}
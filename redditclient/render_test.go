@@ -0,0 +1,72 @@
+package redditclient
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPost_RenderHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"escaped", `{"selftext_html": "&lt;!-- SC_OFF --&gt;&lt;div class=\"md\"&gt;&lt;p&gt;Fish &amp;amp; chips&lt;/p&gt;&lt;/div&gt;&lt;!-- SC_ON --&gt;"}`},
+		{"raw_json", `{"selftext_html": "<!-- SC_OFF --><div class=\"md\"><p>Fish &amp; chips</p></div><!-- SC_ON -->"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var post Post
+			require.NoError(t, json.Unmarshal([]byte(tt.body), &post))
+
+			assert.Equal(t, `<!-- SC_OFF --><div class="md"><p>Fish &amp; chips</p></div><!-- SC_ON -->`, post.RenderHTML(false))
+			assert.Equal(t, `<div><p>Fish &amp; chips</p></div>`, post.RenderHTML(true))
+		})
+	}
+}
+
+func TestPost_RenderHTML_LinkPost(t *testing.T) {
+	var post Post
+	require.NoError(t, json.Unmarshal([]byte(`{"is_self": false, "selftext_html": null}`), &post))
+
+	assert.Empty(t, post.SelfTextHTML)
+	assert.Empty(t, post.RenderHTML(true))
+}
+
+func TestComment_RenderHTML(t *testing.T) {
+	var comment Comment
+	require.NoError(t, json.Unmarshal([]byte(`{"body": "**hi**", "body_html": "&lt;div class=\"md\"&gt;&lt;p&gt;&lt;strong&gt;hi&lt;/strong&gt;&lt;/p&gt;&lt;/div&gt;", "replies": ""}`), &comment))
+
+	assert.Equal(t, `<div class="md"><p><strong>hi</strong></p></div>`, comment.RenderHTML(false))
+	assert.Equal(t, `<div><p><strong>hi</strong></p></div>`, comment.RenderHTML(true))
+}
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"script dropped with content", `<p>a<script>alert(1)</script>b</p>`, `<p>ab</p>`},
+		{"uppercase script", `<SCRIPT src=x>alert(1)</SCRIPT>ok`, `ok`},
+		{"event handler stripped", `<p onclick="alert(1)">hi</p>`, `<p>hi</p>`},
+		{"safe link kept", `<a href="https://go.dev/?a=1&amp;b=2" onmouseover="x">go</a>`, `<a href="https://go.dev/?a=1&amp;b=2">go</a>`},
+		{"relative link kept", `<a href="/r/golang">r/golang</a>`, `<a href="/r/golang">r/golang</a>`},
+		{"javascript link dropped", `<a href="javascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"encoded javascript link dropped", `<a href="javascript&#58;alert(1)">x</a>`, `<a>x</a>`},
+		{"unknown tag dropped", `<iframe src="https://evil.example"></iframe><em>ok</em>`, `<em>ok</em>`},
+		{"quoted angle bracket", `<a href="https://a.example/>" title='>'>x</a>`, `<a href="https://a.example/&gt;">x</a>`},
+		{"stray bracket escaped", `1 < 2 <3`, `1 &lt; 2 &lt;3`},
+		{"self-closing", `a<br/>b<hr />`, `a<br>b<hr>`},
+		{"unclosed comment", `ok<!-- <script>`, `ok`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sanitizeHTML(tt.in))
+		})
+	}
+}
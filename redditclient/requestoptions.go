@@ -0,0 +1,107 @@
+package redditclient
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestOption adjusts the API requests made with a context for a single
+// call, overriding the client's defaults; see WithRequestOptions
+type RequestOption func(*callOptions)
+
+// callOptions are the RequestOptions carried by a context
+type callOptions struct {
+	noCache bool
+	noRetry bool
+	timeout time.Duration
+	header  http.Header
+}
+
+type callOptionsKey struct{}
+
+// WithRequestOptions returns a context whose API requests follow opts. Every
+// method takes a context, so this is how one call skips the cache or retries
+// without reconfiguring the client:
+//
+//	ctx := redditclient.WithRequestOptions(ctx, redditclient.NoRetry(), redditclient.Timeout(5*time.Second))
+//	listing, err := client.GetSubredditListing(ctx, "golang", redditclient.SortNew, nil)
+//
+// Options add to those already on ctx, with later ones winning.
+func WithRequestOptions(ctx context.Context, opts ...RequestOption) context.Context {
+	o := callOptionsFrom(ctx)
+	o.header = o.header.Clone()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return context.WithValue(ctx, callOptionsKey{}, o)
+}
+
+// NoCache makes requests neither read nor write the cache, and not share a
+// round trip with concurrent identical requests. Unlike ForceFresh, the
+// response does not refresh the cache either.
+func NoCache() RequestOption {
+	return func(o *callOptions) {
+		o.noCache = true
+	}
+}
+
+// NoRetry makes a failed request return at once instead of following the
+// client's retry policy. A 429 response is returned as ErrRateLimited rather
+// than waited out.
+func NoRetry() RequestOption {
+	return func(o *callOptions) {
+		o.noRetry = true
+	}
+}
+
+// Timeout bounds each call, including its retries and reading the response,
+// to d. It is applied on top of any deadline ctx already has and of the HTTP
+// client's timeout, so the earliest wins. A d of zero or less removes a
+// Timeout set earlier.
+func Timeout(d time.Duration) RequestOption {
+	return func(o *callOptions) {
+		o.timeout = d
+	}
+}
+
+// ExtraHeader adds a header to requests. It cannot replace a header the
+// client sets itself, such as Authorization or User-Agent. Since a header can
+// change the response, requests carrying one also skip the cache and
+// coalescing, as with NoCache.
+func ExtraHeader(key, value string) RequestOption {
+	return func(o *callOptions) {
+		if o.header == nil {
+			o.header = http.Header{}
+		}
+		o.header.Set(key, value)
+	}
+}
+
+// callOptionsFrom returns the options ctx carries, or the zero callOptions
+func callOptionsFrom(ctx context.Context) callOptions {
+	o, _ := ctx.Value(callOptionsKey{}).(callOptions)
+	return o
+}
+
+// private reports whether requests must bypass the cache and coalescing
+func (o callOptions) private() bool {
+	return o.noCache || len(o.header) > 0
+}
+
+// withTimeout applies the call's Timeout to ctx. The returned cancel must be
+// called once the call is done.
+func (o callOptions) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, o.timeout)
+}
+
+// setHeaders adds the call's extra headers to req, before the client sets
+// its own so that those win
+func (o callOptions) setHeaders(req *http.Request) {
+	for key := range o.header {
+		req.Header.Set(key, o.header.Get(key))
+	}
+}
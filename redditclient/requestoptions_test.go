@@ -0,0 +1,166 @@
+package redditclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const oneChildListing = `{"kind": "Listing", "data": {"children": [{"kind": "t3", "data": {"id": "fresh"}}]}}`
+
+func TestRequestOptions_NoRetry(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		opts    []RequestOption
+		calls   int
+		wantErr error
+	}{
+		{"server error is retried by default", 503, nil, 3, ErrServerError},
+		{"server error with NoRetry", 503, []RequestOption{NoRetry()}, 1, ErrServerError},
+		{"429 is retried by default", 429, nil, 3, ErrRateLimited},
+		{"429 with NoRetry", 429, []RequestOption{NoRetry()}, 1, ErrRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mockHTTP, delays := newRetryingTestClient(t)
+			mockHTTP.On("Do", mock.Anything).Return(func(*http.Request) *http.Response {
+				return createHTTPResponse(tt.status, "", map[string]string{"Retry-After": "1"})
+			}, nil)
+
+			ctx := WithRequestOptions(t.Context(), tt.opts...)
+			_, err := client.GetSubredditListing(ctx, "golang", SortNew, nil)
+
+			require.ErrorIs(t, err, tt.wantErr)
+			mockHTTP.AssertNumberOfCalls(t, "Do", tt.calls)
+			assert.Len(t, *delays, tt.calls-1, "NoRetry must not wait out a 429 either")
+		})
+	}
+}
+
+func TestRequestOptions_NoCache(t *testing.T) {
+	client, mockHTTP, _ := newCachingTestClient(t, time.Minute)
+
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(200, emptyListing, nil), nil).Once()
+	_, err := client.GetSubredditListing(t.Context(), "golang", SortNew, nil)
+	require.NoError(t, err)
+
+	// NoCache goes to the network though the entry is fresh...
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(200, oneChildListing, nil), nil).Once()
+	listing, err := client.GetSubredditListing(WithRequestOptions(t.Context(), NoCache()), "golang", SortNew, nil)
+	require.NoError(t, err)
+	require.Len(t, listing.Data.Children, 1)
+
+	// ...and leaves the cached entry alone
+	listing, err = client.GetSubredditListing(t.Context(), "golang", SortNew, nil)
+	require.NoError(t, err)
+	assert.Empty(t, listing.Data.Children)
+	mockHTTP.AssertNumberOfCalls(t, "Do", 2)
+}
+
+func TestRequestOptions_Timeout(t *testing.T) {
+	client, mockHTTP, _ := newRetryingTestClient(t)
+
+	// The server hangs until the request's context ends
+	mockHTTP.On("Do", mock.Anything).Return(func(req *http.Request) *http.Response {
+		<-req.Context().Done()
+		return nil
+	}, context.DeadlineExceeded).Once()
+
+	start := time.Now()
+	ctx := WithRequestOptions(t.Context(), Timeout(20*time.Millisecond))
+	_, err := client.GetSubredditListing(ctx, "golang", SortNew, nil)
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	mockHTTP.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestRequestOptions_TimeoutCoversBufferedCalls(t *testing.T) {
+	client, mockHTTP, _ := newCachingTestClient(t, time.Minute)
+
+	var deadline time.Time
+	mockHTTP.On("Do", mock.Anything).Return(func(req *http.Request) *http.Response {
+		deadline, _ = req.Context().Deadline()
+		return createHTTPResponse(200, emptyListing, nil)
+	}, nil).Once()
+
+	_, err := client.GetSubredditListing(WithRequestOptions(t.Context(), Timeout(time.Minute)), "golang", SortNew, nil)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+}
+
+func TestRequestOptions_ExtraHeader(t *testing.T) {
+	client, mockHTTP, _ := newCachingTestClient(t, time.Minute)
+
+	var sent []http.Header
+	mockHTTP.On("Do", mock.Anything).Return(func(req *http.Request) *http.Response {
+		sent = append(sent, req.Header.Clone())
+		return createHTTPResponse(200, emptyListing, nil)
+	}, nil)
+
+	ctx := WithRequestOptions(t.Context(),
+		ExtraHeader("Accept-Language", "de"),
+		ExtraHeader("User-Agent", "spoofed"),
+		ExtraHeader("Authorization", "Bearer stolen"))
+	for range 2 {
+		_, err := client.GetSubredditListing(ctx, "golang", SortNew, nil)
+		require.NoError(t, err)
+	}
+
+	// Both calls went out, as a header can change the response
+	require.Len(t, sent, 2)
+	assert.Equal(t, "de", sent[0].Get("Accept-Language"))
+	assert.Equal(t, "Bearer test-token", sent[0].Get("Authorization"))
+	assert.NotEqual(t, "spoofed", sent[0].Get("User-Agent"))
+
+	// The calls with the header cached nothing, so a plain call goes out too
+	_, err := client.GetSubredditListing(t.Context(), "golang", SortNew, nil)
+	require.NoError(t, err)
+	require.Len(t, sent, 3)
+	assert.Empty(t, sent[2].Get("Accept-Language"))
+}
+
+func TestRequestOptions_FormRequests(t *testing.T) {
+	client, mockHTTP, _ := newRetryingTestClient(t)
+	client.authMode = AuthScript
+
+	var got *http.Request
+	mockHTTP.On("Do", mock.Anything).Return(func(req *http.Request) *http.Response {
+		got = req
+		return createHTTPResponse(503, "", nil)
+	}, nil)
+
+	ctx := WithRequestOptions(t.Context(), NoRetry(), ExtraHeader("X-Trace", "abc"), Timeout(time.Minute))
+	err := client.Vote(ctx, "t3_abc", VoteUp)
+
+	require.ErrorIs(t, err, ErrServerError)
+	mockHTTP.AssertNumberOfCalls(t, "Do", 1)
+	assert.Equal(t, "abc", got.Header.Get("X-Trace"))
+	_, hasDeadline := got.Context().Deadline()
+	assert.True(t, hasDeadline)
+}
+
+func TestWithRequestOptions_Layers(t *testing.T) {
+	base := WithRequestOptions(t.Context(), NoRetry(), ExtraHeader("X-A", "1"), Timeout(time.Second))
+	layered := WithRequestOptions(base, ExtraHeader("X-B", "2"), Timeout(0))
+
+	got := callOptionsFrom(layered)
+	assert.True(t, got.noRetry)
+	assert.Zero(t, got.timeout)
+	assert.Equal(t, "1", got.header.Get("X-A"))
+	assert.Equal(t, "2", got.header.Get("X-B"))
+
+	// The outer context is unchanged
+	outer := callOptionsFrom(base)
+	assert.Equal(t, time.Second, outer.timeout)
+	assert.Empty(t, outer.header.Get("X-B"))
+
+	assert.Equal(t, callOptions{}, callOptionsFrom(t.Context()))
+}
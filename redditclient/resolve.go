@@ -0,0 +1,184 @@
+package redditclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxResolveRedirects bounds how many redirects ResolveURL follows
+const maxResolveRedirects = 5
+
+// PostRef identifies a post, and optionally a comment within it, by the
+// components of its URL
+type PostRef struct {
+	Subreddit string // Empty when the URL doesn't name a subreddit
+	PostID    string // Bare post ID, without the t3_ prefix
+	CommentID string // Bare comment ID for comment permalinks; empty otherwise
+}
+
+// ResolvePostURL returns the subreddit and post ID a reddit.com URL points to.
+// See ResolveURL for the accepted forms.
+func (c *Client) ResolvePostURL(ctx context.Context, rawURL string) (subreddit, postID string, err error) {
+	ref, err := c.ResolveURL(ctx, rawURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	return ref.Subreddit, ref.PostID, nil
+}
+
+// ResolveURL parses a post or comment URL. Canonical URLs on reddit.com and
+// its old., np., m. and www. subdomains are parsed locally; /s/ share links
+// and redd.it short links are resolved by following their redirects.
+func (c *Client) ResolveURL(ctx context.Context, rawURL string) (*PostRef, error) {
+	u, err := parseRedditURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	for range maxResolveRedirects {
+		if !isShortLink(u) {
+			if ref, ok := parsePostPath(u.Path); ok {
+				return ref, nil
+			}
+			return nil, fmt.Errorf("%w: %q is not a reddit post URL", ErrInvalidArgument, rawURL)
+		}
+
+		if u, err = c.followRedirect(ctx, u); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("too many redirects resolving %q", rawURL)
+}
+
+// ParsePostURL parses a canonical post or comment URL without making any
+// requests. Share and short links return an error; use ResolveURL for those.
+func ParsePostURL(rawURL string) (*PostRef, error) {
+	u, err := parseRedditURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isShortLink(u) {
+		if ref, ok := parsePostPath(u.Path); ok {
+			return ref, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q is not a canonical reddit post URL", ErrInvalidArgument, rawURL)
+}
+
+// followRedirect requests a short link and returns where it points, without
+// reading the response body
+func (c *Client) followRedirect(ctx context.Context, u *url.URL) (*url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.credentials().userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", u, err)
+	}
+	resp.Body.Close()
+
+	// A client that doesn't follow redirects hands back the 3xx itself
+	if isRedirect(resp.StatusCode) {
+		location, err := resp.Location()
+		if err != nil {
+			return nil, fmt.Errorf("redirect from %s has no location: %w", u, err)
+		}
+		return location, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(u.Path, resp.StatusCode, nil)
+	}
+
+	// http.Client follows redirects itself, leaving the final URL on the request
+	if resp.Request == nil || resp.Request.URL.String() == u.String() {
+		return nil, fmt.Errorf("%s did not redirect to a post", u)
+	}
+
+	return resp.Request.URL, nil
+}
+
+// isRedirect reports whether status is a redirect with a Location to follow
+func isRedirect(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// parseRedditURL parses rawURL and checks that it is on a Reddit domain
+func parseRedditURL(rawURL string) (*url.URL, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArgument, err)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host != "reddit.com" && !strings.HasSuffix(host, ".reddit.com") && host != "redd.it" {
+		return nil, fmt.Errorf("%w: %q is not a reddit URL", ErrInvalidArgument, rawURL)
+	}
+
+	return u, nil
+}
+
+// isShortLink reports whether u must be resolved over the network
+func isShortLink(u *url.URL) bool {
+	if strings.EqualFold(u.Hostname(), "redd.it") {
+		return true
+	}
+
+	segments := pathSegments(u.Path)
+	return len(segments) >= 4 && segments[0] == "r" && segments[2] == "s"
+}
+
+// parsePostPath extracts a PostRef from a comments path, which is one of
+// /r/{sub}/comments/{id}[/{slug}[/{comment}]],
+// /r/{sub}/comments/{id}/comment/{comment} or /comments/{id}[/...]
+func parsePostPath(path string) (*PostRef, bool) {
+	segments := pathSegments(path)
+
+	ref := &PostRef{}
+	if len(segments) >= 2 && segments[0] == "r" {
+		ref.Subreddit = segments[1]
+		segments = segments[2:]
+	}
+
+	if len(segments) < 2 || segments[0] != "comments" || segments[1] == "" {
+		return nil, false
+	}
+	ref.PostID = segments[1]
+
+	if len(segments) >= 4 {
+		ref.CommentID = segments[3]
+	}
+
+	return ref, true
+}
+
+// pathSegments splits a URL path into its non-empty segments
+func pathSegments(path string) []string {
+	var segments []string
+	for segment := range strings.SplitSeq(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
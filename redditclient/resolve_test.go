@@ -0,0 +1,117 @@
+package redditclient
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePostURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want PostRef
+	}{
+		{"canonical", "https://www.reddit.com/r/golang/comments/abc123/title_slug/", PostRef{Subreddit: "golang", PostID: "abc123"}},
+		{"no slug", "https://reddit.com/r/golang/comments/abc123", PostRef{Subreddit: "golang", PostID: "abc123"}},
+		{"old reddit", "https://old.reddit.com/r/golang/comments/abc123/title_slug/", PostRef{Subreddit: "golang", PostID: "abc123"}},
+		{"np reddit", "https://np.reddit.com/r/golang/comments/abc123/title_slug/", PostRef{Subreddit: "golang", PostID: "abc123"}},
+		{"query string", "https://www.reddit.com/r/golang/comments/abc123/title_slug/?utm_source=share&utm_medium=web2x", PostRef{Subreddit: "golang", PostID: "abc123"}},
+		{"no scheme", "reddit.com/r/golang/comments/abc123/", PostRef{Subreddit: "golang", PostID: "abc123"}},
+		{"comment permalink", "https://www.reddit.com/r/golang/comments/abc123/title_slug/def456/?context=3", PostRef{Subreddit: "golang", PostID: "abc123", CommentID: "def456"}},
+		{"new comment permalink", "https://www.reddit.com/r/golang/comments/abc123/comment/def456/", PostRef{Subreddit: "golang", PostID: "abc123", CommentID: "def456"}},
+		{"no subreddit", "https://www.reddit.com/comments/abc123", PostRef{PostID: "abc123"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := ParsePostURL(tt.url)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *ref)
+		})
+	}
+}
+
+func TestParsePostURL_Invalid(t *testing.T) {
+	for _, raw := range []string{
+		"https://example.com/r/golang/comments/abc123/",
+		"https://www.reddit.com/r/golang/",
+		"https://www.reddit.com/r/golang/s/XyZ",
+		"https://redd.it/abc123",
+		"https://notreddit.com/r/golang/comments/abc123/",
+	} {
+		_, err := ParsePostURL(raw)
+		assert.ErrorIs(t, err, ErrInvalidArgument, raw)
+	}
+}
+
+func TestResolvePostURL_Canonical(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	subreddit, postID, err := client.ResolvePostURL(t.Context(), "https://old.reddit.com/r/golang/comments/abc123/title_slug/")
+	require.NoError(t, err)
+	assert.Equal(t, "golang", subreddit)
+	assert.Equal(t, "abc123", postID)
+	mockHTTP.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestResolveURL_ShareLinkRedirect(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == "https://www.reddit.com/r/golang/s/XyZ"
+	})).Return(createHTTPResponse(http.StatusMovedPermanently, "", map[string]string{
+		"Location": "https://www.reddit.com/r/golang/comments/abc123/title_slug/def456/?share_id=q",
+	}), nil).Once()
+
+	ref, err := client.ResolveURL(t.Context(), "https://www.reddit.com/r/golang/s/XyZ")
+	require.NoError(t, err)
+	assert.Equal(t, PostRef{Subreddit: "golang", PostID: "abc123", CommentID: "def456"}, *ref)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestResolveURL_FollowedByHTTPClient(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	// http.Client follows redirects itself and reports the final URL on the request
+	final, err := url.Parse("https://www.reddit.com/r/golang/comments/abc123/title_slug/")
+	require.NoError(t, err)
+	resp := createHTTPResponse(http.StatusOK, "<html></html>", nil)
+	resp.Request = &http.Request{URL: final}
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Host == "redd.it"
+	})).Return(resp, nil).Once()
+
+	subreddit, postID, err := client.ResolvePostURL(t.Context(), "https://redd.it/abc123")
+	require.NoError(t, err)
+	assert.Equal(t, "golang", subreddit)
+	assert.Equal(t, "abc123", postID)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestResolveURL_RedirectLoop(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.Anything).Return(func(*http.Request) *http.Response {
+		return createHTTPResponse(http.StatusFound, "", map[string]string{"Location": "https://www.reddit.com/r/golang/s/XyZ"})
+	}, nil)
+
+	_, err := client.ResolveURL(t.Context(), "https://www.reddit.com/r/golang/s/XyZ")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "too many redirects")
+	mockHTTP.AssertNumberOfCalls(t, "Do", maxResolveRedirects)
+}
+
+func TestResolveURL_ShareLinkNotFound(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(http.StatusNotFound, "", nil), nil).Once()
+
+	_, err := client.ResolveURL(t.Context(), "https://www.reddit.com/r/golang/s/missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
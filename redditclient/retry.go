@@ -0,0 +1,141 @@
+package redditclient
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SetRetryPolicy configures automatic retries; pass RetryPolicy{} to fail fast.
+// It is safe to call while requests are in flight, which keep the policy
+// they started with.
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.retryPolicy.Store(&policy)
+}
+
+// doAPIRequestWithRetry sends an API request, retrying network errors, 429s and
+// 5xx responses according to the retry policy. The final response or error is
+// returned once attempts are exhausted, or at once for a 429 whose Retry-After
+// exceeds the policy's MaxDelay. A streamed 200 response is never retried, and
+// a form POST is retried only after a 429; see isRetryable.
+func (c *Client) doAPIRequestWithRetry(ctx context.Context, fullURL string, opts requestOptions) (*http.Request, *http.Response, []byte, error) {
+	policy := *c.retryPolicy.Load()
+	if callOptionsFrom(ctx).noRetry {
+		policy = RetryPolicy{}
+	}
+
+	for attempt := 1; ; attempt++ {
+		req, resp, body, err := c.doAPIRequest(ctx, fullURL, opts)
+
+		if attempt >= policy.MaxAttempts || ctx.Err() != nil || !isRetryable(opts.form == nil, resp, err) {
+			return req, resp, body, err
+		}
+
+		delay := policy.backoff(attempt, c.rng)
+		if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			if wait, ok := retryAfter(resp.Header, c.now()); ok {
+				// A wait beyond MaxDelay is left to the caller, whose
+				// RateLimitError says how long it is
+				if policy.MaxDelay > 0 && wait > policy.MaxDelay {
+					c.logger.DebugContext(ctx, "not retrying rate-limited request",
+						"url", fullURL,
+						"retry_after", wait,
+						"max_delay", policy.MaxDelay)
+					return req, resp, body, err
+				}
+				delay = wait
+			}
+		}
+
+		c.logger.DebugContext(ctx, "retrying request",
+			"url", fullURL,
+			"attempt", attempt,
+			"delay", delay,
+			"reason", retryReason(resp, err))
+
+		if c.telemetry != nil {
+			callFrom(ctx).retried()
+		}
+		if err := c.sleep(ctx, delay); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+}
+
+// retryReason describes a failed attempt for logging
+func retryReason(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
+
+// isRetryable reports whether a failed attempt is worth repeating. Reddit may
+// already have acted on a request that is not idempotent, such as a submission
+// or vote, when it fails with a 5xx or a transport error, so such a request is
+// repeated only after a 429, which Reddit refuses before acting on it.
+func isRetryable(idempotent bool, resp *http.Response, err error) bool {
+	if errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if !idempotent {
+		return false
+	}
+
+	return err != nil || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// backoff returns the delay before retry number attempt, with jitter in
+// [delay/2, delay] drawn from r
+func (p RetryPolicy) backoff(attempt int, r *rand.Rand) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if p.MaxDelay > 0 && (delay > p.MaxDelay || delay <= 0) {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + time.Duration(r.Int64N(int64(delay-half)+1))
+}
+
+// retryAfter reports how long a 429 response asks the client to wait. It reads
+// Retry-After, given in seconds or as an HTTP date, falling back to the seconds
+// until x-ratelimit-reset.
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	if value := strings.TrimSpace(header.Get("Retry-After")); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			return max(at.Sub(now), 0), true
+		}
+	}
+
+	if reset, ok := parseRateLimitHeader(header.Get("x-ratelimit-reset")); ok && reset >= 0 {
+		return time.Duration(reset * float64(time.Second)), true
+	}
+
+	return 0, false
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,358 @@
+package redditclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Helper function to create a client with retries enabled and a recording sleep
+func newRetryingTestClient(t *testing.T) (*Client, *MockHTTPClient, *[]time.Duration) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	client.SetRetryPolicy(DefaultRetryPolicy)
+
+	var delays []time.Duration
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+
+	return client, mockHTTP, &delays
+}
+
+const emptyListing = `{"kind": "Listing", "data": {"children": []}}`
+
+func TestRetry_TransientStatusThenSuccess(t *testing.T) {
+	for _, status := range []int{500, 502, 503, 504} {
+		t.Run(fmt.Sprint(status), func(t *testing.T) {
+			client, mockHTTP, delays := newRetryingTestClient(t)
+
+			mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+				Return(createHTTPResponse(status, "upstream error", nil), nil).Once()
+			mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+				Return(createHTTPResponse(200, emptyListing, nil), nil).Once()
+
+			_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+			require.NoError(t, err)
+			assert.Len(t, *delays, 1)
+			mockHTTP.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRetry_NetworkErrorThenSuccess(t *testing.T) {
+	client, mockHTTP, delays := newRetryingTestClient(t)
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return((*http.Response)(nil), fmt.Errorf("connection reset by peer")).Once()
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, emptyListing, nil), nil).Once()
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+	require.NoError(t, err)
+	assert.Len(t, *delays, 1)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestRetry_HonorsRetryAfter(t *testing.T) {
+	client, mockHTTP, delays := newRetryingTestClient(t)
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(429, "Too Many Requests", map[string]string{
+			"Retry-After": "7",
+		}), nil).Once()
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, emptyListing, nil), nil).Once()
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{7 * time.Second}, *delays)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestRetry_HonorsRetryAfterHTTPDate(t *testing.T) {
+	client, mockHTTP, delays := newRetryingTestClient(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return now }
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(429, "Too Many Requests", map[string]string{
+			"Retry-After": now.Add(9 * time.Second).Format(http.TimeFormat),
+		}), nil).Once()
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, emptyListing, nil), nil).Once()
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{9 * time.Second}, *delays)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestRetry_FallsBackToRateLimitReset(t *testing.T) {
+	client, mockHTTP, delays := newRetryingTestClient(t)
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(429, "Too Many Requests", map[string]string{
+			"x-ratelimit-reset": "8",
+		}), nil).Once()
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, emptyListing, nil), nil).Once()
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{8 * time.Second}, *delays)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestRetry_RetryAfterBeyondMaxDelay(t *testing.T) {
+	for _, header := range []string{"Retry-After", "x-ratelimit-reset"} {
+		t.Run(header, func(t *testing.T) {
+			client, mockHTTP, delays := newRetryingTestClient(t)
+
+			mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+				Return(createHTTPResponse(429, "Too Many Requests", map[string]string{header: "600"}), nil).Once()
+
+			_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+			// The caller learns how long to wait instead of being held for it
+			var rateErr *RateLimitError
+			require.ErrorAs(t, err, &rateErr)
+			assert.Equal(t, 10*time.Minute, rateErr.RetryAfter)
+			assert.Empty(t, *delays)
+			mockHTTP.AssertExpectations(t)
+		})
+	}
+
+	t.Run("no max delay", func(t *testing.T) {
+		client, mockHTTP, delays := newRetryingTestClient(t)
+		client.SetRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Second})
+
+		mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+			Return(createHTTPResponse(429, "Too Many Requests", map[string]string{"Retry-After": "600"}), nil).Once()
+		mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+			Return(createHTTPResponse(200, emptyListing, nil), nil).Once()
+
+		_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{10 * time.Minute}, *delays)
+	})
+}
+
+func TestRetry_RateLimitedWithoutHeadersUsesBackoff(t *testing.T) {
+	client, mockHTTP, delays := newRetryingTestClient(t)
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(429, "Too Many Requests", nil), nil).Once()
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, emptyListing, nil), nil).Once()
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+	require.NoError(t, err)
+	require.Len(t, *delays, 1)
+	assert.LessOrEqual(t, (*delays)[0], DefaultRetryPolicy.BaseDelay)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   time.Duration
+		ok     bool
+	}{
+		{"seconds", map[string]string{"Retry-After": "30"}, 30 * time.Second, true},
+		{"http date", map[string]string{"Retry-After": now.Add(time.Minute).Format(http.TimeFormat)}, time.Minute, true},
+		{"http date in the past", map[string]string{"Retry-After": now.Add(-time.Minute).Format(http.TimeFormat)}, 0, true},
+		{"ratelimit reset", map[string]string{"x-ratelimit-reset": "12.5"}, 12500 * time.Millisecond, true},
+		{"retry-after wins", map[string]string{"Retry-After": "5", "x-ratelimit-reset": "100"}, 5 * time.Second, true},
+		{"invalid retry-after falls back", map[string]string{"Retry-After": "soon", "x-ratelimit-reset": "8"}, 8 * time.Second, true},
+		{"negative seconds", map[string]string{"Retry-After": "-3"}, 0, false},
+		{"missing", nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := make(http.Header)
+			for k, v := range tt.header {
+				header.Set(k, v)
+			}
+
+			got, ok := retryAfter(header, now)
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	client, mockHTTP, delays := newRetryingTestClient(t)
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(func(*http.Request) *http.Response {
+			return createHTTPResponse(503, "Service Unavailable", nil)
+		}, nil).Times(3)
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+	assert.ErrorIs(t, err, ErrServerError)
+	assert.Len(t, *delays, 2)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestRetry_DoesNotRetryClientErrors(t *testing.T) {
+	client, mockHTTP, delays := newRetryingTestClient(t)
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(404, `{"message": "Not Found", "error": 404}`, nil), nil).Once()
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Empty(t, *delays)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestRetry_DoesNotRepeatPosts(t *testing.T) {
+	reset := errors.New("connection reset by peer")
+	tests := []struct {
+		name    string
+		resp    *http.Response
+		err     error
+		wantErr error
+	}{
+		{"bad gateway", createHTTPResponse(502, "Bad Gateway", nil), nil, ErrServerError},
+		{"connection reset", nil, reset, reset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mockHTTP, delays := newRetryingTestClient(t)
+			client.authMode = AuthScript
+
+			mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).Return(tt.resp, tt.err).Once()
+
+			_, err := client.SubmitPost(t.Context(), "golang", SubmitOptions{Kind: SubmitSelf, Title: "Hello"})
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Empty(t, *delays)
+			mockHTTP.AssertNumberOfCalls(t, "Do", 1)
+		})
+	}
+}
+
+func TestRetry_RepeatsRateLimitedPosts(t *testing.T) {
+	client, mockHTTP, delays := newRetryingTestClient(t)
+	client.authMode = AuthScript
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(429, "", map[string]string{"Retry-After": "2"}), nil).Once()
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, `{}`, nil), nil).Once()
+
+	require.NoError(t, client.Vote(t.Context(), "t3_abc", VoteUp))
+	assert.Equal(t, []time.Duration{2 * time.Second}, *delays)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestRetry_DisabledByDefault(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(503, "Service Unavailable", nil), nil).Once()
+
+	_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+	assert.ErrorIs(t, err, ErrServerError)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestRetry_StopsWhenContextCancelled(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(503, "Service Unavailable", nil), nil).Once()
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err := client.GetSubreddit(ctx, "golang", "hot")
+
+	assert.ErrorIs(t, err, context.Canceled)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestSetRetryPolicy_WhileRequesting(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	client.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).Return(func(*http.Request) *http.Response {
+		return createHTTPResponse(200, emptyListing, nil)
+	}, nil)
+
+	// Run with -race: the policy is swapped while requests read it
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				_, err := client.GetSubreddit(t.Context(), "golang", "hot")
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	for i := range 20 {
+		client.SetRetryPolicy(RetryPolicy{MaxAttempts: i % 3, BaseDelay: time.Millisecond})
+	}
+	wg.Wait()
+
+	client.SetRetryPolicy(DefaultRetryPolicy)
+	assert.Equal(t, DefaultRetryPolicy, *client.retryPolicy.Load())
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+
+	tests := []struct {
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 150 * time.Millisecond, 300 * time.Millisecond},
+		{10, 150 * time.Millisecond, 300 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			delay := policy.backoff(tt.attempt, newRand(nil))
+			assert.GreaterOrEqual(t, delay, tt.min, "attempt %d", tt.attempt)
+			assert.LessOrEqual(t, delay, tt.max, "attempt %d", tt.attempt)
+		}
+	}
+}
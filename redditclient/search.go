@@ -0,0 +1,237 @@
+package redditclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Search result types for SearchOptions.Type
+const (
+	SearchTypeLink      = "link"
+	SearchTypeSubreddit = "sr"
+	SearchTypeUser      = "user"
+)
+
+// SearchWithOptions performs a Reddit search. When opts.Subreddit is set the
+// search is restricted to that subreddit.
+func (c *Client) SearchWithOptions(ctx context.Context, query string, opts *SearchOptions) (*SearchResults, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	if opts == nil {
+		opts = &SearchOptions{}
+	}
+	if err := validateOptional(opts.Sort, opts.Sort.validate); err != nil {
+		return nil, err
+	}
+	if err := validateOptional(opts.Timeframe, opts.Timeframe.validate); err != nil {
+		return nil, err
+	}
+
+	endpoint := "/search.json"
+	params := url.Values{"q": []string{query}}
+	if opts.Subreddit != "" {
+		endpoint = fmt.Sprintf("/r/%s/search.json", url.PathEscape(opts.Subreddit))
+		params.Set("restrict_sr", "1")
+	}
+	if opts.Sort != "" {
+		params.Set("sort", string(opts.Sort))
+	}
+	if opts.Timeframe != "" {
+		params.Set("t", string(opts.Timeframe))
+	}
+	if opts.Type != "" {
+		params.Set("type", opts.Type)
+	}
+	if opts.IncludeOver18 {
+		params.Set("include_over_18", "on")
+	}
+	opts.ListingOptions.apply(params)
+
+	body, err := c.makeAPIRequest(ctx, endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var listing struct {
+		Data struct {
+			Children []rawThing `json:"children"`
+			After    string     `json:"after"`
+			Before   string     `json:"before"`
+			Dist     int        `json:"dist"`
+		} `json:"data"`
+	}
+	if err := c.decodeJSON(ctx, body, &listing, "search results"); err != nil {
+		return nil, err
+	}
+
+	results := &SearchResults{
+		After:  listing.Data.After,
+		Before: listing.Data.Before,
+		Dist:   listing.Data.Dist,
+	}
+	for _, child := range listing.Data.Children {
+		switch child.Kind {
+		case KindPost:
+			var post Post
+			if err := json.Unmarshal(child.Data, &post); err != nil {
+				return nil, fmt.Errorf("failed to decode post search result: %w", err)
+			}
+			results.Posts = append(results.Posts, post)
+		case KindSubreddit:
+			var sub SubredditAbout
+			if err := json.Unmarshal(child.Data, &sub); err != nil {
+				return nil, fmt.Errorf("failed to decode subreddit search result: %w", err)
+			}
+			results.Subreddits = append(results.Subreddits, sub)
+		case KindUser:
+			var user UserResponse
+			user.Kind = child.Kind
+			if err := json.Unmarshal(child.Data, &user.Data); err != nil {
+				return nil, fmt.Errorf("failed to decode user search result: %w", err)
+			}
+			results.Users = append(results.Users, user)
+		}
+	}
+
+	return results, nil
+}
+
+// SearchAll returns an iterator over every post matching a search,
+// following the After cursor like SearchPosts. Reddit's pages sometimes
+// overlap, so a post already yielded is skipped. Reddit serves only about
+// maxSearchResults results per search; when that is why iteration ends,
+// ErrSearchExhausted is yielded last, telling the caller that more posts may
+// match than Reddit will return. opts.MaxItems caps the posts yielded and
+// opts.MaxPages the requests made, without yielding ErrSearchExhausted.
+func (c *Client) SearchAll(ctx context.Context, query string, opts *SearchOptions) iter.Seq2[Post, error] {
+	return func(yield func(Post, error) bool) {
+		var search SearchOptions
+		if opts != nil {
+			search = *opts
+		}
+		page := &search.ListingOptions
+
+		seen := make(map[string]bool)
+		pages, served := 0, 0
+		for {
+			if page.MaxPages > 0 && pages >= page.MaxPages {
+				return
+			}
+			if err := ctx.Err(); err != nil {
+				yield(Post{}, err)
+				return
+			}
+
+			results, err := c.SearchWithOptions(ctx, query, &search)
+			if err != nil {
+				yield(Post{}, err)
+				return
+			}
+			pages++
+			// Reddit's limit counts every result it serves, duplicates included
+			n := len(results.Posts) + len(results.Subreddits) + len(results.Users)
+			served += n
+
+			for _, post := range results.Posts {
+				name := post.Name
+				if name == "" {
+					name = KindPost + "_" + post.ID
+				}
+				if seen[name] {
+					continue
+				}
+				seen[name] = true
+
+				if !yield(post, nil) {
+					return
+				}
+				if page.MaxItems > 0 && len(seen) >= page.MaxItems {
+					return
+				}
+			}
+
+			if served >= maxSearchResults {
+				yield(Post{}, ErrSearchExhausted)
+				return
+			}
+			if n == 0 || results.After == "" || results.After == page.After {
+				return
+			}
+
+			page.After = results.After
+			page.Before = ""
+			page.Count = served
+		}
+	}
+}
+
+// SearchSubreddits returns subreddit name suggestions for a partial query, as
+// used by "go to subreddit" boxes. An empty query returns no suggestions
+// without calling the API.
+func (c *Client) SearchSubreddits(ctx context.Context, query string, opts *SubredditSearchOptions) ([]SubredditSuggestion, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	if strings.TrimSpace(query) == "" {
+		return []SubredditSuggestion{}, nil
+	}
+
+	if opts == nil {
+		opts = &SubredditSearchOptions{}
+	}
+
+	params := url.Values{
+		"query":            []string{query},
+		"include_over_18":  []string{strconv.FormatBool(opts.IncludeOver18)},
+		"include_profiles": []string{strconv.FormatBool(opts.IncludeProfiles)},
+	}
+	if opts.Limit > 0 {
+		params.Set("limit", strconv.Itoa(opts.Limit))
+	}
+
+	body, err := c.makeAPIRequest(ctx, "/api/subreddit_autocomplete_v2.json", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var listing struct {
+		Data struct {
+			Children []struct {
+				Kind string         `json:"kind"`
+				Data SubredditAbout `json:"data"`
+			} `json:"children"`
+		} `json:"data"`
+	}
+	if err := c.decodeJSON(ctx, body, &listing, "subreddit suggestions"); err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]SubredditSuggestion, 0, len(listing.Data.Children))
+	for _, child := range listing.Data.Children {
+		if child.Kind != KindSubreddit {
+			continue
+		}
+
+		icon := child.Data.IconImg
+		if icon == "" {
+			icon = child.Data.CommunityIcon
+		}
+
+		suggestions = append(suggestions, SubredditSuggestion{
+			Name:        child.Data.DisplayName,
+			Subscribers: child.Data.Subscribers,
+			Icon:        icon,
+			Over18:      child.Data.Over18,
+		})
+	}
+
+	return suggestions, nil
+}
@@ -0,0 +1,248 @@
+package redditclient
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchWithOptions_RestrictedToSubreddit(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		params := req.URL.Query()
+		return req.URL.Path == "/r/golang/search.json" &&
+			params.Get("q") == "generics" &&
+			params.Get("restrict_sr") == "1" &&
+			params.Get("sort") == "top" &&
+			params.Get("t") == "month" &&
+			params.Get("type") == "link" &&
+			params.Get("include_over_18") == "on" &&
+			params.Get("limit") == "50" &&
+			params.Get("after") == "t3_prev"
+	})).Return(createHTTPResponse(200, createListingPage([]string{"p1", "p2"}, "t3_p2"), nil), nil)
+
+	results, err := client.SearchWithOptions(t.Context(), "generics", &SearchOptions{
+		ListingOptions: ListingOptions{After: "t3_prev", Limit: 50},
+		Subreddit:      "golang",
+		Sort:           "top",
+		Timeframe:      "month",
+		Type:           SearchTypeLink,
+		IncludeOver18:  true,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, results.Posts, 2)
+	assert.Equal(t, "p1", results.Posts[0].ID)
+	assert.Equal(t, "t3_p2", results.After)
+	assert.Empty(t, results.Subreddits)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestSearchWithOptions_GlobalByDefault(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		params := req.URL.Query()
+		return req.URL.Path == "/search.json" &&
+			params.Get("q") == "golang" &&
+			!params.Has("restrict_sr") &&
+			!params.Has("include_over_18")
+	})).Return(createHTTPResponse(200, createListingPage(nil, ""), nil), nil)
+
+	results, err := client.SearchWithOptions(t.Context(), "golang", nil)
+
+	require.NoError(t, err)
+	assert.Empty(t, results.Posts)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestSearchWithOptions_SubredditResults(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	body := `{"kind": "Listing", "data": {"after": "t5_2", "children": [
+		{"kind": "t5", "data": {"display_name": "golang", "subscribers": 250000, "public_description": "Go", "over18": false}},
+		{"kind": "t5", "data": {"display_name": "golang_jobs", "subscribers": 1200, "over18": false}}
+	]}}`
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("type") == "sr"
+	})).Return(createHTTPResponse(200, body, nil), nil)
+
+	results, err := client.SearchWithOptions(t.Context(), "golang", &SearchOptions{Type: SearchTypeSubreddit})
+
+	require.NoError(t, err)
+	assert.Empty(t, results.Posts)
+	require.Len(t, results.Subreddits, 2)
+	assert.Equal(t, "golang", results.Subreddits[0].DisplayName)
+	assert.Equal(t, 250000, results.Subreddits[0].Subscribers)
+	assert.Equal(t, "Go", results.Subreddits[0].PublicDescription)
+	assert.Equal(t, "t5_2", results.After)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestSearchWithOptions_UserResults(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	body := `{"kind": "Listing", "data": {"children": [
+		{"kind": "t2", "data": {"name": "gopher", "link_karma": 10, "comment_karma": 20}}
+	]}}`
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, body, nil), nil)
+
+	results, err := client.SearchWithOptions(t.Context(), "gopher", &SearchOptions{Type: SearchTypeUser})
+
+	require.NoError(t, err)
+	require.Len(t, results.Users, 1)
+	assert.Equal(t, KindUser, results.Users[0].Kind)
+	assert.Equal(t, "gopher", results.Users[0].Data.Name)
+	assert.Equal(t, 20, results.Users[0].Data.CommentKarma)
+}
+
+func TestSearchWithOptions_NotAuthenticated(t *testing.T) {
+	client, err := NewClient(WithHTTPClient(&MockHTTPClient{}))
+	require.NoError(t, err)
+
+	_, err = client.SearchWithOptions(t.Context(), "golang", nil)
+
+	assert.ErrorIs(t, err, ErrNotAuthenticated)
+}
+
+func TestSearchSubreddits_Success(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	body := `{"kind": "Listing", "data": {"children": [
+		{"kind": "t5", "data": {"display_name": "golang", "subscribers": 250000, "icon_img": "https://example.com/go.png", "over18": false}},
+		{"kind": "t5", "data": {"display_name": "gonewild", "subscribers": 4000000, "icon_img": "", "community_icon": "https://example.com/community.png", "over18": true}},
+		{"kind": "t2", "data": {"name": "gopher"}}
+	]}}`
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		params := req.URL.Query()
+		return req.URL.Path == "/api/subreddit_autocomplete_v2.json" &&
+			params.Get("query") == "go" &&
+			params.Get("include_over_18") == "true" &&
+			params.Get("include_profiles") == "false" &&
+			params.Get("limit") == "10"
+	})).Return(createHTTPResponse(200, body, nil), nil)
+
+	suggestions, err := client.SearchSubreddits(t.Context(), "go", &SubredditSearchOptions{IncludeOver18: true, Limit: 10})
+
+	require.NoError(t, err)
+	assert.Equal(t, []SubredditSuggestion{
+		{Name: "golang", Subscribers: 250000, Icon: "https://example.com/go.png"},
+		{Name: "gonewild", Subscribers: 4000000, Icon: "https://example.com/community.png", Over18: true},
+	}, suggestions)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestSearchSubreddits_EmptyQuery(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	suggestions, err := client.SearchSubreddits(t.Context(), "  ", nil)
+
+	require.NoError(t, err)
+	assert.NotNil(t, suggestions)
+	assert.Empty(t, suggestions)
+	mockHTTP.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestSearchSubreddits_NoResults(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		params := req.URL.Query()
+		return params.Get("include_over_18") == "false" && params.Get("include_profiles") == "false"
+	})).Return(createHTTPResponse(200, `{"kind": "Listing", "data": {"children": []}}`, nil), nil)
+
+	suggestions, err := client.SearchSubreddits(t.Context(), "zzzz", nil)
+
+	require.NoError(t, err)
+	assert.NotNil(t, suggestions)
+	assert.Empty(t, suggestions)
+	mockHTTP.AssertExpectations(t)
+}
+
+// searchPages serves search results p0, p1, ... in pages of size, each page
+// starting at the post after the "after" cursor. overlap repeats that many
+// posts from the end of the previous page, as Reddit sometimes does.
+func searchPages(total, size, overlap int) func(*http.Request) *http.Response {
+	return func(req *http.Request) *http.Response {
+		start := 0
+		if after := req.URL.Query().Get("after"); after != "" {
+			start, _ = strconv.Atoi(strings.TrimPrefix(after, "t3_p"))
+			start = max(start+1-overlap, 0)
+		}
+		end := min(start+size, total)
+
+		var ids []string
+		for i := start; i < end; i++ {
+			ids = append(ids, fmt.Sprintf("p%d", i))
+		}
+		after := ""
+		if end < total {
+			after = fmt.Sprintf("t3_p%d", end-1)
+		}
+		return createHTTPResponse(200, createListingPage(ids, after), nil)
+	}
+}
+
+func TestSearchAll_Deduplicates(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	mockHTTP.On("Do", mock.Anything).Return(searchPages(7, 3, 1), nil)
+
+	ids, err := drainIDs(client.SearchAll(t.Context(), "generics", nil), func(p Post) string { return p.ID })
+	require.NoError(t, err, "a search that runs out of matches is not exhausted")
+	assert.Equal(t, []string{"p0", "p1", "p2", "p3", "p4", "p5", "p6"}, ids)
+}
+
+func TestSearchAll_Exhausted(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	mockHTTP.On("Do", mock.Anything).Return(searchPages(1000, 100, 0), nil)
+
+	ids, err := drainIDs(client.SearchAll(t.Context(), "generics", &SearchOptions{
+		ListingOptions: ListingOptions{Limit: 100},
+	}), func(p Post) string { return p.ID })
+	require.ErrorIs(t, err, ErrSearchExhausted)
+	assert.Len(t, ids, 300)
+	mockHTTP.AssertNumberOfCalls(t, "Do", 3)
+}
+
+func TestSearchAll_CountsDuplicatesTowardsLimit(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	mockHTTP.On("Do", mock.Anything).Return(searchPages(1000, 100, 50), nil)
+
+	ids, err := drainIDs(client.SearchAll(t.Context(), "generics", nil), func(p Post) string { return p.ID })
+	require.ErrorIs(t, err, ErrSearchExhausted)
+	assert.Len(t, ids, 200, "three pages overlapping by half hold 200 distinct posts")
+	assert.Equal(t, "p199", ids[len(ids)-1])
+}
+
+func TestSearchAll_MaxItems(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	mockHTTP.On("Do", mock.Anything).Return(searchPages(1000, 25, 5), nil)
+
+	ids, err := drainIDs(client.SearchAll(t.Context(), "generics", &SearchOptions{
+		ListingOptions: ListingOptions{MaxItems: 30},
+	}), func(p Post) string { return p.ID })
+	require.NoError(t, err, "stopping at the caller's cap is not exhaustion")
+	assert.Len(t, ids, 30)
+	assert.Equal(t, "p29", ids[29])
+}
+
+func TestSearchAll_Error(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(200, createListingPage([]string{"a"}, "t3_a"), nil), nil).Once()
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(404, "", nil), nil).Once()
+
+	ids, err := drainIDs(client.SearchAll(t.Context(), "generics", nil), func(p Post) string { return p.ID })
+	require.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, []string{"a"}, ids)
+}
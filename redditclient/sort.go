@@ -0,0 +1,161 @@
+package redditclient
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Sort is the ordering of a post listing
+type Sort string
+
+// Listing sorts. SortBest is only meaningful on the front page.
+const (
+	SortHot           Sort = "hot"
+	SortNew           Sort = "new"
+	SortRising        Sort = "rising"
+	SortTop           Sort = "top"
+	SortControversial Sort = "controversial"
+	SortBest          Sort = "best"
+)
+
+var sorts = []Sort{SortHot, SortNew, SortRising, SortTop, SortControversial, SortBest}
+
+// Timeframe is the window used by top and controversial orderings
+type Timeframe string
+
+// Timeframes accepted by top/controversial listings and search
+const (
+	TimeframeHour  Timeframe = "hour"
+	TimeframeDay   Timeframe = "day"
+	TimeframeWeek  Timeframe = "week"
+	TimeframeMonth Timeframe = "month"
+	TimeframeYear  Timeframe = "year"
+	TimeframeAll   Timeframe = "all"
+)
+
+var timeframes = []Timeframe{TimeframeHour, TimeframeDay, TimeframeWeek, TimeframeMonth, TimeframeYear, TimeframeAll}
+
+// DefaultTimeframe is the window Reddit ranks top and controversial listings
+// over when none is given
+const DefaultTimeframe = TimeframeDay
+
+// CommentSort is the ordering of a comment thread
+type CommentSort string
+
+// Comment sorts. CommentSortConfidence is what Reddit calls "best".
+const (
+	CommentSortConfidence    CommentSort = "confidence"
+	CommentSortTop           CommentSort = "top"
+	CommentSortNew           CommentSort = "new"
+	CommentSortControversial CommentSort = "controversial"
+	CommentSortOld           CommentSort = "old"
+	CommentSortQA            CommentSort = "qa"
+)
+
+var commentSorts = []CommentSort{
+	CommentSortConfidence, CommentSortTop, CommentSortNew,
+	CommentSortControversial, CommentSortOld, CommentSortQA,
+}
+
+// SearchSort is the ordering of search results
+type SearchSort string
+
+// Search sorts
+const (
+	SearchSortRelevance SearchSort = "relevance"
+	SearchSortHot       SearchSort = "hot"
+	SearchSortTop       SearchSort = "top"
+	SearchSortNew       SearchSort = "new"
+	SearchSortComments  SearchSort = "comments"
+)
+
+var searchSorts = []SearchSort{SearchSortRelevance, SearchSortHot, SearchSortTop, SearchSortNew, SearchSortComments}
+
+// DuplicatesSort is the ordering of a post's other discussions
+type DuplicatesSort string
+
+// Duplicates sorts
+const (
+	DuplicatesSortComments DuplicatesSort = "num_comments"
+	DuplicatesSortNew      DuplicatesSort = "new"
+)
+
+var duplicatesSorts = []DuplicatesSort{DuplicatesSortComments, DuplicatesSortNew}
+
+// ParseSort converts s to a Sort, rejecting unknown values
+func ParseSort(s string) (Sort, error) {
+	return Sort(s), Sort(s).validate()
+}
+
+// ParseTimeframe converts s to a Timeframe, rejecting unknown values
+func ParseTimeframe(s string) (Timeframe, error) {
+	return Timeframe(s), Timeframe(s).validate()
+}
+
+// ParseCommentSort converts s to a CommentSort, rejecting unknown values
+func ParseCommentSort(s string) (CommentSort, error) {
+	return CommentSort(s), CommentSort(s).validate()
+}
+
+// ParseSearchSort converts s to a SearchSort, rejecting unknown values
+func ParseSearchSort(s string) (SearchSort, error) {
+	return SearchSort(s), SearchSort(s).validate()
+}
+
+// ParseDuplicatesSort converts s to a DuplicatesSort, rejecting unknown values
+func ParseDuplicatesSort(s string) (DuplicatesSort, error) {
+	return DuplicatesSort(s), DuplicatesSort(s).validate()
+}
+
+// Valid reports whether s is a known listing sort
+func (s Sort) Valid() bool { return s.validate() == nil }
+
+// Valid reports whether t is a known timeframe
+func (t Timeframe) Valid() bool { return t.validate() == nil }
+
+// Valid reports whether s is a known comment sort
+func (s CommentSort) Valid() bool { return s.validate() == nil }
+
+// Valid reports whether s is a known search sort
+func (s SearchSort) Valid() bool { return s.validate() == nil }
+
+// Valid reports whether s is a known duplicates sort
+func (s DuplicatesSort) Valid() bool { return s.validate() == nil }
+
+func (s Sort) validate() error { return validateEnum("sort", s, sorts) }
+
+// takesTimeframe reports whether s ranks posts within a Timeframe
+func (s Sort) takesTimeframe() bool { return s == SortTop || s == SortControversial }
+
+func (t Timeframe) validate() error { return validateEnum("timeframe", t, timeframes) }
+
+func (s CommentSort) validate() error { return validateEnum("comment sort", s, commentSorts) }
+
+func (s SearchSort) validate() error { return validateEnum("search sort", s, searchSorts) }
+
+func (s DuplicatesSort) validate() error { return validateEnum("duplicates sort", s, duplicatesSorts) }
+
+// validateOptional is like validate but accepts the empty value, which leaves
+// the choice to Reddit
+func validateOptional[T ~string](v T, validate func() error) error {
+	if v == "" {
+		return nil
+	}
+	return validate()
+}
+
+// validateEnum returns an error wrapping ErrInvalidArgument if v is not in valid
+func validateEnum[T ~string](name string, v T, valid []T) error {
+	for _, candidate := range valid {
+		if v == candidate {
+			return nil
+		}
+	}
+
+	names := make([]string, len(valid))
+	for i, candidate := range valid {
+		names[i] = string(candidate)
+	}
+
+	return fmt.Errorf("%w: %s %q is not one of %s", ErrInvalidArgument, name, v, strings.Join(names, ", "))
+}
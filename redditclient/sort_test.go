@@ -0,0 +1,166 @@
+package redditclient
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSort(t *testing.T) {
+	sort, err := ParseSort("controversial")
+	require.NoError(t, err)
+	assert.Equal(t, SortControversial, sort)
+
+	_, err = ParseSort("controversal")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+	assert.Contains(t, err.Error(), `"controversal"`)
+	assert.Contains(t, err.Error(), "controversial")
+
+	_, err = ParseSort("")
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+func TestParseTimeframe(t *testing.T) {
+	for _, tf := range []string{"hour", "day", "week", "month", "year", "all"} {
+		parsed, err := ParseTimeframe(tf)
+		require.NoError(t, err)
+		assert.True(t, parsed.Valid())
+	}
+
+	_, err := ParseTimeframe("fortnight")
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+func TestParseCommentSort(t *testing.T) {
+	for _, s := range []string{"confidence", "top", "new", "controversial", "old", "qa"} {
+		_, err := ParseCommentSort(s)
+		require.NoError(t, err)
+	}
+
+	_, err := ParseCommentSort("best")
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+}
+
+func TestInvalidSortRejectedBeforeRequest(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"GetSubreddit", func() error {
+			_, err := client.GetSubreddit(t.Context(), "golang", "controversal")
+			return err
+		}},
+		{"GetSubredditListing", func() error {
+			_, err := client.GetSubredditListing(t.Context(), "golang", Sort("newest"), nil)
+			return err
+		}},
+		{"GetComments", func() error {
+			_, err := client.GetComments(t.Context(), "golang", "abc123", "oldest")
+			return err
+		}},
+		{"Search sort", func() error {
+			_, err := client.Search(t.Context(), "golang", "popular", "")
+			return err
+		}},
+		{"Search timeframe", func() error {
+			_, err := client.Search(t.Context(), "golang", "top", "decade")
+			return err
+		}},
+		{"SearchWithOptions", func() error {
+			_, err := client.SearchWithOptions(t.Context(), "golang", &SearchOptions{Timeframe: "decade"})
+			return err
+		}},
+		{"GetSubredditTop timeframe", func() error {
+			_, err := client.GetSubredditTop(t.Context(), "golang", Timeframe("decade"), nil)
+			return err
+		}},
+		{"GetSubredditControversial timeframe", func() error {
+			_, err := client.GetSubredditControversial(t.Context(), "golang", Timeframe("forever"), nil)
+			return err
+		}},
+		{"GetUserPosts", func() error {
+			_, err := client.GetUserPosts(t.Context(), "spez", "top", "forever", nil)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.call(), ErrInvalidArgument)
+		})
+	}
+
+	mockHTTP.AssertNotCalled(t, "Do", mock.Anything)
+}
+
+func TestGetSubredditListing_TypedSort(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/r/golang/rising.json"
+	})).Return(createHTTPResponse(200, createListingPage([]string{"a"}, ""), nil), nil).Once()
+
+	listing, err := client.GetSubredditListing(t.Context(), "golang", SortRising, nil)
+	require.NoError(t, err)
+	require.Len(t, listing.Data.Children, 1)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestSubredditListing_Timeframe(t *testing.T) {
+	tests := []struct {
+		name     string
+		call     func(c *Client) (*SubredditListing, error)
+		wantPath string
+		wantT    string
+	}{
+		{"top of all time", func(c *Client) (*SubredditListing, error) {
+			return c.GetSubredditTop(t.Context(), "golang", TimeframeAll, &ListingOptions{Limit: 10})
+		}, "/r/golang/top.json", "all"},
+		{"controversial this week", func(c *Client) (*SubredditListing, error) {
+			return c.GetSubredditControversial(t.Context(), "golang", TimeframeWeek, nil)
+		}, "/r/golang/controversial.json", "week"},
+		{"top without a timeframe", func(c *Client) (*SubredditListing, error) {
+			return c.GetSubredditTop(t.Context(), "golang", "", nil)
+		}, "/r/golang/top.json", "day"},
+		{"generic top defaults to day", func(c *Client) (*SubredditListing, error) {
+			return c.GetSubredditListing(t.Context(), "golang", SortTop, nil)
+		}, "/r/golang/top.json", "day"},
+		{"string-typed controversial defaults to day", func(c *Client) (*SubredditListing, error) {
+			return c.GetSubreddit(t.Context(), "golang", "controversial")
+		}, "/r/golang/controversial.json", "day"},
+		{"combined top defaults to day", func(c *Client) (*SubredditListing, error) {
+			return c.GetCombinedSubreddits(t.Context(), []string{"golang", "rust"}, "top", nil)
+		}, "/r/golang+rust/top.json", "day"},
+		{"front page top defaults to day", func(c *Client) (*SubredditListing, error) {
+			return c.GetFrontpage(t.Context(), "top", nil)
+		}, "/top.json", "day"},
+		{"new takes no timeframe", func(c *Client) (*SubredditListing, error) {
+			return c.GetSubredditListing(t.Context(), "golang", SortNew, nil)
+		}, "/r/golang/new.json", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mockHTTP := newAuthenticatedTestClient(t)
+
+			var got *http.Request
+			mockHTTP.On("Do", mock.Anything).Return(func(req *http.Request) *http.Response {
+				got = req
+				return createHTTPResponse(200, emptyListing, nil)
+			}, nil).Once()
+
+			_, err := tt.call(client)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPath, got.URL.Path)
+			query := got.URL.Query()
+			assert.Equal(t, tt.wantT, query.Get("t"))
+			assert.Equal(t, tt.wantT != "", query.Has("t"))
+		})
+	}
+}
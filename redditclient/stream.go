@@ -0,0 +1,313 @@
+package redditclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+)
+
+// streamAPIRequest is makeAPIRequest for large payloads: the response is decoded
+// into v straight from the network rather than buffered in full first.
+// what names the payload in decode errors.
+func (c *Client) streamAPIRequest(ctx context.Context, endpoint string, params url.Values, v any, what string) (err error) {
+	perCall := callOptionsFrom(ctx)
+
+	// Caching and coalescing share the whole body, so such clients fall back to buffering
+	if (c.cache != nil || c.flight != nil) && !perCall.private() {
+		entry, err := c.apiEntry(ctx, endpoint, params)
+		if err != nil {
+			return err
+		}
+		return c.decodeRestricted(ctx, entry.Body, v, what, entry.Restriction)
+	}
+
+	ctx, call := c.startCall(ctx, http.MethodGet, endpoint, params)
+	defer func() { call.end(c, err) }()
+
+	ctx, cancel := perCall.withTimeout(ctx)
+	defer cancel()
+
+	req, resp, _, err := c.sendAPIRequest(ctx, endpoint, params, requestOptions{stream: true})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := c.openResponseBody(resp)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	defer body.Close()
+
+	br := bufio.NewReaderSize(body, maxRestrictionSniff)
+	notice, err := sniffRestriction(br)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if notice.Reason != "" {
+		data, restriction, err := c.handleRestrictedContent(ctx, req, notice)
+		if err != nil {
+			return err
+		}
+		return c.decodeRestricted(ctx, data, v, what, restriction)
+	}
+	if err := sniffJSONErrors(br, endpoint); err != nil {
+		prefix, _ := br.Peek(maxRestrictionSniff)
+		return c.withErrorBody(err, prefix)
+	}
+
+	// Keep the start of the body for logging, as decoding overwrites the buffer
+	sample, _ := br.Peek(maxLogBodySample + 1)
+	sample = slices.Clone(sample)
+
+	if err := c.decodeJSONStream(ctx, br, v, what, sample); err != nil {
+		return err
+	}
+
+	// Read to EOF so the decoder is verified and can be pooled
+	_, _ = io.Copy(io.Discard, br)
+
+	return nil
+}
+
+// restrictable is implemented by payloads that report the content warning
+// they were fetched past, such as listings
+type restrictable interface {
+	setRestriction(r Restriction)
+}
+
+// decodeRestricted is decodeJSON that also records restriction on v, if v
+// has a place for it
+func (c *Client) decodeRestricted(ctx context.Context, body []byte, v any, what string, restriction Restriction) error {
+	if err := c.decodeJSON(ctx, body, v, what); err != nil {
+		return err
+	}
+	if r, ok := v.(restrictable); ok && restriction != "" {
+		r.setRestriction(restriction)
+	}
+	return nil
+}
+
+// sniffRestriction returns a restriction notice such as {"reason":
+// "quarantined"}, or a zero ErrorResponse if the body is not one. Nothing is
+// consumed from br.
+func sniffRestriction(br *bufio.Reader) (ErrorResponse, error) {
+	prefix, err := br.Peek(maxRestrictionSniff)
+	if err == nil {
+		return ErrorResponse{}, nil
+	}
+	if !errors.Is(err, io.EOF) {
+		return ErrorResponse{}, err
+	}
+	return restrictionNotice(prefix), nil
+}
+
+// restrictionNotice returns body as a restriction notice, or a zero
+// ErrorResponse if it is not one. Notices are small, so a body of
+// maxRestrictionSniff bytes or more is not parsed at all.
+func restrictionNotice(body []byte) ErrorResponse {
+	var notice ErrorResponse
+	if len(body) >= maxRestrictionSniff || json.Unmarshal(body, &notice) != nil {
+		return ErrorResponse{}
+	}
+	return notice
+}
+
+// streamDecoder is implemented by payloads that decode themselves from a token
+// stream. encoding/json buffers a whole value before unmarshaling it, so large
+// listings walk their structure and decode one child at a time instead.
+type streamDecoder interface {
+	decodeStream(dec *json.Decoder) error
+}
+
+// sniffJSONErrors returns the json.errors error of a body small enough to fit
+// within maxRestrictionSniff, without consuming anything from br
+func sniffJSONErrors(br *bufio.Reader, endpoint string) error {
+	prefix, err := br.Peek(maxRestrictionSniff)
+	if !errors.Is(err, io.EOF) {
+		return nil
+	}
+	return jsonErrors(endpoint, prefix)
+}
+
+// decodeStream decodes a listing, buffering at most one child at a time
+func (l *Listing[C]) decodeStream(dec *json.Decoder) error {
+	return decodeObject(dec, func(key string) error {
+		switch key {
+		case "kind":
+			return dec.Decode(&l.Kind)
+		case "data":
+			return l.Data.decodeStream(dec)
+		default:
+			return skipValue(dec)
+		}
+	})
+}
+
+// decodeStream decodes listing data, streaming its children. Reddit sends
+// dist, the number of children, ahead of them, so the slice is sized once.
+func (d *ListingData[C]) decodeStream(dec *json.Decoder) error {
+	return decodeObject(dec, func(key string) error {
+		switch key {
+		case "after":
+			return dec.Decode(&d.After)
+		case "before":
+			return dec.Decode(&d.Before)
+		case "dist":
+			return dec.Decode(&d.Dist)
+		case "modhash":
+			return dec.Decode(&d.Modhash)
+		case "geo_filter":
+			return dec.Decode(&d.GeoFilter)
+		case "children":
+			return d.decodeChildren(dec)
+		default:
+			return skipValue(dec)
+		}
+	})
+}
+
+// decodeChildren decodes the children array one child at a time, sizing the
+// slice from Dist when the listing sent it first
+func (d *ListingData[C]) decodeChildren(dec *json.Decoder) error {
+	size := defaultChildrenCap
+	if d.Dist > 0 {
+		size = min(d.Dist, maxChildrenCap)
+	}
+
+	present, err := decodeArray(dec, func() error {
+		if d.Children == nil {
+			d.Children = make([]C, 0, size)
+		}
+		d.Children = append(d.Children, *new(C))
+		return dec.Decode(&d.Children[len(d.Children)-1])
+	})
+	// Match json.Unmarshal, which gives [] an empty rather than nil slice
+	if present && d.Children == nil {
+		d.Children = []C{}
+	}
+	return err
+}
+
+// decodeStream decodes the [post listing, comment listing] array
+func (r *PostAndCommentsResponse) decodeStream(dec *json.Decoder) error {
+	var post PostListing
+	var comments CommentListing
+	err := decodePairStream(dec, "post and comments response", func(i int) error {
+		if i == 0 {
+			if err := post.decodeStream(dec); err != nil {
+				return fmt.Errorf("failed to decode post listing: %w", err)
+			}
+			return nil
+		}
+		if err := comments.decodeStream(dec); err != nil {
+			return fmt.Errorf("failed to decode comment listing: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := checkPostListing(&post); err != nil {
+		return err
+	}
+	if err := checkCommentListing(&comments); err != nil {
+		return err
+	}
+
+	r.PostListing = post
+	r.CommentListing = comments
+	return nil
+}
+
+// decodePairStream walks a two-element array response, calling elem with the
+// decoder positioned at each element. what names the response in errors.
+func decodePairStream(dec *json.Decoder, what string, elem func(i int) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("%s is not an array: %w", what, err)
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("%s is not an array: got %v", what, tok)
+	}
+
+	n := 0
+	for ; dec.More(); n++ {
+		if n >= 2 {
+			if err := skipValue(dec); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := elem(n); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	if n != 2 {
+		return fmt.Errorf("%s has %d elements, expected 2", what, n)
+	}
+
+	return nil
+}
+
+// decodeObject walks a JSON object, calling field with the decoder positioned
+// at each key's value. A null object is left undecoded.
+func decodeObject(dec *json.Decoder, field func(key string) error) error {
+	tok, err := dec.Token()
+	if err != nil || tok == nil {
+		return err
+	}
+	if tok != json.Delim('{') {
+		return fmt.Errorf("expected object, got %v", tok)
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		if err := field(key); err != nil {
+			return err
+		}
+	}
+
+	_, err = dec.Token()
+	return err
+}
+
+// decodeArray walks a JSON array, calling elem with the decoder positioned at
+// each element. It reports false for a null array, which is left undecoded.
+func decodeArray(dec *json.Decoder, elem func() error) (bool, error) {
+	tok, err := dec.Token()
+	if err != nil || tok == nil {
+		return false, err
+	}
+	if tok != json.Delim('[') {
+		return false, fmt.Errorf("expected array, got %v", tok)
+	}
+
+	for dec.More() {
+		if err := elem(); err != nil {
+			return true, err
+		}
+	}
+
+	_, err = dec.Token()
+	return true, err
+}
+
+// skipValue consumes and discards the next JSON value
+func skipValue(dec *json.Decoder) error {
+	var raw json.RawMessage
+	return dec.Decode(&raw)
+}
@@ -0,0 +1,166 @@
+package redditclient
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSniffRestriction(t *testing.T) {
+	large := `{"reason": "quarantined", "padding": "` + strings.Repeat("x", maxRestrictionSniff) + `"}`
+
+	tests := []struct {
+		name   string
+		body   string
+		reason string
+	}{
+		{name: "notice", body: `{"reason": "quarantined"}`, reason: "quarantined"},
+		{name: "listing", body: emptyListing},
+		{name: "comments array", body: `[{"kind": "Listing"}]`},
+		{name: "empty", body: ""},
+		{name: "larger than sniff buffer", body: large},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			br := bufio.NewReaderSize(strings.NewReader(tt.body), maxRestrictionSniff)
+
+			notice, err := sniffRestriction(br)
+			require.NoError(t, err)
+			assert.Equal(t, tt.reason, notice.Reason)
+
+			// Sniffing must leave the whole body for the decoder
+			rest, err := io.ReadAll(br)
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, string(rest))
+		})
+	}
+}
+
+func TestGetComments_StreamsLargeThread(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	body := largeCommentThread(200)
+	require.Greater(t, len(body), maxRestrictionSniff)
+
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, string(compress(t, "gzip", []byte(body))), map[string]string{
+			"Content-Encoding": "gzip",
+		}), nil).Once()
+
+	result, err := client.GetComments(t.Context(), "golang", "abc123", "")
+
+	require.NoError(t, err)
+	require.Len(t, result.PostListing.Data.Children, 1)
+	assert.Len(t, result.CommentListing.Data.Children, 200)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGetComments_QuarantinedRetriesWithCookie(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("Cookie") == ""
+	})).Return(createHTTPResponse(200, `{"reason": "quarantined"}`, nil), nil).Once()
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("Cookie") == QUARANTINE_ACCEPT_COOKIE
+	})).Return(createHTTPResponse(200, largeCommentThread(3), nil), nil).Once()
+
+	result, err := client.GetComments(t.Context(), "quarantined", "abc123", "")
+
+	require.NoError(t, err)
+	require.Len(t, result.PostListing.Data.Children, 1)
+	assert.Len(t, result.CommentListing.Data.Children, 3)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestDecodeStream_MatchesUnmarshal(t *testing.T) {
+	t.Run("comments", func(t *testing.T) {
+		var want, got PostAndCommentsResponse
+		require.NoError(t, json.Unmarshal([]byte(postAndCommentsFixture), &want))
+		require.NoError(t, got.decodeStream(json.NewDecoder(strings.NewReader(postAndCommentsFixture))))
+		assert.Equal(t, want, got)
+	})
+
+	listings := map[string]string{
+		"paginated": `{"kind": "Listing", "data": {"after": "t3_b", "before": "t3_a", "dist": 2, "children": [{"kind": "t3", "data": {"id": "a"}}, {"kind": "t3", "data": {"id": "b"}}]}}`,
+		"empty":     `{"kind": "Listing", "data": {"children": []}}`,
+		"null data": `{"kind": "Listing", "data": null}`,
+		"no data":   `{"kind": "Listing", "extra": {"nested": [1, 2]}}`,
+	}
+	for name, body := range listings {
+		t.Run(name, func(t *testing.T) {
+			var want, got SubredditListing
+			require.NoError(t, json.Unmarshal([]byte(body), &want))
+			require.NoError(t, got.decodeStream(json.NewDecoder(strings.NewReader(body))))
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+// largeCommentThread builds a post and comments response with n top-level comments
+func largeCommentThread(n int) string {
+	var comments strings.Builder
+	for i := range n {
+		if i > 0 {
+			comments.WriteString(",")
+		}
+		fmt.Fprintf(&comments, `{"kind": "t1", "data": {"id": "c%d", "author": "user%d", "body": "%s", "score": %d}}`,
+			i, i, strings.Repeat("comment text ", 20), i)
+	}
+
+	return `[{"kind": "Listing", "data": {"children": [{"kind": "t3", "data": {"id": "abc123", "title": "Thread"}}]}},` +
+		`{"kind": "Listing", "data": {"children": [` + comments.String() + `]}}]`
+}
+
+// staticHTTPClient serves the same body for every request without recording calls
+type staticHTTPClient struct {
+	body string
+}
+
+func (s staticHTTPClient) Do(*http.Request) (*http.Response, error) {
+	return createHTTPResponse(200, s.body, nil), nil
+}
+
+// BenchmarkGetComments compares buffering a ~10MB thread against streaming it
+func BenchmarkGetComments(b *testing.B) {
+	body := largeCommentThread(35000)
+
+	client, err := NewClient(WithHTTPClient(staticHTTPClient{body: body}))
+	require.NoError(b, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+
+	b.Run("buffered", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(body)))
+		for b.Loop() {
+			data, err := client.makeAPIRequest(b.Context(), "/r/golang/comments/abc123.json", nil)
+			if err != nil {
+				b.Fatal(err)
+			}
+			var comments PostAndCommentsResponse
+			if err := client.decodeJSON(b.Context(), data, &comments, "comments"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("streamed", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(body)))
+		for b.Loop() {
+			if _, err := client.GetComments(b.Context(), "golang", "abc123", ""); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package redditclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// PostComment replies to a post or comment as the logged-in user and returns
+// the new comment. It requires AuthenticateScript and fails with
+// ErrRequiresUserAuth otherwise.
+func (c *Client) PostComment(ctx context.Context, parentFullname, markdown string) (*Comment, error) {
+	if _, err := c.requireUserAuth(); err != nil {
+		return nil, err
+	}
+	if markdown == "" {
+		return nil, fmt.Errorf("%w: empty comment", ErrInvalidArgument)
+	}
+
+	form := url.Values{
+		"thing_id": {parentFullname},
+		"text":     {markdown},
+	}
+	body, err := c.makeFormRequest(ctx, "/api/comment", form)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		JSON struct {
+			Data struct {
+				Things []CommentChild `json:"things"`
+			} `json:"data"`
+		} `json:"json"`
+	}
+	if err := c.decodeJSON(ctx, body, &resp, "comment"); err != nil {
+		return nil, err
+	}
+
+	things := resp.JSON.Data.Things
+	if len(things) == 0 || things[0].Comment == nil {
+		return nil, fmt.Errorf("comment response contained no comment")
+	}
+
+	return things[0].Comment, nil
+}
+
+// SubmitPost creates a post in subreddit as the logged-in user. Reddit only
+// echoes the new post's identifiers, so the returned Post has just ID, Name,
+// Subreddit, Title, IsSelf, URL and Permalink set; fetch it with
+// GetPostsByID for the rest. A FlairID is checked against GetLinkFlairs
+// before submitting. It requires AuthenticateScript and fails with
+// ErrRequiresUserAuth otherwise.
+func (c *Client) SubmitPost(ctx context.Context, subreddit string, opts SubmitOptions) (*Post, error) {
+	if _, err := c.requireUserAuth(); err != nil {
+		return nil, err
+	}
+
+	form, err := opts.form(subreddit)
+	if err != nil {
+		return nil, err
+	}
+	if opts.FlairID != "" {
+		if err := c.checkFlair(ctx, subreddit, opts.FlairID); err != nil {
+			return nil, err
+		}
+	}
+
+	body, err := c.makeFormRequest(ctx, "/api/submit", form)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		JSON struct {
+			Data struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+				URL  string `json:"url"` // Absolute permalink of the new post
+			} `json:"data"`
+		} `json:"json"`
+	}
+	if err := c.decodeJSON(ctx, body, &resp, "submission"); err != nil {
+		return nil, err
+	}
+
+	data := resp.JSON.Data
+	if data.Name == "" {
+		return nil, fmt.Errorf("submit response contained no post")
+	}
+
+	post := &Post{
+		ID:        data.ID,
+		Name:      data.Name,
+		Subreddit: subreddit,
+		Title:     opts.Title,
+		IsSelf:    opts.Kind == SubmitSelf,
+		URL:       opts.URL,
+	}
+	if u, err := url.Parse(data.URL); err == nil {
+		post.Permalink = u.Path
+	}
+	if post.IsSelf {
+		post.URL = data.URL
+	}
+
+	return post, nil
+}
+
+// form validates the options and builds the /api/submit form for subreddit
+func (o SubmitOptions) form(subreddit string) (url.Values, error) {
+	if strings.TrimSpace(o.Title) == "" {
+		return nil, fmt.Errorf("%w: submission needs a title", ErrInvalidArgument)
+	}
+
+	form := url.Values{
+		"sr":    {subreddit},
+		"kind":  {string(o.Kind)},
+		"title": {o.Title},
+	}
+
+	switch o.Kind {
+	case SubmitSelf:
+		form.Set("text", o.Text)
+	case SubmitLink:
+		if o.URL == "" {
+			return nil, fmt.Errorf("%w: link submission needs a URL", ErrInvalidArgument)
+		}
+		form.Set("url", o.URL)
+	default:
+		return nil, fmt.Errorf("%w: submission kind %q, want self or link", ErrInvalidArgument, o.Kind)
+	}
+
+	if o.NSFW {
+		form.Set("nsfw", "true")
+	}
+	if o.Spoiler {
+		form.Set("spoiler", "true")
+	}
+	if o.FlairID != "" {
+		form.Set("flair_id", o.FlairID)
+	}
+
+	return form, nil
+}
@@ -0,0 +1,191 @@
+package redditclient
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	commentCreatedBody = `{"json": {"errors": [], "data": {"things": [{"kind": "t1", "data": {
+		"id": "c9", "name": "t1_c9", "author": "script-user", "body": "Nice post",
+		"link_id": "t3_abc", "parent_id": "t3_abc", "subreddit": "golang", "replies": ""}}]}}}`
+
+	submitRateLimitedBody = `{"json": {"ratelimit": 540.1, "errors": [["RATELIMIT",
+		"Looks like you've been doing that a lot. Take a break for 9 minutes before trying again.", "ratelimit"]]}}`
+
+	submitBannedBody = `{"json": {"errors": [["SUBREDDIT_NOTALLOWED",
+		"you aren't allowed to post there.", "sr"]]}}`
+)
+
+func TestPostComment_Success(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	srv.Respond("/api/comment", http.StatusOK, commentCreatedBody)
+	client := newFakeServerClient(t, srv)
+	authenticateScript(t, client)
+
+	comment, err := client.PostComment(t.Context(), "t3_abc", "Nice post")
+
+	require.NoError(t, err)
+	assert.Equal(t, "t1_c9", comment.Name)
+	assert.Equal(t, "Nice post", comment.Body)
+	assert.Equal(t, "t3_abc", comment.ParentID)
+
+	req, ok := srv.LastRequest("/api/comment")
+	require.True(t, ok)
+	assert.Equal(t, http.MethodPost, req.Method)
+	form, err := url.ParseQuery(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "t3_abc", form.Get("thing_id"))
+	assert.Equal(t, "Nice post", form.Get("text"))
+	assert.Equal(t, "json", form.Get("api_type"))
+}
+
+func TestPostComment_RateLimited(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	srv.Respond("/api/comment", http.StatusOK, submitRateLimitedBody)
+	client := newFakeServerClient(t, srv)
+	authenticateScript(t, client)
+
+	_, err := client.PostComment(t.Context(), "t3_abc", "again")
+
+	require.ErrorIs(t, err, ErrRateLimited)
+	var rateErr *RateLimitError
+	require.ErrorAs(t, err, &rateErr)
+	assert.Equal(t, 540100*time.Millisecond, rateErr.RetryAfter)
+}
+
+func TestSubmitPost_Success(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      SubmitOptions
+		wantField string
+		wantValue string
+		wantURL   string
+	}{
+		{
+			name:      "self",
+			opts:      SubmitOptions{Kind: SubmitSelf, Title: "Hello", Text: "Body text", Spoiler: true},
+			wantField: "text",
+			wantValue: "Body text",
+			wantURL:   "https://www.reddit.com/r/test/comments/new/title/",
+		},
+		{
+			name:      "link",
+			opts:      SubmitOptions{Kind: SubmitLink, Title: "Hello", URL: "https://go.dev", NSFW: true, FlairID: "flair-1"},
+			wantField: "url",
+			wantValue: "https://go.dev",
+			wantURL:   "https://go.dev",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeRedditServer(t)
+			srv.Respond("/r/test/api/link_flair_v2.json", http.StatusOK, linkFlairsBody)
+			client := newFakeServerClient(t, srv)
+			authenticateScript(t, client)
+
+			post, err := client.SubmitPost(t.Context(), "test", tt.opts)
+
+			require.NoError(t, err)
+			assert.Equal(t, "t3_new", post.Name)
+			assert.Equal(t, "new", post.ID)
+			assert.Equal(t, "test", post.Subreddit)
+			assert.Equal(t, "Hello", post.Title)
+			assert.Equal(t, "/r/test/comments/new/title/", post.Permalink)
+			assert.Equal(t, tt.wantURL, post.URL)
+
+			req, ok := srv.LastRequest("/api/submit")
+			require.True(t, ok)
+			form, err := url.ParseQuery(req.Body)
+			require.NoError(t, err)
+			assert.Equal(t, "test", form.Get("sr"))
+			assert.Equal(t, string(tt.opts.Kind), form.Get("kind"))
+			assert.Equal(t, tt.wantValue, form.Get(tt.wantField))
+			assert.Equal(t, tt.opts.NSFW, form.Has("nsfw"))
+			assert.Equal(t, tt.opts.Spoiler, form.Has("spoiler"))
+			assert.Equal(t, tt.opts.FlairID, form.Get("flair_id"))
+		})
+	}
+}
+
+func TestSubmitPost_BannedFromSubreddit(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	srv.Respond("/api/submit", http.StatusOK, submitBannedBody)
+	client := newFakeServerClient(t, srv)
+	authenticateScript(t, client)
+
+	_, err := client.SubmitPost(t.Context(), "test", SubmitOptions{Kind: SubmitSelf, Title: "Hello"})
+
+	assert.ErrorIs(t, err, ErrSubredditNotAllowed)
+	assert.ErrorIs(t, err, ErrForbidden)
+	var actionErr *ActionError
+	require.ErrorAs(t, err, &actionErr)
+	assert.Equal(t, "sr", actionErr.Field)
+}
+
+func TestSubmitPost_RateLimited(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	srv.Respond("/api/submit", http.StatusOK, submitRateLimitedBody)
+	client := newFakeServerClient(t, srv)
+	authenticateScript(t, client)
+
+	_, err := client.SubmitPost(t.Context(), "test", SubmitOptions{Kind: SubmitSelf, Title: "Hello"})
+
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.NotErrorIs(t, err, ErrSubredditNotAllowed)
+}
+
+func TestSubmitPost_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts SubmitOptions
+	}{
+		{"missing title", SubmitOptions{Kind: SubmitSelf}},
+		{"link without URL", SubmitOptions{Kind: SubmitLink, Title: "Hello"}},
+		{"unknown kind", SubmitOptions{Kind: "image", Title: "Hello"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeRedditServer(t)
+			client := newFakeServerClient(t, srv)
+			authenticateScript(t, client)
+
+			_, err := client.SubmitPost(t.Context(), "test", tt.opts)
+
+			assert.ErrorIs(t, err, ErrInvalidArgument)
+			_, sent := srv.LastRequest("/api/submit")
+			assert.False(t, sent)
+		})
+	}
+}
+
+func TestSubmitPost_UnknownFlair(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	srv.Respond("/r/test/api/link_flair_v2.json", http.StatusOK, linkFlairsBody)
+	client := newFakeServerClient(t, srv)
+	authenticateScript(t, client)
+
+	_, err := client.SubmitPost(t.Context(), "test", SubmitOptions{Kind: SubmitSelf, Title: "Hello", FlairID: "flair-9"})
+
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+	assert.ErrorContains(t, err, "flair-9")
+	_, sent := srv.LastRequest("/api/submit")
+	assert.False(t, sent)
+}
+
+func TestSubmit_RequiresUserAuth(t *testing.T) {
+	client, _ := newAuthenticatedFakeServerClient(t)
+
+	_, err := client.PostComment(t.Context(), "t3_abc", "hi")
+	assert.ErrorIs(t, err, ErrRequiresUserAuth)
+
+	_, err = client.SubmitPost(t.Context(), "test", SubmitOptions{Kind: SubmitSelf, Title: "Hello"})
+	assert.ErrorIs(t, err, ErrRequiresUserAuth)
+}
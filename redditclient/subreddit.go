@@ -0,0 +1,71 @@
+package redditclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// GetSubredditAbout fetches a subreddit's metadata. Banned and nonexistent
+// subreddits return an error matching ErrNotFound.
+func (c *Client) GetSubredditAbout(ctx context.Context, subreddit string) (*SubredditAbout, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	endpoint := fmt.Sprintf("/r/%s/about.json", url.PathEscape(subreddit))
+
+	body, err := c.makeAPIRequest(ctx, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var about struct {
+		Kind string         `json:"kind"`
+		Data SubredditAbout `json:"data"`
+	}
+	if err := c.decodeJSON(ctx, body, &about, "subreddit about"); err != nil {
+		return nil, err
+	}
+
+	// Reddit answers some missing subreddits with a search listing instead of a 404
+	if about.Kind != KindSubreddit {
+		return nil, fmt.Errorf("subreddit %s returned kind %q: %w", subreddit, about.Kind, ErrNotFound)
+	}
+
+	return &about.Data, nil
+}
+
+// GetStickies fetches a subreddit's pinned posts in slot order. Subreddits
+// have up to two sticky slots and Reddit answers an empty slot with a 404, so
+// the result may hold zero, one or two posts.
+func (c *Client) GetStickies(ctx context.Context, subreddit string) ([]Post, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	endpoint := fmt.Sprintf("/r/%s/about/sticky.json", url.PathEscape(subreddit))
+
+	var stickies []Post
+	for slot := 1; slot <= maxStickySlots; slot++ {
+		body, err := c.makeAPIRequest(ctx, endpoint, url.Values{"num": {strconv.Itoa(slot)}})
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var thread PostAndCommentsResponse
+		if err := c.decodeJSON(ctx, body, &thread, "sticky"); err != nil {
+			return nil, err
+		}
+		for _, child := range thread.PostListing.Data.Children {
+			stickies = append(stickies, child.Data)
+		}
+	}
+
+	return stickies, nil
+}
@@ -0,0 +1,415 @@
+package redditclient
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const subredditAboutFixture = `{"kind": "t5", "data": {
+	"id": "2rc7j",
+	"name": "t5_2rc7j",
+	"display_name": "golang",
+	"display_name_prefixed": "r/golang",
+	"title": "The Go Programming Language",
+	"url": "/r/golang/",
+	"public_description": "Ask questions and post articles about the Go programming language.",
+	"subscribers": 250000,
+	"accounts_active": 512,
+	"icon_img": "https://b.thumbs.redditmedia.com/icon.png",
+	"community_icon": "https://styles.redditmedia.com/community_icon.png",
+	"banner_img": "https://b.thumbs.redditmedia.com/banner.png",
+	"over18": false,
+	"quarantine": false,
+	"subreddit_type": "public",
+	"submission_type": "any",
+	"created_utc": 1257833561.0
+}}`
+
+func TestGetSubredditAbout_Success(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.Respond("/r/golang/about.json", http.StatusOK, subredditAboutFixture)
+
+	about, err := client.GetSubredditAbout(t.Context(), "golang")
+
+	require.NoError(t, err)
+	assert.Equal(t, "golang", about.DisplayName)
+	assert.Equal(t, "r/golang", about.DisplayNamePrefixed)
+	assert.Equal(t, 250000, about.Subscribers)
+	assert.Equal(t, 512, about.ActiveUsers)
+	assert.Equal(t, "https://b.thumbs.redditmedia.com/icon.png", about.IconImg)
+	assert.Equal(t, "https://b.thumbs.redditmedia.com/banner.png", about.BannerImg)
+	assert.False(t, about.Over18)
+	assert.False(t, about.Quarantine)
+	assert.Equal(t, "any", about.SubmissionType)
+	assert.Equal(t, Timestamp(1257833561), about.Created)
+
+	_, ok := srv.LastRequest("/r/golang/about.json")
+	assert.True(t, ok)
+}
+
+func TestGetSubredditAbout_NotFound(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"banned", 404, `{"reason": "banned", "message": "Not Found", "error": 404}`},
+		{"nonexistent", 404, `{"message": "Not Found", "error": 404}`},
+		{"search listing fallback", 200, `{"kind": "Listing", "data": {"children": []}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, srv := newAuthenticatedFakeServerClient(t)
+			srv.Respond("/r/doesnotexist/about.json", tt.status, tt.body)
+
+			about, err := client.GetSubredditAbout(t.Context(), "doesnotexist")
+
+			assert.Nil(t, about)
+			assert.ErrorIs(t, err, ErrNotFound)
+		})
+	}
+}
+
+func TestGetSubredditAbout_NotAuthenticated(t *testing.T) {
+	client, err := NewClient(WithHTTPClient(&MockHTTPClient{}))
+	require.NoError(t, err)
+
+	_, err = client.GetSubredditAbout(t.Context(), "golang")
+
+	assert.ErrorIs(t, err, ErrNotAuthenticated)
+}
+
+func TestGetCombinedSubreddits_Success(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.Respond("/r/golang+rust/new.json", http.StatusOK, `{"kind": "Listing", "data": {"after": "t3_b", "children": [
+		{"kind": "t3", "data": {"id": "a", "subreddit": "golang"}},
+		{"kind": "t3", "data": {"id": "b", "subreddit": "rust"}}
+	]}}`)
+
+	listing, err := client.GetCombinedSubreddits(t.Context(), []string{"golang", "rust"}, "new", &ListingOptions{After: "t3_prev"})
+
+	require.NoError(t, err)
+	require.Len(t, listing.Data.Children, 2)
+	assert.Equal(t, "golang", listing.Data.Children[0].Data.Subreddit)
+	assert.Equal(t, "rust", listing.Data.Children[1].Data.Subreddit)
+
+	req, ok := srv.LastRequest("/r/golang+rust/new.json")
+	require.True(t, ok)
+	assert.Equal(t, "t3_prev", req.Query.Get("after"))
+}
+
+func TestCombineSubreddits(t *testing.T) {
+	tests := []struct {
+		name       string
+		subreddits []string
+		want       string
+		wantErr    bool
+	}{
+		{name: "single", subreddits: []string{"golang"}, want: "golang"},
+		{name: "joined", subreddits: []string{"golang", "rust", "programming"}, want: "golang+rust+programming"},
+		{name: "escaped", subreddits: []string{"golang", "a b"}, want: "golang+a%20b"},
+		{name: "empty list", subreddits: nil, wantErr: true},
+		{name: "empty name", subreddits: []string{"golang", ""}, wantErr: true},
+		{name: "embedded plus", subreddits: []string{"golang+rust"}, wantErr: true},
+		{name: "embedded slash", subreddits: []string{"golang/new"}, wantErr: true},
+		{name: "too long", subreddits: slices.Repeat([]string{"averagelengthsubname"}, 200), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := combineSubreddits(tt.subreddits)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidArgument)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// matchSticky matches a request for the given sticky slot of r/golang
+func matchSticky(slot string) interface{} {
+	return mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/r/golang/about/sticky.json" && req.URL.Query().Get("num") == slot
+	})
+}
+
+func stickyThread(id, title string) string {
+	return `[{"kind": "Listing", "data": {"children": [{"kind": "t3", "data": {"id": "` + id + `", "title": "` + title + `", "stickied": true}}]}},
+		{"kind": "Listing", "data": {"children": []}}]`
+}
+
+func TestGetStickies(t *testing.T) {
+	const notFound = `{"message": "Not Found", "error": 404}`
+
+	tests := []struct {
+		name  string
+		slot1 *http.Response
+		slot2 *http.Response
+		want  []string
+	}{
+		{
+			name:  "two stickies",
+			slot1: createHTTPResponse(200, stickyThread("s1", "Rules"), nil),
+			slot2: createHTTPResponse(200, stickyThread("s2", "Weekly thread"), nil),
+			want:  []string{"s1", "s2"},
+		},
+		{
+			name:  "one sticky",
+			slot1: createHTTPResponse(200, stickyThread("s1", "Rules"), nil),
+			slot2: createHTTPResponse(404, notFound, nil),
+			want:  []string{"s1"},
+		},
+		{
+			name:  "no stickies",
+			slot1: createHTTPResponse(404, notFound, nil),
+			slot2: createHTTPResponse(404, notFound, nil),
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mockHTTP := newAuthenticatedTestClient(t)
+			mockHTTP.On("Do", matchSticky("1")).Return(tt.slot1, nil).Once()
+			mockHTTP.On("Do", matchSticky("2")).Return(tt.slot2, nil).Once()
+
+			stickies, err := client.GetStickies(t.Context(), "golang")
+
+			require.NoError(t, err)
+			var ids []string
+			for _, post := range stickies {
+				assert.True(t, post.Stickied)
+				ids = append(ids, post.ID)
+			}
+			assert.Equal(t, tt.want, ids)
+			mockHTTP.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetStickies_OtherErrorsFail(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	client.SetRetryPolicy(RetryPolicy{})
+	mockHTTP.On("Do", matchSticky("1")).
+		Return(createHTTPResponse(403, `{"reason": "private", "message": "Forbidden", "error": 403}`, nil), nil).Once()
+
+	_, err := client.GetStickies(t.Context(), "golang")
+
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestRestrictedSubreddits_TypedErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"private status", 403, `{"reason": "private", "message": "Forbidden", "error": 403}`, ErrSubredditPrivate},
+		{"private notice", 200, `{"reason": "private"}`, ErrSubredditPrivate},
+		{"banned status", 404, `{"reason": "banned", "message": "Not Found", "error": 404}`, ErrSubredditBanned},
+		{"banned notice", 200, `{"reason": "banned"}`, ErrSubredditBanned},
+	}
+
+	fetchers := map[string]func(*Client) error{
+		"GetSubreddit": func(c *Client) error {
+			_, err := c.GetSubreddit(t.Context(), "restricted", "hot")
+			return err
+		},
+		"GetSubredditAbout": func(c *Client) error {
+			_, err := c.GetSubredditAbout(t.Context(), "restricted")
+			return err
+		},
+	}
+
+	for _, tt := range tests {
+		for name, fetch := range fetchers {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				client, mockHTTP := newAuthenticatedTestClient(t)
+				mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+					Return(createHTTPResponse(tt.status, tt.body, nil), nil).Once()
+
+				err := fetch(client)
+
+				assert.ErrorIs(t, err, tt.want)
+				mockHTTP.AssertExpectations(t)
+			})
+		}
+	}
+}
+
+func TestRestrictedSubreddits_Quarantined(t *testing.T) {
+	const message = "This community is quarantined."
+
+	t.Run("status", func(t *testing.T) {
+		client, mockHTTP := newAuthenticatedTestClient(t)
+		mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+			Return(createHTTPResponse(403, `{"reason": "quarantined", "quarantine_message": "`+message+`", "message": "Forbidden", "error": 403}`, nil), nil).Once()
+
+		_, err := client.GetSubredditAbout(t.Context(), "quarantined")
+
+		var quarantineErr *QuarantineError
+		require.ErrorAs(t, err, &quarantineErr)
+		assert.Equal(t, message, quarantineErr.Message)
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("still quarantined after opt-in", func(t *testing.T) {
+		client, mockHTTP := newAuthenticatedTestClient(t)
+		mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+			Return(func(*http.Request) *http.Response {
+				return createHTTPResponse(200, `{"reason": "quarantined", "quarantine_message": "`+message+`"}`, nil)
+			}, nil).Twice()
+
+		_, err := client.GetSubreddit(t.Context(), "quarantined", "hot")
+
+		var quarantineErr *QuarantineError
+		require.ErrorAs(t, err, &quarantineErr)
+		assert.Equal(t, message, quarantineErr.Message)
+		assert.Nil(t, quarantineErr.Unwrap())
+		mockHTTP.AssertExpectations(t)
+	})
+}
+
+func TestRestrictedSubreddits_GatedRetriesOnce(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(func(*http.Request) *http.Response {
+			return createHTTPResponse(200, `{"reason": "gated"}`, nil)
+		}, nil).Twice()
+
+	_, err := client.GetSubreddit(t.Context(), "gated", "hot")
+
+	assert.ErrorIs(t, err, ErrSubredditGated)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestRestrictedSubreddits_OptInCookiePerRestriction(t *testing.T) {
+	tests := []struct {
+		reason Restriction
+		cookie string
+	}{
+		{RestrictionGated, GATED_ACCEPT_COOKIE},
+		{RestrictionQuarantined, QUARANTINE_ACCEPT_COOKIE},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.reason), func(t *testing.T) {
+			client, mockHTTP := newAuthenticatedTestClient(t)
+			mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+				return req.Header.Get("Cookie") == ""
+			})).Return(createHTTPResponse(200, `{"reason": "`+string(tt.reason)+`"}`, nil), nil).Once()
+			mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+				return req.Header.Get("Cookie") == tt.cookie
+			})).Return(createHTTPResponse(200, `{"kind": "Listing", "data": {"children": []}}`, nil), nil).Once()
+
+			listing, err := client.GetSubreddit(t.Context(), "restricted", "hot")
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.reason, listing.Restriction)
+			mockHTTP.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRestrictedSubreddits_GatedAndQuarantined(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("Cookie") == ""
+	})).Return(createHTTPResponse(200, `{"reason": "gated"}`, nil), nil).Once()
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("Cookie") == GATED_ACCEPT_COOKIE
+	})).Return(createHTTPResponse(200, `{"reason": "quarantined"}`, nil), nil).Once()
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("Cookie") == CONTENT_WARNING_ACCEPT_COOKIE
+	})).Return(createHTTPResponse(200, `{"kind": "Listing", "data": {"children": []}}`, nil), nil).Once()
+
+	listing, err := client.GetSubreddit(t.Context(), "restricted", "hot")
+
+	require.NoError(t, err)
+	assert.Equal(t, RestrictionGated, listing.Restriction)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestRestrictedSubreddits_NoRestriction(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+		Return(createHTTPResponse(200, `{"kind": "Listing", "data": {"children": []}}`, nil), nil).Once()
+
+	listing, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+	require.NoError(t, err)
+	assert.Empty(t, listing.Restriction)
+}
+
+func TestRestrictedSubreddits_WithContentOptIn(t *testing.T) {
+	newClient := func(t *testing.T, restrictions ...Restriction) (*Client, *MockHTTPClient) {
+		mockHTTP := &MockHTTPClient{}
+		client, err := NewClient(WithHTTPClient(mockHTTP), WithContentOptIn(restrictions...))
+		require.NoError(t, err)
+		client.accessToken = "test-token"
+		client.authenticated = true
+		return client, mockHTTP
+	}
+
+	t.Run("no opt-in", func(t *testing.T) {
+		client, mockHTTP := newClient(t)
+		mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+			Return(createHTTPResponse(200, `{"reason": "gated"}`, nil), nil).Once()
+
+		_, err := client.GetSubreddit(t.Context(), "gated", "hot")
+
+		assert.ErrorIs(t, err, ErrSubredditGated)
+		assert.NotErrorIs(t, err, ErrStillRestricted)
+		mockHTTP.AssertExpectations(t)
+	})
+
+	t.Run("gated only", func(t *testing.T) {
+		client, mockHTTP := newClient(t, RestrictionGated)
+		mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+			Return(createHTTPResponse(200, `{"reason": "quarantined", "quarantine_message": "Quarantined."}`, nil), nil).Once()
+
+		_, err := client.GetSubreddit(t.Context(), "quarantined", "hot")
+
+		var quarantineErr *QuarantineError
+		require.ErrorAs(t, err, &quarantineErr)
+		assert.Equal(t, "Quarantined.", quarantineErr.Message)
+		mockHTTP.AssertExpectations(t)
+	})
+
+	t.Run("unknown restriction", func(t *testing.T) {
+		_, err := NewClient(WithContentOptIn("nsfw"))
+		assert.ErrorIs(t, err, ErrInvalidArgument)
+	})
+}
+
+func TestRestrictedSubreddits_CachedRestriction(t *testing.T) {
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(WithHTTPClient(mockHTTP), WithCache(NewMemoryCache(10), time.Minute))
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("Cookie") == ""
+	})).Return(createHTTPResponse(200, `{"reason": "quarantined"}`, nil), nil).Once()
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("Cookie") == QUARANTINE_ACCEPT_COOKIE
+	})).Return(createHTTPResponse(200, `{"kind": "Listing", "data": {"children": []}}`, nil), nil).Once()
+
+	for range 2 {
+		listing, err := client.GetSubreddit(t.Context(), "quarantined", "hot")
+		require.NoError(t, err)
+		assert.Equal(t, RestrictionQuarantined, listing.Restriction)
+	}
+	mockHTTP.AssertExpectations(t)
+}
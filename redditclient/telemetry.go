@@ -0,0 +1,307 @@
+package redditclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the tracer and meter the client creates
+const instrumentationName = "github.com/Koshroy/grapeddit/redditclient"
+
+// Span and metric attribute keys. Metrics carry only those with a bounded set
+// of values: the operation, sort, status code and cache result.
+const (
+	attrOperation          = attribute.Key("reddit.operation")
+	attrSubreddit          = attribute.Key("reddit.subreddit")
+	attrSort               = attribute.Key("reddit.sort")
+	attrCache              = attribute.Key("reddit.cache")
+	attrRetryCount         = attribute.Key("reddit.retry_count")
+	attrRateLimitRemaining = attribute.Key("reddit.ratelimit.remaining")
+	attrStatusCode         = attribute.Key("http.response.status_code")
+	attrMethod             = attribute.Key("http.request.method")
+	attrPath               = attribute.Key("url.path")
+)
+
+// Values of the reddit.cache attribute
+const (
+	cacheHit  = "hit"
+	cacheMiss = "miss"
+)
+
+// telemetry emits a span and metrics for every API call. A client configured
+// without a TracerProvider or MeterProvider has a nil *telemetry, and each
+// call site checks for that before doing any other work.
+type telemetry struct {
+	tracer   trace.Tracer // nil without a TracerProvider
+	requests metric.Int64Counter
+	retries  metric.Int64Counter
+	duration metric.Float64Histogram
+	metered  bool // The instruments above are set
+}
+
+// newTelemetry creates the tracer and instruments, or returns nil if both
+// providers are nil
+func newTelemetry(tp trace.TracerProvider, mp metric.MeterProvider) (*telemetry, error) {
+	if tp == nil && mp == nil {
+		return nil, nil
+	}
+
+	t := &telemetry{}
+	if tp != nil {
+		t.tracer = tp.Tracer(instrumentationName)
+	}
+	if mp == nil {
+		return t, nil
+	}
+
+	meter := mp.Meter(instrumentationName)
+	var err error
+	if t.requests, err = meter.Int64Counter("reddit.client.requests",
+		metric.WithDescription("API calls made, by operation, status code and cache result"),
+		metric.WithUnit("{call}")); err != nil {
+		return nil, err
+	}
+	if t.retries, err = meter.Int64Counter("reddit.client.retries",
+		metric.WithDescription("Requests retried after a transient failure"),
+		metric.WithUnit("{retry}")); err != nil {
+		return nil, err
+	}
+	if t.duration, err = meter.Float64Histogram("reddit.client.duration",
+		metric.WithDescription("Duration of API calls, including retries"),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	t.metered = true
+	return t, nil
+}
+
+// apiCall is the telemetry of one API call in progress. Layers below the
+// call find it in the context to record the response and retries. Its
+// methods do nothing on a nil *apiCall.
+type apiCall struct {
+	t         *telemetry
+	span      trace.Span // nil without a tracer
+	start     time.Time
+	attrs     []attribute.KeyValue // Operation and sort
+	subreddit string               // On the span only
+	status    int
+	remaining float64
+	limited   bool // remaining was reported
+	retries   int
+	cache     string
+}
+
+type apiCallKey struct{}
+
+// startCall starts the telemetry of a call to endpoint, returning a context
+// carrying it. Without telemetry it returns ctx and a nil *apiCall.
+func (c *Client) startCall(ctx context.Context, method, endpoint string, params url.Values) (context.Context, *apiCall) {
+	if c.telemetry == nil {
+		return ctx, nil
+	}
+
+	op := operationName(endpoint)
+	call := &apiCall{t: c.telemetry, start: c.now()}
+	subreddit, sort := callTarget(op, endpoint, params)
+	call.subreddit = subreddit
+	call.attrs = append(call.attrs, attrOperation.String(op))
+	if sort != "" {
+		call.attrs = append(call.attrs, attrSort.String(sort))
+	}
+
+	if c.telemetry.tracer != nil {
+		ctx, call.span = c.telemetry.tracer.Start(ctx, op,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(call.attrs...),
+			trace.WithAttributes(attrMethod.String(method)))
+		if subreddit != "" {
+			call.span.SetAttributes(attrSubreddit.String(subreddit))
+		}
+		if op == genericOperation {
+			call.span.SetAttributes(attrPath.String(endpoint))
+		}
+	}
+	return context.WithValue(ctx, apiCallKey{}, call), call
+}
+
+// callFrom returns the call ctx carries, or nil
+func callFrom(ctx context.Context) *apiCall {
+	call, _ := ctx.Value(apiCallKey{}).(*apiCall)
+	return call
+}
+
+// observe records the status and rate limit of a response
+func (a *apiCall) observe(resp *http.Response) {
+	if a == nil {
+		return
+	}
+	a.status = resp.StatusCode
+	if remaining, ok := parseRateLimitHeader(resp.Header.Get("x-ratelimit-remaining")); ok {
+		a.remaining, a.limited = remaining, true
+	}
+}
+
+// retried counts a retry
+func (a *apiCall) retried() {
+	if a == nil {
+		return
+	}
+	a.retries++
+}
+
+// cached records whether the call was served from the cache
+func (a *apiCall) cached(hit bool) {
+	if a == nil {
+		return
+	}
+	a.cache = cacheMiss
+	if hit {
+		a.cache = cacheHit
+	}
+}
+
+// end finishes the call's span and records its metrics
+func (a *apiCall) end(c *Client, err error) {
+	if a == nil {
+		return
+	}
+
+	attrs := a.attrs
+	if a.status != 0 {
+		attrs = append(attrs, attrStatusCode.Int(a.status))
+	}
+	if a.cache != "" {
+		attrs = append(attrs, attrCache.String(a.cache))
+	}
+
+	if a.span != nil {
+		a.span.SetAttributes(attrs...)
+		a.span.SetAttributes(attrRetryCount.Int(a.retries))
+		if a.limited {
+			a.span.SetAttributes(attrRateLimitRemaining.Float64(a.remaining))
+		}
+		if err != nil {
+			a.span.RecordError(err)
+			a.span.SetStatus(codes.Error, err.Error())
+		}
+		a.span.End()
+	}
+
+	if a.t.metered {
+		// A background context, as the caller's may already be cancelled
+		ctx := context.Background()
+		set := metric.WithAttributes(attrs...)
+		a.t.requests.Add(ctx, 1, set)
+		if a.retries > 0 {
+			a.t.retries.Add(ctx, int64(a.retries), set)
+		}
+		a.t.duration.Record(ctx, c.now().Sub(a.start).Seconds(), set)
+	}
+}
+
+// operations names the calls the client makes, matched segment by segment
+// with * standing for any one segment and a final ** for one or more. The
+// first match wins, so specific patterns come before general ones.
+var operations = []struct {
+	pattern string
+	name    string
+}{
+	{"/r/*/comments/*/comment/*.json", "reddit.get_comment"},
+	{"/r/*/comments/*.json", "reddit.get_comments"},
+	{"/comments/*.json", "reddit.get_comments"},
+	{"/r/*/about.json", "reddit.get_subreddit_about"},
+	{"/r/*/about/sticky.json", "reddit.get_sticky"},
+	{"/r/*/search.json", "reddit.search"},
+	{"/r/*/wiki/pages.json", "reddit.get_wiki_pages"},
+	{"/r/*/api/link_flair_v2.json", "reddit.get_link_flairs"},
+	{"/r/*/wiki/**", "reddit.get_wiki_page"},
+	{"/r/*/random.json", "reddit.get_random_post"},
+	{"/r/*/*.json", "reddit.get_subreddit"},
+	{"/user/*/about.json", "reddit.get_user"},
+	{"/user/*/m/*/*.json", "reddit.get_multireddit"},
+	{"/user/*/*.json", "reddit.get_user_listing"},
+	{"/duplicates/*.json", "reddit.get_duplicates"},
+	{"/api/v1/user/*/trophies", "reddit.get_trophies"},
+	{"/api/multi/user/*", "reddit.get_multireddits"},
+	{"/api/v1/me", "reddit.me"},
+	{"/api/comment", "reddit.comment"},
+	{"/api/submit", "reddit.submit"},
+	{"/api/vote", "reddit.vote"},
+	{"/api/save", "reddit.save"},
+	{"/api/unsave", "reddit.unsave"},
+	{"/api/read_message", "reddit.read_message"},
+	{"/api/info.json", "reddit.info"},
+	{"/api/morechildren.json", "reddit.morechildren"},
+	{"/api/trending_subreddits.json", "reddit.trending_subreddits"},
+	{"/api/subreddit_autocomplete_v2.json", "reddit.subreddit_autocomplete_v2"},
+	{"/message/inbox.json", "reddit.message_inbox"},
+	{"/message/unread.json", "reddit.message_unread"},
+	{"/subreddits/mine/subscriber.json", "reddit.subreddits_mine_subscriber"},
+	{"/subreddits/default.json", "reddit.subreddits_default"},
+	{"/subreddits/popular.json", "reddit.subreddits_popular"},
+	{"/search.json", "reddit.search"},
+}
+
+// genericOperation names calls to endpoints outside operations, such as those
+// made through GetJSON. Their path is recorded on the span, never in a span
+// name or metric attribute, so arbitrary paths cannot grow either without
+// bound.
+const genericOperation = "reddit.request"
+
+// operationName names a call to endpoint after its template, so that every
+// subreddit's listing shares the span name reddit.get_subreddit
+func operationName(endpoint string) string {
+	segments := strings.Split(endpoint, "/")
+	for _, op := range operations {
+		if matchSegments(strings.Split(op.pattern, "/"), segments) {
+			return op.name
+		}
+	}
+	return genericOperation
+}
+
+// matchSegments reports whether path segments match pattern segments. A *
+// matches any segment, a *.json any segment ending in .json, and a final **
+// the remaining segments, of which there must be at least one.
+func matchSegments(pattern, segments []string) bool {
+	if n := len(pattern); n > 0 && pattern[n-1] == "**" {
+		if len(segments) < n {
+			return false
+		}
+		pattern, segments = pattern[:n-1], segments[:n-1]
+	}
+	if len(pattern) != len(segments) {
+		return false
+	}
+	for i, p := range pattern {
+		if ok, _ := path.Match(p, segments[i]); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// callTarget returns the subreddit and sort a call to endpoint, named op, is
+// for, where it has them
+func callTarget(op, endpoint string, params url.Values) (subreddit, sort string) {
+	sort = params.Get("sort")
+	rest, ok := strings.CutPrefix(endpoint, "/r/")
+	if !ok {
+		return "", sort
+	}
+
+	subreddit, rest, _ = strings.Cut(rest, "/")
+	if sort == "" && op == "reddit.get_subreddit" {
+		sort = strings.TrimSuffix(rest, ".json")
+	}
+	return subreddit, sort
+}
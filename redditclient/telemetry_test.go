@@ -0,0 +1,274 @@
+package redditclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTelemetryTestClient returns an authenticated client recording spans
+// and metrics, with retries that do not sleep
+func newTelemetryTestClient(t *testing.T, opts ...Option) (*Client, *MockHTTPClient, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	t.Helper()
+
+	spans := tracetest.NewSpanRecorder()
+	metrics := sdkmetric.NewManualReader()
+	mockHTTP := &MockHTTPClient{}
+	client, err := NewClient(append([]Option{
+		WithHTTPClient(mockHTTP),
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(metrics))),
+		WithRetryPolicy(DefaultRetryPolicy),
+	}, opts...)...)
+	require.NoError(t, err)
+	client.accessToken = "test-token"
+	client.authenticated = true
+	client.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+
+	return client, mockHTTP, spans, metrics
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTelemetry_SubredditSpan(t *testing.T) {
+	client, mockHTTP, spans, _ := newTelemetryTestClient(t)
+
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(503, "", nil), nil).Once()
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(200, emptyListing, map[string]string{
+		"x-ratelimit-remaining": "594.0",
+	}), nil).Once()
+
+	_, err := client.GetSubredditListing(t.Context(), "golang", SortTop, nil)
+	require.NoError(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+	span := ended[0]
+	assert.Equal(t, "reddit.get_subreddit", span.Name())
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+	assert.Equal(t, codes.Unset, span.Status().Code)
+
+	attrs := spanAttributes(span)
+	assert.Equal(t, "reddit.get_subreddit", attrs[attrOperation].AsString())
+	assert.Equal(t, "golang", attrs[attrSubreddit].AsString())
+	assert.Equal(t, "top", attrs[attrSort].AsString())
+	assert.Equal(t, "GET", attrs[attrMethod].AsString())
+	assert.Equal(t, int64(200), attrs[attrStatusCode].AsInt64())
+	assert.Equal(t, int64(1), attrs[attrRetryCount].AsInt64())
+	assert.Equal(t, 594.0, attrs[attrRateLimitRemaining].AsFloat64())
+	assert.NotContains(t, attrs, attrCache, "no cache is configured")
+}
+
+func TestTelemetry_CacheHitAndMiss(t *testing.T) {
+	client, mockHTTP, spans, _ := newTelemetryTestClient(t, WithCache(NewMemoryCache(10), time.Minute))
+
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(200, emptyListing, nil), nil).Once()
+
+	for range 2 {
+		_, err := client.GetSubredditListing(t.Context(), "golang", SortHot, nil)
+		require.NoError(t, err)
+	}
+
+	ended := spans.Ended()
+	require.Len(t, ended, 2)
+	assert.Equal(t, cacheMiss, spanAttributes(ended[0])[attrCache].AsString())
+	assert.Equal(t, cacheHit, spanAttributes(ended[1])[attrCache].AsString())
+	assert.NotContains(t, spanAttributes(ended[1]), attrStatusCode, "a cache hit sends no request")
+}
+
+func TestTelemetry_ErrorSpan(t *testing.T) {
+	client, mockHTTP, spans, _ := newTelemetryTestClient(t)
+
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(404, `{"error": 404}`, nil), nil).Once()
+
+	_, err := client.GetComments(t.Context(), "golang", "abc123", "")
+	require.Error(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+	assert.Equal(t, "reddit.get_comments", ended[0].Name())
+	assert.Equal(t, codes.Error, ended[0].Status().Code)
+	assert.Equal(t, int64(404), spanAttributes(ended[0])[attrStatusCode].AsInt64())
+}
+
+func TestTelemetry_Metrics(t *testing.T) {
+	client, mockHTTP, _, metrics := newTelemetryTestClient(t)
+
+	mockHTTP.On("Do", mock.Anything).Return((*http.Response)(nil), errors.New("connection reset")).Once()
+	mockHTTP.On("Do", mock.Anything).Return(func(*http.Request) *http.Response {
+		return createHTTPResponse(200, emptyListing, nil)
+	}, nil).Twice()
+
+	for range 2 {
+		_, err := client.GetSubredditListing(t.Context(), "golang", SortNew, nil)
+		require.NoError(t, err)
+	}
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, metrics.Collect(t.Context(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	assert.Equal(t, instrumentationName, rm.ScopeMetrics[0].Scope.Name)
+
+	got := map[string]metricdata.Aggregation{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		got[m.Name] = m.Data
+	}
+
+	requests := got["reddit.client.requests"].(metricdata.Sum[int64])
+	require.Len(t, requests.DataPoints, 1)
+	assert.Equal(t, int64(2), requests.DataPoints[0].Value)
+	status, _ := requests.DataPoints[0].Attributes.Value(attrStatusCode)
+	assert.Equal(t, int64(200), status.AsInt64())
+
+	retries := got["reddit.client.retries"].(metricdata.Sum[int64])
+	require.Len(t, retries.DataPoints, 1)
+	assert.Equal(t, int64(1), retries.DataPoints[0].Value)
+
+	duration := got["reddit.client.duration"].(metricdata.Histogram[float64])
+	require.Len(t, duration.DataPoints, 1)
+	assert.Equal(t, uint64(2), duration.DataPoints[0].Count)
+}
+
+func TestTelemetry_MetricsOmitSubreddit(t *testing.T) {
+	client, mockHTTP, spans, metrics := newTelemetryTestClient(t)
+
+	mockHTTP.On("Do", mock.Anything).Return(func(*http.Request) *http.Response {
+		return createHTTPResponse(200, emptyListing, nil)
+	}, nil)
+
+	for _, subreddit := range []string{"golang", "rust", "python"} {
+		_, err := client.GetSubredditListing(t.Context(), subreddit, SortHot, nil)
+		require.NoError(t, err)
+	}
+
+	// Each span names its subreddit
+	ended := spans.Ended()
+	require.Len(t, ended, 3)
+	assert.Equal(t, "rust", spanAttributes(ended[1])[attrSubreddit].AsString())
+
+	// while the metrics share one series
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, metrics.Collect(t.Context(), &rm))
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name != "reddit.client.requests" {
+			continue
+		}
+		points := m.Data.(metricdata.Sum[int64]).DataPoints
+		require.Len(t, points, 1)
+		assert.Equal(t, int64(3), points[0].Value)
+		assert.False(t, points[0].Attributes.HasValue(attrSubreddit))
+		sort, _ := points[0].Attributes.Value(attrSort)
+		assert.Equal(t, "hot", sort.AsString())
+	}
+}
+
+func TestTelemetry_GenericOperation(t *testing.T) {
+	client, mockHTTP, spans, metrics := newTelemetryTestClient(t)
+
+	mockHTTP.On("Do", mock.Anything).Return(func(*http.Request) *http.Response {
+		return createHTTPResponse(200, `{}`, nil)
+	}, nil)
+
+	for _, endpoint := range []string{"/r/golang/about/rules.json", "/r/rust/about/rules.json"} {
+		_, err := client.GetRaw(t.Context(), endpoint, nil)
+		require.NoError(t, err)
+	}
+
+	ended := spans.Ended()
+	require.Len(t, ended, 2)
+	assert.Equal(t, "reddit.request", ended[0].Name())
+	assert.Equal(t, "/r/golang/about/rules.json", spanAttributes(ended[0])[attrPath].AsString())
+	assert.Equal(t, "/r/rust/about/rules.json", spanAttributes(ended[1])[attrPath].AsString())
+
+	// The path stays off the metrics
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, metrics.Collect(t.Context(), &rm))
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name != "reddit.client.requests" {
+			continue
+		}
+		for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+			op, _ := point.Attributes.Value(attrOperation)
+			assert.Equal(t, "reddit.request", op.AsString())
+			assert.False(t, point.Attributes.HasValue(attrPath))
+		}
+	}
+}
+
+func TestTelemetry_DisabledByDefault(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	assert.Nil(t, client.telemetry)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		// No call is attached to the request context
+		return callFrom(req.Context()) == nil
+	})).Return(createHTTPResponse(200, emptyListing, nil), nil).Once()
+
+	_, err := client.GetSubredditListing(t.Context(), "golang", SortNew, nil)
+	require.NoError(t, err)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestTelemetryOptions_RejectNil(t *testing.T) {
+	_, err := NewClient(WithTracerProvider(nil))
+	assert.Error(t, err)
+	_, err = NewClient(WithMeterProvider(nil))
+	assert.Error(t, err)
+}
+
+func TestOperationName(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{"/r/golang/hot.json", "reddit.get_subreddit"},
+		{"/r/golang+rust/new.json", "reddit.get_subreddit"},
+		{"/r/golang/about.json", "reddit.get_subreddit_about"},
+		{"/r/golang/random.json", "reddit.get_random_post"},
+		{"/r/golang/comments/abc123.json", "reddit.get_comments"},
+		{"/r/golang/comments/abc123/comment/def456.json", "reddit.get_comment"},
+		{"/r/golang/search.json", "reddit.search"},
+		{"/search.json", "reddit.search"},
+		{"/user/gopher/about.json", "reddit.get_user"},
+		{"/user/gopher/submitted.json", "reddit.get_user_listing"},
+		{"/api/morechildren.json", "reddit.morechildren"},
+		{"/api/v1/user/gopher/trophies", "reddit.get_trophies"},
+		{"/api/submit", "reddit.submit"},
+		{"/subreddits/mine/subscriber.json", "reddit.subreddits_mine_subscriber"},
+		{"/comments/abc123.json", "reddit.get_comments"},
+		{"/r/golang/wiki/index.json", "reddit.get_wiki_page"},
+		{"/r/golang/wiki/config/sidebar.json", "reddit.get_wiki_page"},
+		{"/r/golang/wiki/pages.json", "reddit.get_wiki_pages"},
+		{"/user/gopher/m/news/hot.json", "reddit.get_multireddit"},
+		{"/user/gopher/saved.json", "reddit.get_user_listing"},
+		{"/api/v1/me", "reddit.me"},
+		{"/r/golang/about/rules.json", "reddit.request"},
+		{"/api/v1/me/prefs", "reddit.request"},
+		{"/user/gopher/m/news/top/extra.json", "reddit.request"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			assert.Equal(t, tt.want, operationName(tt.endpoint))
+		})
+	}
+}
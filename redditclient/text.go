@@ -0,0 +1,48 @@
+package redditclient
+
+import (
+	"html"
+	"reflect"
+)
+
+// escapedFields lists the text fields Reddit HTML-escapes when raw_json is
+// ignored, keyed by the type holding them
+var escapedFields = map[reflect.Type][]string{
+	reflect.TypeFor[Post]():     {"Title", "SelfText"},
+	reflect.TypeFor[Comment]():  {"Body"},
+	reflect.TypeFor[WikiPage](): {"ContentMD"},
+}
+
+// unescapeText replaces HTML entities in the text fields of every Post,
+// Comment and WikiPage reachable from v. Some endpoints ignore raw_json=1 and
+// return "&amp;", "&lt;" and "&gt;" escapes; unescaping text that already came
+// back raw leaves it unchanged unless it literally contains an entity.
+func unescapeText(v any) {
+	unescapeValue(reflect.ValueOf(v))
+}
+
+func unescapeValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			unescapeValue(v.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			unescapeValue(v.Index(i))
+		}
+	case reflect.Struct:
+		if !v.CanSet() {
+			return
+		}
+		for _, name := range escapedFields[v.Type()] {
+			field := v.FieldByName(name)
+			field.SetString(html.UnescapeString(field.String()))
+		}
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				unescapeValue(v.Field(i))
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package redditclient
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The same thread as served with raw_json=1 honored and with it ignored
+const (
+	rawThreadBody = `[{"kind": "Listing", "data": {"children": [{"kind": "t3", "data": {"id": "abc", "name": "t3_abc",
+		"title": "Tom & Jerry <3", "selftext": "a > b && b < c"}}]}},
+		{"kind": "Listing", "data": {"children": [{"kind": "t1", "data": {"id": "c1", "name": "t1_c1",
+			"body": "Q&A: use <br> tags", "replies": {"kind": "Listing", "data": {"children": [
+				{"kind": "t1", "data": {"id": "c2", "name": "t1_c2", "body": "\"quoted\" & more", "replies": ""}}]}}}}]}}]`
+
+	escapedThreadBody = `[{"kind": "Listing", "data": {"children": [{"kind": "t3", "data": {"id": "abc", "name": "t3_abc",
+		"title": "Tom &amp; Jerry &lt;3", "selftext": "a &gt; b &amp;&amp; b &lt; c"}}]}},
+		{"kind": "Listing", "data": {"children": [{"kind": "t1", "data": {"id": "c1", "name": "t1_c1",
+			"body": "Q&amp;A: use &lt;br&gt; tags", "replies": {"kind": "Listing", "data": {"children": [
+				{"kind": "t1", "data": {"id": "c2", "name": "t1_c2", "body": "&quot;quoted&quot; &amp; more", "replies": ""}}]}}}}]}}]`
+)
+
+func TestUnescapeText_RawAndEscapedMatch(t *testing.T) {
+	for name, body := range map[string]string{"raw_json honored": rawThreadBody, "raw_json ignored": escapedThreadBody} {
+		t.Run(name, func(t *testing.T) {
+			client, srv := newAuthenticatedFakeServerClient(t)
+			srv.Respond("/r/golang/comments/abc.json", http.StatusOK, body)
+
+			resp, err := client.GetComments(t.Context(), "golang", "abc", "")
+
+			require.NoError(t, err)
+			post := resp.PostListing.Data.Children[0].Data
+			assert.Equal(t, "Tom & Jerry <3", post.Title)
+			assert.Equal(t, "a > b && b < c", post.SelfText)
+
+			comment := resp.CommentListing.Data.Children[0].Comment
+			require.NotNil(t, comment)
+			assert.Equal(t, "Q&A: use <br> tags", comment.Body)
+			require.NotNil(t, comment.Replies)
+			assert.Equal(t, `"quoted" & more`, comment.Replies.Data.Children[0].Comment.Body)
+		})
+	}
+}
+
+func TestUnescapeText_StreamedListing(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.Respond("/r/golang/hot.json", http.StatusOK,
+		`{"kind": "Listing", "data": {"children": [{"kind": "t3", "data": {"id": "p1", "title": "Fish &amp; Chips"}}]}}`)
+
+	listing, err := client.GetSubreddit(t.Context(), "golang", "hot")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Fish & Chips", listing.Data.Children[0].Data.Title)
+}
+
+func TestWithRawText_KeepsEntities(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	srv.Respond("/r/golang/comments/abc.json", http.StatusOK, escapedThreadBody)
+	client, err := NewClient(WithBaseURL(srv.URL), WithRawText())
+	require.NoError(t, err)
+	require.NoError(t, client.Authenticate(t.Context()))
+
+	resp, err := client.GetComments(t.Context(), "golang", "abc", "")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Tom &amp; Jerry &lt;3", resp.PostListing.Data.Children[0].Data.Title)
+	assert.Equal(t, "Q&amp;A: use &lt;br&gt; tags", resp.CommentListing.Data.Children[0].Comment.Body)
+}
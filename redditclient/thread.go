@@ -0,0 +1,379 @@
+package redditclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Thing kinds used in Reddit listings
+const (
+	KindComment   = "t1"
+	KindUser      = "t2"
+	KindPost      = "t3"
+	KindMessage   = "t4"
+	KindSubreddit = "t5"
+	KindMore      = "more"
+	KindListing   = "Listing"
+)
+
+// UnmarshalJSON decodes the [post listing, comment listing] array
+func (r *PostAndCommentsResponse) UnmarshalJSON(data []byte) error {
+	raw, err := splitPair(data, "post and comments response")
+	if err != nil {
+		return err
+	}
+
+	var post PostListing
+	if err := json.Unmarshal(raw[0], &post); err != nil {
+		return fmt.Errorf("failed to decode post listing: %w", err)
+	}
+	if err := checkPostListing(&post); err != nil {
+		return err
+	}
+
+	var comments CommentListing
+	if err := json.Unmarshal(raw[1], &comments); err != nil {
+		return fmt.Errorf("failed to decode comment listing: %w", err)
+	}
+	if err := checkCommentListing(&comments); err != nil {
+		return err
+	}
+
+	r.PostListing = post
+	r.CommentListing = comments
+	return nil
+}
+
+// splitPair splits a two-element array response, such as the [post listing,
+// comment listing] of a comments response. what names it in errors.
+func splitPair(data []byte, what string) ([]json.RawMessage, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s is not an array: %w", what, err)
+	}
+	if len(raw) != 2 {
+		return nil, fmt.Errorf("%s has %d elements, expected 2", what, len(raw))
+	}
+
+	return raw, nil
+}
+
+// checkPostListing verifies the first element of a comments response holds posts
+func checkPostListing(post *PostListing) error {
+	if post.Kind != KindListing {
+		return fmt.Errorf("post listing has kind %q, expected %q", post.Kind, KindListing)
+	}
+	for i, child := range post.Data.Children {
+		if child.Kind != KindPost {
+			return fmt.Errorf("post listing child %d has kind %q, expected %q", i, child.Kind, KindPost)
+		}
+	}
+
+	return nil
+}
+
+// checkCommentListing verifies the second element of a comments response is a listing
+func checkCommentListing(comments *CommentListing) error {
+	if comments.Kind != KindListing {
+		return fmt.Errorf("comment listing has kind %q, expected %q", comments.Kind, KindListing)
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the response back into Reddit's two-element array shape
+func (r PostAndCommentsResponse) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]interface{}{r.PostListing, r.CommentListing})
+}
+
+// Post returns the submission the comment thread belongs to
+func (r *PostAndCommentsResponse) Post() (*Post, error) {
+	if len(r.PostListing.Data.Children) == 0 {
+		return nil, errors.New("post listing is empty")
+	}
+
+	return &r.PostListing.Data.Children[0].Data, nil
+}
+
+func (r *PostAndCommentsResponse) setRestriction(restriction Restriction) {
+	r.PostListing.Restriction = restriction
+	r.CommentListing.Restriction = restriction
+}
+
+// Comments returns the top-level comment listing
+func (r *PostAndCommentsResponse) Comments() (*CommentListing, error) {
+	if r.CommentListing.Kind != KindListing {
+		return nil, errors.New("response has no comment listing")
+	}
+
+	return &r.CommentListing, nil
+}
+
+// rawThing is the {"kind", "data"} envelope around every listing child
+type rawThing struct {
+	Kind string          `json:"kind"`
+	Data json.RawMessage `json:"data"`
+}
+
+// thingDecoder is a listing child that decodes its data given its kind
+type thingDecoder interface {
+	decodeData(kind string, data []byte) error
+}
+
+// thingData is the data of a {"kind", "data"} envelope, decoded in place by
+// into once kind is known. Copying each child's data out first, as
+// rawThing does, copies a comment's whole subtree at every level of nesting.
+// Reddit sends kind first; data that comes before it is held until the
+// envelope is decoded.
+type thingData struct {
+	kind    *string
+	into    thingDecoder
+	held    []byte
+	decoded bool
+}
+
+func (d *thingData) UnmarshalJSON(data []byte) error {
+	if *d.kind == "" {
+		d.held = slices.Clone(data)
+		return nil
+	}
+	d.decoded = true
+	return d.into.decodeData(*d.kind, data)
+}
+
+// thingEnvelope is what decodeThing decodes a listing child into
+type thingEnvelope struct {
+	Kind *string   `json:"kind"`
+	Data thingData `json:"data"`
+}
+
+// thingEnvelopePool holds the envelopes decodeThing decodes into. Passing one
+// to json.Unmarshal moves it to the heap, which would otherwise cost an
+// allocation for every child of every listing.
+var thingEnvelopePool = sync.Pool{New: func() any { return new(thingEnvelope) }}
+
+// decodeThing decodes a {"kind", "data"} envelope, storing its kind in kind
+// and passing its data to into
+func decodeThing(data []byte, kind *string, into thingDecoder) error {
+	envelope := thingEnvelopePool.Get().(*thingEnvelope)
+	defer func() {
+		*envelope = thingEnvelope{}
+		thingEnvelopePool.Put(envelope)
+	}()
+
+	*envelope = thingEnvelope{Kind: kind, Data: thingData{kind: kind, into: into}}
+	if err := json.Unmarshal(data, envelope); err != nil {
+		return err
+	}
+	if envelope.Data.decoded {
+		return nil
+	}
+	return into.decodeData(*kind, envelope.Data.held)
+}
+
+// UnmarshalJSON decodes the child's data into a Comment or MoreComments based on its kind
+func (c *CommentChild) UnmarshalJSON(data []byte) error {
+	*c = CommentChild{}
+	return decodeThing(data, &c.Kind, c)
+}
+
+func (c *CommentChild) decodeData(kind string, data []byte) error {
+	switch kind {
+	case KindComment:
+		c.Comment = &Comment{}
+		if err := json.Unmarshal(data, c.Comment); err != nil {
+			return fmt.Errorf("failed to decode comment: %w", err)
+		}
+	case KindMore:
+		scratch := moreScratch.get()
+		c.More = &MoreComments{Children: *scratch}
+		err := json.Unmarshal(data, c.More)
+		c.More.Children = moreScratch.keep(scratch, c.More.Children)
+		if err != nil {
+			return fmt.Errorf("failed to decode more comments: %w", err)
+		}
+	default:
+		return fmt.Errorf("unexpected comment child kind %q", kind)
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the child back into Reddit's {"kind", "data"} shape
+func (c CommentChild) MarshalJSON() ([]byte, error) {
+	var data interface{}
+	switch {
+	case c.Comment != nil:
+		data = c.Comment
+	case c.More != nil:
+		data = c.More
+	}
+
+	return marshalThing(c.Kind, data)
+}
+
+// UnmarshalJSON decodes the child's data into a Post or Comment based on its kind
+func (c *ThingChild) UnmarshalJSON(data []byte) error {
+	*c = ThingChild{}
+	return decodeThing(data, &c.Kind, c)
+}
+
+func (c *ThingChild) decodeData(kind string, data []byte) error {
+	switch kind {
+	case KindPost:
+		c.Post = &Post{}
+		if err := json.Unmarshal(data, c.Post); err != nil {
+			return fmt.Errorf("failed to decode post: %w", err)
+		}
+	case KindComment:
+		c.Comment = &Comment{}
+		if err := json.Unmarshal(data, c.Comment); err != nil {
+			return fmt.Errorf("failed to decode comment: %w", err)
+		}
+	case KindMessage:
+		c.Message = &Message{}
+		if err := json.Unmarshal(data, c.Message); err != nil {
+			return fmt.Errorf("failed to decode message: %w", err)
+		}
+	default:
+		return fmt.Errorf("unexpected listing child kind %q", kind)
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the child back into Reddit's {"kind", "data"} shape
+func (c ThingChild) MarshalJSON() ([]byte, error) {
+	var data interface{}
+	switch {
+	case c.Post != nil:
+		data = c.Post
+	case c.Comment != nil:
+		data = c.Comment
+	case c.Message != nil:
+		data = c.Message
+	}
+
+	return marshalThing(c.Kind, data)
+}
+
+// marshalThing wraps data in Reddit's {"kind", "data"} envelope
+func marshalThing(kind string, data interface{}) ([]byte, error) {
+	return json.Marshal(struct {
+		Kind string      `json:"kind"`
+		Data interface{} `json:"data"`
+	}{kind, data})
+}
+
+// commentAlias is Comment without its UnmarshalJSON method
+type commentAlias Comment
+
+// commentDecoder is what Comment.UnmarshalJSON decodes into: the comment,
+// with its replies set aside for commentReplies to decode
+type commentDecoder struct {
+	*commentAlias
+	Replies commentReplies `json:"replies"`
+}
+
+// commentDecoderPool holds commentDecoders, which like thingEnvelopes would
+// otherwise be allocated for every comment
+var commentDecoderPool = sync.Pool{New: func() any { return new(commentDecoder) }}
+
+// UnmarshalJSON decodes a comment, turning Reddit's "" (no replies) into a nil Replies
+func (c *Comment) UnmarshalJSON(data []byte) error {
+	aux := commentDecoderPool.Get().(*commentDecoder)
+	defer func() {
+		*aux = commentDecoder{}
+		commentDecoderPool.Put(aux)
+	}()
+
+	*aux = commentDecoder{commentAlias: (*commentAlias)(c)}
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+	if aux.Replies.err != nil {
+		return fmt.Errorf("failed to decode replies of comment %s: %w", c.ID, aux.Replies.err)
+	}
+	c.Replies = aux.Replies.listing
+
+	return nil
+}
+
+// commentReplies decodes replies in place rather than copying them out
+// first. Its error is kept for Comment.UnmarshalJSON to report once the
+// comment's ID, which Reddit sends after replies, is known.
+type commentReplies struct {
+	listing *CommentListing
+	err     error
+}
+
+func (r *commentReplies) UnmarshalJSON(data []byte) error {
+	r.listing, r.err = decodeReplies(data)
+	return nil
+}
+
+// decodeReplies decodes a replies value, which is either "" or a nested listing
+func decodeReplies(raw json.RawMessage) (*CommentListing, error) {
+	switch string(raw) {
+	case "", "null", `""`, "{}":
+		return nil, nil
+	}
+
+	scratch := replyScratch.get()
+	listing := &CommentListing{Data: CommentListingData{Children: *scratch}}
+	err := json.Unmarshal(raw, listing)
+	listing.Data.Children = replyScratch.keep(scratch, listing.Data.Children)
+	if err != nil {
+		return nil, err
+	}
+	if listing.Kind == "" && len(listing.Data.Children) == 0 {
+		return nil, nil
+	}
+
+	return listing, nil
+}
+
+// Scratch space for the arrays of comment threads: the children of replies
+// listings and the IDs of "more" stubs
+var (
+	replyScratch scratchPool[CommentChild]
+	moreScratch  scratchPool[string]
+)
+
+// scratchPool holds slices that json.Unmarshal appends an array to. A fresh
+// slice grows by doubling, costing up to three allocations for the handful
+// of replies a comment usually has; copying the elements out of a scratch
+// slice costs one.
+type scratchPool[T any] struct {
+	pool sync.Pool // *[]T
+}
+
+// get returns an empty scratch slice
+func (p *scratchPool[T]) get() *[]T {
+	if s, ok := p.pool.Get().(*[]T); ok {
+		return s
+	}
+	s := make([]T, 0, defaultScratchCap)
+	return &s
+}
+
+// keep returns a copy of decoded, which json.Unmarshal appended to the
+// scratch slice s or to a larger copy of it, and puts s back in the pool. A
+// nil decoded, from a JSON null, stays nil.
+func (p *scratchPool[T]) keep(s *[]T, decoded []T) []T {
+	var kept []T
+	if decoded != nil {
+		kept = make([]T, len(decoded))
+		copy(kept, decoded)
+		if cap(decoded) <= maxScratchCap {
+			*s = decoded
+		}
+	}
+	clear((*s)[:cap(*s)])
+	*s = (*s)[:0]
+	p.pool.Put(s)
+	return kept
+}
@@ -0,0 +1,52 @@
+package redditclient
+
+// Placeholders Reddit substitutes for the author and text of deleted or
+// removed content
+const (
+	deletedPlaceholder = "[deleted]"
+	removedPlaceholder = "[removed]"
+)
+
+// Values of Post.RemovedByCategory that mean the author deleted the post
+// rather than it being removed
+const (
+	removedByDeleted = "deleted"
+	removedByAuthor  = "author"
+)
+
+// IsDeleted reports whether the comment's author deleted it. Only the
+// placeholder remains; the original author and body are gone.
+func (c *Comment) IsDeleted() bool {
+	return c.Author == deletedPlaceholder && c.Body == deletedPlaceholder
+}
+
+// IsRemoved reports whether a moderator, an admin or the spam filter removed
+// the comment. Reddit does not say which.
+func (c *Comment) IsRemoved() bool {
+	return c.Body == removedPlaceholder
+}
+
+// IsDeleted reports whether the post's author deleted it
+func (p *Post) IsDeleted() bool {
+	switch p.RemovedByCategory {
+	case removedByDeleted, removedByAuthor:
+		return true
+	case "":
+		return p.Author == deletedPlaceholder && p.SelfText == deletedPlaceholder
+	}
+	return false
+}
+
+// IsRemoved reports whether the post was taken down by someone other than its
+// author, such as a moderator ("moderator"), AutoModerator
+// ("automod_filtered") or Reddit's spam filter ("reddit"). RemovedByCategory
+// holds the reason.
+func (p *Post) IsRemoved() bool {
+	switch p.RemovedByCategory {
+	case removedByDeleted, removedByAuthor:
+		return false
+	case "":
+		return p.SelfText == removedPlaceholder
+	}
+	return true
+}
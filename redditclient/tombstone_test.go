@@ -0,0 +1,61 @@
+package redditclient
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComment_Tombstones(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantDeleted bool
+		wantRemoved bool
+	}{
+		{"live", `{"author": "gopher", "body": "hello"}`, false, false},
+		{"user deletion", `{"author": "[deleted]", "body": "[deleted]"}`, true, false},
+		{"moderator removal", `{"author": "[deleted]", "body": "[removed]"}`, false, true},
+		{"spam filter removal", `{"author": "spammer", "body": "[removed]"}`, false, true},
+		{"deleted account", `{"author": "[deleted]", "body": "still here"}`, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var comment Comment
+			require.NoError(t, json.Unmarshal([]byte(tt.data), &comment))
+
+			assert.Equal(t, tt.wantDeleted, comment.IsDeleted())
+			assert.Equal(t, tt.wantRemoved, comment.IsRemoved())
+		})
+	}
+}
+
+func TestPost_Tombstones(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantDeleted bool
+		wantRemoved bool
+	}{
+		{"live", `{"author": "gopher", "selftext": "hello"}`, false, false},
+		{"user deletion", `{"author": "[deleted]", "selftext": "[deleted]", "removed_by_category": "deleted"}`, true, false},
+		{"user deletion without category", `{"author": "[deleted]", "selftext": "[deleted]"}`, true, false},
+		{"moderator removal", `{"author": "[deleted]", "selftext": "[removed]", "removed_by_category": "moderator"}`, false, true},
+		{"spam filter removal", `{"author": "spammer", "selftext": "[removed]", "removed_by_category": "reddit"}`, false, true},
+		{"automod filter", `{"author": "gopher", "removed_by_category": "automod_filtered"}`, false, true},
+		{"removed without category", `{"author": "[deleted]", "selftext": "[removed]", "removed_by_category": null}`, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var post Post
+			require.NoError(t, json.Unmarshal([]byte(tt.data), &post))
+
+			assert.Equal(t, tt.wantDeleted, post.IsDeleted())
+			assert.Equal(t, tt.wantRemoved, post.IsRemoved())
+		})
+	}
+}
@@ -0,0 +1,403 @@
+package redditclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// orderedTransport is an HTTP/1.1 RoundTripper that writes request headers in
+// the order and casing of androidHeaderOrder. net/http's Transport always
+// canonicalizes header names and sorts them, which no real Android client
+// does. It keeps idle connections for reuse and can go through HTTP or SOCKS5
+// proxies, but does not support HTTP/2.
+type orderedTransport struct {
+	dialer    net.Dialer
+	tlsConfig *tls.Config                           // Cloned per connection; nil uses the defaults
+	proxy     func(*http.Request) (*url.URL, error) // Picks a proxy per request; nil or a nil URL connects directly
+
+	mu   sync.Mutex
+	idle map[string][]*orderedConn // Keyed by scheme and host:port
+}
+
+// orderedConn is one connection held by orderedTransport
+type orderedConn struct {
+	key      string
+	conn     net.Conn
+	br       *bufio.Reader
+	bw       *bufio.Writer
+	reused   bool
+	idleFrom time.Time
+}
+
+// Limits on the idle connections orderedTransport keeps
+const (
+	maxIdleConnsPerHost = 4
+	idleConnTimeout     = 90 * time.Second
+)
+
+func newOrderedTransport() *orderedTransport {
+	return &orderedTransport{
+		dialer: net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second},
+		idle:   make(map[string][]*orderedConn),
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *orderedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		closeRequestBody(req)
+		return nil, fmt.Errorf("unsupported protocol scheme %q", req.URL.Scheme)
+	}
+
+	var proxy *url.URL
+	if t.proxy != nil {
+		var err error
+		if proxy, err = t.proxy(req); err != nil {
+			closeRequestBody(req)
+			return nil, fmt.Errorf("failed to choose proxy: %w", err)
+		}
+		if proxy != nil {
+			if err := validateProxyURL(proxy); err != nil {
+				closeRequestBody(req)
+				return nil, err
+			}
+		}
+	}
+
+	wire, err := encodeOrderedRequest(req, proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		oc, err := t.getConn(req.Context(), req.URL, proxy)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.exchange(req, oc, wire)
+		// A kept-alive connection may have been closed by the server while
+		// idle; the request never reached it, so send it again on a new one
+		if err != nil && oc.reused && errors.Is(err, errStaleConn) {
+			continue
+		}
+		return resp, err
+	}
+}
+
+// errStaleConn reports that a reused connection failed before any of the
+// response arrived
+var errStaleConn = errors.New("connection closed before response")
+
+// exchange writes wire on oc and reads the response to req
+func (t *orderedTransport) exchange(req *http.Request, oc *orderedConn, wire []byte) (*http.Response, error) {
+	// Closing the connection unblocks any read or write when ctx ends
+	stop := context.AfterFunc(req.Context(), func() { oc.conn.Close() })
+
+	fail := func(err error) (*http.Response, error) {
+		stop()
+		oc.conn.Close()
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
+	}
+
+	if _, err := oc.bw.Write(wire); err != nil {
+		return fail(fmt.Errorf("%w: %w", errStaleConn, err))
+	}
+	if err := oc.bw.Flush(); err != nil {
+		return fail(fmt.Errorf("%w: %w", errStaleConn, err))
+	}
+
+	if _, err := oc.br.Peek(1); err != nil {
+		// The server may have read the request before closing, so only
+		// requests that are safe to repeat are retried
+		if req.Method == http.MethodGet || req.Method == http.MethodHead {
+			err = fmt.Errorf("%w: %w", errStaleConn, err)
+		}
+		return fail(err)
+	}
+	resp, err := http.ReadResponse(oc.br, req)
+	if err != nil {
+		return fail(err)
+	}
+
+	body := &orderedBody{
+		ReadCloser: resp.Body,
+		transport:  t,
+		conn:       oc,
+		stop:       stop,
+		keepAlive:  !resp.Close && !req.Close,
+	}
+	if resp.Body == http.NoBody {
+		body.release()
+	} else {
+		resp.Body = body
+	}
+	return resp, nil
+}
+
+// getConn returns an idle connection to u's host through proxy, or dials a
+// new one. proxy may be nil.
+func (t *orderedTransport) getConn(ctx context.Context, u *url.URL, proxy *url.URL) (*orderedConn, error) {
+	key := u.Scheme + "://" + hostPort(u)
+	if proxy != nil {
+		key = proxy.String() + "|" + key
+	}
+	if oc := t.takeIdle(key); oc != nil {
+		return oc, nil
+	}
+
+	var conn net.Conn
+	var err error
+	if proxy != nil {
+		// Plain HTTP goes to an HTTP proxy as absolute-form requests; anything
+		// else is tunneled
+		conn, err = t.dialProxy(ctx, proxy, hostPort(u), u.Scheme == "https")
+	} else {
+		conn, err = t.dialer.DialContext(ctx, "tcp", hostPort(u))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == "https" {
+		cfg := &tls.Config{}
+		if t.tlsConfig != nil {
+			cfg = t.tlsConfig.Clone()
+		}
+		if cfg.ServerName == "" {
+			cfg.ServerName = u.Hostname()
+		}
+		cfg.NextProtos = []string{"http/1.1"}
+
+		tlsConn := tls.Client(conn, cfg)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	return &orderedConn{
+		key:  key,
+		conn: conn,
+		br:   bufio.NewReader(conn),
+		bw:   bufio.NewWriter(conn),
+	}, nil
+}
+
+// takeIdle removes and returns the newest idle connection for key, closing
+// any that have been idle too long
+func (t *orderedTransport) takeIdle(key string) *orderedConn {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	conns := t.idle[key]
+	for len(conns) > 0 {
+		oc := conns[len(conns)-1]
+		conns = conns[:len(conns)-1]
+		if time.Since(oc.idleFrom) < idleConnTimeout {
+			t.idle[key] = conns
+			oc.reused = true
+			return oc
+		}
+		oc.conn.Close()
+	}
+	delete(t.idle, key)
+	return nil
+}
+
+// putIdle keeps oc for reuse, or closes it when its host has enough idle
+// connections already
+func (t *orderedTransport) putIdle(oc *orderedConn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.idle[oc.key]) >= maxIdleConnsPerHost {
+		oc.conn.Close()
+		return
+	}
+	oc.idleFrom = time.Now()
+	t.idle[oc.key] = append(t.idle[oc.key], oc)
+}
+
+// CloseIdleConnections closes every idle connection. http.Client calls it
+// from its own CloseIdleConnections.
+func (t *orderedTransport) CloseIdleConnections() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key, conns := range t.idle {
+		for _, oc := range conns {
+			oc.conn.Close()
+		}
+		delete(t.idle, key)
+	}
+}
+
+// orderedBody returns its connection to the pool once the response has been
+// read to the end, and closes it if the caller stops early
+type orderedBody struct {
+	io.ReadCloser
+	transport *orderedTransport
+	conn      *orderedConn
+	stop      func() bool
+	keepAlive bool
+	once      sync.Once
+}
+
+func (b *orderedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if errors.Is(err, io.EOF) {
+		b.release()
+	}
+	return n, err
+}
+
+// Close drops the connection when the body was not read to the end, rather
+// than draining what is left of it
+func (b *orderedBody) Close() error {
+	dropped := false
+	b.once.Do(func() {
+		b.stop()
+		b.conn.conn.Close()
+		dropped = true
+	})
+
+	err := b.ReadCloser.Close()
+	if dropped {
+		return nil
+	}
+	return err
+}
+
+// release hands the connection back for reuse, unless the context already
+// closed it or either side asked to close it
+func (b *orderedBody) release() {
+	b.once.Do(func() {
+		if b.stop() && b.keepAlive {
+			b.transport.putIdle(b.conn)
+			return
+		}
+		b.conn.conn.Close()
+	})
+}
+
+// encodeOrderedRequest serializes req as an HTTP/1.1 request, writing its
+// headers in androidHeaderOrder and any others after them in sorted order.
+// A plain HTTP request sent to an HTTP proxy uses the absolute URL as its
+// target and carries the proxy's credentials.
+func encodeOrderedRequest(req *http.Request, proxy *url.URL) ([]byte, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	header := req.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Host", host)
+	if body != nil || req.Method == http.MethodPost || req.Method == http.MethodPut {
+		header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	if header.Get("Connection") == "" {
+		if req.Close {
+			header.Set("Connection", "close")
+		} else {
+			header.Set("Connection", "Keep-Alive")
+		}
+	}
+
+	target := req.URL.RequestURI()
+	if isHTTPProxy(proxy) && req.URL.Scheme == "http" {
+		target = req.URL.Scheme + "://" + req.URL.Host + target
+		if auth := proxyAuthorization(proxy); auth != "" {
+			header.Set("Proxy-Authorization", auth)
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %s HTTP/1.1\r\n", req.Method, target)
+
+	writeHeader := func(name string, values []string) error {
+		for _, v := range values {
+			if strings.ContainsAny(name, "\r\n: ") || strings.ContainsAny(v, "\r\n") {
+				return fmt.Errorf("invalid header %q", name)
+			}
+			fmt.Fprintf(&buf, "%s: %s\r\n", name, v)
+		}
+		return nil
+	}
+
+	for _, name := range androidHeaderOrder {
+		key := http.CanonicalHeaderKey(name)
+		if err := writeHeader(name, header[key]); err != nil {
+			return nil, err
+		}
+		delete(header, key)
+	}
+	for _, name := range slices.Sorted(maps.Keys(header)) {
+		if err := writeHeader(name, header[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	buf.WriteString("\r\n")
+	buf.Write(body)
+	return buf.Bytes(), nil
+}
+
+// readRequestBody reads and closes the request body, so its length is known
+// before the headers are written and a stale connection can be retried
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	return body, nil
+}
+
+// closeRequestBody closes req.Body, as a RoundTripper must even on failure
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+// hostPort returns u's host with the scheme's default port filled in
+func hostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	if u.Scheme == "https" {
+		return net.JoinHostPort(u.Hostname(), "443")
+	}
+	return net.JoinHostPort(u.Hostname(), "80")
+}
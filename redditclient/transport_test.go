@@ -0,0 +1,202 @@
+package redditclient
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wireRequest is a request as it arrived on the wire
+type wireRequest struct {
+	conn    int      // Which accepted connection carried it
+	line    string   // Request line
+	headers []string // Header lines, in order and with their original casing
+}
+
+// names returns the header names of r in the order they were sent
+func (r wireRequest) names() []string {
+	names := make([]string, len(r.headers))
+	for i, h := range r.headers {
+		names[i], _, _ = strings.Cut(h, ":")
+	}
+	return names
+}
+
+// wireServer is a raw TCP HTTP/1.1 server that records request bytes before
+// any parsing could canonicalize or reorder them
+type wireServer struct {
+	ln net.Listener
+
+	// closeAfterResponse makes the server hang up after each response without
+	// saying so, as a server timing out an idle connection would
+	closeAfterResponse bool
+
+	mu       sync.Mutex
+	conns    int
+	requests []wireRequest
+}
+
+func newWireServer(t *testing.T) *wireServer {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	srv := &wireServer{ln: ln}
+	go srv.serve()
+	return srv
+}
+
+func (s *wireServer) URL() string {
+	return "http://" + s.ln.Addr().String()
+}
+
+func (s *wireServer) Requests() []wireRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]wireRequest(nil), s.requests...)
+}
+
+func (s *wireServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns++
+		id := s.conns
+		s.mu.Unlock()
+
+		go s.handle(conn, id)
+	}
+}
+
+func (s *wireServer) handle(conn net.Conn, id int) {
+	defer conn.Close()
+	br := bufio.NewReader(conn)
+
+	for {
+		req := wireRequest{conn: id}
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return
+		}
+		req.line = strings.TrimRight(line, "\r\n")
+
+		length := 0
+		for {
+			line, err := br.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			if line == "" {
+				break
+			}
+			req.headers = append(req.headers, line)
+			if name, value, _ := strings.Cut(line, ":"); strings.EqualFold(name, "Content-Length") {
+				length, _ = strconv.Atoi(strings.TrimSpace(value))
+			}
+		}
+		if _, err := br.Discard(length); err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.requests = append(s.requests, req)
+		s.mu.Unlock()
+
+		body, extra := emptyListing, ""
+		if strings.Contains(req.line, "/auth/") {
+			body = `{"access_token": "wire-token", "token_type": "bearer", "expires_in": 3600, "scope": ["*"]}`
+			extra = "x-reddit-loid: wire-loid\r\nx-reddit-session: wire-session\r\n"
+		}
+		resp := "HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n" + extra +
+			"Content-Length: " + strconv.Itoa(len(body)) + "\r\n\r\n" + body
+		if _, err := conn.Write([]byte(resp)); err != nil || s.closeAfterResponse {
+			return
+		}
+	}
+}
+
+func TestOrderedTransport_HeaderOrder(t *testing.T) {
+	srv := newWireServer(t)
+	client, err := NewClient(WithBaseURL(srv.URL()))
+	require.NoError(t, err)
+
+	require.NoError(t, client.Authenticate(t.Context()))
+	_, err = client.Search(t.Context(), "golang", "", "")
+	require.NoError(t, err)
+
+	requests := srv.Requests()
+	require.Len(t, requests, 2)
+
+	auth, api := requests[0], requests[1]
+	assert.Equal(t, "POST /auth/v2/oauth/access-token/loid HTTP/1.1", auth.line)
+	assert.Equal(t, []string{
+		"Authorization",
+		"client-vendor-id",
+		"x-reddit-device-id",
+		"x-reddit-retry",
+		"x-reddit-compression",
+		"x-reddit-qos",
+		"x-reddit-media-codecs",
+		"Content-Type",
+		"Content-Length",
+		"Host",
+		"Connection",
+		"User-Agent",
+	}, auth.names())
+
+	assert.True(t, strings.HasPrefix(api.line, "GET /search.json?"), api.line)
+	assert.Equal(t, []string{
+		"Authorization",
+		"x-reddit-loid",
+		"x-reddit-session",
+		"Host",
+		"Connection",
+		"Accept-Encoding",
+		"User-Agent",
+	}, api.names())
+	assert.Contains(t, api.headers, "x-reddit-loid: wire-loid")
+	assert.Contains(t, api.headers, "Authorization: Bearer wire-token")
+
+	assert.Equal(t, auth.conn, api.conn, "connection should be kept alive between requests")
+}
+
+func TestOrderedTransport_RetriesStaleConnection(t *testing.T) {
+	srv := newWireServer(t)
+	srv.closeAfterResponse = true
+	client, err := NewClient(WithBaseURL(srv.URL()))
+	require.NoError(t, err)
+	client.accessToken = "wire-token"
+	client.authenticated = true
+
+	for range 3 {
+		_, err = client.Search(t.Context(), "golang", "", "")
+		require.NoError(t, err)
+	}
+
+	requests := srv.Requests()
+	require.Len(t, requests, 3)
+	assert.NotEqual(t, requests[0].conn, requests[1].conn)
+	assert.NotEqual(t, requests[1].conn, requests[2].conn)
+}
+
+func TestEncodeOrderedRequest_RejectsHeaderInjection(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+	require.NoError(t, err)
+	req.Header.Set("User-Agent", "x\r\nInjected: yes")
+
+	_, err = encodeOrderedRequest(req, nil)
+	assert.Error(t, err)
+}
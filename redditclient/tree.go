@@ -0,0 +1,92 @@
+package redditclient
+
+import "strings"
+
+// Go does not allow methods on an instantiated generic type such as
+// CommentListing, so the comment tree helpers are functions taking the listing.
+
+// WalkComments calls fn for each comment in the tree in pre-order, parents
+// before their replies, with depth 0 for top-level comments. "more"
+// placeholders are skipped; see MorePlaceholders. Returning false from fn
+// stops the walk. It reports whether the walk ran to completion.
+func WalkComments(listing *CommentListing, fn func(c *Comment, depth int) bool) bool {
+	return walkComments(listing, 0, fn)
+}
+
+func walkComments(listing *CommentListing, depth int, fn func(c *Comment, depth int) bool) bool {
+	if listing == nil {
+		return true
+	}
+
+	for i := range listing.Data.Children {
+		comment := listing.Data.Children[i].Comment
+		if comment == nil {
+			continue
+		}
+		if !fn(comment, depth) || !walkComments(comment.Replies, depth+1, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// FlattenComments returns every comment in the tree in pre-order. The
+// pointers refer into the listing, so edits through them show in the tree.
+func FlattenComments(listing *CommentListing) []*Comment {
+	var comments []*Comment
+	WalkComments(listing, func(c *Comment, _ int) bool {
+		comments = append(comments, c)
+		return true
+	})
+	return comments
+}
+
+// CountComments returns how many comments the tree holds, not counting those
+// behind "more" placeholders
+func CountComments(listing *CommentListing) int {
+	n := 0
+	WalkComments(listing, func(*Comment, int) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// FindComment returns the comment with the given ID or t1_ fullname, or nil
+// if it is not in the tree
+func FindComment(listing *CommentListing, id string) *Comment {
+	id = strings.TrimPrefix(id, KindComment+"_")
+
+	var found *Comment
+	WalkComments(listing, func(c *Comment, _ int) bool {
+		if c.ID == id {
+			found = c
+		}
+		return found == nil
+	})
+	return found
+}
+
+// MorePlaceholders returns every "more" placeholder in the tree in pre-order,
+// so callers can choose which to resolve with GetMoreComments
+func MorePlaceholders(listing *CommentListing) []*MoreComments {
+	var more []*MoreComments
+	collectMore(listing, &more)
+	return more
+}
+
+func collectMore(listing *CommentListing, more *[]*MoreComments) {
+	if listing == nil {
+		return
+	}
+
+	for i := range listing.Data.Children {
+		child := &listing.Data.Children[i]
+		switch {
+		case child.More != nil:
+			*more = append(*more, child.More)
+		case child.Comment != nil:
+			collectMore(child.Comment.Replies, more)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package redditclient
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// treeFixture is four levels deep with "more" placeholders between comments:
+//
+//	a
+//	  b
+//	    c
+//	      d
+//	    more:m1
+//	  e
+//	more:m2
+//	f
+const treeFixture = `{"kind": "Listing", "data": {"children": [
+	{"kind": "t1", "data": {"id": "a", "replies": {"kind": "Listing", "data": {"children": [
+		{"kind": "t1", "data": {"id": "b", "replies": {"kind": "Listing", "data": {"children": [
+			{"kind": "t1", "data": {"id": "c", "replies": {"kind": "Listing", "data": {"children": [
+				{"kind": "t1", "data": {"id": "d", "replies": ""}}]}}}},
+			{"kind": "more", "data": {"id": "m1", "children": ["x1", "x2"]}}]}}}},
+		{"kind": "t1", "data": {"id": "e", "replies": ""}}]}}}},
+	{"kind": "more", "data": {"id": "m2", "children": ["x3"]}},
+	{"kind": "t1", "data": {"id": "f", "replies": ""}}
+]}}`
+
+func loadTreeFixture(t *testing.T) *CommentListing {
+	t.Helper()
+
+	var listing CommentListing
+	require.NoError(t, json.Unmarshal([]byte(treeFixture), &listing))
+	return &listing
+}
+
+func TestWalkComments(t *testing.T) {
+	tests := []struct {
+		name       string
+		stopAt     string
+		wantVisits []string
+		wantDepths []int
+		wantDone   bool
+	}{
+		{"full walk", "", []string{"a", "b", "c", "d", "e", "f"}, []int{0, 1, 2, 3, 1, 0}, true},
+		{"stop at deepest", "d", []string{"a", "b", "c", "d"}, []int{0, 1, 2, 3}, false},
+		{"stop at first", "a", []string{"a"}, []int{0}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var visits []string
+			var depths []int
+			done := WalkComments(loadTreeFixture(t), func(c *Comment, depth int) bool {
+				visits = append(visits, c.ID)
+				depths = append(depths, depth)
+				return c.ID != tt.stopAt
+			})
+
+			assert.Equal(t, tt.wantVisits, visits)
+			assert.Equal(t, tt.wantDepths, depths)
+			assert.Equal(t, tt.wantDone, done)
+		})
+	}
+}
+
+func TestFlattenAndCountComments(t *testing.T) {
+	tests := []struct {
+		name    string
+		listing *CommentListing
+		wantIDs []string
+	}{
+		{"nested", loadTreeFixture(t), []string{"a", "b", "c", "d", "e", "f"}},
+		{"empty", &CommentListing{}, nil},
+		{"nil", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			for _, c := range FlattenComments(tt.listing) {
+				ids = append(ids, c.ID)
+			}
+
+			assert.Equal(t, tt.wantIDs, ids)
+			assert.Equal(t, len(tt.wantIDs), CountComments(tt.listing))
+		})
+	}
+}
+
+func TestFindComment(t *testing.T) {
+	listing := loadTreeFixture(t)
+
+	tests := []struct {
+		id     string
+		wantID string // Empty when the comment should not be found
+	}{
+		{"a", "a"},
+		{"d", "d"},
+		{"t1_e", "e"},
+		{"m1", ""},
+		{"missing", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			comment := FindComment(listing, tt.id)
+			if tt.wantID == "" {
+				assert.Nil(t, comment)
+				return
+			}
+			require.NotNil(t, comment)
+			assert.Equal(t, tt.wantID, comment.ID)
+		})
+	}
+
+	// The result points into the tree
+	FindComment(listing, "d").Body = "edited"
+	assert.Equal(t, "edited", listing.Data.Children[0].Comment.Replies.Data.Children[0].Comment.
+		Replies.Data.Children[0].Comment.Replies.Data.Children[0].Comment.Body)
+}
+
+func TestMorePlaceholders(t *testing.T) {
+	more := MorePlaceholders(loadTreeFixture(t))
+
+	require.Len(t, more, 2)
+	assert.Equal(t, "m1", more[0].ID)
+	assert.Equal(t, []string{"x1", "x2"}, more[0].Children)
+	assert.Equal(t, "m2", more[1].ID)
+	assert.Empty(t, MorePlaceholders(nil))
+}
@@ -0,0 +1,660 @@
+package redditclient
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Error variables
+var (
+	ErrNotAuthenticated = errors.New("client is not authenticated - call Authenticate() first")
+)
+
+// HTTPClient interface for dependency injection
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// RedditClient interface for testability
+type RedditClient interface {
+	Authenticate(ctx context.Context) error
+	AuthenticateScript(ctx context.Context, clientID, clientSecret, username, password string) error
+	Verify(ctx context.Context) error
+	GetSubreddit(ctx context.Context, subreddit, sort string) (*SubredditListing, error)
+	GetSubredditPage(ctx context.Context, subreddit, sort string, opts *ListingOptions) (*SubredditListing, error)
+	GetSubredditListing(ctx context.Context, subreddit string, sort Sort, opts *ListingOptions) (*SubredditListing, error)
+	GetSubredditTop(ctx context.Context, subreddit string, tf Timeframe, opts *ListingOptions) (*SubredditListing, error)
+	GetSubredditControversial(ctx context.Context, subreddit string, tf Timeframe, opts *ListingOptions) (*SubredditListing, error)
+	GetCombinedSubreddits(ctx context.Context, subreddits []string, sort string, opts *ListingOptions) (*SubredditListing, error)
+	GetSubredditAbout(ctx context.Context, subreddit string) (*SubredditAbout, error)
+	GetStickies(ctx context.Context, subreddit string) ([]Post, error)
+	GetFrontpage(ctx context.Context, sort string, opts *ListingOptions) (*SubredditListing, error)
+	GetPopular(ctx context.Context, sort string, opts *ListingOptions) (*SubredditListing, error)
+	GetAll(ctx context.Context, sort string, opts *ListingOptions) (*SubredditListing, error)
+	GetPost(ctx context.Context, subreddit, postID string) (*Post, error)
+	GetRandomPost(ctx context.Context, subreddit string) (*Post, *CommentListing, error)
+	GetPostsByID(ctx context.Context, fullnames []string) ([]Post, error)
+	ResolvePostURL(ctx context.Context, rawURL string) (subreddit, postID string, err error)
+	ResolveURL(ctx context.Context, rawURL string) (*PostRef, error)
+	GetUser(ctx context.Context, username string) (*UserResponse, error)
+	GetUserPosts(ctx context.Context, username, sort, timeframe string, opts *ListingOptions) (*PostListing, error)
+	GetUserComments(ctx context.Context, username, sort, timeframe string, opts *ListingOptions) (*CommentListing, error)
+	GetUserOverview(ctx context.Context, username string, opts *ListingOptions) (*ThingListing, error)
+	GetUserTrophies(ctx context.Context, username string) ([]Trophy, error)
+	GetMultireddits(ctx context.Context, username string) ([]Multireddit, error)
+	GetWikiPage(ctx context.Context, subreddit, page string) (*WikiPage, error)
+	ListWikiPages(ctx context.Context, subreddit string) ([]string, error)
+	GetMultiListing(ctx context.Context, username, multiName, sort string, opts *ListingOptions) (*PostListing, error)
+	GetSubscriptions(ctx context.Context, opts *ListingOptions) (*SubscriptionListing, error)
+	GetTrendingSubreddits(ctx context.Context) (*TrendingSubreddits, error)
+	GetDefaultSubreddits(ctx context.Context, opts *ListingOptions) (*SubscriptionListing, error)
+	GetPopularSubreddits(ctx context.Context, opts *ListingOptions) (*SubscriptionListing, error)
+	GetSaved(ctx context.Context, username string, opts *ListingOptions) (*ThingListing, error)
+	Vote(ctx context.Context, fullname string, dir int) error
+	Save(ctx context.Context, fullname string) error
+	Unsave(ctx context.Context, fullname string) error
+	PostComment(ctx context.Context, parentFullname, markdown string) (*Comment, error)
+	GetInbox(ctx context.Context, opts *ListingOptions) (*ThingListing, error)
+	GetUnread(ctx context.Context, opts *ListingOptions) (*ThingListing, error)
+	MarkRead(ctx context.Context, fullnames []string) error
+	SubmitPost(ctx context.Context, subreddit string, opts SubmitOptions) (*Post, error)
+	GetLinkFlairs(ctx context.Context, subreddit string) ([]FlairTemplate, error)
+	Search(ctx context.Context, query, sort, timeframe string) (*SearchResponse, error)
+	SearchWithOptions(ctx context.Context, query string, opts *SearchOptions) (*SearchResults, error)
+	SearchSubreddits(ctx context.Context, query string, opts *SubredditSearchOptions) ([]SubredditSuggestion, error)
+	GetComments(ctx context.Context, subreddit, postID, sort string) (*PostAndCommentsResponse, error)
+	GetCommentsWithOptions(ctx context.Context, subreddit, postID string, opts *CommentOptions) (*PostAndCommentsResponse, error)
+	GetComment(ctx context.Context, subreddit, postID, commentID string, contextDepth int) (*PostAndCommentsResponse, error)
+	GetDuplicates(ctx context.Context, postID string, opts *ListingOptions) (*Post, []Post, error)
+	GetDuplicatesWithOptions(ctx context.Context, postID string, opts *DuplicatesOptions) (*DuplicatesResponse, error)
+	GetMoreComments(ctx context.Context, linkID string, children []string) (*MoreChildrenResponse, error)
+	GetMoreCommentsWithSort(ctx context.Context, linkID string, children []string, sort CommentSort) (*MoreChildrenResponse, error)
+	GetAllComments(ctx context.Context, subreddit, postID string, opts *CommentOptions) (*CommentListing, error)
+	GetJSON(ctx context.Context, endpoint string, params url.Values, v any) error
+	GetRaw(ctx context.Context, endpoint string, params url.Values) ([]byte, error)
+}
+
+// Client implements RedditClient
+type Client struct {
+	httpClient     HTTPClient
+	authenticated  bool
+	authMode       AuthMode
+	accessToken    string
+	loid           string
+	session        string
+	script         scriptCredentials // Set in AuthScript mode to repeat the grant
+	deviceID       string
+	clientID       string   // Installed-app OAuth client ID for Authenticate
+	scopes         []string // OAuth scopes Authenticate requests
+	userAgent      string   // Guarded by credLock, as it is paired with the session
+	userAgentPool  []string // User agents pickUserAgent chooses from
+	rotateUA       bool     // Picks a new user agent from the pool on each re-authentication
+	uaGeneration   uint64   // Sessions rotateUA has started; guarded by authLock
+	baseURL        string
+	authURL        string
+	logger         *slog.Logger
+	tokenExpiry    time.Time
+	refreshMargin  time.Duration
+	authLock       sync.Mutex   // Serializes authentication requests
+	credLock       sync.RWMutex // Guards the credentials, token expiry and refresh margin
+	now            func() time.Time
+	rateLimitLock  sync.RWMutex
+	rateLimit      float64
+	rateLimitUsed  float64
+	rateLimitReset time.Time
+	rateLimitMode  RateLimitBehavior
+	rateLimitFloor float64
+	refreshing     atomic.Bool
+	retryPolicy    atomic.Pointer[RetryPolicy] // Never nil; replaced whole by SetRetryPolicy
+	sleep          func(ctx context.Context, d time.Duration) error
+	rng            *rand.Rand   // Randomness for the device ID, x-reddit-qos and retry jitter; see WithRandSource
+	qos            QoSGenerator // Generates the x-reddit-qos header
+	maxRespBytes   int64
+	maxErrorBody   int // Bytes of response body kept on an APIError
+	cache          Cache
+	cacheTTL       time.Duration
+	rawText        bool                // Skips unescaping HTML entities in decoded text
+	contentOptIn   []Restriction       // Content warnings handleRestrictedContent opts past
+	flight         *singleflight.Group // Coalesces identical requests; nil when disabled
+	telemetry      *telemetry          // Spans and metrics; nil without a TracerProvider or MeterProvider
+	breaker        *circuitBreaker     // nil unless WithCircuitBreaker is set
+	gzipReaderPool sync.Pool           // *gzip.Reader; Get returns nil when empty
+	brotliPool     sync.Pool           // *brotli.Reader
+	zstdPool       sync.Pool           // *zstd.Decoder
+}
+
+// Compile-time check that Client satisfies RedditClient
+var _ RedditClient = (*Client)(nil)
+
+// RateLimitBehavior controls what the client does when the rate limit quota runs out
+type RateLimitBehavior int
+
+const (
+	// IgnoreLimit sends requests regardless of the remaining quota
+	IgnoreLimit RateLimitBehavior = iota
+	// WaitOnLimit blocks requests until the rate limit window resets
+	WaitOnLimit
+)
+
+// RetryPolicy controls automatic retries of transient API failures.
+// The zero value disables retries.
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts including the first; values below 2 disable retries
+	BaseDelay   time.Duration // Delay before the first retry, doubled on each subsequent one
+	MaxDelay    time.Duration // Upper bound on any single delay; a 429 asking for a longer wait is returned instead. Zero means no bound.
+}
+
+// RateLimitStatus is a snapshot of the rate limit headers from the last API response
+type RateLimitStatus struct {
+	Remaining float64       // Requests left in the current window
+	Used      float64       // Requests made in the current window
+	ResetIn   time.Duration // Time until the window resets
+}
+
+// ListingOptions controls pagination of listing endpoints
+type ListingOptions struct {
+	After  string // Fullname of the item to fetch results after, e.g. "t3_abc123"
+	Before string // Fullname of the item to fetch results before
+	Limit  int    // Maximum number of items to return (Reddit caps this at 100)
+	Count  int    // Number of items already seen in this listing
+
+	// GeoFilter restricts r/popular to a region, e.g. "US" or "GLOBAL". It is
+	// ignored by other listings.
+	GeoFilter string
+
+	// Caps used by the iterator helpers; zero means no cap
+	MaxPages int // Maximum number of pages to fetch
+	MaxItems int // Maximum number of items to yield
+}
+
+// CommentOptions controls how a comment thread is fetched
+type CommentOptions struct {
+	Sort     CommentSort
+	Limit    int  // Maximum number of comments to return
+	Depth    int  // Maximum depth of the reply tree
+	Context  int  // Number of parent comments to include when focusing a comment
+	Truncate int  // Truncate the tree after this many top-level comments
+	ShowMore bool // Include "more" placeholders for truncated replies
+
+	// MaxExpansions caps the API calls GetAllComments makes to resolve "more"
+	// placeholders; zero means DefaultMaxCommentExpansions
+	MaxExpansions int
+}
+
+// OAuth response structures
+type OAuthResponse struct {
+	AccessToken string   `json:"access_token"`
+	TokenType   string   `json:"token_type"`
+	ExpiresIn   int      `json:"expires_in"`
+	Scope       []string `json:"scope"`
+}
+
+// API response structures
+// SubredditListing is a page of posts from a subreddit or front page listing
+type SubredditListing = PostListing
+
+type Post struct {
+	ID                         string                   `json:"id"`
+	Name                       string                   `json:"name"`
+	Title                      string                   `json:"title"`
+	Author                     string                   `json:"author"`
+	Subreddit                  string                   `json:"subreddit"`
+	Score                      int                      `json:"score"`
+	ScoreHidden                bool                     `json:"hide_score"` // Score is not yet public and should not be displayed
+	UpvoteRatio                float64                  `json:"upvote_ratio"`
+	URL                        string                   `json:"url"`
+	Permalink                  string                   `json:"permalink"`
+	Domain                     string                   `json:"domain"`
+	SelfText                   string                   `json:"selftext"`
+	SelfTextHTML               string                   `json:"selftext_html"` // Escaped HTML rendering of SelfText; see RenderHTML
+	IsSelf                     bool                     `json:"is_self"`
+	Thumbnail                  string                   `json:"thumbnail"`
+	NumComments                int                      `json:"num_comments"`
+	LinkFlairText              string                   `json:"link_flair_text"`
+	LinkFlairBackgroundColor   string                   `json:"link_flair_background_color"`
+	AuthorFlairText            string                   `json:"author_flair_text"`
+	AuthorFlairBackgroundColor string                   `json:"author_flair_background_color"`
+	AuthorFlairTextColor       string                   `json:"author_flair_text_color"` // "dark" or "light"
+	AuthorFlairRichText        []FlairRun               `json:"author_flair_richtext,omitempty"`
+	Over18                     bool                     `json:"over_18"`
+	Spoiler                    bool                     `json:"spoiler"`
+	Stickied                   bool                     `json:"stickied"` // Pinned to the top of the subreddit, see GetStickies
+	Locked                     bool                     `json:"locked"`
+	RemovedByCategory          string                   `json:"removed_by_category,omitempty"` // Why the post was removed, e.g. "moderator" or "deleted"
+	Distinguished              string                   `json:"distinguished"`                 // "moderator", "admin", or empty
+	Edited                     Edited                   `json:"edited"`
+	Created                    Timestamp                `json:"created_utc"`
+	Preview                    *Preview                 `json:"preview,omitempty"`
+	IsGallery                  bool                     `json:"is_gallery"`
+	GalleryData                *GalleryData             `json:"gallery_data,omitempty"`
+	MediaMetadata              map[string]MediaMetadata `json:"media_metadata,omitempty"`
+	IsVideo                    bool                     `json:"is_video"`
+	Media                      *Media                   `json:"media,omitempty"`
+	SecureMedia                *Media                   `json:"secure_media,omitempty"`
+	CrosspostParent            string                   `json:"crosspost_parent,omitempty"` // Fullname of the original post
+	CrosspostParents           []Post                   `json:"crosspost_parent_list,omitempty"`
+	TotalAwardsReceived        int                      `json:"total_awards_received"`
+	Gilded                     int                      `json:"gilded"` // Gold awards, counted separately by older clients
+	AllAwardings               []Awarding               `json:"all_awardings,omitempty"`
+}
+
+// SubredditAbout is the metadata returned by /r/{subreddit}/about.json
+type SubredditAbout struct {
+	ID                  string    `json:"id"`
+	Name                string    `json:"name"`
+	DisplayName         string    `json:"display_name"`
+	DisplayNamePrefixed string    `json:"display_name_prefixed"`
+	Title               string    `json:"title"`
+	URL                 string    `json:"url"`
+	PublicDescription   string    `json:"public_description"`
+	Description         string    `json:"description"`
+	Subscribers         int       `json:"subscribers"`
+	ActiveUsers         int       `json:"accounts_active"`
+	IconImg             string    `json:"icon_img"`
+	CommunityIcon       string    `json:"community_icon"`
+	BannerImg           string    `json:"banner_img"`
+	BannerBackground    string    `json:"banner_background_image"`
+	PrimaryColor        string    `json:"primary_color"`
+	Over18              bool      `json:"over18"`
+	Quarantine          bool      `json:"quarantine"`
+	SubredditType       string    `json:"subreddit_type"`  // public, private, restricted, gold_restricted, archived
+	SubmissionType      string    `json:"submission_type"` // any, link, self
+	Created             Timestamp `json:"created_utc"`
+}
+
+// Listing is Reddit's paginated container of things. C is the child type,
+// either a Thing[T] when every child has the same kind or a type like
+// CommentChild that dispatches on kind.
+type Listing[C any] struct {
+	Kind string         `json:"kind"`
+	Data ListingData[C] `json:"data"`
+
+	// Restriction is the content warning the client opted past to fetch the
+	// listing, or empty if Reddit showed none
+	Restriction Restriction `json:"-"`
+}
+
+func (l *Listing[C]) setRestriction(r Restriction) { l.Restriction = r }
+
+// ListingData holds a listing's children and pagination cursors
+type ListingData[C any] struct {
+	Children  []C    `json:"children"`
+	After     string `json:"after"`
+	Before    string `json:"before"`
+	Dist      int    `json:"dist"`                 // Number of children, when Reddit reports it
+	Modhash   string `json:"modhash,omitempty"`    // Legacy CSRF token, empty for OAuth clients
+	GeoFilter string `json:"geo_filter,omitempty"` // Region r/popular was filtered to, see ListingOptions.GeoFilter
+}
+
+// Thing is a kind-tagged object whose data has a single known type
+type Thing[T any] struct {
+	Kind string `json:"kind"`
+	Data T      `json:"data"`
+}
+
+// PostListing is a listing of t3 (post) children
+type PostListing = Listing[PostChild]
+
+type PostListingData = ListingData[PostChild]
+
+type PostChild = Thing[Post]
+
+// CommentListing is a listing of t1 (comment) and "more" children
+type CommentListing = Listing[CommentChild]
+
+type CommentListingData = ListingData[CommentChild]
+
+// CommentChild is a child of a comment listing. Exactly one of Comment or
+// More is set, depending on Kind.
+type CommentChild struct {
+	Kind    string
+	Comment *Comment
+	More    *MoreComments
+}
+
+type Comment struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Author    string          `json:"author"`
+	Body      string          `json:"body"`
+	BodyHTML  string          `json:"body_html"` // Escaped HTML rendering of Body; see RenderHTML
+	Score     int             `json:"score"`
+	Subreddit string          `json:"subreddit"`
+	LinkID    string          `json:"link_id"`
+	ParentID  string          `json:"parent_id"`
+	Permalink string          `json:"permalink"`
+	Depth     int             `json:"depth"`
+	Created   Timestamp       `json:"created_utc"`
+	Replies   *CommentListing `json:"replies"` // nil when the comment has no replies
+
+	Stickied            bool   `json:"stickied"`              // Pinned to the top of the thread, as AutoModerator's comments often are
+	Distinguished       string `json:"distinguished"`         // "moderator", "admin", or empty
+	IsSubmitter         bool   `json:"is_submitter"`          // Written by the post's author
+	ScoreHidden         bool   `json:"score_hidden"`          // Score is not yet public; see DisplayScore
+	Collapsed           bool   `json:"collapsed"`             // Shown collapsed by default
+	CollapsedReason     string `json:"collapsed_reason"`      // Why the comment is collapsed, e.g. "comment score below threshold"
+	CollapsedReasonCode string `json:"collapsed_reason_code"` // e.g. "CROWD_CONTROL" or "LOW_SCORE"
+
+	AuthorFlairText            string     `json:"author_flair_text"`
+	AuthorFlairBackgroundColor string     `json:"author_flair_background_color"`
+	AuthorFlairTextColor       string     `json:"author_flair_text_color"` // "dark" or "light"
+	AuthorFlairRichText        []FlairRun `json:"author_flair_richtext,omitempty"`
+
+	TotalAwardsReceived int        `json:"total_awards_received"`
+	Gilded              int        `json:"gilded"`
+	AllAwardings        []Awarding `json:"all_awardings,omitempty"`
+}
+
+// MoreComments is a placeholder for comments that were not included in the
+// response. A "continue this thread" link has an empty Children list.
+type MoreComments struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	ParentID string   `json:"parent_id"`
+	Depth    int      `json:"depth"`
+	Count    int      `json:"count"`
+	Children []string `json:"children"`
+}
+
+// ThingListing is a listing that interleaves posts and comments, such as a user overview
+type ThingListing = Listing[ThingChild]
+
+type ThingListingData = ListingData[ThingChild]
+
+// ThingChild is a child of a mixed listing. Exactly one of Post, Comment or
+// Message is set, depending on Kind.
+type ThingChild struct {
+	Kind    string
+	Post    *Post
+	Comment *Comment
+	Message *Message
+}
+
+// Message is a t4 private message. Inbox listings also report comment
+// replies and username mentions in this shape, with WasComment set.
+type Message struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	Author           string    `json:"author"`
+	Dest             string    `json:"dest"` // Recipient username
+	Subject          string    `json:"subject"`
+	Body             string    `json:"body"`
+	Subreddit        string    `json:"subreddit"` // Set for modmail and comment replies
+	ParentID         string    `json:"parent_id"`
+	FirstMessageName string    `json:"first_message_name"` // Fullname of the first message in the conversation
+	Context          string    `json:"context"`            // Permalink of a comment reply
+	WasComment       bool      `json:"was_comment"`
+	New              bool      `json:"new"` // Unread
+	Created          Timestamp `json:"created_utc"`
+}
+
+// SubscriptionListing is a listing of t5 (subreddit) children, as returned
+// by GetSubscriptions
+type SubscriptionListing = Listing[Thing[SubredditAbout]]
+
+// Multireddit is a user-curated collection of subreddits, the data of a
+// LabeledMulti thing
+type Multireddit struct {
+	Name        string    `json:"name"`
+	DisplayName string    `json:"display_name"`
+	Path        string    `json:"path"` // e.g. "/user/spez/m/news"
+	Owner       string    `json:"owner"`
+	Description string    `json:"description_md"`
+	Subreddits  []string  `json:"subreddits"`
+	Visibility  string    `json:"visibility"` // private, public or hidden
+	IconURL     string    `json:"icon_url"`
+	Over18      bool      `json:"over_18"`
+	Created     Timestamp `json:"created_utc"`
+}
+
+// MoreChildrenResponse is returned by /api/morechildren. Things is a flat list;
+// each comment's ParentID locates it in the tree.
+type MoreChildrenResponse struct {
+	JSON struct {
+		Errors [][]string `json:"errors"`
+		Data   struct {
+			Things []CommentChild `json:"things"`
+		} `json:"data"`
+	} `json:"json"`
+}
+
+// PostAndCommentsResponse is the two-element array returned by the comments
+// endpoint: a listing holding the post, followed by a listing of comments
+type PostAndCommentsResponse struct {
+	PostListing    PostListing
+	CommentListing CommentListing
+}
+
+// DuplicatesResponse is the two-element array returned by the duplicates
+// endpoint: a listing holding the post, followed by a page of other posts
+// linking to the same URL
+type DuplicatesResponse struct {
+	PostListing      PostListing
+	DuplicateListing PostListing
+}
+
+// UserResponse is the t2 thing returned by /user/{name}/about.json
+type UserResponse = Thing[UserAbout]
+
+// UserAbout is a user's public profile summary
+type UserAbout struct {
+	Name             string         `json:"name"`
+	IconImg          string         `json:"icon_img"`
+	LinkKarma        int            `json:"link_karma"`
+	CommentKarma     int            `json:"comment_karma"`
+	TotalKarma       int            `json:"total_karma"`
+	AwardeeKarma     int            `json:"awardee_karma"`
+	AwarderKarma     int            `json:"awarder_karma"`
+	IsGold           bool           `json:"is_gold"`
+	IsMod            bool           `json:"is_mod"`
+	IsEmployee       bool           `json:"is_employee"`
+	Verified         bool           `json:"verified"`
+	HasVerifiedEmail bool           `json:"has_verified_email"`
+	Created          Timestamp      `json:"created_utc"`
+	IsSuspended      bool           `json:"is_suspended"`        // Only Name is set for suspended users
+	Subreddit        *UserSubreddit `json:"subreddit,omitempty"` // Profile page; nil for accounts without one
+}
+
+// UserSubreddit is the u_{name} subreddit backing a user's profile page
+type UserSubreddit struct {
+	DisplayNamePrefixed string `json:"display_name_prefixed"` // e.g. "u/spez"
+	Title               string `json:"title"`
+	PublicDescription   string `json:"public_description"`
+	IconImg             string `json:"icon_img"`
+	BannerImg           string `json:"banner_img"`
+	Over18              bool   `json:"over_18"`
+}
+
+// Trophy is an award shown on a user's profile
+type Trophy struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IconURL70   string    `json:"icon_70"`
+	IconURL40   string    `json:"icon_40"`
+	AwardID     string    `json:"award_id"`
+	GrantedAt   Timestamp `json:"granted_at"` // Unix time; zero when Reddit did not record it
+}
+
+// TrendingSubreddits is the response of /api/trending_subreddits.json
+type TrendingSubreddits struct {
+	SubredditNames []string `json:"subreddit_names"`
+	CommentCount   int      `json:"comment_count"` // Comments on the daily trending post
+	CommentURL     string   `json:"comment_url"`   // Permalink of the daily trending post
+}
+
+// WikiPage is a subreddit wiki page
+type WikiPage struct {
+	ContentMD    string    `json:"content_md"`
+	ContentHTML  string    `json:"content_html"`
+	RevisionDate Timestamp `json:"revision_date"`
+	RevisionBy   string    `json:"revision_by"` // Username of the last editor
+	MayRevise    bool      `json:"may_revise"`  // Whether the authenticated user may edit the page
+}
+
+// trophyList is the TrophyList thing returned by /api/v1/user/{name}/trophies.
+// Unlike a Listing, its t6 children sit under data.trophies.
+type trophyList struct {
+	Kind string `json:"kind"`
+	Data struct {
+		Trophies []Thing[Trophy] `json:"trophies"`
+	} `json:"data"`
+}
+
+// SearchResponse is a page of post results from /search.json
+type SearchResponse = PostListing
+
+// SearchOptions controls SearchWithOptions. Pagination fields come from the
+// embedded ListingOptions.
+type SearchOptions struct {
+	ListingOptions
+	Subreddit     string // Restrict the search to this subreddit
+	Sort          SearchSort
+	Timeframe     Timeframe
+	Type          string // Comma-separated result types: link, sr, user
+	IncludeOver18 bool   // Include NSFW results
+}
+
+// DuplicatesOptions controls GetDuplicatesWithOptions. Pagination fields come
+// from the embedded ListingOptions.
+type DuplicatesOptions struct {
+	ListingOptions
+	Sort           DuplicatesSort
+	CrosspostsOnly bool // Only include crossposts of the post
+}
+
+// SearchResults holds a page of search results. Children are sorted into
+// slices by kind, so which slices are populated depends on SearchOptions.Type.
+type SearchResults struct {
+	Posts      []Post
+	Subreddits []SubredditAbout
+	Users      []UserResponse
+	After      string
+	Before     string
+	Dist       int // Number of results on this page, of all types
+}
+
+// SubmitKind is the type of post created by SubmitPost
+type SubmitKind string
+
+// Submission kinds
+const (
+	SubmitSelf SubmitKind = "self" // A text post whose body is SubmitOptions.Text
+	SubmitLink SubmitKind = "link" // A link to SubmitOptions.URL
+)
+
+// SubmitOptions describes a post created by SubmitPost
+type SubmitOptions struct {
+	Kind    SubmitKind
+	Title   string
+	Text    string // Markdown body of a self post
+	URL     string // Target of a link post
+	NSFW    bool
+	Spoiler bool
+	FlairID string // Flair template ID from GetLinkFlairs
+}
+
+// SubredditSearchOptions controls SearchSubreddits
+type SubredditSearchOptions struct {
+	IncludeOver18   bool // Include NSFW subreddits
+	IncludeProfiles bool // Include user profile subreddits (u_name)
+	Limit           int  // Maximum number of suggestions
+}
+
+// SubredditSuggestion is a single autocomplete result
+type SubredditSuggestion struct {
+	Name        string
+	Subscribers int
+	Icon        string
+	Over18      bool
+}
+
+// Restriction is a content warning Reddit shows in place of a subreddit's
+// content until the reader opts in
+type Restriction string
+
+// Restrictions the client can opt into
+const (
+	RestrictionGated       Restriction = "gated"       // Over-18 subreddit
+	RestrictionQuarantined Restriction = "quarantined" // Quarantined subreddit
+)
+
+// optInCookie returns the cookie accepting all of restrictions
+func optInCookie(restrictions []Restriction) string {
+	gated := slices.Contains(restrictions, RestrictionGated)
+	quarantined := slices.Contains(restrictions, RestrictionQuarantined)
+	switch {
+	case gated && quarantined:
+		return CONTENT_WARNING_ACCEPT_COOKIE
+	case gated:
+		return GATED_ACCEPT_COOKIE
+	default:
+		return QUARANTINE_ACCEPT_COOKIE
+	}
+}
+
+// ErrorResponse is the restriction notice Reddit sends in place of content,
+// e.g. {"reason": "quarantined", "quarantine_message": "..."}
+type ErrorResponse struct {
+	Reason            string `json:"reason"`
+	QuarantineMessage string `json:"quarantine_message"`
+}
+
+// apply sets the comment query parameters on params; a nil receiver is a no-op
+func (o *CommentOptions) apply(params url.Values) {
+	if o == nil {
+		return
+	}
+
+	if o.Sort != "" {
+		params.Set("sort", string(o.Sort))
+	}
+	if o.Limit > 0 {
+		params.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Depth > 0 {
+		params.Set("depth", strconv.Itoa(o.Depth))
+	}
+	if o.Context > 0 {
+		params.Set("context", strconv.Itoa(o.Context))
+	}
+	if o.Truncate > 0 {
+		params.Set("truncate", strconv.Itoa(o.Truncate))
+	}
+	if o.ShowMore {
+		params.Set("showmore", "true")
+	}
+}
+
+// apply sets the pagination query parameters on params; a nil receiver is a no-op
+func (o *ListingOptions) apply(params url.Values) {
+	if o == nil {
+		return
+	}
+
+	if o.After != "" {
+		params.Set("after", o.After)
+	}
+	if o.Before != "" {
+		params.Set("before", o.Before)
+	}
+	if o.Limit > 0 {
+		params.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Count > 0 {
+		params.Set("count", strconv.Itoa(o.Count))
+	}
+	if o.GeoFilter != "" {
+		params.Set("geo_filter", o.GeoFilter)
+	}
+}
@@ -0,0 +1,155 @@
+package redditclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+)
+
+// GetUserPosts fetches the posts a user has submitted. sort is one of new,
+// hot, top or controversial; timeframe applies to top and controversial.
+func (c *Client) GetUserPosts(ctx context.Context, username, sort, timeframe string, opts *ListingOptions) (*PostListing, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	params, err := userListingParams(sort, timeframe, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.makeAPIRequest(ctx, fmt.Sprintf("/user/%s/submitted.json", url.PathEscape(username)), params)
+	if err != nil {
+		return nil, err
+	}
+
+	var listing PostListing
+	if err := c.decodeJSON(ctx, body, &listing, "user posts"); err != nil {
+		return nil, err
+	}
+
+	return &listing, nil
+}
+
+// GetUserComments fetches the comments a user has made. sort is one of new,
+// hot, top or controversial; timeframe applies to top and controversial.
+func (c *Client) GetUserComments(ctx context.Context, username, sort, timeframe string, opts *ListingOptions) (*CommentListing, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	params, err := userListingParams(sort, timeframe, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.makeAPIRequest(ctx, fmt.Sprintf("/user/%s/comments.json", url.PathEscape(username)), params)
+	if err != nil {
+		return nil, err
+	}
+
+	var listing CommentListing
+	if err := c.decodeJSON(ctx, body, &listing, "user comments"); err != nil {
+		return nil, err
+	}
+
+	return &listing, nil
+}
+
+// GetUserOverview fetches a user's combined history of posts and comments
+func (c *Client) GetUserOverview(ctx context.Context, username string, opts *ListingOptions) (*ThingListing, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	params := url.Values{}
+	opts.apply(params)
+
+	body, err := c.makeAPIRequest(ctx, fmt.Sprintf("/user/%s/overview.json", url.PathEscape(username)), params)
+	if err != nil {
+		return nil, err
+	}
+
+	var listing ThingListing
+	if err := c.decodeJSON(ctx, body, &listing, "user overview"); err != nil {
+		return nil, err
+	}
+
+	return &listing, nil
+}
+
+// userListingParams validates sort and timeframe and builds the query for the
+// user listing endpoints
+func userListingParams(sort, timeframe string, opts *ListingOptions) (url.Values, error) {
+	if err := validateOptional(Sort(sort), Sort(sort).validate); err != nil {
+		return nil, err
+	}
+	if err := validateOptional(Timeframe(timeframe), Timeframe(timeframe).validate); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	if sort != "" {
+		params.Set("sort", sort)
+	}
+	if timeframe != "" {
+		params.Set("t", timeframe)
+	}
+	opts.apply(params)
+
+	return params, nil
+}
+
+// GetUserTrophies fetches the trophies shown on a user's profile. It returns
+// ErrUserNotFound if the account does not exist.
+func (c *Client) GetUserTrophies(ctx context.Context, username string) ([]Trophy, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/user/%s/trophies", url.PathEscape(username))
+
+	body, err := c.makeAPIRequest(ctx, endpoint, nil)
+	if err != nil {
+		return nil, userError(username, err)
+	}
+
+	var list trophyList
+	if err := c.decodeJSON(ctx, body, &list, "trophies"); err != nil {
+		return nil, err
+	}
+
+	trophies := make([]Trophy, len(list.Data.Trophies))
+	for i, thing := range list.Data.Trophies {
+		trophies[i] = thing.Data
+	}
+	return trophies, nil
+}
+
+// userError wraps a 404 for username's profile in ErrUserNotFound
+func userError(username string, err error) error {
+	if errors.Is(err, ErrNotFound) && !errors.Is(err, ErrUserNotFound) {
+		return fmt.Errorf("%w: %s: %w", ErrUserNotFound, username, err)
+	}
+	return err
+}
+
+// UnmarshalJSON decodes the profile, unescaping the icon and banner URLs.
+// Reddit sends their resizing query parameters with &amp; entities, which
+// break the URL if it is used as is.
+func (u *UserAbout) UnmarshalJSON(data []byte) error {
+	type userAboutAlias UserAbout
+	if err := json.Unmarshal(data, (*userAboutAlias)(u)); err != nil {
+		return err
+	}
+
+	u.IconImg = html.UnescapeString(u.IconImg)
+	if u.Subreddit != nil {
+		u.Subreddit.IconImg = html.UnescapeString(u.Subreddit.IconImg)
+		u.Subreddit.BannerImg = html.UnescapeString(u.Subreddit.BannerImg)
+	}
+	return nil
+}
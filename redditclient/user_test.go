@@ -0,0 +1,282 @@
+package redditclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUserPosts_Success(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		params := req.URL.Query()
+		return req.URL.Path == "/user/testuser/submitted.json" &&
+			params.Get("sort") == "top" &&
+			params.Get("t") == "year" &&
+			params.Get("after") == "t3_prev" &&
+			params.Get("limit") == "25"
+	})).Return(createHTTPResponse(200, createListingPage([]string{"p1", "p2"}, "t3_p2"), nil), nil)
+
+	result, err := client.GetUserPosts(t.Context(), "testuser", "top", "year", &ListingOptions{After: "t3_prev", Limit: 25})
+
+	require.NoError(t, err)
+	require.Len(t, result.Data.Children, 2)
+	assert.Equal(t, "p1", result.Data.Children[0].Data.ID)
+	assert.Equal(t, "t3_p2", result.Data.After)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGetUserComments_Success(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	body := `{"kind": "Listing", "data": {"after": "t1_c2", "children": [
+		{"kind": "t1", "data": {"id": "c1", "body": "hello", "link_id": "t3_abc", "replies": ""}},
+		{"kind": "t1", "data": {"id": "c2", "body": "world", "link_id": "t3_def", "replies": ""}}
+	]}}`
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		params := req.URL.Query()
+		return req.URL.Path == "/user/testuser/comments.json" &&
+			params.Get("sort") == "new" &&
+			!params.Has("t")
+	})).Return(createHTTPResponse(200, body, nil), nil)
+
+	result, err := client.GetUserComments(t.Context(), "testuser", "new", "", nil)
+
+	require.NoError(t, err)
+	require.Len(t, result.Data.Children, 2)
+	assert.Equal(t, "hello", result.Data.Children[0].Comment.Body)
+	assert.Equal(t, "t1_c2", result.Data.After)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGetUserListings_TypedErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		sentinel error
+	}{
+		{"not found", 404, `{"message": "Not Found", "error": 404}`, ErrNotFound},
+		{"suspended", 403, `{"message": "Forbidden", "error": 403}`, ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mockHTTP := newAuthenticatedTestClient(t)
+			mockHTTP.On("Do", mock.AnythingOfType("*http.Request")).
+				Return(func(*http.Request) *http.Response {
+					return createHTTPResponse(tt.status, tt.body, nil)
+				}, nil).Twice()
+
+			_, err := client.GetUserPosts(t.Context(), "ghost", "new", "", nil)
+			assert.ErrorIs(t, err, tt.sentinel)
+
+			_, err = client.GetUserComments(t.Context(), "ghost", "new", "", nil)
+			assert.ErrorIs(t, err, tt.sentinel)
+		})
+	}
+}
+
+func TestGetUserListings_NotAuthenticated(t *testing.T) {
+	client, err := NewClient(WithHTTPClient(&MockHTTPClient{}))
+	require.NoError(t, err)
+
+	_, err = client.GetUserPosts(t.Context(), "testuser", "new", "", nil)
+	assert.ErrorIs(t, err, ErrNotAuthenticated)
+
+	_, err = client.GetUserComments(t.Context(), "testuser", "new", "", nil)
+	assert.ErrorIs(t, err, ErrNotAuthenticated)
+}
+
+const userOverviewFixture = `{"kind": "Listing", "data": {"after": "t3_p2", "before": null, "children": [
+	{"kind": "t1", "data": {"id": "c1", "name": "t1_c1", "body": "a comment", "link_id": "t3_p0", "subreddit": "golang", "replies": ""}},
+	{"kind": "t3", "data": {"id": "p1", "name": "t3_p1", "title": "a post", "subreddit": "golang", "score": 12}},
+	{"kind": "t1", "data": {"id": "c2", "name": "t1_c2", "body": "another comment", "link_id": "t3_p1", "subreddit": "rust", "replies": ""}},
+	{"kind": "t3", "data": {"id": "p2", "name": "t3_p2", "title": "another post", "subreddit": "rust", "score": 3}}
+]}}`
+
+func TestGetUserOverview_MixedKinds(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Path == "/user/testuser/overview.json" &&
+			req.URL.Query().Get("after") == "t1_prev"
+	})).Return(createHTTPResponse(200, userOverviewFixture, nil), nil)
+
+	result, err := client.GetUserOverview(t.Context(), "testuser", &ListingOptions{After: "t1_prev"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "t3_p2", result.Data.After)
+	require.Len(t, result.Data.Children, 4)
+
+	kinds := make([]string, 0, 4)
+	for _, child := range result.Data.Children {
+		kinds = append(kinds, child.Kind)
+		switch child.Kind {
+		case KindComment:
+			require.NotNil(t, child.Comment)
+			assert.Nil(t, child.Post)
+		case KindPost:
+			require.NotNil(t, child.Post)
+			assert.Nil(t, child.Comment)
+		}
+	}
+	assert.Equal(t, []string{KindComment, KindPost, KindComment, KindPost}, kinds)
+	assert.Equal(t, "a comment", result.Data.Children[0].Comment.Body)
+	assert.Equal(t, "a post", result.Data.Children[1].Post.Title)
+	assert.Equal(t, 3, result.Data.Children[3].Post.Score)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestThingChild_RejectsUnknownKind(t *testing.T) {
+	var listing ThingListing
+	err := json.Unmarshal([]byte(`{"kind": "Listing", "data": {"children": [{"kind": "t9", "data": {}}]}}`), &listing)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unexpected listing child kind "t9"`)
+}
+
+func TestThingChild_MarshalRoundTrip(t *testing.T) {
+	var listing ThingListing
+	require.NoError(t, json.Unmarshal([]byte(userOverviewFixture), &listing))
+
+	data, err := json.Marshal(listing)
+	require.NoError(t, err)
+
+	var decoded ThingListing
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, listing, decoded)
+}
+
+const userAboutFixture = `{"kind": "t2", "data": {
+	"name": "gopher", "link_karma": 10, "comment_karma": 20, "total_karma": 35,
+	"awardee_karma": 3, "awarder_karma": 2, "is_gold": true, "is_mod": true,
+	"is_employee": false, "verified": true, "has_verified_email": null, "created_utc": 1600000000,
+	"icon_img": "https://styles.redditmedia.com/t5_1/styles/profileIcon_x.png?width=256&amp;height=256&amp;s=abc",
+	"subreddit": {"display_name_prefixed": "u/gopher", "title": "Gopher", "public_description": "I dig",
+		"icon_img": "https://styles.redditmedia.com/t5_1/styles/profileIcon_x.png?width=256&amp;s=abc",
+		"banner_img": "https://styles.redditmedia.com/t5_1/styles/profileBanner_x.png?width=1280&amp;s=def",
+		"over_18": false}}}`
+
+func assertUserProfile(t *testing.T, user UserAbout) {
+	t.Helper()
+
+	assert.Equal(t, 35, user.TotalKarma)
+	assert.Equal(t, 3, user.AwardeeKarma)
+	assert.Equal(t, 2, user.AwarderKarma)
+	assert.True(t, user.IsGold)
+	assert.True(t, user.IsMod)
+	assert.False(t, user.IsEmployee)
+	assert.True(t, user.Verified)
+	assert.False(t, user.HasVerifiedEmail)
+	assert.Equal(t, "https://styles.redditmedia.com/t5_1/styles/profileIcon_x.png?width=256&height=256&s=abc", user.IconImg)
+
+	require.NotNil(t, user.Subreddit)
+	assert.Equal(t, "u/gopher", user.Subreddit.DisplayNamePrefixed)
+	assert.Equal(t, "I dig", user.Subreddit.PublicDescription)
+	assert.Equal(t, "https://styles.redditmedia.com/t5_1/styles/profileIcon_x.png?width=256&s=abc", user.Subreddit.IconImg)
+	assert.Equal(t, "https://styles.redditmedia.com/t5_1/styles/profileBanner_x.png?width=1280&s=def", user.Subreddit.BannerImg)
+}
+
+func TestGetUser_AccountStates(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		sentinel error
+	}{
+		{"active", http.StatusOK, userAboutFixture, nil},
+		{"suspended", http.StatusOK, `{"kind": "t2", "data": {"name": "gopher", "is_suspended": true}}`, ErrUserSuspended},
+		{"shadowbanned or missing", http.StatusNotFound, `{"message": "Not Found", "error": 404}`, ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, srv := newAuthenticatedFakeServerClient(t)
+			srv.Respond("/user/gopher/about.json", tt.status, tt.body)
+
+			user, err := client.GetUser(t.Context(), "gopher")
+
+			if tt.sentinel == nil {
+				require.NoError(t, err)
+				assert.Equal(t, "gopher", user.Data.Name)
+				assert.Equal(t, 20, user.Data.CommentKarma)
+				assert.False(t, user.Data.IsSuspended)
+				assertUserProfile(t, user.Data)
+				return
+			}
+			assert.Nil(t, user)
+			assert.ErrorIs(t, err, tt.sentinel)
+			assert.Contains(t, err.Error(), "gopher")
+		})
+	}
+}
+
+func TestGetUser_NotFoundKeepsAPIError(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.Respond("/user/ghost/about.json", http.StatusNotFound, `{"message": "Not Found", "error": 404}`)
+
+	_, err := client.GetUser(t.Context(), "ghost")
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+}
+
+const trophiesFixture = `{"kind": "TrophyList", "data": {"trophies": [
+	{"kind": "t6", "data": {"icon_70": "https://www.redditstatic.com/awards2/verified_email-70.png",
+		"granted_at": null, "url": null, "icon_40": "https://www.redditstatic.com/awards2/verified_email-40.png",
+		"name": "Verified Email", "award_id": null, "id": null, "description": null}},
+	{"kind": "t6", "data": {"icon_70": "https://www.redditstatic.com/awards2/3_year_club-70.png",
+		"granted_at": 1600000000, "icon_40": "https://www.redditstatic.com/awards2/3_year_club-40.png",
+		"name": "Three-Year Club", "award_id": "3", "id": "1q2w3e", "description": "Since 2017"}}
+]}}`
+
+func TestGetUserTrophies_Success(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.Respond("/api/v1/user/gopher/trophies", http.StatusOK, trophiesFixture)
+
+	trophies, err := client.GetUserTrophies(t.Context(), "gopher")
+
+	require.NoError(t, err)
+	require.Len(t, trophies, 2)
+	assert.Equal(t, Trophy{
+		Name:      "Verified Email",
+		IconURL70: "https://www.redditstatic.com/awards2/verified_email-70.png",
+		IconURL40: "https://www.redditstatic.com/awards2/verified_email-40.png",
+	}, trophies[0])
+	assert.Equal(t, "Three-Year Club", trophies[1].Name)
+	assert.Equal(t, "Since 2017", trophies[1].Description)
+	assert.Equal(t, "3", trophies[1].AwardID)
+	assert.Equal(t, Timestamp(1600000000), trophies[1].GrantedAt)
+}
+
+func TestGetUserTrophies_UserNotFound(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"not found", http.StatusNotFound, `{"message": "Not Found", "error": 404}`},
+		{"user doesn't exist", http.StatusBadRequest,
+			`{"fields": ["id"], "explanation": "that user doesn't exist", "message": "Bad Request", "reason": "USER_DOESNT_EXIST"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, srv := newAuthenticatedFakeServerClient(t)
+			srv.Respond("/api/v1/user/ghost/trophies", tt.status, tt.body)
+
+			_, err := client.GetUserTrophies(t.Context(), "ghost")
+
+			assert.ErrorIs(t, err, ErrUserNotFound)
+		})
+	}
+}
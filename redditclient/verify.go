@@ -0,0 +1,70 @@
+package redditclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ErrTokenExpired is returned by Verify when the access token has expired by
+// the client's clock or Reddit rejects it; authenticating again recovers
+var ErrTokenExpired = errors.New("access token expired")
+
+// Verify checks that the client's session works by making one cheap request:
+// /api/v1/me when logged in as a user, or a one-post page of r/popular
+// otherwise. CLIs and servers can call it at startup to fail fast with a
+// useful message rather than on their first real request. The result is
+//
+//   - nil if Reddit accepted the token
+//   - ErrNotAuthenticated if Authenticate has not succeeded
+//   - ErrTokenExpired if the token has expired or Reddit rejected it
+//   - ErrRateLimited if the quota is used up, with RateLimitStatus saying
+//     until when
+//   - ErrServerError, ErrCircuitOpen or a transport error if Reddit is down or
+//     unreachable
+//
+// Unlike other methods, Verify does not refresh the token, re-authenticate
+// after a 401 or retry, so the result describes the session as it stands. It
+// bypasses the cache and records the rate-limit headers of the response.
+func (c *Client) Verify(ctx context.Context) (err error) {
+	creds := c.credentials()
+	if !creds.authenticated {
+		return ErrNotAuthenticated
+	}
+	if !c.IsTokenValid() {
+		return fmt.Errorf("%w at %s", ErrTokenExpired, creds.tokenExpiry.Format(time.RFC3339))
+	}
+
+	endpoint, params := "/r/popular/hot.json", url.Values{"limit": {"1"}}
+	if creds.mode == AuthScript {
+		endpoint, params = "/api/v1/me", nil
+	}
+
+	ctx, call := c.startCall(ctx, http.MethodGet, endpoint, params)
+	defer func() { call.end(c, err) }()
+
+	ctx, cancel := callOptionsFrom(ctx).withTimeout(ctx)
+	defer cancel()
+
+	fullURL := c.baseURL + endpoint
+	if len(params) > 0 {
+		fullURL += "?" + params.Encode()
+	}
+
+	_, resp, body, err := c.doAPIRequest(ctx, fullURL, requestOptions{})
+	if err != nil {
+		return err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized:
+		return fmt.Errorf("%w: %w", ErrTokenExpired, c.statusError(endpoint, resp, body))
+	default:
+		return c.statusError(endpoint, resp, body)
+	}
+}
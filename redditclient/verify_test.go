@@ -0,0 +1,137 @@
+package redditclient
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify_OK(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+
+	var got *http.Request
+	mockHTTP.On("Do", mock.Anything).Return(func(req *http.Request) *http.Response {
+		got = req
+		return createHTTPResponse(200, emptyListing, map[string]string{
+			"x-ratelimit-remaining": "42",
+			"x-ratelimit-used":      "58",
+			"x-ratelimit-reset":     "120",
+		})
+	}, nil).Once()
+
+	require.NoError(t, client.Verify(t.Context()))
+	assert.Equal(t, "/r/popular/hot.json", got.URL.Path)
+	assert.Equal(t, "1", got.URL.Query().Get("limit"))
+
+	status := client.RateLimitStatus()
+	assert.InDelta(t, 42, status.Remaining, 0)
+	assert.InDelta(t, 58, status.Used, 0)
+}
+
+func TestVerify_UserAuthChecksIdentity(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	client.authMode = AuthScript
+
+	var got *http.Request
+	mockHTTP.On("Do", mock.Anything).Return(func(req *http.Request) *http.Response {
+		got = req
+		return createHTTPResponse(200, `{"name": "gopher"}`, nil)
+	}, nil).Once()
+
+	require.NoError(t, client.Verify(t.Context()))
+	assert.Equal(t, "/api/v1/me", got.URL.Path)
+	assert.Empty(t, got.URL.RawQuery)
+}
+
+func TestVerify_Classifies(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr []error
+	}{
+		{"rejected token", 401, []error{ErrTokenExpired, ErrUnauthorized}},
+		{"rate limited", 429, []error{ErrRateLimited}},
+		{"outage", 503, []error{ErrServerError}},
+		{"forbidden", 403, []error{ErrForbidden}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A retrying client shows Verify neither retries nor re-authenticates
+			client, mockHTTP, delays := newRetryingTestClient(t)
+			mockHTTP.On("Do", mock.Anything).Return(func(*http.Request) *http.Response {
+				return createHTTPResponse(tt.status, "", map[string]string{"Retry-After": "30"})
+			}, nil)
+
+			err := client.Verify(t.Context())
+			for _, want := range tt.wantErr {
+				assert.ErrorIs(t, err, want)
+			}
+			if tt.status != 401 {
+				assert.NotErrorIs(t, err, ErrTokenExpired)
+			}
+			mockHTTP.AssertNumberOfCalls(t, "Do", 1)
+			assert.Empty(t, *delays)
+		})
+	}
+}
+
+func TestVerify_RateLimitRetryAfter(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	mockHTTP.On("Do", mock.Anything).Return(createHTTPResponse(429, "", map[string]string{"Retry-After": "30"}), nil).Once()
+
+	err := client.Verify(t.Context())
+
+	var rateErr *RateLimitError
+	require.ErrorAs(t, err, &rateErr)
+	assert.Equal(t, 30*time.Second, rateErr.RetryAfter)
+}
+
+func TestVerify_TransportError(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	refused := errors.New("connection refused")
+	mockHTTP.On("Do", mock.Anything).Return((*http.Response)(nil), refused).Once()
+
+	err := client.Verify(t.Context())
+	assert.ErrorIs(t, err, refused)
+	assert.NotErrorIs(t, err, ErrTokenExpired)
+}
+
+func TestVerify_WithoutRequest(t *testing.T) {
+	t.Run("not authenticated", func(t *testing.T) {
+		client, mockHTTP := newAuthenticatedTestClient(t)
+		client.authenticated = false
+
+		assert.ErrorIs(t, client.Verify(t.Context()), ErrNotAuthenticated)
+		mockHTTP.AssertNotCalled(t, "Do", mock.Anything)
+	})
+
+	t.Run("expired by the clock", func(t *testing.T) {
+		client, mockHTTP := newAuthenticatedTestClient(t)
+		client.tokenExpiry = time.Now().Add(-time.Minute)
+
+		assert.ErrorIs(t, client.Verify(t.Context()), ErrTokenExpired)
+		mockHTTP.AssertNotCalled(t, "Do", mock.Anything)
+	})
+}
+
+func TestVerify_BypassesCache(t *testing.T) {
+	client, mockHTTP, _ := newCachingTestClient(t, time.Hour)
+	mockHTTP.On("Do", mock.Anything).Return(func(*http.Request) *http.Response {
+		return createHTTPResponse(200, emptyListing, nil)
+	}, nil)
+
+	// Fill the cache with the very request Verify makes
+	_, err := client.GetPopular(t.Context(), "hot", &ListingOptions{Limit: 1})
+	require.NoError(t, err)
+
+	for range 2 {
+		require.NoError(t, client.Verify(t.Context()))
+	}
+	mockHTTP.AssertNumberOfCalls(t, "Do", 3)
+}
@@ -0,0 +1,245 @@
+package redditclient
+
+import (
+	"cmp"
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// WatchSubreddit polls a subreddit's new listing every interval and sends each
+// post not delivered before, oldest first. The first poll only records what is
+// already there, so the channel carries posts submitted after the watch
+// starts. Failed polls are reported on the error channel and polling carries
+// on; that channel holds one error, and further errors are dropped while it is
+// full so an unread error channel never stalls the watch. Both channels are
+// closed once ctx is cancelled. An interval of zero or less means
+// DefaultWatchInterval.
+func (c *Client) WatchSubreddit(ctx context.Context, subreddit string, interval time.Duration) (<-chan Post, <-chan error) {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	posts := make(chan Post)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(posts)
+		defer close(errs)
+
+		w := postWatcher{}
+		for {
+			// Polls must see fresh data, so they skip the response cache
+			listing, err := c.GetSubredditListing(ForceFresh(ctx), subreddit, SortNew, &ListingOptions{Limit: maxListingLimit})
+			switch {
+			case ctx.Err() != nil:
+				return
+			case err != nil:
+				c.logger.DebugContext(ctx, "watch poll failed", "subreddit", subreddit, "error", err)
+				select {
+				case errs <- err:
+				default:
+				}
+			default:
+				for _, post := range w.update(listing.Data.Children) {
+					select {
+					case posts <- post:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+
+			if err := c.sleep(ctx, interval); err != nil {
+				return
+			}
+		}
+	}()
+
+	return posts, errs
+}
+
+// postWatcher tracks which posts of a new listing have been seen
+type postWatcher struct {
+	seeded bool
+	seen   map[string]bool // Fullnames on the last page
+	oldest Timestamp       // Creation time of the oldest post on the last page
+}
+
+// update records a freshly fetched page, newest first, and returns the posts
+// not seen before in chronological order. The first page only seeds the state.
+func (w *postWatcher) update(children []PostChild) []Post {
+	var fresh []Post
+	if w.seeded {
+		for _, child := range children {
+			post := child.Data
+			// A post older than the last page only slid into view because
+			// newer ones were removed, so it is not new
+			if w.seen[post.Name] || post.Created < w.oldest {
+				continue
+			}
+			fresh = append(fresh, post)
+		}
+	}
+
+	// Remember only this page: anything that has dropped off it cannot
+	// reappear. An empty page, e.g. during an outage, keeps the previous state.
+	if len(children) > 0 {
+		w.seen = make(map[string]bool, len(children))
+		w.oldest = children[0].Data.Created
+		for _, child := range children {
+			w.seen[child.Data.Name] = true
+			w.oldest = min(w.oldest, child.Data.Created)
+		}
+	}
+	w.seeded = true
+
+	slices.SortStableFunc(fresh, func(a, b Post) int {
+		return cmp.Compare(a.Created, b.Created)
+	})
+	return fresh
+}
+
+// Errors that end a WatchComments watch
+var (
+	ErrPostLocked  = errors.New("post is locked")
+	ErrPostDeleted = errors.New("post was deleted or removed")
+)
+
+// WatchComments polls a post's comment thread, sorted by new, every interval
+// and sends each comment not delivered before, oldest first. "More"
+// placeholders are expanded on every poll, within the default expansion
+// budget, so comments hidden behind them are found too. As with
+// WatchSubreddit, the first poll only records existing comments, failed polls
+// are reported on the error channel without stopping the watch, and both
+// channels are closed once ctx is cancelled. If the post is locked, deleted or
+// removed, or the thread is gone, the watch sends an error matching
+// ErrPostLocked, ErrPostDeleted or ErrNotFound and stops. Seen comment IDs are
+// kept in an LRU set of maxWatchedComments entries, so long watches use
+// bounded memory.
+func (c *Client) WatchComments(ctx context.Context, subreddit, postID string, interval time.Duration) (<-chan Comment, <-chan error) {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	comments := make(chan Comment)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(comments)
+		defer close(errs)
+
+		seen := newSeenSet(maxWatchedComments)
+		seeded := false
+		opts := &CommentOptions{Sort: CommentSortNew}
+
+		for {
+			thread, err := c.pollThread(ForceFresh(ctx), subreddit, postID, opts)
+			switch {
+			case ctx.Err() != nil:
+				return
+			case errors.Is(err, ErrPostLocked), errors.Is(err, ErrPostDeleted), errors.Is(err, ErrNotFound):
+				// Make room for the final error in case a transient one is unread
+				select {
+				case <-errs:
+				default:
+				}
+				errs <- fmt.Errorf("stopped watching comments on %s: %w", postID, err)
+				return
+			case err != nil:
+				c.logger.DebugContext(ctx, "watch poll failed", "post", postID, "error", err)
+				select {
+				case errs <- err:
+				default:
+				}
+			default:
+				var fresh []Comment
+				for _, comment := range FlattenComments(thread) {
+					if seen.add(comment.Name) && seeded {
+						fresh = append(fresh, *comment)
+					}
+				}
+				seeded = true
+
+				slices.SortStableFunc(fresh, func(a, b Comment) int {
+					return cmp.Compare(a.Created, b.Created)
+				})
+				for _, comment := range fresh {
+					select {
+					case comments <- comment:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+
+			if err := c.sleep(ctx, interval); err != nil {
+				return
+			}
+		}
+	}()
+
+	return comments, errs
+}
+
+// pollThread fetches a thread for WatchComments with its placeholders
+// expanded, failing with ErrPostLocked or ErrPostDeleted when the post can no
+// longer gain comments
+func (c *Client) pollThread(ctx context.Context, subreddit, postID string, opts *CommentOptions) (*CommentListing, error) {
+	thread, err := c.GetCommentsWithOptions(ctx, subreddit, postID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if children := thread.PostListing.Data.Children; len(children) > 0 {
+		post := children[0].Data
+		switch {
+		case post.Author == deletedPlaceholder:
+			return nil, fmt.Errorf("%w: author deleted it", ErrPostDeleted)
+		case post.RemovedByCategory != "":
+			return nil, fmt.Errorf("%w: removed by %s", ErrPostDeleted, post.RemovedByCategory)
+		case post.Locked:
+			return nil, ErrPostLocked
+		}
+	}
+
+	if err := c.expandComments(ctx, subreddit, postID, &thread.CommentListing, opts); err != nil {
+		return nil, err
+	}
+
+	return &thread.CommentListing, nil
+}
+
+// seenSet remembers up to max keys, forgetting the least recently seen first
+type seenSet struct {
+	max   int
+	order *list.List // Front is most recently seen
+	items map[string]*list.Element
+}
+
+func newSeenSet(max int) *seenSet {
+	return &seenSet{
+		max:   max,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// add records key, reporting whether it was not already in the set
+func (s *seenSet) add(key string) bool {
+	if elem, ok := s.items[key]; ok {
+		s.order.MoveToFront(elem)
+		return false
+	}
+
+	s.items[key] = s.order.PushFront(key)
+	if s.order.Len() > s.max {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.items, oldest.Value.(string))
+	}
+	return true
+}
@@ -0,0 +1,258 @@
+package redditclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPage builds a new-listing body from "id:created" pairs, newest first
+func newPage(posts ...string) string {
+	children := make([]string, len(posts))
+	for i, p := range posts {
+		id, created, _ := strings.Cut(p, ":")
+		children[i] = fmt.Sprintf(`{"kind": "t3", "data": {"id": "%s", "name": "t3_%s", "created_utc": %s}}`, id, id, created)
+	}
+	return `{"kind": "Listing", "data": {"children": [` + strings.Join(children, ",") + `]}}`
+}
+
+// pollGate lets a test step a watcher from one poll to the next
+type pollGate struct {
+	idle chan struct{} // Receives once a poll has finished
+	next chan struct{} // Starts the next poll
+}
+
+// step waits for the current poll to finish, runs between, and starts the next poll
+func (g pollGate) step(between func()) {
+	<-g.idle
+	between()
+	g.next <- struct{}{}
+}
+
+// newWatchClient returns a client against srv whose polls are driven by the
+// returned gate instead of the interval
+func newWatchClient(t *testing.T, srv *fakeRedditServer) (*Client, pollGate) {
+	t.Helper()
+
+	client := newFakeServerClient(t, srv)
+	require.NoError(t, client.Authenticate(t.Context()))
+	client.SetRetryPolicy(RetryPolicy{})
+
+	gate := pollGate{idle: make(chan struct{}), next: make(chan struct{})}
+	client.sleep = func(ctx context.Context, _ time.Duration) error {
+		select {
+		case gate.idle <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		select {
+		case <-gate.next:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return client, gate
+}
+
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+
+	select {
+	case v, ok := <-ch:
+		require.True(t, ok, "channel closed")
+		return v
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for watcher")
+	}
+	panic("unreachable")
+}
+
+func TestWatchSubreddit(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	client, gate := newWatchClient(t, srv)
+	const path = "/r/golang/new.json"
+	respond := func(status int, body string) func() {
+		return func() { srv.Respond(path, status, body) }
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	// The first poll seeds the state without emitting anything
+	srv.Respond(path, http.StatusOK, newPage("b:20", "a:10"))
+	posts, errs := client.WatchSubreddit(ctx, "golang", time.Minute)
+
+	// New posts arrive oldest first; overlapping ones are skipped
+	gate.step(respond(http.StatusOK, newPage("d:40", "c:30", "b:20", "a:10")))
+	assert.Equal(t, "c", receive(t, posts).ID)
+	assert.Equal(t, "d", receive(t, posts).ID)
+
+	// A failed poll is reported and polling continues
+	gate.step(respond(http.StatusInternalServerError, `{"message": "Internal Server Error"}`))
+	assert.ErrorIs(t, receive(t, errs), ErrServerError)
+
+	// "a" dropping off and "z" sliding in from below the previous page is not new
+	gate.step(respond(http.StatusOK, newPage("e:50", "d:40", "c:30", "z:5")))
+	assert.Equal(t, "e", receive(t, posts).ID)
+
+	// Nothing new on a repeat of the same page
+	gate.step(func() {})
+	<-gate.idle
+	select {
+	case post := <-posts:
+		t.Fatalf("unexpected post %s", post.ID)
+	default:
+	}
+
+	req, ok := srv.LastRequest(path)
+	require.True(t, ok)
+	assert.Equal(t, "100", req.Query.Get("limit"))
+
+	cancel()
+	for range posts {
+	}
+	for range errs {
+	}
+}
+
+func TestWatchSubreddit_ErrorsDoNotBlock(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	client, gate := newWatchClient(t, srv)
+	srv.Respond("/r/golang/new.json", http.StatusInternalServerError, `{}`)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	posts, errs := client.WatchSubreddit(ctx, "golang", time.Minute)
+
+	// Several failed polls complete even though nobody reads errs
+	for range 3 {
+		gate.step(func() {})
+	}
+	<-gate.idle
+
+	cancel()
+	_, open := <-posts
+	assert.False(t, open)
+	assert.ErrorIs(t, <-errs, ErrServerError)
+	_, open = <-errs
+	assert.False(t, open)
+}
+
+func TestPostWatcher_EmptyPageKeepsState(t *testing.T) {
+	page := func(posts ...Post) []PostChild {
+		children := make([]PostChild, len(posts))
+		for i, p := range posts {
+			children[i] = PostChild{Kind: KindPost, Data: p}
+		}
+		return children
+	}
+	a := Post{Name: "t3_a", Created: 10}
+	b := Post{Name: "t3_b", Created: 20}
+
+	var w postWatcher
+	assert.Empty(t, w.update(page(a)))
+	assert.Empty(t, w.update(nil))
+	assert.Equal(t, []Post{b}, w.update(page(b, a)))
+}
+
+// threadBody builds a comments response for post abc with the given post
+// fields and top-level comment children
+func threadBody(postFields string, comments ...string) string {
+	return `[{"kind": "Listing", "data": {"children": [{"kind": "t3", "data": {"id": "abc", "name": "t3_abc"` + postFields + `}}]}},
+		{"kind": "Listing", "data": {"children": [` + strings.Join(comments, ",") + `]}}]`
+}
+
+func commentJSON(id string, created int, replies ...string) string {
+	repliesJSON := `""`
+	if len(replies) > 0 {
+		repliesJSON = `{"kind": "Listing", "data": {"children": [` + strings.Join(replies, ",") + `]}}`
+	}
+	return fmt.Sprintf(`{"kind": "t1", "data": {"id": "%s", "name": "t1_%s", "parent_id": "t3_abc", "created_utc": %d, "replies": %s}}`,
+		id, id, created, repliesJSON)
+}
+
+func TestWatchComments(t *testing.T) {
+	srv := newFakeRedditServer(t)
+	client, gate := newWatchClient(t, srv)
+	const path = "/r/golang/comments/abc.json"
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	srv.Respond(path, http.StatusOK, threadBody("", commentJSON("c1", 10)))
+	comments, errs := client.WatchComments(ctx, "golang", "abc", time.Minute)
+
+	// A reply in the tree and a comment behind a "more" placeholder are both new
+	gate.step(func() {
+		srv.Respond(path, http.StatusOK, threadBody("",
+			commentJSON("c1", 10, commentJSON("c3", 30)),
+			`{"kind": "more", "data": {"id": "c2", "parent_id": "t3_abc", "count": 1, "children": ["c2"]}}`))
+		srv.Respond("/api/morechildren.json", http.StatusOK,
+			`{"json": {"errors": [], "data": {"things": [`+commentJSON("c2", 20)+`]}}}`)
+	})
+	assert.Equal(t, "c2", receive(t, comments).ID)
+	assert.Equal(t, "c3", receive(t, comments).ID)
+
+	req, ok := srv.LastRequest(path)
+	require.True(t, ok)
+	assert.Equal(t, "new", req.Query.Get("sort"))
+
+	// Locking the post ends the watch
+	gate.step(func() {
+		srv.Respond(path, http.StatusOK, threadBody(`, "locked": true`, commentJSON("c1", 10)))
+	})
+	err := receive(t, errs)
+	assert.ErrorIs(t, err, ErrPostLocked)
+	assert.Contains(t, err.Error(), "abc")
+
+	_, open := <-comments
+	assert.False(t, open)
+	_, open = <-errs
+	assert.False(t, open)
+}
+
+func TestWatchComments_StopsWhenPostGone(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"deleted", http.StatusOK, threadBody(`, "author": "[deleted]"`), ErrPostDeleted},
+		{"removed", http.StatusOK, threadBody(`, "removed_by_category": "moderator"`), ErrPostDeleted},
+		{"not found", http.StatusNotFound, `{"message": "Not Found", "error": 404}`, ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newFakeRedditServer(t)
+			client, _ := newWatchClient(t, srv)
+			srv.Respond("/r/golang/comments/abc.json", tt.status, tt.body)
+
+			comments, errs := client.WatchComments(t.Context(), "golang", "abc", time.Minute)
+
+			assert.ErrorIs(t, receive(t, errs), tt.want)
+			_, open := <-comments
+			assert.False(t, open)
+		})
+	}
+}
+
+func TestSeenSet_EvictsLeastRecentlySeen(t *testing.T) {
+	s := newSeenSet(2)
+
+	assert.True(t, s.add("a"))
+	assert.True(t, s.add("b"))
+	assert.False(t, s.add("a")) // Refreshes "a"
+	assert.True(t, s.add("c"))  // Evicts "b"
+
+	assert.False(t, s.add("a"))
+	assert.True(t, s.add("b"))
+	assert.Equal(t, 2, s.order.Len())
+}
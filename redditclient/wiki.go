@@ -0,0 +1,97 @@
+package redditclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// GetWikiPage fetches a page of a subreddit's wiki. Nested pages are named by
+// their path, e.g. "index/faq". Pages that were never created match
+// ErrNotFound and pages restricted to moderators match ErrForbidden.
+func (c *Client) GetWikiPage(ctx context.Context, subreddit, page string) (*WikiPage, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	path, err := wikiPath(page)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.makeAPIRequest(ctx, fmt.Sprintf("/r/%s/wiki/%s.json", url.PathEscape(subreddit), path), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var thing Thing[WikiPage]
+	if err := c.decodeJSON(ctx, body, &thing, "wiki page"); err != nil {
+		return nil, err
+	}
+
+	return &thing.Data, nil
+}
+
+// ListWikiPages returns the paths of every wiki page of a subreddit the
+// client may view
+func (c *Client) ListWikiPages(ctx context.Context, subreddit string) ([]string, error) {
+	if !c.isAuthenticated() {
+		return nil, ErrNotAuthenticated
+	}
+
+	body, err := c.makeAPIRequest(ctx, fmt.Sprintf("/r/%s/wiki/pages.json", url.PathEscape(subreddit)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var pages Thing[[]string]
+	if err := c.decodeJSON(ctx, body, &pages, "wiki pages"); err != nil {
+		return nil, err
+	}
+
+	return pages.Data, nil
+}
+
+// wikiPath escapes each segment of a wiki page path, keeping the slashes
+// between them
+func wikiPath(page string) (string, error) {
+	segments := strings.Split(page, "/")
+	for i, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", fmt.Errorf("%w: wiki page %q", ErrInvalidArgument, page)
+		}
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/"), nil
+}
+
+// UnmarshalJSON decodes the page, flattening the t2 revision_by thing into a
+// username and unescaping content_html if Reddit ignored raw_json
+func (w *WikiPage) UnmarshalJSON(data []byte) error {
+	type wikiPageAlias WikiPage
+	aux := struct {
+		*wikiPageAlias
+		RevisionBy json.RawMessage `json:"revision_by"`
+	}{wikiPageAlias: (*wikiPageAlias)(w)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	w.ContentHTML = renderHTML(w.ContentHTML, false)
+	if len(aux.RevisionBy) == 0 {
+		return nil
+	}
+
+	var user UserResponse
+	if err := json.Unmarshal(aux.RevisionBy, &user); err != nil {
+		if err := json.Unmarshal(aux.RevisionBy, &w.RevisionBy); err != nil {
+			return fmt.Errorf("failed to decode wiki revision author: %w", err)
+		}
+		return nil
+	}
+	w.RevisionBy = user.Data.Name
+	return nil
+}
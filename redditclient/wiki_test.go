@@ -0,0 +1,95 @@
+package redditclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const wikiPageBody = `{"kind": "wikipage", "data": {
+	"content_md": "# FAQ\n\nQ &amp; A",
+	"content_html": "&lt;!-- SC_OFF --&gt;&lt;div class=\"md wiki\"&gt;&lt;h1&gt;FAQ&lt;/h1&gt;&lt;/div&gt;&lt;!-- SC_ON --&gt;",
+	"revision_date": 1700000000, "may_revise": false,
+	"revision_by": {"kind": "t2", "data": {"name": "golang-mod"}}}}`
+
+func TestGetWikiPage_Success(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.Respond("/r/golang/wiki/index/faq.json", http.StatusOK, wikiPageBody)
+
+	page, err := client.GetWikiPage(t.Context(), "golang", "index/faq")
+
+	require.NoError(t, err)
+	assert.Equal(t, "# FAQ\n\nQ & A", page.ContentMD)
+	assert.Equal(t, `<!-- SC_OFF --><div class="md wiki"><h1>FAQ</h1></div><!-- SC_ON -->`, page.ContentHTML)
+	assert.Equal(t, Timestamp(1700000000), page.RevisionDate)
+	assert.Equal(t, "golang-mod", page.RevisionBy)
+}
+
+func TestGetWikiPage_EscapesSegments(t *testing.T) {
+	client, mockHTTP := newAuthenticatedTestClient(t)
+	mockHTTP.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.EscapedPath() == "/r/golang/wiki/index/getting%20started%3F.json"
+	})).Return(createHTTPResponse(http.StatusOK, wikiPageBody, nil), nil)
+
+	_, err := client.GetWikiPage(t.Context(), "golang", "index/getting started?")
+
+	require.NoError(t, err)
+	mockHTTP.AssertExpectations(t)
+}
+
+func TestGetWikiPage_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		sentinel error
+	}{
+		{"not created", http.StatusNotFound, `{"reason": "PAGE_NOT_CREATED", "message": "Not Found", "error": 404}`, ErrNotFound},
+		{"not created behind 403", http.StatusForbidden, `{"reason": "PAGE_NOT_CREATED", "message": "Forbidden", "error": 403}`, ErrNotFound},
+		{"restricted", http.StatusForbidden, `{"reason": "MAY_NOT_VIEW", "message": "Forbidden", "error": 403}`, ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, srv := newAuthenticatedFakeServerClient(t)
+			srv.Respond("/r/golang/wiki/secret.json", tt.status, tt.body)
+
+			_, err := client.GetWikiPage(t.Context(), "golang", "secret")
+
+			assert.ErrorIs(t, err, tt.sentinel)
+		})
+	}
+}
+
+func TestGetWikiPage_InvalidPath(t *testing.T) {
+	client, _ := newAuthenticatedFakeServerClient(t)
+
+	for _, page := range []string{"", "index/", "../config/sidebar"} {
+		_, err := client.GetWikiPage(t.Context(), "golang", page)
+		assert.ErrorIs(t, err, ErrInvalidArgument, page)
+	}
+}
+
+func TestListWikiPages_Success(t *testing.T) {
+	client, srv := newAuthenticatedFakeServerClient(t)
+	srv.Respond("/r/golang/wiki/pages.json", http.StatusOK,
+		`{"kind": "wikipagelisting", "data": ["index", "index/faq", "config/sidebar"]}`)
+
+	pages, err := client.ListWikiPages(t.Context(), "golang")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"index", "index/faq", "config/sidebar"}, pages)
+}
+
+func TestWikiPage_RoundTrip(t *testing.T) {
+	data, err := json.Marshal(WikiPage{ContentMD: "hi", RevisionBy: "golang-mod"})
+	require.NoError(t, err)
+
+	var page WikiPage
+	require.NoError(t, json.Unmarshal(data, &page))
+	assert.Equal(t, "golang-mod", page.RevisionBy)
+}
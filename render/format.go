@@ -0,0 +1,113 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+)
+
+// Helpers shared by the Markdown, Gemtext and Text renderers
+
+// age formats a created_utc timestamp relative to now, e.g. "3 hours ago"
+func age(now time.Time, created redditclient.Timestamp) string {
+	d := created.Age(now)
+
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return formatCount(int(d/time.Minute), "minute") + " ago"
+	case d < 24*time.Hour:
+		return formatCount(int(d/time.Hour), "hour") + " ago"
+	case d < 30*24*time.Hour:
+		return formatCount(int(d/(24*time.Hour)), "day") + " ago"
+	case d < 365*24*time.Hour:
+		return formatCount(int(d/(30*24*time.Hour)), "month") + " ago"
+	default:
+		return formatCount(int(d/(365*24*time.Hour)), "year") + " ago"
+	}
+}
+
+// formatCount returns "1 point", "2 points" and so on
+func formatCount(n int, unit string) string {
+	if n == 1 || n == -1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// permalinkURL makes a Reddit permalink absolute
+func permalinkURL(permalink string) string {
+	if strings.HasPrefix(permalink, "/") {
+		return "https://www.reddit.com" + permalink
+	}
+	return permalink
+}
+
+// postMeta returns a post's subreddit, author, score, comment count and age,
+// passing names through escape
+func postMeta(now time.Time, p *redditclient.Post, escape func(string) string) string {
+	parts := []string{"r/" + escape(p.Subreddit), "u/" + escape(p.Author)}
+	if !p.ScoreHidden {
+		parts = append(parts, formatCount(p.Score, "point"))
+	}
+	parts = append(parts, formatCount(p.NumComments, "comment"), age(now, p.Created))
+	return strings.Join(parts, " · ")
+}
+
+// commentMeta returns a comment's author, score and age separated by " · ",
+// leaving out a score Reddit is hiding. author is the already formatted name.
+func commentMeta(c *redditclient.Comment, author, age string) string {
+	parts := []string{author}
+	if score, ok := c.DisplayScore(); ok {
+		parts = append(parts, formatCount(score, "point"))
+	}
+	return strings.Join(append(parts, age), " · ")
+}
+
+// moreText describes a "more" placeholder
+func moreText(more *redditclient.MoreComments) string {
+	if len(more.Children) == 0 {
+		return "Continue this thread…"
+	}
+	return formatCount(more.Count, "more comment")
+}
+
+// writeIndentedComments writes the comments in listing at depth, indented
+// four spaces per level, with their replies below them. body formats a
+// comment's text at an indent, and more the line for a "more" placeholder.
+// It reports whether it wrote anything.
+func writeIndentedComments(w *bufio.Writer, now time.Time, listing *redditclient.CommentListing, depth int,
+	body func(text, indent string) string, more func(more *redditclient.MoreComments, indent string) string) bool {
+	if listing == nil {
+		return false
+	}
+
+	indent := strings.Repeat("    ", depth)
+	wrote := false
+	for _, child := range listing.Data.Children {
+		switch {
+		case child.Comment != nil:
+			c := child.Comment
+			switch {
+			case c.IsDeleted():
+				fmt.Fprintf(w, "\n%s[deleted]\n", indent)
+			case c.IsRemoved():
+				fmt.Fprintf(w, "\n%s[removed]\n", indent)
+			default:
+				fmt.Fprintf(w, "\n%s%s\n", indent, commentMeta(c, "u/"+c.Author, age(now, c.Created)))
+				fmt.Fprintf(w, "%s\n", body(strings.TrimSpace(c.Body), indent))
+			}
+			writeIndentedComments(w, now, c.Replies, depth+1, body, more)
+		case child.More != nil:
+			fmt.Fprintf(w, "\n%s\n", more(child.More, indent))
+		default:
+			continue
+		}
+		wrote = true
+	}
+	return wrote
+}
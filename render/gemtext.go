@@ -0,0 +1,149 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+)
+
+// Gemtext renders posts, comment threads and profiles as text/gemini pages.
+// Gemtext has no nesting, so comment depth is shown by indenting text lines.
+type Gemtext struct {
+	// Now is the time ages are measured from; see Markdown.Now
+	Now time.Time
+
+	// MoreLink returns the link target that loads the comments behind a
+	// "more" placeholder. When nil, or when it returns "", the placeholder
+	// is shown as a plain count line instead.
+	MoreLink func(more *redditclient.MoreComments) string
+}
+
+func (g Gemtext) now() time.Time {
+	if g.Now.IsZero() {
+		return time.Now()
+	}
+	return g.Now
+}
+
+// Posts writes a listing page headed by title. Each post is a link line to
+// its permalink followed by its metadata in a preformatted block.
+func (g Gemtext) Posts(w io.Writer, title string, posts []redditclient.Post) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# %s\n", title)
+	if len(posts) == 0 {
+		fmt.Fprint(bw, "\nNo posts.\n")
+	}
+	for i := range posts {
+		p := &posts[i]
+		fmt.Fprintf(bw, "\n=> %s %s\n", p.Permalink, oneLine(p.Title))
+		fmt.Fprintf(bw, "```\n%s\n```\n", postMeta(g.now(), p, noEscape))
+	}
+	return bw.Flush()
+}
+
+// Thread writes a post followed by its comment tree. Deleted and removed
+// comments are collapsed to a single line, keeping their replies.
+func (g Gemtext) Thread(w io.Writer, p *redditclient.Post, comments *redditclient.CommentListing) error {
+	bw := bufio.NewWriter(w)
+	g.writePost(bw, p)
+
+	fmt.Fprint(bw, "\n## Comments\n")
+	if !g.writeComments(bw, comments, 0) {
+		fmt.Fprint(bw, "\nNo comments.\n")
+	}
+	return bw.Flush()
+}
+
+// Comments writes a comment tree on its own, as the page for a "more"
+// placeholder
+func (g Gemtext) Comments(w io.Writer, title string, comments *redditclient.CommentListing) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# %s\n", title)
+	if !g.writeComments(bw, comments, 0) {
+		fmt.Fprint(bw, "\nNo comments.\n")
+	}
+	return bw.Flush()
+}
+
+// User writes a profile page
+func (g Gemtext) User(w io.Writer, u *redditclient.UserAbout) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# u/%s\n\n", u.Name)
+	if u.IsSuspended {
+		fmt.Fprint(bw, "Suspended.\n")
+		return bw.Flush()
+	}
+	fmt.Fprintf(bw, "```\n%s link karma\n%s comment karma\njoined %s\n```\n",
+		formatCount(u.LinkKarma, "point"), formatCount(u.CommentKarma, "point"), age(g.now(), u.Created))
+	return bw.Flush()
+}
+
+func (g Gemtext) writePost(w *bufio.Writer, p *redditclient.Post) {
+	fmt.Fprintf(w, "# %s\n\n", oneLine(p.Title))
+	fmt.Fprintf(w, "```\n%s\n```\n", postMeta(g.now(), p, noEscape))
+
+	switch {
+	case p.IsDeleted():
+		fmt.Fprint(w, "\n[deleted]\n")
+	case p.IsRemoved():
+		fmt.Fprint(w, "\n[removed]\n")
+	case p.IsSelf:
+		if text := strings.TrimSpace(p.SelfText); text != "" {
+			fmt.Fprintf(w, "\n%s\n", safeText(text, ""))
+		}
+	default:
+		fmt.Fprintf(w, "\n=> %s %s\n", p.URL, p.URL)
+	}
+}
+
+// writeComments writes the comments in listing at depth, and their replies
+// below them. It reports whether it wrote anything.
+func (g Gemtext) writeComments(w *bufio.Writer, listing *redditclient.CommentListing, depth int) bool {
+	return writeIndentedComments(w, g.now(), listing, depth, safeText, func(more *redditclient.MoreComments, indent string) string {
+		link := ""
+		if g.MoreLink != nil {
+			link = g.MoreLink(more)
+		}
+		if link == "" {
+			return indent + moreText(more)
+		}
+		return fmt.Sprintf("=> %s %s%s", link, indent, moreText(more))
+	})
+}
+
+// safeText indents each line of Reddit markdown and keeps it a text line:
+// lines that would read as gemtext links, headings, lists, quotes or
+// preformatting toggles get a leading space when not already indented
+func safeText(text, indent string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if indent == "" && isGemtextMarkup(line) {
+			line = " " + line
+		}
+		if line != "" {
+			line = indent + line
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isGemtextMarkup(line string) bool {
+	for _, prefix := range []string{"=>", "#", "* ", ">", "```"} {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// oneLine joins the lines of s, for headings and link labels
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func noEscape(s string) string { return s }
@@ -0,0 +1,91 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+	"github.com/Koshroy/grapeddit/redditclient/redditclienttest"
+)
+
+func TestGemtext_ThreadGolden(t *testing.T) {
+	thread := redditclienttest.Comments()
+	post, err := thread.Post()
+	require.NoError(t, err)
+
+	g := Gemtext{
+		Now: fixedNow,
+		MoreLink: func(more *redditclient.MoreComments) string {
+			return "/more/" + strings.Join(more.Children, ",")
+		},
+	}
+	var b strings.Builder
+	require.NoError(t, g.Thread(&b, post, &thread.CommentListing))
+
+	path := filepath.Join("testdata", "thread.gmi")
+	if *update {
+		require.NoError(t, os.WriteFile(path, []byte(b.String()), 0o644))
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "run go test -update to create the golden file")
+	assert.Equal(t, string(want), b.String())
+}
+
+func TestGemtext_Posts(t *testing.T) {
+	listing := redditclienttest.SubredditListing()
+	posts := make([]redditclient.Post, len(listing.Data.Children))
+	for i, child := range listing.Data.Children {
+		posts[i] = child.Data
+	}
+
+	var b strings.Builder
+	require.NoError(t, Gemtext{Now: fixedNow}.Posts(&b, "r/golang", posts))
+
+	lines := strings.Split(b.String(), "\n")
+	assert.Equal(t, "# r/golang", lines[0])
+	var links []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "=> ") {
+			links = append(links, line)
+		}
+	}
+	assert.Equal(t, []string{
+		"=> /r/golang/comments/1c3xk7a/whos_hiring_monthly_thread/ Who's hiring? Monthly thread",
+		"=> /r/golang/comments/1c3xk8f/go_122_rangeoverfunc_is_great_for_iterators/ Go 1.22 range-over-func is great for iterators",
+		"=> /r/golang/comments/1c3xk9p/how_do_you_structure_a_large_go_monorepo/ How do you structure a large Go monorepo?",
+	}, links)
+	assert.Contains(t, b.String(), "```\nr/golang · u/gopher_jane · 412 points · 96 comments · 1 day ago\n```\n")
+}
+
+func TestGemtext_MoreWithoutLink(t *testing.T) {
+	thread := redditclienttest.Comments()
+	post, err := thread.Post()
+	require.NoError(t, err)
+
+	var b strings.Builder
+	require.NoError(t, Gemtext{Now: fixedNow}.Thread(&b, post, &thread.CommentListing))
+	assert.Contains(t, b.String(), "\n    2 more comments\n")
+	assert.NotContains(t, b.String(), "=> /r/")
+}
+
+func TestGemtext_EscapesMarkupInBodies(t *testing.T) {
+	comments := decodeComments(t, `{"kind": "Listing", "data": {"children": [
+		{"kind": "t1", "data": {"id": "a", "author": "gopher", "body": "=> not a link\n# not a heading\n`+"```"+`", "created_utc": 1713000000}},
+		{"kind": "t1", "data": {"id": "b", "author": "[deleted]", "body": "[deleted]", "created_utc": 1713000000}}
+	]}}`)
+	post := &redditclient.Post{Title: "multi\nline title", IsSelf: true, SelfText: "=> https://example.com sneaky"}
+
+	var b strings.Builder
+	require.NoError(t, Gemtext{Now: fixedNow}.Thread(&b, post, comments))
+	out := b.String()
+
+	assert.True(t, strings.HasPrefix(out, "# multi line title\n"), out)
+	assert.Contains(t, out, "\n => https://example.com sneaky\n")
+	assert.Contains(t, out, "\n => not a link\n # not a heading\n ```\n")
+	assert.Contains(t, out, "\n[deleted]\n")
+}
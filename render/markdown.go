@@ -0,0 +1,163 @@
+// Package render formats Reddit posts and comment threads for reading outside
+// Reddit, such as piping into glow or saving as notes.
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+)
+
+// Markdown renders posts, comment threads and profiles as CommonMark. Comment
+// trees become nested blockquotes, one level per reply depth.
+type Markdown struct {
+	// Now is the time ages such as "3 hours ago" are measured from. The zero
+	// value uses the current time; set it for reproducible output.
+	Now time.Time
+}
+
+func (m Markdown) now() time.Time {
+	if m.Now.IsZero() {
+		return time.Now()
+	}
+	return m.Now
+}
+
+// Post writes a post: its title as an H1, a metadata line, then the link or
+// selftext
+func (m Markdown) Post(w io.Writer, p *redditclient.Post) error {
+	bw := bufio.NewWriter(w)
+	m.writePost(bw, p)
+	return bw.Flush()
+}
+
+// Thread writes a post followed by its comment tree. Deleted and removed
+// comments are collapsed to a single line, keeping their replies, and "more"
+// placeholders are shown as a count of the comments they hide.
+func (m Markdown) Thread(w io.Writer, p *redditclient.Post, comments *redditclient.CommentListing) error {
+	bw := bufio.NewWriter(w)
+	m.writePost(bw, p)
+
+	fmt.Fprint(bw, "\n## Comments\n")
+	if !m.writeComments(bw, comments, 0) {
+		fmt.Fprint(bw, "\n*No comments.*\n")
+	}
+	return bw.Flush()
+}
+
+// Posts writes a listing as a bulleted list of links with metadata
+func (m Markdown) Posts(w io.Writer, posts []redditclient.Post) error {
+	bw := bufio.NewWriter(w)
+	for i := range posts {
+		p := &posts[i]
+		fmt.Fprintf(bw, "- [%s](%s)  \n  %s\n", escape(p.Title), permalinkURL(p.Permalink), m.postMeta(p))
+	}
+	return bw.Flush()
+}
+
+// User writes a profile summary
+func (m Markdown) User(w io.Writer, u *redditclient.UserAbout) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# u/%s\n\n", escape(u.Name))
+	if u.IsSuspended {
+		fmt.Fprint(bw, "*Suspended.*\n")
+		return bw.Flush()
+	}
+	fmt.Fprintf(bw, "- %s link karma\n", formatCount(u.LinkKarma, "point"))
+	fmt.Fprintf(bw, "- %s comment karma\n", formatCount(u.CommentKarma, "point"))
+	fmt.Fprintf(bw, "- joined %s\n", m.age(u.Created))
+	return bw.Flush()
+}
+
+func (m Markdown) writePost(w *bufio.Writer, p *redditclient.Post) {
+	fmt.Fprintf(w, "# %s\n\n", escape(p.Title))
+	fmt.Fprintf(w, "%s\n", m.postMeta(p))
+
+	switch {
+	case p.IsDeleted():
+		fmt.Fprint(w, "\n*[deleted]*\n")
+	case p.IsRemoved():
+		fmt.Fprint(w, "\n*[removed]*\n")
+	case p.IsSelf:
+		if text := strings.TrimSpace(p.SelfText); text != "" {
+			fmt.Fprintf(w, "\n%s\n", text)
+		}
+	default:
+		fmt.Fprintf(w, "\n<%s>\n", p.URL)
+	}
+}
+
+func (m Markdown) postMeta(p *redditclient.Post) string {
+	return postMeta(m.now(), p, escape)
+}
+
+// writeComments writes the comments in listing at depth, and their replies
+// below them. It reports whether it wrote anything.
+func (m Markdown) writeComments(w *bufio.Writer, listing *redditclient.CommentListing, depth int) bool {
+	if listing == nil {
+		return false
+	}
+
+	wrote := false
+	for _, child := range listing.Data.Children {
+		switch {
+		case child.Comment != nil:
+			m.writeComment(w, child.Comment, depth)
+			m.writeComments(w, child.Comment.Replies, depth+1)
+		case child.More != nil:
+			writeBlock(w, depth, "*"+moreText(child.More)+"*")
+		default:
+			continue
+		}
+		wrote = true
+	}
+	return wrote
+}
+
+func (m Markdown) writeComment(w *bufio.Writer, c *redditclient.Comment, depth int) {
+	switch {
+	case c.IsDeleted():
+		writeBlock(w, depth, "*[deleted]*")
+	case c.IsRemoved():
+		writeBlock(w, depth, "*[removed]*")
+	default:
+		header := commentMeta(c, "**u/"+escape(c.Author)+"**", m.age(c.Created))
+		writeBlock(w, depth, header+"\n\n"+strings.TrimSpace(c.Body))
+	}
+}
+
+// writeBlock writes text as a blockquote nested depth+1 levels deep. The line
+// before it is quoted depth levels deep, which ends the previous block at
+// this depth or deeper while staying inside the parent's quote.
+func writeBlock(w *bufio.Writer, depth int, text string) {
+	outer := strings.Repeat("> ", depth)
+	inner := outer + "> "
+
+	fmt.Fprintf(w, "%s\n", strings.TrimRight(outer, " "))
+	for _, line := range strings.Split(text, "\n") {
+		if line == "" {
+			fmt.Fprintf(w, "%s\n", strings.TrimRight(inner, " "))
+		} else {
+			fmt.Fprintf(w, "%s%s\n", inner, line)
+		}
+	}
+}
+
+// age formats a created_utc timestamp relative to Now, e.g. "3 hours ago"
+func (m Markdown) age(created redditclient.Timestamp) string {
+	return age(m.now(), created)
+}
+
+// markdownEscaper escapes the characters that would turn plain text such as
+// titles and usernames into markup
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`, "#", `\#`,
+)
+
+func escape(s string) string {
+	return markdownEscaper.Replace(s)
+}
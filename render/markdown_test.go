@@ -0,0 +1,150 @@
+package render
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+	"github.com/Koshroy/grapeddit/redditclient/redditclienttest"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// fixedNow is a day after the fixture thread was posted
+var fixedNow = time.Date(2024, 4, 14, 0, 0, 0, 0, time.UTC)
+
+func TestMarkdown_ThreadGolden(t *testing.T) {
+	thread := redditclienttest.Comments()
+	post, err := thread.Post()
+	require.NoError(t, err)
+
+	var b strings.Builder
+	require.NoError(t, Markdown{Now: fixedNow}.Thread(&b, post, &thread.CommentListing))
+
+	path := filepath.Join("testdata", "thread.md")
+	if *update {
+		require.NoError(t, os.WriteFile(path, []byte(b.String()), 0o644))
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "run go test -update to create the golden file")
+	assert.Equal(t, string(want), b.String())
+}
+
+func TestMarkdown_ThreadDepth(t *testing.T) {
+	thread := redditclienttest.Comments()
+	post, err := thread.Post()
+	require.NoError(t, err)
+
+	var b strings.Builder
+	require.NoError(t, Markdown{Now: fixedNow}.Thread(&b, post, &thread.CommentListing))
+	out := b.String()
+
+	// Each comment's header is quoted one level deeper than its parent's
+	redditclient.WalkComments(&thread.CommentListing, func(c *redditclient.Comment, depth int) bool {
+		prefix := strings.Repeat("> ", depth+1) + "**u/" + escape(c.Author) + "**"
+		assert.Contains(t, out, "\n"+prefix, "comment %s at depth %d", c.ID, depth)
+		return true
+	})
+	assert.Contains(t, out, "\n> > *2 more comments*\n")
+	assert.Contains(t, out, "\n> *27 more comments*\n")
+}
+
+// decodeComments decodes a comment listing from JSON
+func decodeComments(t *testing.T, data string) *redditclient.CommentListing {
+	t.Helper()
+
+	var listing redditclient.CommentListing
+	require.NoError(t, json.Unmarshal([]byte(data), &listing))
+	return &listing
+}
+
+func TestMarkdown_CollapsesDeletedComments(t *testing.T) {
+	comments := decodeComments(t, `{"kind": "Listing", "data": {"children": [
+		{"kind": "t1", "data": {"id": "a", "author": "[deleted]", "body": "[deleted]", "score": 5, "created_utc": 1713000000,
+			"replies": {"kind": "Listing", "data": {"children": [
+				{"kind": "t1", "data": {"id": "b", "author": "gopher", "body": "still here", "score": 2, "depth": 1, "created_utc": 1713000000}}
+			]}}}},
+		{"kind": "t1", "data": {"id": "c", "author": "[deleted]", "body": "[removed]", "score": 1, "created_utc": 1713000000}},
+		{"kind": "more", "data": {"id": "d", "count": 0, "children": []}}
+	]}}`)
+	post := &redditclient.Post{Title: "t", Subreddit: "golang", Author: "op", IsSelf: true, Created: 1713000000}
+
+	var b strings.Builder
+	require.NoError(t, Markdown{Now: fixedNow}.Thread(&b, post, comments))
+
+	_, got, _ := strings.Cut(b.String(), "## Comments\n")
+	assert.Equal(t, `
+> *[deleted]*
+>
+> > **u/gopher** · 2 points · 14 hours ago
+> >
+> > still here
+
+> *[removed]*
+
+> *Continue this thread…*
+`, got)
+}
+
+func TestMarkdown_HidesCommentScore(t *testing.T) {
+	comments := decodeComments(t, `{"kind": "Listing", "data": {"children": [
+		{"kind": "t1", "data": {"id": "a", "author": "gopher", "body": "first", "score": 1, "score_hidden": true, "created_utc": 1713000000}}
+	]}}`)
+	post := &redditclient.Post{Title: "t", Subreddit: "golang", Author: "op", IsSelf: true, Created: 1713000000}
+
+	var b strings.Builder
+	require.NoError(t, Markdown{Now: fixedNow}.Thread(&b, post, comments))
+
+	_, got, _ := strings.Cut(b.String(), "## Comments\n")
+	assert.Equal(t, `
+> **u/gopher** · 14 hours ago
+>
+> first
+`, got)
+}
+
+func TestMarkdown_NoComments(t *testing.T) {
+	post := &redditclient.Post{Title: "t", Subreddit: "golang", Author: "op", URL: "https://go.dev/", Created: 1713000000}
+
+	var b strings.Builder
+	require.NoError(t, Markdown{Now: fixedNow}.Thread(&b, post, &redditclient.CommentListing{}))
+	assert.Equal(t, "# t\n\nr/golang · u/op · 0 points · 0 comments · 14 hours ago\n\n<https://go.dev/>\n\n## Comments\n\n*No comments.*\n", b.String())
+}
+
+func TestMarkdown_EscapesTitle(t *testing.T) {
+	post := &redditclient.Post{Title: "# [Meta] *don't* use_this", Subreddit: "golang", Author: "_op_", IsSelf: true}
+
+	var b strings.Builder
+	require.NoError(t, Markdown{Now: fixedNow}.Post(&b, post))
+	assert.True(t, strings.HasPrefix(b.String(), `# \# \[Meta\] \*don't\* use\_this`+"\n\nr/golang · u/\\_op\\_ · "), b.String())
+}
+
+func TestMarkdown_Age(t *testing.T) {
+	m := Markdown{Now: fixedNow}
+	ago := func(d time.Duration) redditclient.Timestamp { return redditclient.Timestamp(fixedNow.Add(-d).Unix()) }
+
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{10 * time.Second, "just now"},
+		{time.Minute, "1 minute ago"},
+		{45 * time.Minute, "45 minutes ago"},
+		{3 * time.Hour, "3 hours ago"},
+		{48 * time.Hour, "2 days ago"},
+		{90 * 24 * time.Hour, "3 months ago"},
+		{800 * 24 * time.Hour, "2 years ago"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, m.age(ago(tt.age)), tt.age.String())
+	}
+}
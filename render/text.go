@@ -0,0 +1,72 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+)
+
+// Text renders posts and comment threads as plain text, for Gopher text
+// files and terminals. Comment depth is shown by indentation.
+type Text struct {
+	// Now is the time ages are measured from; see Markdown.Now
+	Now time.Time
+}
+
+func (t Text) now() time.Time {
+	if t.Now.IsZero() {
+		return time.Now()
+	}
+	return t.Now
+}
+
+// Meta returns a post's metadata on one line: subreddit, author, score,
+// comment count and age
+func (t Text) Meta(p *redditclient.Post) string {
+	return postMeta(t.now(), p, noEscape)
+}
+
+// Thread writes a post followed by its comment tree. Deleted and removed
+// comments are collapsed to a single line, keeping their replies, and "more"
+// placeholders are shown as a count of the comments they hide.
+func (t Text) Thread(w io.Writer, p *redditclient.Post, comments *redditclient.CommentListing) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s\n%s\n", oneLine(p.Title), t.Meta(p))
+
+	switch {
+	case p.IsDeleted():
+		fmt.Fprint(bw, "\n[deleted]\n")
+	case p.IsRemoved():
+		fmt.Fprint(bw, "\n[removed]\n")
+	case p.IsSelf:
+		if text := strings.TrimSpace(p.SelfText); text != "" {
+			fmt.Fprintf(bw, "\n%s\n", text)
+		}
+	default:
+		fmt.Fprintf(bw, "\n%s\n", p.URL)
+	}
+
+	fmt.Fprint(bw, "\nComments\n--------\n")
+	wrote := writeIndentedComments(bw, t.now(), comments, 0, indentLines, func(more *redditclient.MoreComments, indent string) string {
+		return indent + moreText(more)
+	})
+	if !wrote {
+		fmt.Fprint(bw, "\nNo comments.\n")
+	}
+	return bw.Flush()
+}
+
+// indentLines prefixes each non-empty line of text with indent
+func indentLines(text, indent string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,52 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+	"github.com/Koshroy/grapeddit/redditclient/redditclienttest"
+)
+
+func TestText_ThreadGolden(t *testing.T) {
+	thread := redditclienttest.Comments()
+	post, err := thread.Post()
+	require.NoError(t, err)
+
+	var b strings.Builder
+	require.NoError(t, Text{Now: fixedNow}.Thread(&b, post, &thread.CommentListing))
+
+	path := filepath.Join("testdata", "thread.txt")
+	if *update {
+		require.NoError(t, os.WriteFile(path, []byte(b.String()), 0o644))
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "run go test -update to create the golden file")
+	assert.Equal(t, string(want), b.String())
+}
+
+func TestText_LinkPostWithoutComments(t *testing.T) {
+	post := &redditclient.Post{
+		Title:       "Go 1.22 is out",
+		Subreddit:   "golang",
+		Author:      "gopher",
+		URL:         "https://go.dev/blog/go1.22",
+		ScoreHidden: true,
+		Created:     redditclient.Timestamp(fixedNow.Add(-90 * time.Minute).Unix()),
+	}
+
+	var b strings.Builder
+	require.NoError(t, Text{Now: fixedNow}.Thread(&b, post, nil))
+	assert.Equal(t, "Go 1.22 is out\n"+
+		"r/golang · u/gopher · 0 comments · 1 hour ago\n"+
+		"\nhttps://go.dev/blog/go1.22\n"+
+		"\nComments\n--------\n"+
+		"\nNo comments.\n", b.String())
+	assert.Equal(t, "r/golang · u/gopher · 0 comments · 1 hour ago", Text{Now: fixedNow}.Meta(post))
+}
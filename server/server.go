@@ -0,0 +1,258 @@
+// Package server exposes a RedditClient over a small read-only HTTP API, so
+// many local consumers can share one Reddit identity, cache and rate limit.
+//
+// Routes:
+//
+//	GET /r/{subreddit}?sort=hot&limit=&after=&before=
+//	GET /r/{subreddit}/comments/{id}?sort=&limit=&depth=
+//	GET /u/{username}
+//	GET /search?q=...&sort=&t=&sub=&limit=&after=&before=
+//
+// Responses are the client's types encoded as JSON, in the same shapes
+// Reddit uses. Errors are a JSON object {"error": "...", "status": 404} with
+// the status mapped from the client's error; see StatusCode.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+)
+
+// DefaultMaxAge is how long clients may cache successful responses when
+// NewHandler is given no max age
+const DefaultMaxAge = time.Minute
+
+// validName matches the subreddit, post and user names the API passes on.
+// Path values arrive unescaped, so a name with "/", "?" or ".." in it could
+// otherwise point the client at any Reddit endpoint.
+var validName = regexp.MustCompile(`^[A-Za-z0-9_+-]+$`)
+
+// Handler serves the API from a RedditClient. It is safe for concurrent use
+// as long as the client is.
+type Handler struct {
+	client redditclient.RedditClient
+	maxAge time.Duration
+	mux    *http.ServeMux
+}
+
+// NewHandler returns a Handler backed by client, which must already be
+// authenticated. maxAge sets the Cache-Control max-age of successful
+// responses; zero means DefaultMaxAge. It should usually match the client's
+// cache TTL, as a response is no fresher than the client's copy.
+func NewHandler(client redditclient.RedditClient, maxAge time.Duration) *Handler {
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+
+	h := &Handler{client: client, maxAge: maxAge, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /r/{subreddit}", h.subreddit)
+	h.mux.HandleFunc("GET /r/{subreddit}/comments/{id}", h.comments)
+	h.mux.HandleFunc("GET /u/{username}", h.user)
+	h.mux.HandleFunc("GET /search", h.search)
+	h.mux.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "no such endpoint")
+	})
+	return h
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) subreddit(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	listOpts, err := listingOptions(q.Get("limit"), q.Get("after"), q.Get("before"))
+	if err != nil {
+		h.fail(w, err)
+		return
+	}
+
+	sort := redditclient.Sort(q.Get("sort"))
+	if sort == "" {
+		sort = redditclient.SortHot
+	}
+
+	subreddit, err := pathName(r, "subreddit")
+	if err != nil {
+		h.fail(w, err)
+		return
+	}
+
+	listing, err := h.client.GetSubredditListing(r.Context(), subreddit, sort, listOpts)
+	h.respond(w, listing, err)
+}
+
+func (h *Handler) comments(w http.ResponseWriter, r *http.Request) {
+	subreddit, err := pathName(r, "subreddit")
+	if err != nil {
+		h.fail(w, err)
+		return
+	}
+	id, err := pathName(r, "id")
+	if err != nil {
+		h.fail(w, err)
+		return
+	}
+
+	q := r.URL.Query()
+	opts := &redditclient.CommentOptions{Sort: redditclient.CommentSort(q.Get("sort"))}
+	if opts.Limit, err = intParam("limit", q.Get("limit")); err != nil {
+		h.fail(w, err)
+		return
+	}
+	if opts.Depth, err = intParam("depth", q.Get("depth")); err != nil {
+		h.fail(w, err)
+		return
+	}
+
+	thread, err := h.client.GetCommentsWithOptions(r.Context(), subreddit, id, opts)
+	h.respond(w, thread, err)
+}
+
+func (h *Handler) user(w http.ResponseWriter, r *http.Request) {
+	username, err := pathName(r, "username")
+	if err != nil {
+		h.fail(w, err)
+		return
+	}
+
+	user, err := h.client.GetUser(r.Context(), username)
+	h.respond(w, user, err)
+}
+
+func (h *Handler) search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("q") == "" {
+		h.fail(w, fmt.Errorf("%w: q is required", redditclient.ErrInvalidArgument))
+		return
+	}
+	if sub := q.Get("sub"); sub != "" && !validName.MatchString(sub) {
+		h.fail(w, fmt.Errorf("%w: invalid sub %q", redditclient.ErrInvalidArgument, sub))
+		return
+	}
+	listOpts, err := listingOptions(q.Get("limit"), q.Get("after"), q.Get("before"))
+	if err != nil {
+		h.fail(w, err)
+		return
+	}
+
+	results, err := h.client.SearchWithOptions(r.Context(), q.Get("q"), &redditclient.SearchOptions{
+		ListingOptions: *listOpts,
+		Subreddit:      q.Get("sub"),
+		Sort:           redditclient.SearchSort(q.Get("sort")),
+		Timeframe:      redditclient.Timeframe(q.Get("t")),
+	})
+	h.respond(w, results, err)
+}
+
+// pathName returns the path value key, or an ErrInvalidArgument if it is not
+// a valid name
+func pathName(r *http.Request, key string) (string, error) {
+	name := r.PathValue(key)
+	if !validName.MatchString(name) {
+		return "", fmt.Errorf("%w: invalid %s %q", redditclient.ErrInvalidArgument, key, name)
+	}
+	return name, nil
+}
+
+// listingOptions parses the pagination query parameters
+func listingOptions(limit, after, before string) (*redditclient.ListingOptions, error) {
+	n, err := intParam("limit", limit)
+	if err != nil {
+		return nil, err
+	}
+	return &redditclient.ListingOptions{Limit: n, After: after, Before: before}, nil
+}
+
+// intParam parses a non-negative integer query parameter; empty means zero
+func intParam(name, value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: %s must be a non-negative integer, got %q", redditclient.ErrInvalidArgument, name, value)
+	}
+	return n, nil
+}
+
+// respond writes v, or the error if err is set
+func (h *Handler) respond(w http.ResponseWriter, v any, err error) {
+	if err != nil {
+		h.fail(w, err)
+		return
+	}
+
+	body, err := json.Marshal(v)
+	if err != nil {
+		h.fail(w, fmt.Errorf("failed to encode response: %w", err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.maxAge.Seconds())))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// fail writes err with the status StatusCode maps it to
+func (h *Handler) fail(w http.ResponseWriter, err error) {
+	status := StatusCode(err)
+
+	var rateErr *redditclient.RateLimitError
+	if errors.As(err, &rateErr) && rateErr.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(rateErr.RetryAfter.Seconds()))))
+	}
+	writeError(w, status, err.Error())
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	body, _ := json.Marshal(struct {
+		Error  string `json:"error"`
+		Status int    `json:"status"`
+	}{message, status})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// StatusCode maps an error from the client to the HTTP status the API
+// answers with
+func StatusCode(err error) int {
+	var quarantineErr *redditclient.QuarantineError
+	switch {
+	case errors.Is(err, redditclient.ErrInvalidArgument):
+		return http.StatusBadRequest
+	case errors.Is(err, redditclient.ErrNotFound),
+		errors.Is(err, redditclient.ErrUserNotFound),
+		errors.Is(err, redditclient.ErrSubredditBanned):
+		return http.StatusNotFound
+	case errors.Is(err, redditclient.ErrUserSuspended):
+		return http.StatusGone
+	case errors.Is(err, redditclient.ErrForbidden),
+		errors.Is(err, redditclient.ErrSubredditPrivate),
+		errors.Is(err, redditclient.ErrSubredditGated),
+		errors.As(err, &quarantineErr):
+		return http.StatusForbidden
+	case errors.Is(err, redditclient.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		// The consumer went away; nobody reads this status
+		return 499
+	default:
+		return http.StatusBadGateway
+	}
+}
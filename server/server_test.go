@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+	"github.com/Koshroy/grapeddit/redditclient/redditclienttest"
+)
+
+// get serves a GET of target from a Handler backed by fake
+func get(t *testing.T, fake *redditclienttest.FakeClient, target string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	NewHandler(fake, 30*time.Second).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestHandler_Subreddit(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+
+	rec := get(t, fake, "/r/golang?sort=new&limit=10&after=t3_abc")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=30", rec.Header().Get("Cache-Control"))
+
+	var listing redditclient.SubredditListing
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listing))
+	assert.Len(t, listing.Data.Children, 3)
+
+	calls := fake.CallsTo("GetSubredditListing")
+	require.Len(t, calls, 1)
+	assert.Equal(t, []any{"golang", redditclient.SortNew, &redditclient.ListingOptions{Limit: 10, After: "t3_abc"}}, calls[0].Args)
+}
+
+func TestHandler_SubredditDefaultSort(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+
+	rec := get(t, fake, "/r/golang")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, redditclient.SortHot, fake.CallsTo("GetSubredditListing")[0].Args[1])
+}
+
+func TestHandler_Comments(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+
+	rec := get(t, fake, "/r/golang/comments/1c3xk9p?sort=top&depth=2")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var thread redditclient.PostAndCommentsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &thread))
+	post, err := thread.Post()
+	require.NoError(t, err)
+	assert.Equal(t, "1c3xk9p", post.ID)
+	assert.Equal(t, 3, redditclient.CountComments(&thread.CommentListing))
+
+	calls := fake.CallsTo("GetCommentsWithOptions")
+	require.Len(t, calls, 1)
+	assert.Equal(t, []any{"golang", "1c3xk9p", &redditclient.CommentOptions{Sort: redditclient.CommentSortTop, Depth: 2}}, calls[0].Args)
+}
+
+func TestHandler_User(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+
+	rec := get(t, fake, "/u/gopher_jane")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var user redditclient.UserResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &user))
+	assert.Equal(t, "gopher_jane", user.Data.Name)
+	assert.Equal(t, []any{"gopher_jane"}, fake.CallsTo("GetUser")[0].Args)
+}
+
+func TestHandler_Search(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+
+	rec := get(t, fake, "/search?q=generics&sort=top&t=week&sub=golang&limit=5")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var results redditclient.SearchResults
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &results))
+	assert.Len(t, results.Posts, 3)
+
+	calls := fake.CallsTo("SearchWithOptions")
+	require.Len(t, calls, 1)
+	assert.Equal(t, []any{"generics", &redditclient.SearchOptions{
+		ListingOptions: redditclient.ListingOptions{Limit: 5},
+		Subreddit:      "golang",
+		Sort:           redditclient.SearchSortTop,
+		Timeframe:      redditclient.TimeframeWeek,
+	}}, calls[0].Args)
+}
+
+func TestHandler_BadRequests(t *testing.T) {
+	tests := []struct {
+		target string
+		status int
+	}{
+		{"/search", http.StatusBadRequest},
+		{"/r/golang?limit=ten", http.StatusBadRequest},
+		{"/r/golang/comments/abc?depth=-1", http.StatusBadRequest},
+		{"/nope", http.StatusNotFound},
+		{"/r/golang%2F..%2F..%2Fapi%2Fv1%2Fme%3F", http.StatusBadRequest},
+		{"/r/golang/comments/abc%3Fx", http.StatusBadRequest},
+		{"/r/golang%23x/comments/abc", http.StatusBadRequest},
+		{"/u/x%2F..%2F..%2Fapi%2Fv1%2Fme%3F", http.StatusBadRequest},
+		{"/search?q=go&sub=golang%2F..", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			fake := redditclienttest.NewFakeClient()
+			rec := get(t, fake, tt.target)
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+			assert.Empty(t, fake.Calls())
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	NewHandler(redditclienttest.NewFakeClient(), 0).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/r/golang", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestHandler_UpstreamErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"not found", &redditclient.APIError{StatusCode: 404, Endpoint: "/r/x"}, http.StatusNotFound},
+		{"forbidden", &redditclient.APIError{StatusCode: 403, Endpoint: "/r/x"}, http.StatusForbidden},
+		{"private", fmt.Errorf("r/x: %w", redditclient.ErrSubredditPrivate), http.StatusForbidden},
+		{"quarantined", &redditclient.QuarantineError{}, http.StatusForbidden},
+		{"banned", redditclient.ErrSubredditBanned, http.StatusNotFound},
+		{"invalid sort", fmt.Errorf("%w: sort", redditclient.ErrInvalidArgument), http.StatusBadRequest},
+		{"server error", &redditclient.APIError{StatusCode: 503, Endpoint: "/r/x"}, http.StatusBadGateway},
+		{"timeout", context.DeadlineExceeded, http.StatusGatewayTimeout},
+		{"other", errors.New("connection reset"), http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := redditclienttest.NewFakeClient()
+			fake.Err = tt.err
+
+			rec := get(t, fake, "/r/x")
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+
+			var body struct {
+				Error  string `json:"error"`
+				Status int    `json:"status"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.status, body.Status)
+			assert.Equal(t, tt.err.Error(), body.Error)
+		})
+	}
+}
+
+func TestHandler_RateLimited(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+	fake.Err = &redditclient.RateLimitError{
+		APIError:   &redditclient.APIError{StatusCode: 429, Endpoint: "/r/golang"},
+		RetryAfter: 1500 * time.Millisecond,
+	}
+
+	rec := get(t, fake, "/r/golang")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+}
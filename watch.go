@@ -0,0 +1,547 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+)
+
+const (
+	// watchErrorStreak is how many poll errors in a row make watch back off
+	watchErrorStreak = 3
+
+	// maxWatchBackoff caps the wait between retries after repeated errors
+	maxWatchBackoff = 15 * time.Minute
+
+	// webhookTimeout bounds a single webhook delivery
+	webhookTimeout = 10 * time.Second
+
+	// scoreWindow is how long after submission a post below --min-score is
+	// re-checked in case it reaches the threshold
+	scoreWindow = 24 * time.Hour
+
+	// maxPendingScores caps the posts awaiting --min-score, so re-checking
+	// them takes one request per poll
+	maxPendingScores = 100
+)
+
+// subredditWatcher is the streaming part of *redditclient.Client that watch
+// is built on; RedditClient does not include it
+type subredditWatcher interface {
+	WatchSubreddit(ctx context.Context, subreddit string, interval time.Duration) (<-chan redditclient.Post, <-chan error)
+}
+
+func runWatch(ctx context.Context, app *cli, fs *flag.FlagSet, args []string) error {
+	var filter postFilter
+	fs.Func("keyword", "regular expression the title or text must match, case-insensitively; repeatable, any may match", func(s string) error {
+		re, err := regexp.Compile("(?i)" + s)
+		if err != nil {
+			return err
+		}
+		filter.keywords = append(filter.keywords, re)
+		return nil
+	})
+	fs.Func("flair", "post flair to match, case-insensitively; repeatable", func(s string) error {
+		filter.flairs = append(filter.flairs, s)
+		return nil
+	})
+	fs.Func("author", "author to match, case-insensitively; repeatable", func(s string) error {
+		filter.authors = append(filter.authors, s)
+		return nil
+	})
+	fs.IntVar(&filter.minScore, "min-score", 0, "minimum score; posts below it are re-checked each poll for a day")
+	interval := fs.Duration("interval", redditclient.DefaultWatchInterval, "how often to poll")
+	webhook := fs.String("webhook", "", "URL to POST a JSON payload to for each match")
+	execTmpl := fs.String("exec", "", "command to run for each match, split into words as by a shell; each word is a template over the post, e.g. 'notify-send \"New: {{.Title}}\"'")
+	statePath := fs.String("state", "", "file remembering the last post seen, so a restart neither repeats nor misses posts")
+	pos, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if *interval <= 0 {
+		return usageError("--interval must be positive")
+	}
+	subreddit := strings.TrimPrefix(pos[0], "r/")
+
+	w := &watcher{
+		app:       app,
+		subreddit: subreddit,
+		interval:  *interval,
+		filter:    filter,
+		statePath: *statePath,
+		webhook:   *webhook,
+		http:      &http.Client{Timeout: webhookTimeout},
+	}
+	if *execTmpl != "" {
+		if w.exec, err = parseCommandTemplate(*execTmpl); err != nil {
+			return usageError(fmt.Sprintf("invalid --exec: %v", err))
+		}
+	}
+	if *statePath != "" {
+		if w.state, err = loadWatchState(*statePath, subreddit); err != nil {
+			return err
+		}
+	}
+
+	client, err := app.connect(ctx)
+	if err != nil {
+		return err
+	}
+	stream, ok := client.(subredditWatcher)
+	if !ok {
+		return errors.New("client cannot watch subreddits")
+	}
+	w.client, w.stream = client, stream
+
+	fmt.Fprintf(app.stderr, "grapeddit: watching r/%s every %s\n", subreddit, *interval)
+	w.run(ctx)
+	fmt.Fprintf(app.stderr, "grapeddit: stopped watching r/%s\n", subreddit)
+	return nil
+}
+
+// postFilter selects the posts watch reports. Every set criterion must hold;
+// within a repeatable criterion any value may match.
+type postFilter struct {
+	keywords []*regexp.Regexp // Matched against the title and selftext
+	minScore int
+	flairs   []string
+	authors  []string
+}
+
+func (f *postFilter) match(p *redditclient.Post) bool {
+	return p.Score >= f.minScore && f.matchContent(p)
+}
+
+// matchContent checks every criterion but the score, which can still change
+// after the post is seen
+func (f *postFilter) matchContent(p *redditclient.Post) bool {
+	if len(f.flairs) > 0 && !containsFold(f.flairs, p.LinkFlairText) {
+		return false
+	}
+	if len(f.authors) > 0 && !containsFold(f.authors, p.Author) {
+		return false
+	}
+	if len(f.keywords) == 0 {
+		return true
+	}
+	for _, re := range f.keywords {
+		if re.MatchString(p.Title) || re.MatchString(p.SelfText) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// watchState is what watch persists between runs
+type watchState struct {
+	Subreddit   string                 `json:"subreddit"`
+	LastSeen    string                 `json:"last_seen"`     // Fullname of the newest post handled
+	LastSeenUTC redditclient.Timestamp `json:"last_seen_utc"` // Its creation time
+}
+
+// loadWatchState reads the state file at path; a missing file is an empty
+// state. A state kept for another subreddit is an error rather than a
+// cursor into the wrong listing.
+func loadWatchState(path, subreddit string) (watchState, error) {
+	state := watchState{Subreddit: subreddit}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("failed to read state file %s: %w", path, err)
+	}
+	if !strings.EqualFold(state.Subreddit, subreddit) {
+		return state, fmt.Errorf("state file %s is for r/%s, not r/%s", path, state.Subreddit, subreddit)
+	}
+	return state, nil
+}
+
+// save writes the state to path through a temporary file, so an interrupted
+// write never leaves a truncated state behind
+func (s watchState) save(path string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// watcher runs the watch command's polling loop
+type watcher struct {
+	app       *cli
+	client    redditclient.RedditClient
+	stream    subredditWatcher
+	subreddit string
+	interval  time.Duration
+	filter    postFilter
+	state     watchState
+	statePath string // Empty when the state is not persisted
+	webhook   string
+	exec      []*template.Template
+	http      *http.Client
+	pending   []redditclient.Post // Matches but for the score, oldest first
+}
+
+// run watches until ctx is done. After watchErrorStreak poll errors in a row
+// it stops the stream and waits, doubling the wait up to maxWatchBackoff
+// while the errors persist, before starting over.
+func (w *watcher) run(ctx context.Context) {
+	backoff := w.interval
+	for {
+		healthy, err := w.session(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if healthy {
+			backoff = w.interval
+		}
+		fmt.Fprintf(w.app.stderr, "grapeddit: %v; retrying in %s\n", err, backoff)
+		if sleepContext(ctx, backoff) != nil {
+			return
+		}
+		backoff = min(backoff*2, maxWatchBackoff)
+	}
+}
+
+// session catches up on posts missed since the last seen one, then streams
+// new posts until ctx is done or polls fail watchErrorStreak times in a row.
+// It reports whether catching up succeeded.
+func (w *watcher) session(ctx context.Context) (bool, error) {
+	if err := w.catchUp(ctx); err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	posts, errs := w.stream.WatchSubreddit(ctx, w.subreddit, w.interval)
+
+	// Scores of pending posts are re-checked once per interval
+	var recheck <-chan time.Time
+	if w.filter.minScore > 0 {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		recheck = ticker.C
+	}
+
+	// The stream reports failed polls but not successful ones, so errors
+	// count as in a row when they are about one interval apart
+	streak := 0
+	var lastErr time.Time
+	for {
+		select {
+		case p, ok := <-posts:
+			if !ok {
+				return true, ctx.Err()
+			}
+			streak = 0
+			w.handle(ctx, &p)
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			now := w.app.clock()
+			if streak > 0 && now.Sub(lastErr) > w.interval*3/2 {
+				streak = 0
+			}
+			streak++
+			lastErr = now
+			fmt.Fprintf(w.app.stderr, "grapeddit: poll failed: %v\n", err)
+			if streak >= watchErrorStreak {
+				return true, fmt.Errorf("%d polls failed in a row", streak)
+			}
+		case <-recheck:
+			w.recheck(ctx)
+		}
+	}
+}
+
+// catchUp handles up to a page of posts submitted after the last seen one,
+// which the stream would skip as already there when it starts
+func (w *watcher) catchUp(ctx context.Context) error {
+	if w.state.LastSeen == "" {
+		return nil
+	}
+	listing, err := w.client.GetSubredditListing(redditclient.ForceFresh(ctx), w.subreddit, redditclient.SortNew,
+		&redditclient.ListingOptions{Limit: 100, Before: w.state.LastSeen})
+	if err != nil {
+		return fmt.Errorf("failed to catch up on r/%s: %w", w.subreddit, err)
+	}
+
+	posts := make([]redditclient.Post, len(listing.Data.Children))
+	for i, child := range listing.Data.Children {
+		posts[i] = child.Data
+	}
+	slices.SortStableFunc(posts, func(a, b redditclient.Post) int {
+		return cmp.Compare(a.Created, b.Created)
+	})
+	for i := range posts {
+		w.handle(ctx, &posts[i])
+	}
+	return nil
+}
+
+// handle reports p if it matches and records it as the last post seen. A post
+// matching all but the score is kept for recheck instead. Posts no newer than
+// the last seen one were handled before a restart.
+func (w *watcher) handle(ctx context.Context, p *redditclient.Post) {
+	if p.Name == w.state.LastSeen || p.Created < w.state.LastSeenUTC {
+		return
+	}
+	if w.filter.match(p) {
+		w.notify(ctx, p)
+	} else if w.filter.matchContent(p) {
+		if len(w.pending) == maxPendingScores {
+			w.pending = w.pending[1:]
+		}
+		w.pending = append(w.pending, *p)
+	}
+
+	w.state.LastSeen, w.state.LastSeenUTC = p.Name, p.Created
+	if w.statePath != "" {
+		if err := w.state.save(w.statePath); err != nil {
+			fmt.Fprintf(w.app.stderr, "grapeddit: failed to save state: %v\n", err)
+		}
+	}
+}
+
+// recheck fetches the current scores of the pending posts, reporting those
+// that have reached --min-score. Posts older than scoreWindow or no longer on
+// Reddit are dropped; a failed fetch leaves them for the next recheck.
+func (w *watcher) recheck(ctx context.Context) {
+	now := w.app.clock()
+	w.pending = slices.DeleteFunc(w.pending, func(p redditclient.Post) bool {
+		return p.Created.Age(now) > scoreWindow
+	})
+	if len(w.pending) == 0 {
+		return
+	}
+
+	names := make([]string, len(w.pending))
+	for i, p := range w.pending {
+		names[i] = p.Name
+	}
+	posts, err := w.client.GetPostsByID(redditclient.ForceFresh(ctx), names)
+	if err != nil {
+		fmt.Fprintf(w.app.stderr, "grapeddit: failed to re-check scores: %v\n", err)
+		return
+	}
+
+	w.pending = w.pending[:0]
+	for i := range posts {
+		if posts[i].Score >= w.filter.minScore {
+			w.notify(ctx, &posts[i])
+		} else {
+			w.pending = append(w.pending, posts[i])
+		}
+	}
+}
+
+// notify reports a match: to the webhook and command when set, otherwise on
+// stdout. Delivery failures are reported without stopping the watch.
+func (w *watcher) notify(ctx context.Context, p *redditclient.Post) {
+	record := newPostRecord(p)
+	if w.webhook == "" && w.exec == nil {
+		if w.app.jsonOutput() {
+			writeRecords(w.app.stdout, false, []postRecord{record})
+		} else {
+			printPostLine(w.app.stdout, p)
+		}
+		return
+	}
+
+	if w.webhook != "" {
+		if err := postWebhook(ctx, w.http, w.webhook, newWebhookPayload(record)); err != nil {
+			fmt.Fprintf(w.app.stderr, "grapeddit: webhook failed for %s: %v\n", p.Name, err)
+		}
+	}
+	if w.exec != nil {
+		if err := runCommandTemplate(ctx, w.app, w.exec, record); err != nil {
+			fmt.Fprintf(w.app.stderr, "grapeddit: command failed for %s: %v\n", p.Name, err)
+		}
+	}
+}
+
+// webhookPayload is the JSON body POSTed to --webhook for each match
+type webhookPayload struct {
+	Event string     `json:"event"` // Always "match"
+	Text  string     `json:"text"`  // One-line summary, shown by chat webhooks
+	Post  postRecord `json:"post"`
+}
+
+func newWebhookPayload(record postRecord) webhookPayload {
+	return webhookPayload{
+		Event: "match",
+		Text:  fmt.Sprintf("r/%s: %s https://www.reddit.com%s", record.Subreddit, record.Title, record.Permalink),
+		Post:  record,
+	}
+}
+
+func postWebhook(ctx context.Context, client *http.Client, url string, payload webhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+// parseCommandTemplate splits a --exec command into words and parses each as
+// a template. Splitting before expanding keeps post text out of the word
+// boundaries, so titles cannot inject arguments and no shell is involved.
+func parseCommandTemplate(command string) ([]*template.Template, error) {
+	words, err := splitWords(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, errors.New("empty command")
+	}
+	tmpls := make([]*template.Template, len(words))
+	for i, word := range words {
+		tmpl, err := template.New("exec").Parse(word)
+		if err != nil {
+			return nil, err
+		}
+		tmpls[i] = tmpl
+	}
+	return tmpls, nil
+}
+
+// splitWords splits s into words as a POSIX shell would, without expansions:
+// words are separated by unquoted whitespace, single quotes keep everything
+// up to the closing quote, and double quotes keep everything but backslash
+// escapes of \, " and $. An unquoted backslash escapes the next character.
+func splitWords(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+			continue
+		case c == '\\':
+			if i+1 == len(s) {
+				return nil, errors.New("trailing backslash")
+			}
+			i++
+			word.WriteByte(s[i])
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.New("unterminated single quote")
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+		case c == '"':
+			closed := false
+			for i++; i < len(s); i++ {
+				if s[i] == '"' {
+					closed = true
+					break
+				}
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte(`\"$`, s[i+1]) >= 0 {
+					i++
+				}
+				word.WriteByte(s[i])
+			}
+			if !closed {
+				return nil, errors.New("unterminated double quote")
+			}
+		default:
+			word.WriteByte(c)
+		}
+		inWord = true
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// expandCommand expands each word of a command template over the post's
+// record, whose fields are named as in postRecord, e.g. {{.Title}}
+func expandCommand(tmpls []*template.Template, record postRecord) ([]string, error) {
+	args := make([]string, len(tmpls))
+	for i, tmpl := range tmpls {
+		var b strings.Builder
+		if err := tmpl.Execute(&b, record); err != nil {
+			return nil, err
+		}
+		args[i] = b.String()
+	}
+	return args, nil
+}
+
+func runCommandTemplate(ctx context.Context, app *cli, tmpls []*template.Template, record postRecord) error {
+	args, err := expandCommand(tmpls, record)
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = app.stdout
+	cmd.Stderr = app.stderr
+	return cmd.Run()
+}
+
+// sleepContext waits for d, returning early with ctx's error once it is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
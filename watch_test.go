@@ -0,0 +1,449 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Koshroy/grapeddit/redditclient"
+	"github.com/Koshroy/grapeddit/redditclient/redditclienttest"
+)
+
+func TestPostFilter(t *testing.T) {
+	post := &redditclient.Post{
+		Title:         "Generics in Go 1.24",
+		SelfText:      "Type parameters on methods when?",
+		Author:        "gopher_jane",
+		Score:         60,
+		LinkFlairText: "Discussion",
+	}
+
+	tests := []struct {
+		name   string
+		filter postFilter
+		want   bool
+	}{
+		{"no criteria", postFilter{}, true},
+		{"keyword in title", postFilter{keywords: []*regexp.Regexp{regexp.MustCompile("(?i)generics")}}, true},
+		{"keyword in selftext", postFilter{keywords: []*regexp.Regexp{regexp.MustCompile(`(?i)type param\w+`)}}, true},
+		{"any keyword", postFilter{keywords: []*regexp.Regexp{regexp.MustCompile("(?i)rust"), regexp.MustCompile("(?i)go 1\\.2\\d")}}, true},
+		{"no keyword", postFilter{keywords: []*regexp.Regexp{regexp.MustCompile("(?i)rust")}}, false},
+		{"score at threshold", postFilter{minScore: 60}, true},
+		{"score below threshold", postFilter{minScore: 61}, false},
+		{"flair ignores case", postFilter{flairs: []string{"help", "discussion"}}, true},
+		{"other flair", postFilter{flairs: []string{"help"}}, false},
+		{"author ignores case", postFilter{authors: []string{"Gopher_Jane"}}, true},
+		{"other author", postFilter{authors: []string{"busy_builder"}}, false},
+		{"all criteria must hold", postFilter{
+			keywords: []*regexp.Regexp{regexp.MustCompile("(?i)generics")},
+			minScore: 50,
+			authors:  []string{"busy_builder"},
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.match(post))
+		})
+	}
+}
+
+func TestWebhookPayload(t *testing.T) {
+	listing := redditclienttest.SubredditListing()
+	payload := newWebhookPayload(newPostRecord(&listing.Data.Children[2].Data))
+
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(body, &got))
+	assert.Equal(t, "match", got["event"])
+	assert.Equal(t, "r/golang: How do you structure a large Go monorepo? https://www.reddit.com/r/golang/comments/1c3xk9p/how_do_you_structure_a_large_go_monorepo/", got["text"])
+
+	post, ok := got["post"].(map[string]any)
+	require.True(t, ok, "post is not an object: %v", got["post"])
+	assert.Equal(t, "post", post["kind"])
+	assert.Equal(t, "t3_1c3xk9p", post["name"])
+	assert.Equal(t, "busy_builder", post["author"])
+	assert.Equal(t, "help", post["flair"])
+	assert.Equal(t, 58.0, post["score"])
+	assert.Equal(t, "2024-04-13T00:00:00Z", post["created"])
+}
+
+func TestPostWebhook(t *testing.T) {
+	var got webhookPayload
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer srv.Close()
+
+	payload := newWebhookPayload(postRecord{Kind: "post", Name: "t3_abc", Title: "Hello"})
+	require.NoError(t, postWebhook(t.Context(), srv.Client(), srv.URL, payload))
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, payload, got)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	assert.ErrorContains(t, postWebhook(t.Context(), failing.Client(), failing.URL, payload), "502")
+}
+
+func TestCommandTemplate(t *testing.T) {
+	tmpls, err := parseCommandTemplate("notify-send  {{.Title}} r/{{.Subreddit}}")
+	require.NoError(t, err)
+
+	// Post text stays inside the word it is expanded into
+	args, err := expandCommand(tmpls, postRecord{Title: "rm -rf / ; echo pwned", Subreddit: "golang"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"notify-send", "rm -rf / ; echo pwned", "r/golang"}, args)
+
+	// Quotes group a template with the text around it into one word
+	tmpls, err = parseCommandTemplate(`notify-send "New in r/{{.Subreddit}}" '{{printf "%.20s" .Title}}'`)
+	require.NoError(t, err)
+	args, err = expandCommand(tmpls, postRecord{Title: "How do you structure a large Go monorepo?", Subreddit: "golang"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"notify-send", "New in r/golang", "How do you structure"}, args)
+
+	_, err = parseCommandTemplate("   ")
+	assert.Error(t, err)
+	_, err = parseCommandTemplate("echo {{.Title")
+	assert.Error(t, err)
+	_, err = parseCommandTemplate(`echo "{{.Title}}`)
+	assert.Error(t, err)
+}
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"a  b\tc\n", []string{"a", "b", "c"}},
+		{`say "hello world"`, []string{"say", "hello world"}},
+		{`say 'it''s' "" x`, []string{"say", "its", "", "x"}},
+		{`a" b "c`, []string{"a b c"}},
+		{`"quote \" slash \\ dollar \$ other \n"`, []string{`quote " slash \ dollar $ other \n`}},
+		{`'no \escapes "here"'`, []string{`no \escapes "here"`}},
+		{`one\ word \'`, []string{"one word", "'"}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := splitWords(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, in := range []string{`"open`, `'open`, `trailing\`} {
+		_, err := splitWords(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestWatchState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	state, err := loadWatchState(path, "golang")
+	require.NoError(t, err)
+	assert.Equal(t, watchState{Subreddit: "golang"}, state)
+
+	state.LastSeen, state.LastSeenUTC = "t3_abc", 1712966400
+	require.NoError(t, state.save(path))
+
+	loaded, err := loadWatchState(path, "golang")
+	require.NoError(t, err)
+	assert.Equal(t, state, loaded)
+
+	_, err = loadWatchState(path, "rust")
+	assert.ErrorContains(t, err, "is for r/golang")
+}
+
+func TestWatcher_RechecksScores(t *testing.T) {
+	posts := fixturePosts()
+	created := posts[2].Created.Time()
+	newWatcher := func(fake *redditclienttest.FakeClient, now time.Time) (*watcher, *bytes.Buffer) {
+		var stdout bytes.Buffer
+		app := &cli{stdout: &stdout, stderr: io.Discard, now: func() time.Time { return now }}
+		return &watcher{app: app, client: fake, filter: postFilter{minScore: 100}}, &stdout
+	}
+	scored := func(score int) func(context.Context, []string) ([]redditclient.Post, error) {
+		return func(ctx context.Context, names []string) ([]redditclient.Post, error) {
+			p := posts[2]
+			p.Score = score
+			return []redditclient.Post{p}, nil
+		}
+	}
+
+	t.Run("reaches the threshold", func(t *testing.T) {
+		fake := redditclienttest.NewFakeClient()
+		w, stdout := newWatcher(fake, created.Add(time.Hour))
+
+		// At 58 points the monorepo post is held rather than reported
+		w.handle(t.Context(), &posts[2])
+		assert.Empty(t, stdout.String())
+		assert.Equal(t, "t3_1c3xk9p", w.state.LastSeen)
+
+		fake.PostsByIDFn = scored(99)
+		w.recheck(t.Context())
+		assert.Empty(t, stdout.String())
+		require.Len(t, w.pending, 1)
+
+		fake.PostsByIDFn = scored(100)
+		w.recheck(t.Context())
+		assert.Contains(t, stdout.String(), "1c3xk9p")
+		assert.Empty(t, w.pending)
+		assert.Equal(t, []any{[]string{"t3_1c3xk9p"}}, fake.CallsTo("GetPostsByID")[0].Args)
+	})
+
+	t.Run("ages out", func(t *testing.T) {
+		fake := redditclienttest.NewFakeClient()
+		w, stdout := newWatcher(fake, created.Add(scoreWindow+time.Minute))
+		fake.PostsByIDFn = scored(500)
+
+		w.handle(t.Context(), &posts[2])
+		w.recheck(t.Context())
+		assert.Empty(t, stdout.String())
+		assert.Empty(t, w.pending)
+		assert.Empty(t, fake.CallsTo("GetPostsByID"))
+	})
+
+	t.Run("removed from Reddit", func(t *testing.T) {
+		fake := redditclienttest.NewFakeClient()
+		w, _ := newWatcher(fake, created.Add(time.Hour))
+		fake.PostsByIDFn = func(context.Context, []string) ([]redditclient.Post, error) { return nil, nil }
+
+		w.handle(t.Context(), &posts[2])
+		w.recheck(t.Context())
+		assert.Empty(t, w.pending)
+	})
+
+	t.Run("other criteria are not re-checked", func(t *testing.T) {
+		fake := redditclienttest.NewFakeClient()
+		w, _ := newWatcher(fake, created.Add(time.Hour))
+		w.filter.authors = []string{"someone_else"}
+
+		w.handle(t.Context(), &posts[2])
+		assert.Empty(t, w.pending)
+	})
+}
+
+// failingStream is a stream whose polls all fail, once per error time. The
+// watcher's clock reads the times in turn, and the stream ends after the last.
+type failingStream struct {
+	times []time.Time
+}
+
+func (f *failingStream) WatchSubreddit(ctx context.Context, subreddit string, interval time.Duration) (<-chan redditclient.Post, <-chan error) {
+	posts := make(chan redditclient.Post)
+	errs := make(chan error)
+	go func() {
+		defer close(posts)
+		for range f.times {
+			select {
+			case errs <- errors.New("503 Service Unavailable"):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return posts, errs
+}
+
+func (f *failingStream) clock() time.Time {
+	now := f.times[0]
+	f.times = f.times[1:]
+	return now
+}
+
+func TestWatcher_ErrorStreak(t *testing.T) {
+	const interval = time.Minute
+	start := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+	errorsAt := func(gap time.Duration, n int) []time.Time {
+		times := make([]time.Time, n)
+		for i := range times {
+			times[i] = start.Add(time.Duration(i) * gap)
+		}
+		return times
+	}
+
+	tests := []struct {
+		name    string
+		times   []time.Time
+		wantErr bool
+	}{
+		{"in a row", errorsAt(interval, watchErrorStreak), true},
+		{"between successful polls", errorsAt(2*interval, watchErrorStreak+2), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &failingStream{times: slices.Clone(tt.times)}
+			app := &cli{stdout: io.Discard, stderr: io.Discard, now: stream.clock}
+			w := &watcher{app: app, stream: stream, subreddit: "golang", interval: interval}
+
+			caughtUp, err := w.session(t.Context())
+
+			assert.True(t, caughtUp)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "polls failed in a row")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// streamingFake adds WatchSubreddit to the fake client. It sends posts, then
+// ends the watch by cancelling the command's context.
+type streamingFake struct {
+	*redditclienttest.FakeClient
+	posts  []redditclient.Post
+	cancel context.CancelFunc
+}
+
+func (f *streamingFake) WatchSubreddit(ctx context.Context, subreddit string, interval time.Duration) (<-chan redditclient.Post, <-chan error) {
+	posts := make(chan redditclient.Post)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(posts)
+		defer close(errs)
+		for _, p := range f.posts {
+			select {
+			case posts <- p:
+			case <-ctx.Done():
+				return
+			}
+		}
+		f.cancel()
+		<-ctx.Done()
+	}()
+	return posts, errs
+}
+
+// runWatchCLI runs the watch command against a client streaming posts
+func runWatchCLI(t *testing.T, fake *redditclienttest.FakeClient, posts []redditclient.Post, args ...string) (int, string, string) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	client := &streamingFake{FakeClient: fake, posts: posts, cancel: cancel}
+
+	var stdout, stderr bytes.Buffer
+	app := &cli{
+		stdout: &stdout,
+		stderr: &stderr,
+		connect: func(ctx context.Context, opts ...redditclient.Option) (redditclient.RedditClient, error) {
+			return client, nil
+		},
+	}
+	code := app.run(ctx, append([]string{"watch"}, args...))
+	return code, stdout.String(), stderr.String()
+}
+
+func fixturePosts() []redditclient.Post {
+	listing := redditclienttest.SubredditListing()
+	posts := make([]redditclient.Post, len(listing.Data.Children))
+	for i, child := range listing.Data.Children {
+		posts[i] = child.Data
+	}
+	return posts
+}
+
+func TestCLI_WatchPrintsMatches(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	code, stdout, stderr := runWatchCLI(t, fake, fixturePosts(), "r/golang", "--keyword", "monorepo", "--keyword", "ITERATORS", "--min-score", "50", "--state", statePath)
+	require.Equal(t, exitOK, code, stderr)
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 2, stdout)
+	assert.Contains(t, lines[0], "1c3xk8f")
+	assert.Contains(t, lines[1], "1c3xk9p")
+	assert.Contains(t, stderr, "stopped watching r/golang")
+
+	// Without a saved cursor there is nothing to catch up on
+	assert.Empty(t, fake.CallsTo("GetSubredditListing"))
+
+	state, err := loadWatchState(statePath, "golang")
+	require.NoError(t, err)
+	assert.Equal(t, "t3_1c3xk9p", state.LastSeen)
+}
+
+func TestCLI_WatchResumesFromState(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, watchState{Subreddit: "golang", LastSeen: "t3_1c3xk7a", LastSeenUTC: 1712959200}.save(statePath))
+
+	// The stream repeats the newest post, which catching up already handled
+	posts := fixturePosts()
+	code, stdout, stderr := runWatchCLI(t, fake, posts[2:], "golang", "--state", statePath, "--json")
+	require.Equal(t, exitOK, code, stderr)
+
+	calls := fake.CallsTo("GetSubredditListing")
+	require.Len(t, calls, 1)
+	assert.Equal(t, []any{"golang", redditclient.SortNew, &redditclient.ListingOptions{Limit: 100, Before: "t3_1c3xk7a"}}, calls[0].Args)
+
+	var names []string
+	dec := json.NewDecoder(strings.NewReader(stdout))
+	for {
+		var record postRecord
+		if err := dec.Decode(&record); err == io.EOF {
+			break
+		} else {
+			require.NoError(t, err)
+		}
+		names = append(names, record.Name)
+	}
+	assert.Equal(t, []string{"t3_1c3xk8f", "t3_1c3xk9p"}, names)
+}
+
+func TestCLI_WatchWebhook(t *testing.T) {
+	var got []webhookPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload webhookPayload
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		got = append(got, payload)
+	}))
+	defer srv.Close()
+
+	code, stdout, stderr := runWatchCLI(t, redditclienttest.NewFakeClient(), fixturePosts(), "golang", "--flair", "jobs", "--webhook", srv.URL)
+	require.Equal(t, exitOK, code, stderr)
+	assert.Empty(t, stdout, "matches go to the webhook instead of stdout")
+	require.Len(t, got, 1)
+	assert.Equal(t, "t3_1c3xk7a", got[0].Post.Name)
+}
+
+func TestCLI_WatchUsage(t *testing.T) {
+	fake := redditclienttest.NewFakeClient()
+
+	code, _, stderr := runWatchCLI(t, fake, nil, "golang", "--interval", "0s")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "--interval must be positive")
+
+	code, _, _ = runWatchCLI(t, fake, nil, "golang", "--keyword", "(")
+	assert.Equal(t, exitUsage, code)
+
+	// The plain fake has no WatchSubreddit
+	code, _, stderr = runCLI(t, fake, "watch", "golang")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "cannot watch")
+}